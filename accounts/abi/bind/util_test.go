@@ -77,7 +77,7 @@ func TestWaitDeployed(t *testing.T) {
 			// Create the transaction.
 			// Create the transaction.
 			var tx types.Transaction = types.NewContractCreation(0, u256.Num0, test.gas, u256.Num1, common.FromHex(test.code))
-			signer := types.MakeSigner(params.TestChainConfig, 1)
+			signer := types.MakeSigner(params.TestChainConfig, 1, 0)
 			tx, _ = types.SignTx(tx, *signer, testKey)
 
 			// Wait for it to get mined in the background.
@@ -127,7 +127,7 @@ func TestWaitDeployedCornerCases(t *testing.T) {
 
 	// Create a transaction to an account.
 	code := "6060604052600a8060106000396000f360606040526008565b00"
-	signer := types.MakeSigner(params.TestChainConfig, 1)
+	signer := types.MakeSigner(params.TestChainConfig, 1, 0)
 	var tx types.Transaction = types.NewTransaction(0, libcommon.HexToAddress("0x01"), u256.Num0, 3000000, u256.Num1, common.FromHex(code))
 	tx, _ = types.SignTx(tx, *signer, testKey)
 	ctx, cancel := context.WithCancel(context.Background())
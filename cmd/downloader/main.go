@@ -25,6 +25,7 @@ import (
 	"github.com/ledgerwatch/erigon/params"
 	"github.com/ledgerwatch/erigon/turbo/debug"
 	"github.com/ledgerwatch/erigon/turbo/logging"
+	"github.com/ledgerwatch/erigon/turbo/snapshotsync/httpdownloader"
 	"github.com/ledgerwatch/log/v3"
 	"github.com/pelletier/go-toml/v2"
 	"github.com/spf13/cobra"
@@ -52,6 +53,7 @@ var (
 	targetFile                     string
 	disableIPV6                    bool
 	disableIPV4                    bool
+	httpMirror                     string
 )
 
 func init() {
@@ -71,6 +73,7 @@ func init() {
 	rootCmd.Flags().StringVar(&staticPeersStr, utils.TorrentStaticPeersFlag.Name, utils.TorrentStaticPeersFlag.Value, utils.TorrentStaticPeersFlag.Usage)
 	rootCmd.Flags().BoolVar(&disableIPV6, "downloader.disable.ipv6", utils.DisableIPV6.Value, utils.DisableIPV6.Usage)
 	rootCmd.Flags().BoolVar(&disableIPV4, "downloader.disable.ipv4", utils.DisableIPV4.Value, utils.DisableIPV6.Usage)
+	rootCmd.Flags().StringVar(&httpMirror, utils.DownloaderHTTPMirrorFlag.Name, utils.DownloaderHTTPMirrorFlag.Value, utils.DownloaderHTTPMirrorFlag.Usage)
 	rootCmd.PersistentFlags().BoolVar(&forceVerify, "verify", false, "Force verify data files if have .torrent files")
 
 	withDataDir(printTorrentHashes)
@@ -125,6 +128,9 @@ var rootCmd = &cobra.Command{
 
 func Downloader(ctx context.Context, logger log.Logger) error {
 	dirs := datadir.New(datadirCli)
+	if httpMirror != "" {
+		return MirrorDownloader(ctx, dirs, logger)
+	}
 	torrentLogLevel, _, err := downloadercfg2.Int2LogLevel(torrentVerbosity)
 	if err != nil {
 		return err
@@ -186,6 +192,25 @@ func Downloader(ctx context.Context, logger log.Logger) error {
 	return nil
 }
 
+// MirrorDownloader serves the downloader interface, downloading the snapshots from an HTTP(S)
+// mirror instead of BitTorrent
+func MirrorDownloader(ctx context.Context, dirs datadir.Dirs, logger log.Logger) error {
+	logger.Info("Run snapshot downloader", "addr", downloaderApiAddr, "datadir", dirs.DataDir, "mirror", httpMirror, "download.slots", torrentDownloadSlots)
+	mirrorServer, err := httpdownloader.New(ctx, httpMirror, dirs.Snap, torrentDownloadSlots, logger)
+	if err != nil {
+		return err
+	}
+
+	grpcServer, err := StartGrpc(mirrorServer, downloaderApiAddr, nil /* transportCredentials */, logger)
+	if err != nil {
+		return err
+	}
+	defer grpcServer.GracefulStop()
+
+	<-ctx.Done()
+	return nil
+}
+
 var printTorrentHashes = &cobra.Command{
 	Use:     "torrent_hashes",
 	Example: "go run ./cmd/downloader torrent_hashes --datadir <your_datadir>",
@@ -269,7 +294,7 @@ func removePieceCompletionStorage(snapDir string) {
 	_ = os.RemoveAll(filepath.Join(snapDir, ".torrent.db-wal"))
 }
 
-func StartGrpc(snServer *downloader.GrpcServer, addr string, creds *credentials.TransportCredentials, logger log.Logger) (*grpc.Server, error) {
+func StartGrpc(snServer proto_downloader.DownloaderServer, addr string, creds *credentials.TransportCredentials, logger log.Logger) (*grpc.Server, error) {
 	lis, err := net.Listen("tcp", addr)
 	if err != nil {
 		return nil, fmt.Errorf("could not create listener: %w, addr=%s", err, addr)
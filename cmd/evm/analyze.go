@@ -0,0 +1,59 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"strings"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/ledgerwatch/erigon/common"
+	"github.com/ledgerwatch/erigon/core/vm"
+)
+
+var AnalyzeDotFlag = cli.BoolFlag{
+	Name:  "dot",
+	Usage: "print the control flow graph in the DOT language instead of JSON",
+}
+
+var analyzeCommand = cli.Command{
+	Action:    analyzeCmd,
+	Name:      "analyze",
+	Usage:     "recovers the control flow graph and the function selectors of hex encoded evm bytecode",
+	ArgsUsage: "<file>",
+	Flags: []cli.Flag{
+		&AnalyzeDotFlag,
+	},
+}
+
+func analyzeCmd(ctx *cli.Context) error {
+	var in string
+	switch {
+	case len(ctx.Args().First()) > 0:
+		input, err := os.ReadFile(ctx.Args().First())
+		if err != nil {
+			return err
+		}
+		in = string(input)
+	case ctx.IsSet(InputFlag.Name):
+		in = ctx.String(InputFlag.Name)
+	default:
+		return errors.New("missing filename or --input value")
+	}
+
+	code := common.FromHex(strings.TrimSpace(in))
+	if len(code) == 0 {
+		return errors.New("empty or invalid hex")
+	}
+	analysis := vm.AnalyzeContract(context.Background(), code)
+	if ctx.Bool(AnalyzeDotFlag.Name) {
+		analysis.WriteDot(os.Stdout)
+		return nil
+	}
+	analysis.Dot = ""
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(analysis)
+}
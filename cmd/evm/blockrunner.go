@@ -0,0 +1,224 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ledgerwatch/log/v3"
+	"github.com/urfave/cli/v2"
+
+	"github.com/ledgerwatch/erigon/tests"
+)
+
+var (
+	BlockTestForkFlag = cli.StringFlag{
+		Name:  "fork",
+		Usage: "comma separated list of forks to run the tests of, e.g. Shanghai,Merge (default: all)",
+	}
+	BlockTestRunFlag = cli.StringFlag{
+		Name:  "run",
+		Usage: "regular expression the test names have to match",
+	}
+	BlockTestWorkersFlag = cli.IntFlag{
+		Name:  "workers",
+		Usage: "number of tests run in parallel",
+		Value: runtime.NumCPU(),
+	}
+)
+
+var blockTestCommand = cli.Command{
+	Action:    blockTestCmd,
+	Name:      "blocktest",
+	Usage:     "executes the given blockchain tests",
+	ArgsUsage: "<file|dir>...",
+	Flags: []cli.Flag{
+		&BlockTestForkFlag,
+		&BlockTestRunFlag,
+		&BlockTestWorkersFlag,
+	},
+}
+
+// BlocktestResult contains the outcome of a single blockchain test and, for a failed test,
+// the first block value which diverged from the test file if it could be told.
+type BlocktestResult struct {
+	Name       string                     `json:"name"`
+	File       string                     `json:"file"`
+	Fork       string                     `json:"fork"`
+	Pass       bool                       `json:"pass"`
+	Error      string                     `json:"error,omitempty"`
+	Divergence *tests.BlockTestDivergence `json:"divergence,omitempty"`
+	Duration   string                     `json:"duration"`
+}
+
+// BlocktestReport is the output of the blocktest command.
+type BlocktestReport struct {
+	Passed  int               `json:"passed"`
+	Failed  int               `json:"failed"`
+	Results []BlocktestResult `json:"results"`
+}
+
+type blockTestJob struct {
+	file string
+	name string
+	test tests.BlockTest
+}
+
+func blockTestCmd(ctx *cli.Context) error {
+	if ctx.Args().Len() == 0 {
+		return errors.New("path-to-test argument required")
+	}
+	// The stages are chatty, only let the errors through
+	log.Root().SetHandler(log.LvlFilterHandler(log.LvlError, log.StderrHandler))
+
+	var forks map[string]bool
+	if s := ctx.String(BlockTestForkFlag.Name); s != "" {
+		forks = make(map[string]bool)
+		for _, fork := range strings.Split(s, ",") {
+			forks[strings.TrimSpace(fork)] = true
+		}
+	}
+	var nameFilter *regexp.Regexp
+	if s := ctx.String(BlockTestRunFlag.Name); s != "" {
+		var err error
+		if nameFilter, err = regexp.Compile(s); err != nil {
+			return fmt.Errorf("invalid --%s: %w", BlockTestRunFlag.Name, err)
+		}
+	}
+
+	files, err := blockTestFiles(ctx.Args().Slice())
+	if err != nil {
+		return err
+	}
+	var jobs []blockTestJob
+	for _, file := range files {
+		src, err := os.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		var blockTests map[string]tests.BlockTest
+		if err = json.Unmarshal(src, &blockTests); err != nil {
+			return fmt.Errorf("%s: %w", file, err)
+		}
+		for name, test := range blockTests {
+			if forks != nil && !forks[test.Network()] {
+				continue
+			}
+			if nameFilter != nil && !nameFilter.MatchString(name) {
+				continue
+			}
+			jobs = append(jobs, blockTestJob{file: file, name: name, test: test})
+		}
+	}
+
+	results := runBlockTests(jobs, ctx.Int(BlockTestWorkersFlag.Name))
+	report := BlocktestReport{Results: results}
+	for _, result := range results {
+		if result.Pass {
+			report.Passed++
+		} else {
+			report.Failed++
+		}
+	}
+
+	out, _ := json.MarshalIndent(report, "", "  ")
+	fmt.Println(string(out))
+	if report.Failed > 0 {
+		return fmt.Errorf("%d of %d tests failed", report.Failed, len(results))
+	}
+	return nil
+}
+
+// blockTestFiles expands the directories among the given paths into the json files in them.
+func blockTestFiles(paths []string) ([]string, error) {
+	var files []string
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			files = append(files, path)
+			continue
+		}
+		if err := filepath.WalkDir(path, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if !d.IsDir() && filepath.Ext(path) == ".json" {
+				files = append(files, path)
+			}
+			return nil
+		}); err != nil {
+			return nil, err
+		}
+	}
+	return files, nil
+}
+
+// runBlockTests runs the tests on the given number of workers. The results are sorted by
+// file and test name.
+func runBlockTests(jobs []blockTestJob, workers int) []BlocktestResult {
+	if workers < 1 {
+		workers = 1
+	}
+	results := make([]BlocktestResult, len(jobs))
+
+	var wg sync.WaitGroup
+	next := make(chan int)
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for idx := range next {
+				results[idx] = runBlockTest(&jobs[idx])
+			}
+		}()
+	}
+	for idx := range jobs {
+		next <- idx
+	}
+	close(next)
+	wg.Wait()
+
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].File != results[j].File {
+			return results[i].File < results[j].File
+		}
+		return results[i].Name < results[j].Name
+	})
+	return results
+}
+
+func runBlockTest(job *blockTestJob) (result BlocktestResult) {
+	result = BlocktestResult{Name: job.name, File: job.file, Fork: job.test.Network()}
+	start := time.Now()
+	defer func() {
+		// a broken test file shouldn't take the other tests down with it
+		if r := recover(); r != nil {
+			result.Pass, result.Error = false, fmt.Sprintf("panic: %v", r)
+		}
+		result.Duration = time.Since(start).String()
+	}()
+
+	err := job.test.RunStandalone()
+	if err == nil {
+		result.Pass = true
+		return result
+	}
+	result.Error = err.Error()
+	var testErr *tests.BlockTestError
+	if errors.As(err, &testErr) {
+		result.Divergence = testErr.Divergence
+	}
+	return result
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/ledgerwatch/erigon/common"
+	"github.com/ledgerwatch/erigon/core/vm"
+)
+
+var eofParseCommand = cli.Command{
+	Action:    eofParseCmd,
+	Name:      "eofparse",
+	Aliases:   []string{"eof"},
+	Usage:     "parses and validates hex encoded EOF containers, one per line",
+	ArgsUsage: "<file>",
+}
+
+func eofParseCmd(ctx *cli.Context) error {
+	var in io.Reader
+	switch {
+	case len(ctx.Args().First()) > 0:
+		f, err := os.Open(ctx.Args().First())
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		in = f
+	case ctx.IsSet(InputFlag.Name):
+		in = strings.NewReader(ctx.String(InputFlag.Name))
+	default:
+		return errors.New("missing filename or --input value")
+	}
+
+	var invalid int
+	scanner := bufio.NewScanner(in)
+	scanner.Buffer(make([]byte, 1024*1024), 10*1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		c, err := parseEOF(line)
+		if err != nil {
+			invalid++
+			fmt.Printf("err: %v\n", err)
+			continue
+		}
+		fmt.Printf("OK %s\n", describeEOF(c))
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	if invalid > 0 {
+		return fmt.Errorf("%d invalid containers", invalid)
+	}
+	return nil
+}
+
+func parseEOF(s string) (*vm.Container, error) {
+	b := common.FromHex(s)
+	if len(b) == 0 {
+		return nil, errors.New("empty or invalid hex")
+	}
+	return vm.ParseAndValidateEOF(b)
+}
+
+// describeEOF lists the code sections of the container with their types, and the size of
+// the data section.
+func describeEOF(c *vm.Container) string {
+	var sb strings.Builder
+	for i, code := range c.Code {
+		ty := c.Types[i]
+		fmt.Fprintf(&sb, "code[%d]: %d bytes, inputs %d, outputs %d, max stack %d; ", i, len(code), ty.Input, ty.Output, ty.MaxStackHeight)
+	}
+	fmt.Fprintf(&sb, "data: %d bytes", len(c.Data))
+	return sb.String()
+}
@@ -26,9 +26,13 @@ import (
 	"github.com/ledgerwatch/erigon-lib/kv"
 	"github.com/ledgerwatch/erigon/common"
 	"github.com/ledgerwatch/erigon/common/math"
+	"github.com/ledgerwatch/erigon/consensus"
 	"github.com/ledgerwatch/erigon/consensus/ethash"
+	"github.com/ledgerwatch/erigon/consensus/misc"
+	"github.com/ledgerwatch/erigon/core"
 	"github.com/ledgerwatch/erigon/core/state"
 	"github.com/ledgerwatch/erigon/core/types"
+	"github.com/ledgerwatch/erigon/params"
 )
 
 type Prestate struct {
@@ -59,6 +63,13 @@ type stEnv struct {
 	UncleHash        libcommon.Hash                         `json:"uncleHash,omitempty"`
 	Withdrawals      []*types.Withdrawal                    `json:"withdrawals,omitempty"`
 	WithdrawalsHash  *libcommon.Hash                        `json:"withdrawalsRoot,omitempty"`
+
+	// EIP-4844: the excess blob gas is calculated from the parent if not given
+	ExcessBlobGas       *uint64 `json:"currentExcessBlobGas,omitempty"`
+	ParentExcessBlobGas *uint64 `json:"parentExcessBlobGas,omitempty"`
+	ParentBlobGasUsed   *uint64 `json:"parentBlobGasUsed,omitempty"`
+	// EIP-4788
+	ParentBeaconBlockRoot *libcommon.Hash `json:"parentBeaconBlockRoot,omitempty"`
 }
 
 type stEnvMarshaling struct {
@@ -71,6 +82,27 @@ type stEnvMarshaling struct {
 	Timestamp        math.HexOrDecimal64
 	ParentTimestamp  math.HexOrDecimal64
 	BaseFee          *math.HexOrDecimal256
+
+	ExcessBlobGas       *math.HexOrDecimal64
+	ParentExcessBlobGas *math.HexOrDecimal64
+	ParentBlobGasUsed   *math.HexOrDecimal64
+}
+
+// ExecutionResult is the result of the transition: the block execution result along with
+// the withdrawals root and the blob gas accounting, which the block execution leaves out.
+type ExecutionResult struct {
+	*core.EphemeralExecResult
+	WithdrawalsRoot      *libcommon.Hash      `json:"withdrawalsRoot,omitempty"`
+	CurrentExcessBlobGas *math.HexOrDecimal64 `json:"currentExcessBlobGas,omitempty"`
+	BlobGasUsed          *math.HexOrDecimal64 `json:"blobGasUsed,omitempty"`
+	TxBlobGas            []txBlobGas          `json:"txBlobGas,omitempty"`
+}
+
+// txBlobGas is the blob gas paid for by an included blob transaction.
+type txBlobGas struct {
+	TxHash       libcommon.Hash        `json:"transactionHash"`
+	BlobGasUsed  math.HexOrDecimal64   `json:"blobGasUsed"`
+	BlobGasPrice *math.HexOrDecimal256 `json:"blobGasPrice"`
 }
 
 func MakePreState(chainRules *chain.Rules, tx kv.RwTx, accounts types.GenesisAlloc) (*state.PlainStateReader, *state.PlainStateWriter) {
@@ -124,3 +156,99 @@ func calcDifficulty(config *chain.Config, number, currentTime, parentTime uint64
 	}
 	return ethash.CalcDifficulty(config, currentTime, parent.Time, parent.Difficulty, number-1, parent.UncleHash)
 }
+
+// calcExcessBlobGas is based on misc.CalcExcessDataGas, which adds the blobs of a block on top
+// of the excess of its parent.
+func calcExcessBlobGas(parentExcessBlobGas, parentBlobGasUsed *uint64) uint64 {
+	parentExcess := new(big.Int)
+	if parentExcessBlobGas != nil {
+		parentExcess.SetUint64(*parentExcessBlobGas)
+	}
+	var parentBlobs int
+	if parentBlobGasUsed != nil {
+		parentBlobs = int(*parentBlobGasUsed / params.DataGasPerBlob)
+	}
+	return misc.CalcExcessDataGas(parentExcess, parentBlobs).Uint64()
+}
+
+// blobGasUsage sums up the blob gas of the included transactions.
+func blobGasUsage(txs types.Transactions, receipts types.Receipts, excessBlobGas uint64) (uint64, []txBlobGas, error) {
+	price, err := misc.GetDataGasPrice(new(big.Int).SetUint64(excessBlobGas))
+	if err != nil {
+		return 0, nil, err
+	}
+	byHash := make(map[libcommon.Hash]types.Transaction, len(txs))
+	for _, tx := range txs {
+		byHash[tx.Hash()] = tx
+	}
+
+	var total uint64
+	var perTx []txBlobGas
+	for _, receipt := range receipts {
+		tx, ok := byHash[receipt.TxHash]
+		if !ok || len(tx.GetDataHashes()) == 0 {
+			continue
+		}
+		used := misc.GetDataGasUsed(len(tx.GetDataHashes()))
+		total += used
+		perTx = append(perTx, txBlobGas{
+			TxHash:       receipt.TxHash,
+			BlobGasUsed:  math.HexOrDecimal64(used),
+			BlobGasPrice: (*math.HexOrDecimal256)(price.ToBig()),
+		})
+	}
+	return total, perTx, nil
+}
+
+// processBeaconBlockRoot stores the parent beacon block root in the EIP-4788 contract, if it's
+// deployed. The system call happens before the transactions of the block, so it's applied to the
+// prestate right away.
+func processBeaconBlockRoot(chainConfig *chain.Config, tx kv.RwTx, header *types.Header, engine consensus.EngineReader, root libcommon.Hash) error {
+	ibs := state.New(state.NewPlainStateReader(tx))
+	if ibs.GetCodeSize(params.BeaconRootsAddress) == 0 {
+		return nil
+	}
+	if _, err := core.SysCallContract(params.BeaconRootsAddress, root[:], chainConfig, ibs, header, engine, false /* constCall */, header.ExcessDataGas); err != nil {
+		return err
+	}
+	rules := chainConfig.Rules(header.Number.Uint64(), header.Time)
+	if err := ibs.FinalizeTx(rules, state.NewNoopWriter()); err != nil {
+		return err
+	}
+	return ibs.CommitBlock(rules, state.NewPlainStateWriter(tx, tx, header.Number.Uint64()))
+}
+
+// parentHeaderReader gives the engine access to the parent of the block built by the transition,
+// which is all it needs to know about the chain. Its excess data gas is the one the blob gas of
+// the block is priced with.
+type parentHeaderReader struct {
+	config     *chain.Config
+	parentHash libcommon.Hash
+	parent     *types.Header
+}
+
+func (r *parentHeaderReader) Config() *chain.Config        { return r.config }
+func (r *parentHeaderReader) CurrentHeader() *types.Header { return r.parent }
+
+func (r *parentHeaderReader) GetHeader(hash libcommon.Hash, number uint64) *types.Header {
+	if hash != r.parentHash || number != r.parent.Number.Uint64() {
+		return nil
+	}
+	return r.parent
+}
+
+func (r *parentHeaderReader) GetHeaderByNumber(number uint64) *types.Header {
+	if number != r.parent.Number.Uint64() {
+		return nil
+	}
+	return r.parent
+}
+
+func (r *parentHeaderReader) GetHeaderByHash(hash libcommon.Hash) *types.Header {
+	if hash != r.parentHash {
+		return nil
+	}
+	return r.parent
+}
+
+func (r *parentHeaderReader) GetTd(libcommon.Hash, uint64) *big.Int { return nil }
@@ -19,20 +19,24 @@ var _ = (*stEnvMarshaling)(nil)
 // MarshalJSON marshals as JSON.
 func (s stEnv) MarshalJSON() ([]byte, error) {
 	type stEnv struct {
-		Coinbase         common.UnprefixedAddress               `json:"currentCoinbase"   gencodec:"required"`
-		Difficulty       *math.HexOrDecimal256                  `json:"currentDifficulty"`
-		Random           *math.HexOrDecimal256                  `json:"currentRandom"`
-		ParentDifficulty *math.HexOrDecimal256                  `json:"parentDifficulty"`
-		GasLimit         math.HexOrDecimal64                    `json:"currentGasLimit"   gencodec:"required"`
-		Number           math.HexOrDecimal64                    `json:"currentNumber"     gencodec:"required"`
-		Timestamp        math.HexOrDecimal64                    `json:"currentTimestamp"  gencodec:"required"`
-		ParentTimestamp  math.HexOrDecimal64                    `json:"parentTimestamp,omitempty"`
-		BlockHashes      map[math.HexOrDecimal64]libcommon.Hash `json:"blockHashes,omitempty"`
-		Ommers           []ommer                                `json:"ommers,omitempty"`
-		BaseFee          *math.HexOrDecimal256                  `json:"currentBaseFee,omitempty"`
-		ParentUncleHash  libcommon.Hash                         `json:"parentUncleHash"`
-		UncleHash        libcommon.Hash                         `json:"uncleHash,omitempty"`
-		Withdrawals      []*types.Withdrawal                    `json:"withdrawals,omitempty"`
+		Coinbase              common.UnprefixedAddress               `json:"currentCoinbase"   gencodec:"required"`
+		Difficulty            *math.HexOrDecimal256                  `json:"currentDifficulty"`
+		Random                *math.HexOrDecimal256                  `json:"currentRandom"`
+		ParentDifficulty      *math.HexOrDecimal256                  `json:"parentDifficulty"`
+		GasLimit              math.HexOrDecimal64                    `json:"currentGasLimit"   gencodec:"required"`
+		Number                math.HexOrDecimal64                    `json:"currentNumber"     gencodec:"required"`
+		Timestamp             math.HexOrDecimal64                    `json:"currentTimestamp"  gencodec:"required"`
+		ParentTimestamp       math.HexOrDecimal64                    `json:"parentTimestamp,omitempty"`
+		BlockHashes           map[math.HexOrDecimal64]libcommon.Hash `json:"blockHashes,omitempty"`
+		Ommers                []ommer                                `json:"ommers,omitempty"`
+		BaseFee               *math.HexOrDecimal256                  `json:"currentBaseFee,omitempty"`
+		ParentUncleHash       libcommon.Hash                         `json:"parentUncleHash"`
+		UncleHash             libcommon.Hash                         `json:"uncleHash,omitempty"`
+		Withdrawals           []*types.Withdrawal                    `json:"withdrawals,omitempty"`
+		ExcessBlobGas         *math.HexOrDecimal64                   `json:"currentExcessBlobGas,omitempty"`
+		ParentExcessBlobGas   *math.HexOrDecimal64                   `json:"parentExcessBlobGas,omitempty"`
+		ParentBlobGasUsed     *math.HexOrDecimal64                   `json:"parentBlobGasUsed,omitempty"`
+		ParentBeaconBlockRoot *libcommon.Hash                        `json:"parentBeaconBlockRoot,omitempty"`
 	}
 	var enc stEnv
 	enc.Coinbase = common.UnprefixedAddress(s.Coinbase)
@@ -49,26 +53,34 @@ func (s stEnv) MarshalJSON() ([]byte, error) {
 	enc.ParentUncleHash = s.ParentUncleHash
 	enc.UncleHash = s.UncleHash
 	enc.Withdrawals = s.Withdrawals
+	enc.ExcessBlobGas = (*math.HexOrDecimal64)(s.ExcessBlobGas)
+	enc.ParentExcessBlobGas = (*math.HexOrDecimal64)(s.ParentExcessBlobGas)
+	enc.ParentBlobGasUsed = (*math.HexOrDecimal64)(s.ParentBlobGasUsed)
+	enc.ParentBeaconBlockRoot = s.ParentBeaconBlockRoot
 	return json.Marshal(&enc)
 }
 
 // UnmarshalJSON unmarshals from JSON.
 func (s *stEnv) UnmarshalJSON(input []byte) error {
 	type stEnv struct {
-		Coinbase         *common.UnprefixedAddress              `json:"currentCoinbase"   gencodec:"required"`
-		Difficulty       *math.HexOrDecimal256                  `json:"currentDifficulty"`
-		Random           *math.HexOrDecimal256                  `json:"currentRandom"`
-		ParentDifficulty *math.HexOrDecimal256                  `json:"parentDifficulty"`
-		GasLimit         *math.HexOrDecimal64                   `json:"currentGasLimit"   gencodec:"required"`
-		Number           *math.HexOrDecimal64                   `json:"currentNumber"     gencodec:"required"`
-		Timestamp        *math.HexOrDecimal64                   `json:"currentTimestamp"  gencodec:"required"`
-		ParentTimestamp  *math.HexOrDecimal64                   `json:"parentTimestamp,omitempty"`
-		BlockHashes      map[math.HexOrDecimal64]libcommon.Hash `json:"blockHashes,omitempty"`
-		Ommers           []ommer                                `json:"ommers,omitempty"`
-		BaseFee          *math.HexOrDecimal256                  `json:"currentBaseFee,omitempty"`
-		ParentUncleHash  *libcommon.Hash                        `json:"parentUncleHash"`
-		UncleHash        libcommon.Hash                         `json:"uncleHash,omitempty"`
-		Withdrawals      []*types.Withdrawal                    `json:"withdrawals,omitempty"`
+		Coinbase              *common.UnprefixedAddress              `json:"currentCoinbase"   gencodec:"required"`
+		Difficulty            *math.HexOrDecimal256                  `json:"currentDifficulty"`
+		Random                *math.HexOrDecimal256                  `json:"currentRandom"`
+		ParentDifficulty      *math.HexOrDecimal256                  `json:"parentDifficulty"`
+		GasLimit              *math.HexOrDecimal64                   `json:"currentGasLimit"   gencodec:"required"`
+		Number                *math.HexOrDecimal64                   `json:"currentNumber"     gencodec:"required"`
+		Timestamp             *math.HexOrDecimal64                   `json:"currentTimestamp"  gencodec:"required"`
+		ParentTimestamp       *math.HexOrDecimal64                   `json:"parentTimestamp,omitempty"`
+		BlockHashes           map[math.HexOrDecimal64]libcommon.Hash `json:"blockHashes,omitempty"`
+		Ommers                []ommer                                `json:"ommers,omitempty"`
+		BaseFee               *math.HexOrDecimal256                  `json:"currentBaseFee,omitempty"`
+		ParentUncleHash       *libcommon.Hash                        `json:"parentUncleHash"`
+		UncleHash             libcommon.Hash                         `json:"uncleHash,omitempty"`
+		Withdrawals           []*types.Withdrawal                    `json:"withdrawals,omitempty"`
+		ExcessBlobGas         *math.HexOrDecimal64                   `json:"currentExcessBlobGas,omitempty"`
+		ParentExcessBlobGas   *math.HexOrDecimal64                   `json:"parentExcessBlobGas,omitempty"`
+		ParentBlobGasUsed     *math.HexOrDecimal64                   `json:"parentBlobGasUsed,omitempty"`
+		ParentBeaconBlockRoot *libcommon.Hash                        `json:"parentBeaconBlockRoot,omitempty"`
 	}
 	var dec stEnv
 	if err := json.Unmarshal(input, &dec); err != nil {
@@ -118,6 +130,18 @@ func (s *stEnv) UnmarshalJSON(input []byte) error {
 	if dec.Withdrawals != nil {
 		s.Withdrawals = dec.Withdrawals
 	}
+	if dec.ExcessBlobGas != nil {
+		s.ExcessBlobGas = (*uint64)(dec.ExcessBlobGas)
+	}
+	if dec.ParentExcessBlobGas != nil {
+		s.ParentExcessBlobGas = (*uint64)(dec.ParentExcessBlobGas)
+	}
+	if dec.ParentBlobGasUsed != nil {
+		s.ParentBlobGasUsed = (*uint64)(dec.ParentBlobGasUsed)
+	}
+	if dec.ParentBeaconBlockRoot != nil {
+		s.ParentBeaconBlockRoot = dec.ParentBeaconBlockRoot
+	}
 
 	return nil
 }
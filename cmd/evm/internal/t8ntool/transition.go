@@ -42,6 +42,7 @@ import (
 	"github.com/ledgerwatch/erigon/cmd/rpcdaemon/commands"
 	"github.com/ledgerwatch/erigon/common"
 	"github.com/ledgerwatch/erigon/common/math"
+	"github.com/ledgerwatch/erigon/consensus"
 	"github.com/ledgerwatch/erigon/consensus/ethash"
 	"github.com/ledgerwatch/erigon/consensus/merge"
 	"github.com/ledgerwatch/erigon/core"
@@ -221,7 +222,7 @@ func Main(ctx *cli.Context) error {
 		txsWithKeys = inputData.Txs
 	}
 	// We may have to sign the transactions.
-	signer := types.MakeSigner(chainConfig, prestate.Env.Number)
+	signer := types.MakeSigner(chainConfig, prestate.Env.Number, prestate.Env.Timestamp)
 
 	if txs, err = signUnsignedTransactions(txsWithKeys, *signer); err != nil {
 		return NewError(ErrorJson, fmt.Errorf("failed signing transactions: %v", err))
@@ -241,6 +242,20 @@ func Main(ctx *cli.Context) error {
 		return NewError(ErrorVMConfig, errors.New("Shanghai config but missing 'withdrawals' in env section"))
 	}
 
+	cancun := chainConfig.IsCancun(prestate.Env.Timestamp)
+	if cancun {
+		if prestate.Env.ParentBeaconBlockRoot == nil {
+			return NewError(ErrorVMConfig, errors.New("Cancun config but missing 'parentBeaconBlockRoot' in env section"))
+		}
+		if prestate.Env.ExcessBlobGas == nil {
+			excessBlobGas := calcExcessBlobGas(prestate.Env.ParentExcessBlobGas, prestate.Env.ParentBlobGasUsed)
+			prestate.Env.ExcessBlobGas = &excessBlobGas
+		}
+	} else {
+		prestate.Env.ExcessBlobGas = nil
+		prestate.Env.ParentBeaconBlockRoot = nil
+	}
+
 	isMerged := chainConfig.TerminalTotalDifficulty != nil && chainConfig.TerminalTotalDifficulty.BitLen() == 0
 	env := prestate.Env
 	if isMerged {
@@ -307,7 +322,28 @@ func Main(ctx *cli.Context) error {
 	// redirects to the ethash engine based on the block number
 	engine := merge.New(&ethash.FakeEthash{})
 
-	result, err := core.ExecuteBlockEphemerally(chainConfig, &vmConfig, getHash, engine, block, reader, writer, nil, getTracer)
+	var chainReader consensus.ChainHeaderReader
+	if cancun {
+		// blob gas is priced by the excess data gas of the parent header, which includes the blobs of the parent
+		parentExcessDataGas := *prestate.Env.ExcessBlobGas
+		if prestate.Env.ParentExcessBlobGas != nil || prestate.Env.ParentBlobGasUsed != nil {
+			parentExcessDataGas = calcExcessBlobGas(prestate.Env.ParentExcessBlobGas, prestate.Env.ParentBlobGasUsed)
+		}
+		chainReader = &parentHeaderReader{
+			config:     chainConfig,
+			parentHash: block.ParentHash(),
+			parent: &types.Header{
+				Number:        new(big.Int).Sub(header.Number, big.NewInt(1)),
+				Time:          prestate.Env.ParentTimestamp,
+				ExcessDataGas: new(big.Int).SetUint64(parentExcessDataGas),
+			},
+		}
+		if err = processBeaconBlockRoot(chainConfig, tx, block.Header(), engine, *prestate.Env.ParentBeaconBlockRoot); err != nil {
+			return NewError(ErrorEVM, fmt.Errorf("failed to process parent beacon block root: %v", err))
+		}
+	}
+
+	result, err := core.ExecuteBlockEphemerally(chainConfig, &vmConfig, getHash, engine, block, reader, writer, chainReader, getTracer)
 
 	if hashError != nil {
 		return NewError(ErrorMissingBlockhash, fmt.Errorf("blockhash error: %v", err))
@@ -324,6 +360,20 @@ func Main(ctx *cli.Context) error {
 	}
 	result.StateRoot = *root
 
+	execResult := &ExecutionResult{EphemeralExecResult: result}
+	if chainConfig.IsShanghai(prestate.Env.Timestamp) {
+		execResult.WithdrawalsRoot = block.Header().WithdrawalsHash
+	}
+	if cancun {
+		blobGasUsed, perTx, err := blobGasUsage(txs, result.Receipts, *prestate.Env.ExcessBlobGas)
+		if err != nil {
+			return NewError(ErrorEVM, err)
+		}
+		execResult.CurrentExcessBlobGas = (*math.HexOrDecimal64)(prestate.Env.ExcessBlobGas)
+		execResult.BlobGasUsed = (*math.HexOrDecimal64)(&blobGasUsed)
+		execResult.TxBlobGas = perTx
+	}
+
 	// Dump the execution result
 	body, _ := rlp.EncodeToBytes(txs)
 	collector := make(Alloc)
@@ -334,7 +384,7 @@ func Main(ctx *cli.Context) error {
 	}
 	dumper := state.NewDumper(tx, prestate.Env.Number, historyV3)
 	dumper.DumpToCollector(collector, false, false, libcommon.Address{}, 0)
-	return dispatchOutput(ctx, baseDir, result, collector, body)
+	return dispatchOutput(ctx, baseDir, execResult, collector, body)
 }
 
 // txWithKey is a helper-struct, to allow us to use the types.Transaction along with
@@ -368,6 +418,14 @@ func (t *txWithKey) UnmarshalJSON(input []byte) error {
 	if err := json.Unmarshal(input, &txJson); err != nil {
 		return err
 	}
+	if txJson.Type == types.BlobTxType {
+		tx, err := types.UnmarshalBlobTxJSON(input)
+		if err != nil {
+			return err
+		}
+		t.tx = tx
+		return nil
+	}
 
 	// assemble transaction
 	tx, err := getTransaction(txJson)
@@ -540,7 +598,7 @@ func saveFile(baseDir, filename string, data interface{}) error {
 
 // dispatchOutput writes the output data to either stderr or stdout, or to the specified
 // files
-func dispatchOutput(ctx *cli.Context, baseDir string, result *core.EphemeralExecResult, alloc Alloc, body hexutility.Bytes) error {
+func dispatchOutput(ctx *cli.Context, baseDir string, result *ExecutionResult, alloc Alloc, body hexutility.Bytes) error {
 	stdOutObject := make(map[string]interface{})
 	stdErrObject := make(map[string]interface{})
 	dispatch := func(baseDir, fName, name string, obj interface{}) error {
@@ -596,6 +654,9 @@ func NewHeader(env stEnv) *types.Header {
 
 	header.UncleHash = env.UncleHash
 	header.WithdrawalsHash = env.WithdrawalsHash
+	if env.ExcessBlobGas != nil {
+		header.ExcessDataGas = new(big.Int).SetUint64(*env.ExcessBlobGas)
+	}
 
 	return &header
 }
@@ -46,6 +46,10 @@ var (
 		Name:  "cpuprofile",
 		Usage: "creates a CPU profile at the given path",
 	}
+	ProfileFlag = cli.StringFlag{
+		Name:  "profile",
+		Usage: "writes an opcode level gas and time profile of the execution to <path>.pb.gz (pprof) and <path>.folded (collapsed stacks)",
+	}
 	StatDumpFlag = cli.BoolFlag{
 		Name:  "statdump",
 		Usage: "displays stack and heap memory information",
@@ -170,6 +174,7 @@ func init() {
 		&InputFileFlag,
 		&MemProfileFlag,
 		&CPUProfileFlag,
+		&ProfileFlag,
 		&StatDumpFlag,
 		&GenesisFlag,
 		&MachineFlag,
@@ -181,8 +186,11 @@ func init() {
 		&DisableReturnDataFlag,
 	}
 	app.Commands = []*cli.Command{
+		&analyzeCommand,
+		&blockTestCommand,
 		&compileCommand,
 		&disasmCommand,
+		&eofParseCommand,
 		&runCommand,
 		&stateTestCommand,
 		&stateTransitionCommand,
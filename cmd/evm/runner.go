@@ -48,7 +48,9 @@ import (
 	"github.com/ledgerwatch/erigon/core/state"
 	"github.com/ledgerwatch/erigon/core/vm"
 	"github.com/ledgerwatch/erigon/core/vm/runtime"
+	"github.com/ledgerwatch/erigon/eth/tracers"
 	"github.com/ledgerwatch/erigon/eth/tracers/logger"
+	_ "github.com/ledgerwatch/erigon/eth/tracers/native"
 	"github.com/ledgerwatch/erigon/params"
 )
 
@@ -149,6 +151,16 @@ func runCmd(ctx *cli.Context) error {
 	} else {
 		debugLogger = logger.NewStructLogger(logconfig)
 	}
+	var profiler tracers.Tracer
+	if ctx.String(ProfileFlag.Name) != "" {
+		if tracer != nil {
+			return fmt.Errorf("--%s can't be combined with --%s or --%s", ProfileFlag.Name, MachineFlag.Name, DebugFlag.Name)
+		}
+		var err error
+		if profiler, err = tracers.New("profilerTracer", new(tracers.Context), nil); err != nil {
+			return err
+		}
+	}
 	db := memdb.New("")
 	defer db.Close()
 	if ctx.String(GenesisFlag.Name) != "" {
@@ -240,6 +252,10 @@ func runCmd(ctx *cli.Context) error {
 			Debug:  ctx.Bool(DebugFlag.Name) || ctx.Bool(MachineFlag.Name),
 		},
 	}
+	if profiler != nil {
+		runtimeConfig.EVMConfig.Tracer = profiler
+		runtimeConfig.EVMConfig.Debug = true
+	}
 
 	if cpuProfilePath := ctx.String(CPUProfileFlag.Name); cpuProfilePath != "" {
 		f, err := os.Create(cpuProfilePath)
@@ -307,6 +323,13 @@ func runCmd(ctx *cli.Context) error {
 		fmt.Println(string(state.NewDumper(tx, 0, historyV3).DefaultDump()))
 	}
 
+	if profiler != nil {
+		if err := writeExecutionProfile(profiler, ctx.String(ProfileFlag.Name)); err != nil {
+			fmt.Println("could not write execution profile: ", err)
+			os.Exit(1)
+		}
+	}
+
 	if memProfilePath := ctx.String(MemProfileFlag.Name); memProfilePath != "" {
 		f, err := os.Create(memProfilePath)
 		if err != nil {
@@ -357,3 +380,23 @@ allocated bytes: %d
 
 	return nil
 }
+
+// writeExecutionProfile writes the result of the profiler tracer to <path>.pb.gz, which can
+// be opened with `go tool pprof`, and to <path>.folded for the flamegraph tools.
+func writeExecutionProfile(profiler tracers.Tracer, path string) error {
+	res, err := profiler.GetResult()
+	if err != nil {
+		return err
+	}
+	var profile struct {
+		Collapsed string           `json:"collapsed"`
+		Pprof     hexutility.Bytes `json:"pprof"`
+	}
+	if err = json.Unmarshal(res, &profile); err != nil {
+		return err
+	}
+	if err = os.WriteFile(path+".pb.gz", profile.Pprof, 0644); err != nil {
+		return err
+	}
+	return os.WriteFile(path+".folded", []byte(profile.Collapsed+"\n"), 0644)
+}
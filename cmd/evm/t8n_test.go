@@ -197,6 +197,21 @@ func TestT8n(t *testing.T) {
 			expOut: "exp.json",
 			output: t8nOutput{alloc: true, result: true},
 		},
+		{ // eip-4788: missing parent beacon block root
+			base: "./testdata/28",
+			input: t8nInput{
+				"alloc.json", "txs.json", "env.json", "Cancun",
+			},
+			expExitCode: 3,
+		},
+		{ // eip-4844: excess blob gas from the parent
+			base: "./testdata/29",
+			input: t8nInput{
+				"alloc.json", "txs.json", "env.json", "Cancun",
+			},
+			expOut: "exp.json",
+			output: t8nOutput{alloc: true, result: true},
+		},
 	} {
 
 		args := []string{"t8n"}
@@ -0,0 +1,128 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/ledgerwatch/log/v3"
+	"github.com/spf13/cobra"
+
+	"github.com/ledgerwatch/erigon/eth/integrity"
+	"github.com/ledgerwatch/erigon/turbo/debug"
+)
+
+var (
+	checkDBFrom   uint64
+	checkDBTo     uint64
+	checkDBChecks []string
+	checkDBJson   bool
+)
+
+var cmdCheckDB = &cobra.Command{
+	Use:   "check_db",
+	Short: "Check the invariants across the tables of the chain db, and suggest the stage unwinds repairing the problems",
+	Long: `Checks (all by default):
+  canonical_headers - canonical hashes are the ones of the headers of their number, chained by parent hashes
+  txs_begin_end     - canonical bodies have their system txs, their tx ranges don't overlap and their txs are in the db
+  tx_lookup         - txs of canonical bodies are in TxLookup at their block
+  history_index     - keys of the change sets are in the history indices
+  state_root        - IntermediateHashes reproduce the state root of the header of the last executed block
+Blocks in snapshots are not checked. Exits with code 1 if a problem is found.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		var logger log.Logger
+		var err error
+		if logger, err = debug.SetupCobra(cmd, "integration"); err != nil {
+			logger.Error("Setting up", "error", err)
+			return
+		}
+		db, err := openDB(dbCfg(kv.ChainDB, chaindata).Readonly(), false, logger)
+		if err != nil {
+			logger.Error("Opening DB", "error", err)
+			return
+		}
+		found, err := checkDB(db, cmd.Context(), logger)
+		db.Close()
+		if err != nil {
+			if !errors.Is(err, context.Canceled) {
+				logger.Error(err.Error())
+			}
+			return
+		}
+		if found {
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	withConfig(cmdCheckDB)
+	withDataDir(cmdCheckDB)
+	withChain(cmdCheckDB)
+	cmdCheckDB.Flags().Uint64Var(&checkDBFrom, "from", 0, "first block to check")
+	cmdCheckDB.Flags().Uint64Var(&checkDBTo, "to", 0, "last block to check, the progress of the stages by default")
+	cmdCheckDB.Flags().StringSliceVar(&checkDBChecks, "checks", nil, "comma separated checks to run, all by default: "+strings.Join(integrity.AllDBChecks, ","))
+	cmdCheckDB.Flags().BoolVar(&checkDBJson, "json", false, "print the report as json")
+	rootCmd.AddCommand(cmdCheckDB)
+}
+
+// checkDB prints the report of integrity.CheckDB, it returns whether a problem was found.
+func checkDB(db kv.RoDB, ctx context.Context, logger log.Logger) (found bool, err error) {
+	cfg := integrity.CheckDBCfg{
+		Checks:      checkDBChecks,
+		From:        checkDBFrom,
+		To:          checkDBTo,
+		CommandArgs: fmt.Sprintf("--datadir=%s --chain=%s", datadirCli, chain),
+	}
+	var results []integrity.CheckResult
+	if err := db.View(ctx, func(tx kv.Tx) error {
+		results, err = integrity.CheckDB(ctx, tx, cfg, logger)
+		return err
+	}); err != nil {
+		return false, err
+	}
+	for _, r := range results {
+		found = found || len(r.Problems) > 0
+	}
+
+	if checkDBJson {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return found, encoder.Encode(results)
+	}
+	w := new(tabwriter.Writer)
+	w.Init(os.Stdout, 8, 8, 1, ' ', 0)
+	fmt.Fprint(w, "check\tblocks\tproblems\ttook\t\n")
+	for _, r := range results {
+		problems := fmt.Sprintf("%d", len(r.Problems))
+		if r.Truncated {
+			problems += "+"
+		}
+		if r.Skipped != "" {
+			problems = "skipped: " + r.Skipped
+		}
+		fmt.Fprintf(w, "%s\t%d-%d\t%s\t%s\t\n", r.Name, r.From, r.To, problems, r.Duration)
+	}
+	if err := w.Flush(); err != nil {
+		return found, err
+	}
+	for _, r := range results {
+		for _, p := range r.Problems {
+			fmt.Printf("\n[%s] block %d, %s: %s\n", r.Name, p.Block, p.Table, p.Message)
+			if p.UnwindTo == 0 {
+				fmt.Printf("  repair: reset %s and the stages depending on it, then restart erigon:\n", p.Stage)
+			} else {
+				fmt.Printf("  repair: unwind %s and the stages depending on it to block %d, then restart erigon:\n", p.Stage, p.UnwindTo)
+			}
+			for _, step := range p.Repair {
+				fmt.Printf("    %s\n", step.Command)
+			}
+		}
+	}
+	return found, nil
+}
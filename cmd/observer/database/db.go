@@ -52,6 +52,7 @@ type DB interface {
 	TakeHandshakeCandidates(ctx context.Context, limit uint) ([]NodeID, error)
 
 	UpdateForkCompatibility(ctx context.Context, id NodeID, isCompatFork bool) error
+	UpdateENR(ctx context.Context, id NodeID, enr string) error
 
 	UpdateNeighborBucketKeys(ctx context.Context, id NodeID, keys []string) error
 	FindNeighborBucketKeys(ctx context.Context, id NodeID) ([]string, error)
@@ -74,4 +75,6 @@ type DB interface {
 	CountClientsWithNetworkID(ctx context.Context, clientIDPrefix string, maxPingTries uint) (uint, error)
 	CountClientsWithHandshakeTransientError(ctx context.Context, clientIDPrefix string, maxPingTries uint) (uint, error)
 	EnumerateClientIDs(ctx context.Context, maxPingTries uint, networkID uint, enumFunc func(clientID *string)) error
+	// EnumerateENRs lists the signed node records of live nodes with a compatible fork ID.
+	EnumerateENRs(ctx context.Context, maxPingTries uint, networkID uint, enumFunc func(enr string)) error
 }
@@ -198,6 +198,13 @@ func (db DBRetrier) UpdateForkCompatibility(ctx context.Context, id NodeID, isCo
 	return err
 }
 
+func (db DBRetrier) UpdateENR(ctx context.Context, id NodeID, enr string) error {
+	_, err := db.retry(ctx, "UpdateENR", func(ctx context.Context) (interface{}, error) {
+		return nil, db.db.UpdateENR(ctx, id, enr)
+	})
+	return err
+}
+
 func (db DBRetrier) UpdateNeighborBucketKeys(ctx context.Context, id NodeID, keys []string) error {
 	_, err := db.retry(ctx, "UpdateNeighborBucketKeys", func(ctx context.Context) (interface{}, error) {
 		return nil, db.db.UpdateNeighborBucketKeys(ctx, id, keys)
@@ -38,6 +38,9 @@ CREATE TABLE IF NOT EXISTS nodes (
     compat_fork INTEGER,
     compat_fork_updated INTEGER,
 
+    enr TEXT,
+    enr_updated INTEGER,
+
     client_id TEXT,
     network_id INTEGER,
     eth_version INTEGER,
@@ -195,6 +198,10 @@ UPDATE nodes SET handshake_retry_time = ? WHERE id IN (123)
 
 	sqlUpdateForkCompatibility = `
 UPDATE nodes SET compat_fork = ?, compat_fork_updated = ? WHERE id = ?
+`
+
+	sqlUpdateENR = `
+UPDATE nodes SET enr = ?, enr_updated = ? WHERE id = ?
 `
 
 	sqlUpdateNeighborBucketKeys = `
@@ -279,6 +286,14 @@ WHERE (ping_try < ?)
 	AND (client_id LIKE ?)
 `
 
+	sqlEnumerateENRs = `
+SELECT enr FROM nodes
+WHERE (ping_try < ?)
+    AND (network_id = ?)
+    AND (compat_fork == TRUE)
+    AND (enr IS NOT NULL)
+`
+
 	sqlEnumerateClientIDs = `
 SELECT client_id FROM nodes
 WHERE (ping_try < ?)
@@ -298,10 +313,46 @@ func NewDBSQLite(filePath string) (*DBSQLite, error) {
 		return nil, fmt.Errorf("failed to create the DB schema: %w", err)
 	}
 
+	err = migrateSchema(db)
+	if err != nil {
+		return nil, fmt.Errorf("failed to migrate the DB schema: %w", err)
+	}
+
 	instance := DBSQLite{db}
 	return &instance, nil
 }
 
+// migrateSchema adds the columns introduced after the initial schema to existing databases.
+func migrateSchema(db *sql.DB) error {
+	columns := map[string]string{
+		"enr":         "TEXT",
+		"enr_updated": "INTEGER",
+	}
+	rows, err := db.Query("SELECT name FROM pragma_table_info('nodes')")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return err
+		}
+		delete(columns, name)
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	for name, columnType := range columns {
+		if _, err := db.Exec(fmt.Sprintf("ALTER TABLE nodes ADD COLUMN %s %s", name, columnType)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (db *DBSQLite) Close() error {
 	return db.db.Close()
 }
@@ -685,6 +736,16 @@ func (db *DBSQLite) UpdateForkCompatibility(ctx context.Context, id NodeID, isCo
 	return nil
 }
 
+func (db *DBSQLite) UpdateENR(ctx context.Context, id NodeID, enr string) error {
+	updated := time.Now().Unix()
+
+	_, err := db.db.ExecContext(ctx, sqlUpdateENR, enr, updated, id)
+	if err != nil {
+		return fmt.Errorf("UpdateENR failed to update a node: %w", err)
+	}
+	return nil
+}
+
 func (db *DBSQLite) UpdateNeighborBucketKeys(ctx context.Context, id NodeID, keys []string) error {
 	keysStr := strings.Join(keys, ",")
 
@@ -920,6 +981,35 @@ func (db *DBSQLite) EnumerateClientIDs(
 	return nil
 }
 
+func (db *DBSQLite) EnumerateENRs(
+	ctx context.Context,
+	maxPingTries uint,
+	networkID uint,
+	enumFunc func(enr string),
+) error {
+	cursor, err := db.db.QueryContext(ctx, sqlEnumerateENRs, maxPingTries, networkID)
+	if err != nil {
+		return fmt.Errorf("EnumerateENRs failed to query: %w", err)
+	}
+	defer func() {
+		_ = cursor.Close()
+	}()
+
+	for cursor.Next() {
+		var enr string
+		err := cursor.Scan(&enr)
+		if err != nil {
+			return fmt.Errorf("EnumerateENRs failed to read data: %w", err)
+		}
+		enumFunc(enr)
+	}
+
+	if err := cursor.Err(); err != nil {
+		return fmt.Errorf("EnumerateENRs failed to iterate: %w", err)
+	}
+	return nil
+}
+
 func stringsToAny(strValues []NodeID) []interface{} {
 	values := make([]interface{}, 0, len(strValues))
 	for _, value := range strValues {
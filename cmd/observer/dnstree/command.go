@@ -0,0 +1,129 @@
+package dnstree
+
+import (
+	"context"
+
+	"github.com/spf13/cobra"
+	"github.com/urfave/cli/v2"
+
+	"github.com/ledgerwatch/erigon/cmd/utils"
+)
+
+type CommandFlags struct {
+	DataDir      string
+	Chain        string
+	MaxPingTries uint
+
+	Domain    string
+	KeyFile   string
+	Links     []string
+	Seq       uint
+	OutputDir string
+}
+
+type Command struct {
+	command cobra.Command
+	flags   CommandFlags
+}
+
+func NewCommand() *Command {
+	command := cobra.Command{
+		Use:   "dnstree",
+		Short: "Build a signed EIP-1459 DNS discovery tree from the crawler database",
+	}
+
+	instance := Command{
+		command: command,
+	}
+	instance.withDatadir()
+	instance.withChain()
+	instance.withMaxPingTries()
+	instance.withDomain()
+	instance.withKeyFile()
+	instance.withLinks()
+	instance.withSeq()
+	instance.withOutputDir()
+
+	return &instance
+}
+
+func (command *Command) withDatadir() {
+	flag := utils.DataDirFlag
+	command.command.Flags().StringVar(&command.flags.DataDir, flag.Name, flag.Value.String(), flag.Usage)
+	must(command.command.MarkFlagDirname(utils.DataDirFlag.Name))
+}
+
+func (command *Command) withChain() {
+	flag := utils.ChainFlag
+	command.command.Flags().StringVar(&command.flags.Chain, flag.Name, flag.Value, flag.Usage)
+}
+
+func (command *Command) withMaxPingTries() {
+	flag := cli.UintFlag{
+		Name:  "max-ping-tries",
+		Usage: "A number of PING failures for a node to be considered dead",
+		Value: 3,
+	}
+	command.command.Flags().UintVar(&command.flags.MaxPingTries, flag.Name, flag.Value, flag.Usage)
+}
+
+func (command *Command) withDomain() {
+	flag := cli.StringFlag{
+		Name:  "domain",
+		Usage: "DNS domain of the tree root (e.g. nodes.example.org)",
+	}
+	command.command.Flags().StringVar(&command.flags.Domain, flag.Name, flag.Value, flag.Usage)
+	must(command.command.MarkFlagRequired(flag.Name))
+}
+
+func (command *Command) withKeyFile() {
+	flag := cli.StringFlag{
+		Name:  "signing-key",
+		Usage: "Path to a hex-encoded secp256k1 private key file used to sign the tree root",
+	}
+	command.command.Flags().StringVar(&command.flags.KeyFile, flag.Name, flag.Value, flag.Usage)
+	must(command.command.MarkFlagRequired(flag.Name))
+	must(command.command.MarkFlagFilename(flag.Name))
+}
+
+func (command *Command) withLinks() {
+	flag := cli.StringSliceFlag{
+		Name:  "links",
+		Usage: "enrtree:// URLs of other trees to link from this tree",
+	}
+	command.command.Flags().StringSliceVar(&command.flags.Links, flag.Name, nil, flag.Usage)
+}
+
+func (command *Command) withSeq() {
+	flag := cli.UintFlag{
+		Name:  "seq",
+		Usage: "Sequence number of the tree root (default: current unix time)",
+	}
+	command.command.Flags().UintVar(&command.flags.Seq, flag.Name, flag.Value, flag.Usage)
+}
+
+func (command *Command) withOutputDir() {
+	flag := cli.StringFlag{
+		Name:  "output",
+		Usage: "Directory to write the zone file into",
+		Value: ".",
+	}
+	command.command.Flags().StringVar(&command.flags.OutputDir, flag.Name, flag.Value, flag.Usage)
+	must(command.command.MarkFlagDirname(flag.Name))
+}
+
+func (command *Command) RawCommand() *cobra.Command {
+	return &command.command
+}
+
+func (command *Command) OnRun(runFunc func(ctx context.Context, flags CommandFlags) error) {
+	command.command.RunE = func(cmd *cobra.Command, args []string) error {
+		return runFunc(cmd.Context(), command.flags)
+	}
+}
+
+func must(err error) {
+	if err != nil {
+		panic(err)
+	}
+}
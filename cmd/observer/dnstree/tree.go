@@ -0,0 +1,104 @@
+package dnstree
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/ledgerwatch/erigon/cmd/observer/database"
+	"github.com/ledgerwatch/erigon/p2p/dnsdisc"
+	"github.com/ledgerwatch/erigon/p2p/enode"
+)
+
+// zoneTTL is the TTL of the records in the generated zone file.
+const zoneTTL = 300
+
+// maxTXTStringLen is the maximum length of a single character-string in a TXT record (RFC 1035).
+const maxTXTStringLen = 255
+
+// BuildTree creates a signed tree of the live nodes which advertised a compatible fork ID.
+// It returns the tree and its enrtree:// URL.
+func BuildTree(
+	ctx context.Context,
+	db database.DB,
+	maxPingTries uint,
+	networkID uint,
+	seq uint,
+	links []string,
+	key *ecdsa.PrivateKey,
+	domain string,
+) (*dnsdisc.Tree, string, error) {
+	var nodes []*enode.Node
+	var parseErr error
+	err := db.EnumerateENRs(ctx, maxPingTries, networkID, func(enr string) {
+		if parseErr != nil {
+			return
+		}
+		node, err := enode.Parse(enode.ValidSchemes, enr)
+		if err != nil {
+			parseErr = fmt.Errorf("invalid node record %s: %w", enr, err)
+			return
+		}
+		nodes = append(nodes, node)
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	if parseErr != nil {
+		return nil, "", parseErr
+	}
+
+	tree, err := dnsdisc.MakeTree(seq, nodes, links)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to make the tree: %w", err)
+	}
+	url, err := tree.Sign(key, domain)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to sign the tree: %w", err)
+	}
+	return tree, url, nil
+}
+
+// WriteZoneFile writes the TXT records of a signed tree in the BIND zone file format.
+func WriteZoneFile(w io.Writer, tree *dnsdisc.Tree, domain string) error {
+	records := tree.ToTXT(domain)
+	names := make([]string, 0, len(records))
+	for name := range records {
+		if name != domain {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	if _, err := fmt.Fprintf(w, "$ORIGIN %s.\n$TTL %d\n", domain, zoneTTL); err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(w, "@\tIN\tTXT\t%s\n", quoteTXT(records[domain])); err != nil {
+		return err
+	}
+	for _, name := range names {
+		label := strings.TrimSuffix(name, "."+domain)
+		if _, err := fmt.Fprintf(w, "%s\tIN\tTXT\t%s\n", label, quoteTXT(records[name])); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// quoteTXT splits a TXT record value into quoted character-strings of the allowed length.
+func quoteTXT(value string) string {
+	var parts []string
+	for len(value) > maxTXTStringLen {
+		parts = append(parts, value[:maxTXTStringLen])
+		value = value[maxTXTStringLen:]
+	}
+	parts = append(parts, value)
+	for i, part := range parts {
+		part = strings.ReplaceAll(part, `\`, `\\`)
+		parts[i] = `"` + strings.ReplaceAll(part, `"`, `\"`) + `"`
+	}
+	return strings.Join(parts, " ")
+}
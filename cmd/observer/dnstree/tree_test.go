@@ -0,0 +1,84 @@
+package dnstree
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ledgerwatch/erigon/cmd/observer/database"
+	"github.com/ledgerwatch/erigon/cmd/observer/observer/node_utils"
+	"github.com/ledgerwatch/erigon/crypto"
+	"github.com/ledgerwatch/erigon/p2p/enode"
+	"github.com/ledgerwatch/erigon/p2p/enr"
+)
+
+func makeSignedNode(t *testing.T, port int) *enode.Node {
+	key, err := crypto.GenerateKey()
+	require.Nil(t, err)
+	var r enr.Record
+	r.Set(enr.IPv4{10, 0, 1, 16})
+	r.Set(enr.TCP(port))
+	r.Set(enr.UDP(port))
+	require.Nil(t, enode.SignV4(&r, key))
+	node, err := enode.New(enode.ValidSchemes, &r)
+	require.Nil(t, err)
+	return node
+}
+
+func TestBuildTreeAndWriteZoneFile(t *testing.T) {
+	ctx := context.Background()
+	db, err := database.NewDBSQLite(filepath.Join(t.TempDir(), "observer.sqlite"))
+	require.Nil(t, err)
+	defer func() { _ = db.Close() }()
+
+	const networkID = 1
+	for i, isCompatFork := range []bool{true, false} {
+		node := makeSignedNode(t, 30303+i)
+		id, err := node_utils.NodeID(node)
+		require.Nil(t, err)
+		require.Nil(t, db.UpsertNodeAddr(ctx, id, node_utils.MakeNodeAddr(node)))
+		require.Nil(t, db.UpdateNetworkID(ctx, id, networkID))
+		require.Nil(t, db.UpdateForkCompatibility(ctx, id, isCompatFork))
+		require.Nil(t, db.UpdateENR(ctx, id, node.String()))
+	}
+
+	key, err := crypto.GenerateKey()
+	require.Nil(t, err)
+	const domain = "nodes.example.org"
+	tree, url, err := BuildTree(ctx, db, 3, networkID, 1, nil, key, domain)
+	require.Nil(t, err)
+	assert.True(t, strings.HasPrefix(url, "enrtree://"))
+	assert.Equal(t, 1, len(tree.Nodes()))
+
+	var zone bytes.Buffer
+	require.Nil(t, WriteZoneFile(&zone, tree, domain))
+
+	records := tree.ToTXT(domain)
+	lines := strings.Split(strings.TrimSpace(zone.String()), "\n")
+	require.Equal(t, 2+len(records), len(lines))
+	assert.Equal(t, "$ORIGIN "+domain+".", lines[0])
+	assert.True(t, strings.HasPrefix(lines[2], "@\tIN\tTXT\t\"enrtree-root:v1 "))
+
+	for _, line := range lines[2:] {
+		fields := strings.SplitN(line, "\t", 4)
+		require.Equal(t, 4, len(fields))
+		name := domain
+		if fields[0] != "@" {
+			name = fields[0] + "." + domain
+		}
+		value := strings.ReplaceAll(strings.Trim(fields[3], `"`), `" "`, "")
+		assert.Equal(t, records[name], value)
+	}
+}
+
+func TestQuoteTXT(t *testing.T) {
+	assert.Equal(t, `"enrtree-branch:"`, quoteTXT("enrtree-branch:"))
+
+	long := strings.Repeat("a", maxTXTStringLen) + "bc"
+	assert.Equal(t, `"`+strings.Repeat("a", maxTXTStringLen)+`" "bc"`, quoteTXT(long))
+}
@@ -4,13 +4,17 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/ledgerwatch/erigon-lib/common"
 	"github.com/ledgerwatch/erigon/cmd/observer/database"
+	"github.com/ledgerwatch/erigon/cmd/observer/dnstree"
 	"github.com/ledgerwatch/erigon/cmd/observer/observer"
 	"github.com/ledgerwatch/erigon/cmd/observer/reports"
 	"github.com/ledgerwatch/erigon/cmd/utils"
+	"github.com/ledgerwatch/erigon/crypto"
 	"github.com/ledgerwatch/erigon/params"
 	"github.com/ledgerwatch/log/v3"
 )
@@ -103,6 +107,44 @@ func reportWithFlags(ctx context.Context, flags reports.CommandFlags) error {
 	return nil
 }
 
+func dnsTreeWithFlags(ctx context.Context, flags dnstree.CommandFlags) error {
+	key, err := crypto.LoadECDSA(flags.KeyFile)
+	if err != nil {
+		return fmt.Errorf("failed to load the signing key: %w", err)
+	}
+
+	db, err := database.NewDBSQLite(filepath.Join(flags.DataDir, "observer.sqlite"))
+	if err != nil {
+		return err
+	}
+	defer func() { _ = db.Close() }()
+
+	networkID := uint(params.NetworkIDByChainName(flags.Chain))
+
+	seq := flags.Seq
+	if seq == 0 {
+		seq = uint(time.Now().Unix())
+	}
+
+	tree, url, err := dnstree.BuildTree(ctx, db, flags.MaxPingTries, networkID, seq, flags.Links, key, flags.Domain)
+	if err != nil {
+		return err
+	}
+
+	zonePath := filepath.Join(flags.OutputDir, flags.Domain+".zone")
+	file, err := os.Create(zonePath)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = file.Close() }()
+	if err := dnstree.WriteZoneFile(file, tree, flags.Domain); err != nil {
+		return fmt.Errorf("failed to write the zone file: %w", err)
+	}
+
+	log.Info("DNS tree created", "nodes", len(tree.Nodes()), "seq", seq, "zone", zonePath, "url", url)
+	return nil
+}
+
 func main() {
 	ctx, cancel := common.RootContext()
 	defer cancel()
@@ -113,6 +155,10 @@ func main() {
 	reportCommand.OnRun(reportWithFlags)
 	command.AddSubCommand(reportCommand.RawCommand())
 
+	dnsTreeCommand := dnstree.NewCommand()
+	dnsTreeCommand.OnRun(dnsTreeWithFlags)
+	command.AddSubCommand(dnsTreeCommand.RawCommand())
+
 	err := command.ExecuteContext(ctx, mainWithFlags)
 	if (err != nil) && !errors.Is(err, context.Canceled) {
 		utils.Fatalf("%v", err)
@@ -54,16 +54,20 @@ import (
 	"github.com/ledgerwatch/erigon/cmd/utils"
 	"github.com/ledgerwatch/erigon/common"
 	"github.com/ledgerwatch/erigon/common/paths"
+	"github.com/ledgerwatch/erigon/consensus"
+	"github.com/ledgerwatch/erigon/consensus/ethash"
 	"github.com/ledgerwatch/erigon/core/rawdb"
+	"github.com/ledgerwatch/erigon/eth/ethconsensusconfig"
 	"github.com/ledgerwatch/erigon/node"
 	"github.com/ledgerwatch/erigon/node/nodecfg"
+	"github.com/ledgerwatch/erigon/params"
 	"github.com/ledgerwatch/erigon/rpc"
 	"github.com/ledgerwatch/erigon/turbo/rpchelper"
 	"github.com/ledgerwatch/erigon/turbo/services"
 	"github.com/ledgerwatch/erigon/turbo/snapshotsync"
 
 	// Force-load native and js packages, to trigger registration
-	_ "github.com/ledgerwatch/erigon/eth/tracers/js"
+	"github.com/ledgerwatch/erigon/eth/tracers/js"
 	_ "github.com/ledgerwatch/erigon/eth/tracers/native"
 )
 
@@ -82,6 +86,7 @@ func RootCommand() (*cobra.Command, *httpcfg.HttpCfg) {
 	cfg := &httpcfg.HttpCfg{Enabled: true, StateCache: kvcache.DefaultCoherentConfig}
 	rootCmd.PersistentFlags().StringVar(&cfg.PrivateApiAddr, "private.api.addr", "127.0.0.1:9090", "Erigon's components (txpool, rpcdaemon, sentry, downloader, ...) can be deployed as independent Processes on same/another server. Then components will connect to erigon by this internal grpc API. Example: 127.0.0.1:9090")
 	rootCmd.PersistentFlags().StringVar(&cfg.DataDir, "datadir", "", "path to Erigon working directory")
+	rootCmd.PersistentFlags().StringVar(&cfg.CliqueDataDir, "clique.datadir", "", "a path to clique db folder, if not the one of --datadir")
 	rootCmd.PersistentFlags().BoolVar(&cfg.GraphQLEnabled, "graphql", false, "enables graphql endpoint (disabled by default)")
 	rootCmd.PersistentFlags().StringVar(&cfg.HttpListenAddress, "http.addr", nodecfg.DefaultHTTPHost, "HTTP-RPC server listening interface")
 	rootCmd.PersistentFlags().StringVar(&cfg.TLSCertfile, "tls.cert", "", "certificate for client side TLS handshake")
@@ -120,6 +125,11 @@ func RootCommand() (*cobra.Command, *httpcfg.HttpCfg) {
 	rootCmd.PersistentFlags().DurationVar(&cfg.EvmCallTimeout, "rpc.evmtimeout", rpccfg.DefaultEvmCallTimeout, "Maximum amount of time to wait for the answer from EVM call.")
 	rootCmd.PersistentFlags().IntVar(&cfg.BatchLimit, utils.RpcBatchLimit.Name, utils.RpcBatchLimit.Value, utils.RpcBatchLimit.Usage)
 	rootCmd.PersistentFlags().IntVar(&cfg.ReturnDataLimit, utils.RpcReturnDataLimit.Name, utils.RpcReturnDataLimit.Value, utils.RpcReturnDataLimit.Usage)
+	rootCmd.PersistentFlags().Uint64Var(&cfg.JSTracerStepLimit, utils.RpcJSTracerStepLimit.Name, utils.RpcJSTracerStepLimit.Value, utils.RpcJSTracerStepLimit.Usage)
+	rootCmd.PersistentFlags().DurationVar(&cfg.JSTracerTimeLimit, utils.RpcJSTracerTimeLimit.Name, utils.RpcJSTracerTimeLimit.Value, utils.RpcJSTracerTimeLimit.Usage)
+	rootCmd.PersistentFlags().Uint64Var(&cfg.JSTracerMemoryLimit, utils.RpcJSTracerMemoryLimit.Name, utils.RpcJSTracerMemoryLimit.Value, utils.RpcJSTracerMemoryLimit.Usage)
+	rootCmd.PersistentFlags().IntVar(&cfg.TraceParallelWorkers, utils.RpcTraceParallelWorkersFlag.Name, utils.RpcTraceParallelWorkersFlag.Value, utils.RpcTraceParallelWorkersFlag.Usage)
+	rootCmd.PersistentFlags().StringVar(&cfg.JSTracersDir, utils.RpcJSTracersDir.Name, utils.RpcJSTracersDir.Value, utils.RpcJSTracersDir.Usage)
 
 	if err := rootCmd.MarkPersistentFlagFilename("rpc.accessList", "json"); err != nil {
 		panic(err)
@@ -478,7 +488,61 @@ func RemoteServices(ctx context.Context, cfg httpcfg.HttpCfg, logger log.Logger,
 	return db, borDb, eth, txPool, mining, stateCache, blockReader, ff, agg, err
 }
 
+// ConsensusEngine creates the consensus engine backing the engine-specific RPC namespaces.
+// Clique and AuRa keep their snapshots, votes and epoch transitions in a database of their
+// own, which the node creates and which is opened read-only here so that `clique_*` and
+// `aura_*` work without the node. Other chains, and the ones whose consensus database
+// can't be found, get a fake ethash engine, which leaves those namespaces out.
+func ConsensusEngine(ctx context.Context, cfg httpcfg.HttpCfg, db kv.RoDB, logger log.Logger) consensus.Engine {
+	if !cfg.WithDatadir {
+		return ethash.NewFaker()
+	}
+	var cc *chain.Config
+	if err := db.View(ctx, func(tx kv.Tx) error {
+		genesisHash, err := rawdb.ReadCanonicalHash(tx, 0)
+		if err != nil {
+			return err
+		}
+		cc, err = rawdb.ReadChainConfig(tx, genesisHash)
+		return err
+	}); err != nil {
+		logger.Warn("Could not read the chain config, using a fake consensus engine", "err", err)
+		return ethash.NewFaker()
+	}
+	var consensusConfig interface{}
+	var consensusDbPath string
+	switch {
+	case cc != nil && cc.Clique != nil:
+		cliqueDataDir := cfg.DataDir
+		if cfg.CliqueDataDir != "" {
+			cliqueDataDir = cfg.CliqueDataDir
+		}
+		snapshotConfig := *params.CliqueSnapshot
+		snapshotConfig.DBPath = filepath.Join(cliqueDataDir, "clique", "db")
+		snapshotConfig.InMemory = false
+		consensusConfig, consensusDbPath = &snapshotConfig, snapshotConfig.DBPath
+	case cc != nil && cc.Aura != nil:
+		consensusConfig, consensusDbPath = &chain.AuRaConfig{}, filepath.Join(cfg.DataDir, "aura")
+	default:
+		return ethash.NewFaker()
+	}
+	if _, err := os.Stat(consensusDbPath); err != nil {
+		logger.Warn("Could not find the consensus db of the node, using a fake consensus engine", "err", err)
+		return ethash.NewFaker()
+	}
+	logger.Trace("Opening consensus db", "path", consensusDbPath)
+	return ethconsensusconfig.CreateConsensusEngine(cc, consensusConfig, nil /* notify */, true, /* noVerify */
+		"" /* heimdallGrpcAddress */, "" /* heimdallUrl */, true /* withoutHeimdall */, cfg.DataDir, true /* readonly */, logger)
+}
+
 func StartRpcServer(ctx context.Context, cfg httpcfg.HttpCfg, rpcAPI []rpc.API, authAPI []rpc.API, logger log.Logger) error {
+	js.SetLimits(js.Limits{Steps: cfg.JSTracerStepLimit, Time: cfg.JSTracerTimeLimit, Memory: cfg.JSTracerMemoryLimit})
+	if cfg.JSTracersDir != "" {
+		if err := js.LoadTracers(cfg.JSTracersDir); err != nil {
+			return fmt.Errorf("loading JS tracers: %w", err)
+		}
+	}
+
 	if len(authAPI) > 0 {
 		engineInfo, err := startAuthenticatedRpcServer(cfg, authAPI, logger)
 		if err != nil {
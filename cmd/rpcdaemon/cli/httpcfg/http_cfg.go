@@ -15,6 +15,7 @@ type HttpCfg struct {
 	GraphQLEnabled           bool
 	WithDatadir              bool // Erigon's database can be read by separated processes on same machine - in read-only mode - with full support of transactions. It will share same "OS PageCache" with Erigon process.
 	DataDir                  string
+	CliqueDataDir            string // the clique db is in the clique/db folder of this directory, if set, rather than of DataDir
 	Dirs                     datadir.Dirs
 	HttpListenAddress        string
 	AuthRpcHTTPListenAddress string
@@ -64,4 +65,11 @@ type HttpCfg struct {
 
 	BatchLimit      int // Maximum number of requests in a batch
 	ReturnDataLimit int // Maximum number of bytes returned from calls (like eth_call)
+
+	JSTracerStepLimit   uint64        // Maximum number of EVM steps traced by a JS tracer
+	JSTracerTimeLimit   time.Duration // Maximum time spent running the code of a JS tracer
+	JSTracerMemoryLimit uint64        // Maximum number of bytes copied out of the EVM and returned by a JS tracer
+	JSTracersDir        string        // Directory of the named JS tracers
+
+	TraceParallelWorkers int // Number of workers tracing the transactions of large blocks concurrently
 }
@@ -19,6 +19,9 @@ type BorAPI interface {
 	GetCurrentProposer() (common.Address, error)
 	GetCurrentValidators() ([]*valset.Validator, error)
 	GetRootHash(start uint64, end uint64) (string, error)
+
+	// Bor finality related (see ./bor_finality.go)
+	GetFinalizedBlock() (*FinalizedBlock, error)
 }
 
 // BorImpl is implementation of the BorAPI interface
@@ -0,0 +1,48 @@
+package commands
+
+import (
+	"context"
+	"errors"
+
+	"github.com/ledgerwatch/erigon-lib/common"
+
+	"github.com/ledgerwatch/erigon/common/hexutil"
+	"github.com/ledgerwatch/erigon/consensus/bor/finality"
+)
+
+// FinalizedBlock is the highest block whitelisted from Heimdall checkpoints and milestones.
+type FinalizedBlock struct {
+	Number hexutil.Uint64 `json:"number"`
+	Hash   common.Hash    `json:"hash"`
+	Source string         `json:"source"` // "checkpoint" or "milestone"
+}
+
+// GetFinalizedBlock returns the highest block whitelisted from Heimdall, or nil if no
+// checkpoint or milestone has been whitelisted yet.
+func (api *BorImpl) GetFinalizedBlock() (*FinalizedBlock, error) {
+	if api.borDb == nil {
+		return nil, errors.New("bor database is not available")
+	}
+	borTx, err := api.borDb.BeginRo(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	defer borTx.Rollback()
+
+	checkpoint, err := finality.ReadCheckpoint(borTx)
+	if err != nil {
+		return nil, err
+	}
+	milestone, err := finality.ReadMilestone(borTx)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case milestone != nil && (checkpoint == nil || milestone.Number > checkpoint.Number):
+		return &FinalizedBlock{Number: hexutil.Uint64(milestone.Number), Hash: milestone.Hash, Source: "milestone"}, nil
+	case checkpoint != nil:
+		return &FinalizedBlock{Number: hexutil.Uint64(checkpoint.Number), Hash: checkpoint.Hash, Source: "checkpoint"}, nil
+	default:
+		return nil, nil
+	}
+}
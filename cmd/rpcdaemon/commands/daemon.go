@@ -7,7 +7,9 @@ import (
 	libstate "github.com/ledgerwatch/erigon-lib/state"
 	"github.com/ledgerwatch/erigon/cmd/rpcdaemon/cli/httpcfg"
 	"github.com/ledgerwatch/erigon/consensus"
+	"github.com/ledgerwatch/erigon/consensus/aura"
 	"github.com/ledgerwatch/erigon/consensus/clique"
+	"github.com/ledgerwatch/erigon/consensus/merge"
 	"github.com/ledgerwatch/erigon/rpc"
 	"github.com/ledgerwatch/erigon/turbo/rpchelper"
 	"github.com/ledgerwatch/erigon/turbo/services"
@@ -26,6 +28,7 @@ func APIList(db kv.RoDB, borDb kv.RoDB, eth rpchelper.ApiBackend, txPool txpool.
 	txpoolImpl := NewTxPoolAPI(base, db, txPool)
 	netImpl := NewNetAPIImpl(eth)
 	debugImpl := NewPrivateDebugAPI(base, db, cfg.Gascap)
+	debugImpl.ParallelTraceWorkers = cfg.TraceParallelWorkers
 	traceImpl := NewTraceAPI(base, db, &cfg)
 	web3Impl := NewWeb3APIImpl(eth)
 	dbImpl := NewDBAPIImpl() /* deprecated */
@@ -33,7 +36,7 @@ func APIList(db kv.RoDB, borDb kv.RoDB, eth rpchelper.ApiBackend, txPool txpool.
 	parityImpl := NewParityAPIImpl(db)
 	borImpl := NewBorAPI(base, db, borDb) // bor (consensus) specific
 	otsImpl := NewOtterscanAPI(base, db)
-	gqlImpl := NewGraphQLAPI(base, db)
+	gqlImpl := NewGraphQLAPI(base, db, debugImpl)
 
 	if cfg.GraphQLEnabled {
 		list = append(list, rpc.API{
@@ -131,13 +134,32 @@ func APIList(db kv.RoDB, borDb kv.RoDB, eth rpchelper.ApiBackend, txPool txpool.
 				Version:   "1.0",
 			})
 		case "clique":
-			list = append(list, clique.NewCliqueAPI(db, engine))
+			if _, ok := innerEngine(engine).(*clique.Clique); !ok {
+				logger.Warn("The clique namespace is not served, the chain is not driven by clique or its consensus db is missing")
+				continue
+			}
+			list = append(list, clique.NewCliqueAPI(db, innerEngine(engine)))
+		case "aura":
+			if _, ok := innerEngine(engine).(*aura.AuRa); !ok {
+				logger.Warn("The aura namespace is not served, the chain is not driven by aura or its consensus db is missing")
+				continue
+			}
+			list = append(list, aura.NewAuraAPI(db, innerEngine(engine), base.systemCallAt))
 		}
 	}
 
 	return list
 }
 
+// innerEngine unwraps the engine which ran the chain before the Merge, the one the
+// engine-specific namespaces are served by.
+func innerEngine(engine consensus.EngineReader) consensus.EngineReader {
+	if merged, ok := engine.(*merge.Merge); ok {
+		return merged.InnerEngine()
+	}
+	return engine
+}
+
 func AuthAPIList(db kv.RoDB, eth rpchelper.ApiBackend, txPool txpool.TxpoolClient, mining txpool.MiningClient,
 	filters *rpchelper.Filters, stateCache kvcache.Cache, blockReader services.FullBlockReader,
 	agg *libstate.AggregatorV3,
@@ -16,6 +16,7 @@ import (
 	"github.com/ledgerwatch/erigon/core/rawdb"
 	"github.com/ledgerwatch/erigon/core/state"
 	"github.com/ledgerwatch/erigon/core/state/temporal"
+	"github.com/ledgerwatch/erigon/core/types"
 	"github.com/ledgerwatch/erigon/core/types/accounts"
 	"github.com/ledgerwatch/erigon/eth/stagedsync/stages"
 	"github.com/ledgerwatch/erigon/eth/tracers"
@@ -42,6 +43,7 @@ type PrivateDebugAPI interface {
 	AccountAt(ctx context.Context, blockHash common.Hash, txIndex uint64, account common.Address) (*AccountResult, error)
 	GetRawHeader(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash) (hexutility.Bytes, error)
 	GetRawBlock(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash) (hexutility.Bytes, error)
+	GetBadBlocks(ctx context.Context) ([]*BadBlockResult, error)
 }
 
 // PrivateDebugAPIImpl is implementation of the PrivateDebugAPI interface based on remote Db access
@@ -49,6 +51,9 @@ type PrivateDebugAPIImpl struct {
 	*BaseAPI
 	db     kv.RoDB
 	GasCap uint64
+	// ParallelTraceWorkers is the number of workers tracing the transactions of the large
+	// blocks concurrently, blocks are traced sequentially if it's 0 or 1.
+	ParallelTraceWorkers int
 }
 
 // NewPrivateDebugAPI returns PrivateDebugAPIImpl instance
@@ -406,3 +411,51 @@ func (api *PrivateDebugAPIImpl) GetRawBlock(ctx context.Context, blockNrOrHash r
 	}
 	return rlp.EncodeToBytes(block)
 }
+
+// BadBlockResult is a block found invalid, with the reason.
+type BadBlockResult struct {
+	Hash            common.Hash            `json:"hash"`
+	Block           map[string]interface{} `json:"block"`
+	RLP             hexutility.Bytes       `json:"rlp"`
+	LatestValidHash common.Hash            `json:"latestValidHash"`
+	ValidationError string                 `json:"validationError"`
+}
+
+// GetBadBlocks implements debug_getBadBlocks. Returns the last blocks found invalid by the execution or the
+// payload validation, highest first.
+func (api *PrivateDebugAPIImpl) GetBadBlocks(ctx context.Context) ([]*BadBlockResult, error) {
+	tx, err := api.db.BeginRo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+	badBlocks, err := rawdb.ReadBadBlocks(tx)
+	if err != nil {
+		return nil, err
+	}
+	results := make([]*BadBlockResult, 0, len(badBlocks))
+	for _, badBlock := range badBlocks {
+		hash := badBlock.Header.Hash()
+		txs, err := types.DecodeTransactions(badBlock.Body.Transactions)
+		if err != nil {
+			return nil, fmt.Errorf("bad block %x: %w", hash, err)
+		}
+		block := types.NewBlockFromStorage(hash, badBlock.Header, txs, badBlock.Body.Uncles, badBlock.Body.Withdrawals)
+		fields, err := ethapi.RPCMarshalBlockDeprecated(block, true, false)
+		if err != nil {
+			return nil, err
+		}
+		enc, err := rlp.EncodeToBytes(block)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, &BadBlockResult{
+			Hash:            hash,
+			Block:           fields,
+			RLP:             enc,
+			LatestValidHash: badBlock.LastValidHash,
+			ValidationError: badBlock.ValidationError,
+		})
+	}
+	return results, nil
+}
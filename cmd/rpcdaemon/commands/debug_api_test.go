@@ -2,11 +2,14 @@ package commands
 
 import (
 	"bytes"
+	"crypto/ecdsa"
 	"encoding/json"
+	"math/big"
 	"reflect"
 	"testing"
 
 	"github.com/davecgh/go-spew/spew"
+	"github.com/holiman/uint256"
 	jsoniter "github.com/json-iterator/go"
 	"github.com/ledgerwatch/erigon-lib/common"
 	"github.com/ledgerwatch/erigon-lib/kv"
@@ -15,14 +18,20 @@ import (
 	"github.com/ledgerwatch/erigon-lib/kv/order"
 	"github.com/ledgerwatch/erigon/cmd/rpcdaemon/rpcdaemontest"
 	common2 "github.com/ledgerwatch/erigon/common"
+	"github.com/ledgerwatch/erigon/core"
 	"github.com/ledgerwatch/erigon/core/rawdb"
 	"github.com/ledgerwatch/erigon/core/state/temporal"
 	"github.com/ledgerwatch/erigon/core/types"
+	"github.com/ledgerwatch/erigon/crypto"
 	"github.com/ledgerwatch/erigon/eth/tracers"
+	"github.com/ledgerwatch/erigon/params"
+	"github.com/ledgerwatch/erigon/rlp"
 	"github.com/ledgerwatch/erigon/rpc"
 	"github.com/ledgerwatch/erigon/rpc/rpccfg"
 	"github.com/ledgerwatch/erigon/turbo/adapter/ethapi"
 	"github.com/ledgerwatch/erigon/turbo/snapshotsync"
+	"github.com/ledgerwatch/erigon/turbo/stages"
+	"github.com/ledgerwatch/erigon/turbo/transactions"
 	"github.com/ledgerwatch/log/v3"
 	"github.com/stretchr/testify/require"
 )
@@ -136,6 +145,81 @@ func TestTraceBlockByHash(t *testing.T) {
 	}
 }
 
+func TestTraceBlockParallel(t *testing.T) {
+	m, _, _ := rpcdaemontest.CreateTestSentry(t)
+	agg := m.HistoryV3Components()
+	br := snapshotsync.NewBlockReaderWithSnapshots(m.BlockSnapshots, m.TransactionsV3)
+	stateCache := kvcache.New(kvcache.DefaultCoherentConfig)
+	baseApi := NewBaseApi(nil, stateCache, br, agg, false, rpccfg.DefaultEvmCallTimeout, m.Engine, m.Dirs)
+	ethApi := NewEthAPI(baseApi, m.DB, nil, nil, nil, 5000000, 100_000, log.New())
+	api := NewPrivateDebugAPI(baseApi, m.DB, 0)
+	parallelApi := NewPrivateDebugAPI(baseApi, m.DB, 0)
+	parallelApi.ParallelTraceWorkers = 4
+	defer func(minTxs int) { parallelTraceMinTxs = minTxs }(parallelTraceMinTxs)
+	parallelTraceMinTxs = 1
+
+	callTracer := "callTracer"
+	for _, config := range []*tracers.TraceConfig{{}, {Tracer: &callTracer}} {
+		for _, tt := range debugTraceTransactionTests {
+			tx, err := ethApi.GetTransactionByHash(m.Ctx, common.HexToHash(tt.txHash))
+			require.NoError(t, err)
+			blockNum := tx.BlockNumber.ToInt().Uint64()
+			var want, have bytes.Buffer
+			stream := jsoniter.NewStream(jsoniter.ConfigDefault, &want, 4096)
+			require.NoError(t, api.TraceBlockByNumber(m.Ctx, rpc.BlockNumber(blockNum), config, stream))
+			require.NoError(t, stream.Flush())
+			stream = jsoniter.NewStream(jsoniter.ConfigDefault, &have, 4096)
+			require.NoError(t, parallelApi.TraceBlockByNumber(m.Ctx, rpc.BlockNumber(blockNum), config, stream))
+			require.NoError(t, stream.Flush())
+			require.Equal(t, want.String(), have.String(), "block %d", blockNum)
+		}
+	}
+}
+
+func TestTraceBlockParallelIndependentTransfers(t *testing.T) {
+	// a block of transfers between distinct accounts, which only share the coinbase
+	keys := make([]*ecdsa.PrivateKey, 8)
+	alloc := types.GenesisAlloc{}
+	for i := range keys {
+		keys[i], _ = crypto.GenerateKey()
+		alloc[crypto.PubkeyToAddress(keys[i].PublicKey)] = types.GenesisAccount{Balance: big.NewInt(params.Ether)}
+	}
+	gspec := &types.Genesis{Config: params.TestChainConfig, Alloc: alloc, GasLimit: 10_000_000}
+	m := stages.MockWithGenesis(t, gspec, keys[0], false)
+	signer := types.LatestSignerForChainID(m.ChainConfig.ChainID)
+	chain, err := core.GenerateChain(m.ChainConfig, m.Genesis, m.Engine, m.DB, 1, func(i int, b *core.BlockGen) {
+		b.SetCoinbase(common.Address{1})
+		for j, key := range keys {
+			to := common.BytesToAddress([]byte{0x01, byte(j)})
+			txn, err := types.SignTx(types.NewTransaction(0, to, uint256.NewInt(1000), params.TxGas, uint256.NewInt(2*params.GWei), nil), *signer, key)
+			require.NoError(t, err)
+			b.AddTx(txn)
+		}
+	}, false /* intermediateHashes */)
+	require.NoError(t, err)
+	require.NoError(t, m.InsertChain(chain))
+
+	br := snapshotsync.NewBlockReaderWithSnapshots(m.BlockSnapshots, m.TransactionsV3)
+	baseApi := NewBaseApi(nil, kvcache.New(kvcache.DefaultCoherentConfig), br, m.HistoryV3Components(), false, rpccfg.DefaultEvmCallTimeout, m.Engine, m.Dirs)
+	api := NewPrivateDebugAPI(baseApi, m.DB, 0)
+	parallelApi := NewPrivateDebugAPI(baseApi, m.DB, 0)
+	parallelApi.ParallelTraceWorkers = 4
+	defer func(minTxs int) { parallelTraceMinTxs = minTxs }(parallelTraceMinTxs)
+	parallelTraceMinTxs = 1
+
+	var want, have bytes.Buffer
+	stream := jsoniter.NewStream(jsoniter.ConfigDefault, &want, 4096)
+	require.NoError(t, api.TraceBlockByNumber(m.Ctx, rpc.BlockNumber(1), &tracers.TraceConfig{}, stream))
+	require.NoError(t, stream.Flush())
+	retraces := transactions.ParallelTraceRetraces.Get()
+	stream = jsoniter.NewStream(jsoniter.ConfigDefault, &have, 4096)
+	require.NoError(t, parallelApi.TraceBlockByNumber(m.Ctx, rpc.BlockNumber(1), &tracers.TraceConfig{}, stream))
+	require.NoError(t, stream.Flush())
+	require.Equal(t, want.String(), have.String())
+	// the fees credited to the coinbase don't make the transactions conflict
+	require.Equal(t, retraces, transactions.ParallelTraceRetraces.Get())
+}
+
 func TestTraceTransaction(t *testing.T) {
 	m, _, _ := rpcdaemontest.CreateTestSentry(t)
 	agg := m.HistoryV3Components()
@@ -535,3 +619,32 @@ func TestAccountAt(t *testing.T) {
 		require.Equal(0, int(results.Nonce))
 	})
 }
+
+func TestGetBadBlocks(t *testing.T) {
+	m, chain, _ := rpcdaemontest.CreateTestSentry(t)
+	agg := m.HistoryV3Components()
+	br := snapshotsync.NewBlockReaderWithSnapshots(m.BlockSnapshots, m.TransactionsV3)
+	stateCache := kvcache.New(kvcache.DefaultCoherentConfig)
+	base := NewBaseApi(nil, stateCache, br, agg, false, rpccfg.DefaultEvmCallTimeout, m.Engine, m.Dirs)
+	api := NewPrivateDebugAPI(base, m.DB, 0)
+
+	results, err := api.GetBadBlocks(m.Ctx)
+	require.NoError(t, err)
+	require.Empty(t, results)
+
+	block := chain.Blocks[4]
+	require.NoError(t, m.DB.Update(m.Ctx, func(tx kv.RwTx) error {
+		return rawdb.WriteBadBlock(tx, &rawdb.BadBlock{Header: block.Header(), Body: block.RawBody(), LastValidHash: block.ParentHash(), ValidationError: "invalid receipt root hash"})
+	}))
+	results, err = api.GetBadBlocks(m.Ctx)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	require.Equal(t, block.Hash(), results[0].Hash)
+	require.Equal(t, block.Hash(), results[0].Block["hash"])
+	require.Equal(t, block.ParentHash(), results[0].LatestValidHash)
+	require.Equal(t, "invalid receipt root hash", results[0].ValidationError)
+	var decoded types.Block
+	require.NoError(t, rlp.DecodeBytes(results[0].RLP, &decoded))
+	require.Equal(t, block.Hash(), decoded.Hash())
+	require.Equal(t, block.Transactions().Len(), decoded.Transactions().Len())
+}
@@ -7,11 +7,14 @@ import (
 	"fmt"
 	"math/big"
 
+	gokzg4844 "github.com/crate-crypto/go-kzg-4844"
+	lru "github.com/hashicorp/golang-lru/v2"
 	"github.com/holiman/uint256"
 	"github.com/ledgerwatch/log/v3"
 
 	"github.com/ledgerwatch/erigon-lib/common"
 	"github.com/ledgerwatch/erigon-lib/common/hexutility"
+	libkzg "github.com/ledgerwatch/erigon-lib/crypto/kzg"
 	"github.com/ledgerwatch/erigon-lib/gointerfaces"
 	"github.com/ledgerwatch/erigon-lib/gointerfaces/remote"
 	types2 "github.com/ledgerwatch/erigon-lib/gointerfaces/types"
@@ -71,6 +74,7 @@ type PayloadAttributes struct {
 	PrevRandao            common.Hash         `json:"prevRandao"            gencodec:"required"`
 	SuggestedFeeRecipient common.Address      `json:"suggestedFeeRecipient" gencodec:"required"`
 	Withdrawals           []*types.Withdrawal `json:"withdrawals"`
+	ParentBeaconBlockRoot *common.Hash        `json:"parentBeaconBlockRoot"`
 }
 
 // TransitionConfiguration represents the correct configurations of the CL and the EL
@@ -82,9 +86,9 @@ type TransitionConfiguration struct {
 
 // BlobsBundleV1 holds the blobs of an execution payload
 type BlobsBundleV1 struct {
-	KZGs   []types.KZGCommitment `json:"kzgs"   gencodec:"required"`
-	Blobs  []types.Blob          `json:"blobs"  gencodec:"required"`
-	Proofs []types.KZGProof      `json:"proofs" gencodec:"required"`
+	Commitments []types.KZGCommitment `json:"commitments" gencodec:"required"`
+	Blobs       []types.Blob          `json:"blobs"       gencodec:"required"`
+	Proofs      []types.KZGProof      `json:"proofs"      gencodec:"required"`
 }
 
 type ExecutionPayloadBodyV1 struct {
@@ -96,9 +100,10 @@ type ExecutionPayloadBodyV1 struct {
 type EngineAPI interface {
 	NewPayloadV1(context.Context, *ExecutionPayload) (map[string]interface{}, error)
 	NewPayloadV2(context.Context, *ExecutionPayload) (map[string]interface{}, error)
-	NewPayloadV3(context.Context, *ExecutionPayload) (map[string]interface{}, error)
+	NewPayloadV3(ctx context.Context, payload *ExecutionPayload, expectedBlobHashes []common.Hash, parentBeaconBlockRoot *common.Hash) (map[string]interface{}, error)
 	ForkchoiceUpdatedV1(ctx context.Context, forkChoiceState *ForkChoiceState, payloadAttributes *PayloadAttributes) (map[string]interface{}, error)
 	ForkchoiceUpdatedV2(ctx context.Context, forkChoiceState *ForkChoiceState, payloadAttributes *PayloadAttributes) (map[string]interface{}, error)
+	ForkchoiceUpdatedV3(ctx context.Context, forkChoiceState *ForkChoiceState, payloadAttributes *PayloadAttributes) (map[string]interface{}, error)
 	GetPayloadV1(ctx context.Context, payloadID hexutility.Bytes) (*ExecutionPayload, error)
 	GetPayloadV2(ctx context.Context, payloadID hexutility.Bytes) (*GetPayloadV2Response, error)
 	GetPayloadV3(ctx context.Context, payloadID hexutility.Bytes) (*GetPayloadV3Response, error)
@@ -113,8 +118,12 @@ type EngineImpl struct {
 	db         kv.RoDB
 	api        rpchelper.ApiBackend
 	internalCL bool
+	blobProofs *lru.Cache[types.KZGCommitment, types.KZGProof] // thread-safe
 }
 
+// blobProofsCacheSize is the number of KZG proofs of blobs kept, for several full blocks of blobs.
+const blobProofsCacheSize = 1024
+
 func convertPayloadStatus(ctx context.Context, db kv.RoDB, x *remote.EnginePayloadStatus) (map[string]interface{}, error) {
 	json := map[string]interface{}{
 		"status": x.Status.String(),
@@ -170,6 +179,12 @@ func (e *EngineImpl) ForkchoiceUpdatedV2(ctx context.Context, forkChoiceState *F
 	return e.forkchoiceUpdated(2, ctx, forkChoiceState, payloadAttributes)
 }
 
+// ForkchoiceUpdatedV3 is ForkchoiceUpdatedV2 building Cancun payloads, whose attributes have the parent beacon block root.
+// See https://github.com/ethereum/execution-apis/blob/main/src/engine/cancun.md#engine_forkchoiceupdatedv3
+func (e *EngineImpl) ForkchoiceUpdatedV3(ctx context.Context, forkChoiceState *ForkChoiceState, payloadAttributes *PayloadAttributes) (map[string]interface{}, error) {
+	return e.forkchoiceUpdated(3, ctx, forkChoiceState, payloadAttributes)
+}
+
 // Converts slice of pointers to slice of structs
 func withdrawalValues(ptrs []*types.Withdrawal) []types.Withdrawal {
 	if ptrs == nil {
@@ -184,6 +199,38 @@ func withdrawalValues(ptrs []*types.Withdrawal) []types.Withdrawal {
 
 var errEmbedeedConsensus = errors.New("engine api should not be used, restart without --internalcl")
 
+// checkFork returns the Unsupported fork error when the timestamp of a payload doesn't fall within the
+// fork of the method version: the V3 methods serve the Cancun payloads, the older ones the payloads before Cancun.
+func (e *EngineImpl) checkFork(ctx context.Context, version uint32, timestamp uint64) error {
+	tx, err := e.db.BeginRo(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	chainConfig, err := e.BaseAPI.chainConfig(tx)
+	if err != nil {
+		return err
+	}
+	if chainConfig.IsCancun(timestamp) != (version >= 3) {
+		return &privateapi.UnsupportedForkErr
+	}
+	return nil
+}
+
+// blobHashes returns the versioned hashes of the blobs of the transactions, in their order.
+func blobHashes(transactions []hexutility.Bytes) ([]common.Hash, error) {
+	var hashes []common.Hash
+	for _, transaction := range transactions {
+		txn, err := types.DecodeTransaction(transaction)
+		if err != nil {
+			return nil, err
+		}
+		hashes = append(hashes, txn.GetDataHashes()...)
+	}
+	return hashes, nil
+}
+
 func (e *EngineImpl) forkchoiceUpdated(version uint32, ctx context.Context, forkChoiceState *ForkChoiceState, payloadAttributes *PayloadAttributes) (map[string]interface{}, error) {
 	if e.internalCL {
 		return nil, errEmbedeedConsensus
@@ -200,6 +247,17 @@ func (e *EngineImpl) forkchoiceUpdated(version uint32, ctx context.Context, fork
 
 	var attributes *remote.EnginePayloadAttributes
 	if payloadAttributes != nil {
+		if version >= 3 && (payloadAttributes.Withdrawals == nil || payloadAttributes.ParentBeaconBlockRoot == nil) {
+			return nil, &rpc.InvalidParamsError{Message: "missing withdrawals or parent beacon block root"}
+		}
+		if version < 3 && payloadAttributes.ParentBeaconBlockRoot != nil {
+			return nil, &rpc.InvalidParamsError{Message: "unexpected parent beacon block root"}
+		}
+		if version >= 2 {
+			if err := e.checkFork(ctx, version, uint64(payloadAttributes.Timestamp)); err != nil {
+				return nil, err
+			}
+		}
 		attributes = &remote.EnginePayloadAttributes{
 			Version:               1,
 			Timestamp:             uint64(payloadAttributes.Timestamp),
@@ -210,6 +268,11 @@ func (e *EngineImpl) forkchoiceUpdated(version uint32, ctx context.Context, fork
 			attributes.Version = 2
 			attributes.Withdrawals = privateapi.ConvertWithdrawalsToRpc(payloadAttributes.Withdrawals)
 		}
+		if version >= 3 {
+			// the parent beacon block root is checked but not given to the builder: the headers don't
+			// commit to it without EIP-4788, which isn't supported yet.
+			attributes.Version = 3
+		}
 	}
 	reply, err := e.api.EngineForkchoiceUpdated(ctx, &remote.EngineForkChoiceUpdatedRequest{
 		ForkchoiceState: &remote.EngineForkChoiceState{
@@ -239,27 +302,55 @@ func (e *EngineImpl) forkchoiceUpdated(version uint32, ctx context.Context, fork
 // NewPayloadV1 processes new payloads (blocks) from the beacon chain without withdrawals.
 // See https://github.com/ethereum/execution-apis/blob/main/src/engine/paris.md#engine_newpayloadv1
 func (e *EngineImpl) NewPayloadV1(ctx context.Context, payload *ExecutionPayload) (map[string]interface{}, error) {
-	return e.newPayload(1, ctx, payload)
+	return e.newPayload(1, ctx, payload, nil)
 }
 
 // NewPayloadV2 processes new payloads (blocks) from the beacon chain with withdrawals.
 // See https://github.com/ethereum/execution-apis/blob/main/src/engine/shanghai.md#engine_newpayloadv2
 func (e *EngineImpl) NewPayloadV2(ctx context.Context, payload *ExecutionPayload) (map[string]interface{}, error) {
-	return e.newPayload(2, ctx, payload)
+	return e.newPayload(2, ctx, payload, nil)
 }
 
 // NewPayloadV3 processes new payloads (blocks) from the beacon chain with withdrawals & excess data gas.
-// See https://github.com/ethereum/execution-apis/blob/main/src/engine/specification.md#engine_newpayloadv3
-func (e *EngineImpl) NewPayloadV3(ctx context.Context, payload *ExecutionPayload) (map[string]interface{}, error) {
-	return e.newPayload(3, ctx, payload)
+// The versioned hashes of the blobs of the payload must be the expected ones. The parent beacon block
+// root is required, the headers don't commit to it yet (EIP-4788), so it isn't sent to the execution.
+// See https://github.com/ethereum/execution-apis/blob/main/src/engine/cancun.md#engine_newpayloadv3
+func (e *EngineImpl) NewPayloadV3(ctx context.Context, payload *ExecutionPayload, expectedBlobHashes []common.Hash, parentBeaconBlockRoot *common.Hash) (map[string]interface{}, error) {
+	if payload.ExcessDataGas == nil || expectedBlobHashes == nil || parentBeaconBlockRoot == nil {
+		return nil, &rpc.InvalidParamsError{Message: "missing excess data gas, expected blob versioned hashes or parent beacon block root"}
+	}
+	return e.newPayload(3, ctx, payload, expectedBlobHashes)
 }
 
-func (e *EngineImpl) newPayload(version uint32, ctx context.Context, payload *ExecutionPayload) (map[string]interface{}, error) {
+func (e *EngineImpl) newPayload(version uint32, ctx context.Context, payload *ExecutionPayload, expectedBlobHashes []common.Hash) (map[string]interface{}, error) {
 	if e.internalCL {
 		return nil, errEmbedeedConsensus
 	}
 	log.Debug("Received NewPayload", "version", version, "height", uint64(payload.BlockNumber), "hash", payload.BlockHash)
 
+	if version >= 2 {
+		if err := e.checkFork(ctx, version, uint64(payload.Timestamp)); err != nil {
+			return nil, err
+		}
+	}
+	if version >= 3 {
+		hashes, err := blobHashes(payload.Transactions)
+		if err != nil {
+			log.Warn("NewPayload blob versioned hashes", "hash", payload.BlockHash, "err", err)
+			return convertPayloadStatus(ctx, e.db, &remote.EnginePayloadStatus{
+				Status:          remote.EngineStatus_INVALID,
+				ValidationError: err.Error(),
+			})
+		}
+		if !equalHashes(hashes, expectedBlobHashes) {
+			log.Warn("NewPayload blob versioned hashes mismatch", "hash", payload.BlockHash, "expected", len(expectedBlobHashes), "got", len(hashes))
+			return convertPayloadStatus(ctx, e.db, &remote.EnginePayloadStatus{
+				Status:          remote.EngineStatus_INVALID,
+				ValidationError: "invalid blob versioned hashes",
+			})
+		}
+	}
+
 	baseFee, overflow := uint256.FromBig((*big.Int)(payload.BaseFeePerGas))
 	if overflow {
 		log.Warn("NewPayload BaseFeePerGas overflow")
@@ -378,6 +469,9 @@ func (e *EngineImpl) GetPayloadV2(ctx context.Context, payloadID hexutility.Byte
 	}
 
 	epl := convertPayloadFromRpc(response.ExecutionPayload)
+	if err := e.checkFork(ctx, 2, uint64(epl.Timestamp)); err != nil {
+		return nil, err
+	}
 	blockValue := gointerfaces.ConvertH256ToUint256Int(response.BlockValue).ToBig()
 	return &GetPayloadV2Response{
 		epl,
@@ -387,8 +481,7 @@ func (e *EngineImpl) GetPayloadV2(ctx context.Context, payloadID hexutility.Byte
 
 func (e *EngineImpl) GetPayloadV3(ctx context.Context, payloadID hexutility.Bytes) (*GetPayloadV3Response, error) {
 	if e.internalCL {
-		log.Error("EXTERNAL CONSENSUS LAYER IS NOT ENABLED, PLEASE RESTART WITH FLAG --externalcl")
-		return nil, fmt.Errorf("engine api should not be used, restart with --externalcl")
+		return nil, errEmbedeedConsensus
 	}
 
 	decodedPayloadId := binary.BigEndian.Uint64(payloadID)
@@ -400,6 +493,9 @@ func (e *EngineImpl) GetPayloadV3(ctx context.Context, payloadID hexutility.Byte
 	}
 
 	epl := convertPayloadFromRpc(response.ExecutionPayload)
+	if err := e.checkFork(ctx, 3, uint64(epl.Timestamp)); err != nil {
+		return nil, err
+	}
 	blockValue := gointerfaces.ConvertH256ToUint256Int(response.BlockValue).ToBig()
 
 	ep, err := e.api.EngineGetBlobsBundleV1(ctx, decodedPayloadId)
@@ -417,9 +513,14 @@ func (e *EngineImpl) GetPayloadV3(ctx context.Context, payloadID hexutility.Byte
 		copy(replyKzgs[i][:], kzgs[i])
 		copy(replyBlobs[i][:], blobs[i])
 	}
+	proofs, err := e.computeBlobProofs(replyBlobs, replyKzgs)
+	if err != nil {
+		return nil, err
+	}
 	bb := &BlobsBundleV1{
-		KZGs:  replyKzgs,
-		Blobs: replyBlobs,
+		Commitments: replyKzgs,
+		Blobs:       replyBlobs,
+		Proofs:      proofs,
 	}
 
 	return &GetPayloadV3Response{
@@ -429,6 +530,26 @@ func (e *EngineImpl) GetPayloadV3(ctx context.Context, payloadID hexutility.Byte
 	}, nil
 }
 
+// computeBlobProofs returns the KZG proofs of the blobs of a payload. The blobs bundle of the execution
+// doesn't carry the proofs, so they're computed from the commitments, once per blob: the payloads built
+// again for a new slot keep most of their blobs.
+func (e *EngineImpl) computeBlobProofs(blobs []types.Blob, commitments []types.KZGCommitment) ([]types.KZGProof, error) {
+	proofs := make([]types.KZGProof, len(blobs))
+	for i := range blobs {
+		if proof, ok := e.blobProofs.Get(commitments[i]); ok {
+			proofs[i] = proof
+			continue
+		}
+		proof, err := libkzg.Ctx().ComputeBlobKZGProof(gokzg4844.Blob(blobs[i]), gokzg4844.KZGCommitment(commitments[i]), 1 /*numGoRoutines*/)
+		if err != nil {
+			return nil, fmt.Errorf("could not compute proof for blob %d: %w", i, err)
+		}
+		proofs[i] = types.KZGProof(proof)
+		e.blobProofs.Add(commitments[i], proofs[i])
+	}
+	return proofs, nil
+}
+
 // Receives consensus layer's transition configuration and checks if the execution layer has the correct configuration.
 // Can also be used to ping the execution layer (heartbeats).
 // See https://github.com/ethereum/execution-apis/blob/v1.0.0-beta.1/src/engine/specification.md#engine_exchangetransitionconfigurationv1
@@ -499,15 +620,15 @@ func (e *EngineImpl) GetPayloadBodiesByRangeV1(ctx context.Context, start, count
 	return convertExecutionPayloadV1(apiRes), nil
 }
 
+// ourCapabilities are the methods advertised to the CL. The V3 methods aren't: without EIP-4788 the
+// blocks don't commit to the parent beacon block root, so their hashes differ from the CL ones.
 var ourCapabilities = []string{
 	"engine_forkchoiceUpdatedV1",
 	"engine_forkchoiceUpdatedV2",
 	"engine_newPayloadV1",
 	"engine_newPayloadV2",
-	// "engine_newPayloadV3",
 	"engine_getPayloadV1",
 	"engine_getPayloadV2",
-	// "engine_getPayloadV3",
 	"engine_exchangeTransitionConfigurationV1",
 	"engine_getPayloadBodiesByHashV1",
 	"engine_getPayloadBodiesByRangeV1",
@@ -542,6 +663,18 @@ func compareCapabilities(from []string, to []string) []string {
 	return result
 }
 
+func equalHashes(a, b []common.Hash) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
 func convertExecutionPayloadV1(response *remote.EngineGetPayloadBodiesV1Response) []*ExecutionPayloadBodyV1 {
 	result := make([]*ExecutionPayloadBodyV1, len(response.Bodies))
 	for idx, body := range response.Bodies {
@@ -564,10 +697,15 @@ func convertExecutionPayloadV1(response *remote.EngineGetPayloadBodiesV1Response
 
 // NewEngineAPI returns EngineImpl instance
 func NewEngineAPI(base *BaseAPI, db kv.RoDB, api rpchelper.ApiBackend, internalCL bool) *EngineImpl {
+	blobProofs, err := lru.New[types.KZGCommitment, types.KZGProof](blobProofsCacheSize)
+	if err != nil {
+		panic(err)
+	}
 	return &EngineImpl{
 		BaseAPI:    base,
 		db:         db,
 		api:        api,
 		internalCL: internalCL,
+		blobProofs: blobProofs,
 	}
 }
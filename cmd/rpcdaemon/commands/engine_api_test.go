@@ -1,14 +1,37 @@
 package commands
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
 	"testing"
 
 	"github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/common/hexutility"
+	"github.com/ledgerwatch/erigon-lib/direct"
 	"github.com/ledgerwatch/erigon-lib/gointerfaces"
 	"github.com/ledgerwatch/erigon-lib/gointerfaces/remote"
+	"github.com/ledgerwatch/erigon-lib/kv/kvcache"
+	"github.com/ledgerwatch/log/v3"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/ledgerwatch/erigon/cmd/rpcdaemon/rpcdaemontest"
+	"github.com/ledgerwatch/erigon/cmd/rpcdaemon/rpcservices"
+	"github.com/ledgerwatch/erigon/common/hexutil"
+	"github.com/ledgerwatch/erigon/consensus/ethash"
+	"github.com/ledgerwatch/erigon/consensus/merge"
+	"github.com/ledgerwatch/erigon/core/types"
+	"github.com/ledgerwatch/erigon/crypto"
+	"github.com/ledgerwatch/erigon/ethdb/privateapi"
+	"github.com/ledgerwatch/erigon/ethdb/prune"
+	"github.com/ledgerwatch/erigon/params"
+	"github.com/ledgerwatch/erigon/rpc"
+	"github.com/ledgerwatch/erigon/rpc/rpccfg"
+	"github.com/ledgerwatch/erigon/turbo/engineapi"
+	"github.com/ledgerwatch/erigon/turbo/snapshotsync"
+	"github.com/ledgerwatch/erigon/turbo/stages"
 )
 
 // Test case for https://github.com/ethereum/execution-apis/pull/217 responses
@@ -19,3 +42,311 @@ func TestZeroLatestValidHash(t *testing.T) {
 	assert.Equal(t, "INVALID", json["status"])
 	assert.Equal(t, common.Hash{}, json["latestValidHash"])
 }
+
+// mockCL drives the Engine API through JSON-RPC as a consensus layer client does, like the
+// Hive engine simulators.
+type mockCL struct {
+	t      *testing.T
+	client *rpc.Client
+}
+
+func newMockCL(t *testing.T, api EngineAPI) *mockCL {
+	server := rpc.NewServer(50, false /* traceRequests */, true /* disableStreaming */, log.New())
+	require.NoError(t, server.RegisterName("engine", api))
+	client := rpc.DialInProc(server, log.New())
+	t.Cleanup(func() {
+		client.Close()
+		server.Stop()
+	})
+	return &mockCL{t: t, client: client}
+}
+
+// call returns the raw result of the method, or the code of its error.
+func (cl *mockCL) call(method string, args ...interface{}) (json.RawMessage, int) {
+	var result json.RawMessage
+	err := cl.client.CallContext(context.Background(), &result, method, args...)
+	if err == nil {
+		return result, 0
+	}
+	var rpcErr rpc.Error
+	require.True(cl.t, errors.As(err, &rpcErr), "%s: %v", method, err)
+	return nil, rpcErr.ErrorCode()
+}
+
+func payloadBodies(t *testing.T, blocks ...*types.Block) string {
+	bodies := make([]*ExecutionPayloadBodyV1, len(blocks))
+	for i, block := range blocks {
+		if block == nil {
+			continue
+		}
+		body := &ExecutionPayloadBodyV1{Transactions: []hexutility.Bytes{}, Withdrawals: block.Withdrawals()}
+		for _, txn := range block.Transactions() {
+			var buf bytes.Buffer
+			require.NoError(t, txn.MarshalBinary(&buf))
+			body.Transactions = append(body.Transactions, buf.Bytes())
+		}
+		bodies[i] = body
+	}
+	enc, err := json.Marshal(bodies)
+	require.NoError(t, err)
+	return string(enc)
+}
+
+func TestEngineGetPayloadBodies(t *testing.T) {
+	m, chain, _ := rpcdaemontest.CreateTestSentry(t)
+	br := snapshotsync.NewBlockReaderWithSnapshots(m.BlockSnapshots, m.TransactionsV3)
+	backendServer := privateapi.NewEthBackendServer(context.Background(), nil, m.DB, m.Notifications.Events, br, nil, nil, nil, false, m.Log)
+	backend := rpcservices.NewRemoteBackend(direct.NewEthBackendClientDirect(backendServer), m.DB, br)
+	base := NewBaseApi(nil, kvcache.New(kvcache.DefaultCoherentConfig), br, m.HistoryV3Components(), false, rpccfg.DefaultEvmCallTimeout, m.Engine, m.Dirs)
+	cl := newMockCL(t, NewEngineAPI(base, m.DB, backend, false))
+
+	blocks := chain.Blocks
+	head := blocks[len(blocks)-1].NumberU64()
+	for _, tt := range []struct {
+		name   string
+		method string
+		args   []interface{}
+		want   string
+		code   int
+	}{
+		{
+			name:   "range",
+			method: "engine_getPayloadBodiesByRangeV1",
+			args:   []interface{}{hexutil.Uint64(1), hexutil.Uint64(3)},
+			want:   payloadBodies(t, blocks[0], blocks[1], blocks[2]),
+		},
+		{
+			name:   "range past the head",
+			method: "engine_getPayloadBodiesByRangeV1",
+			args:   []interface{}{hexutil.Uint64(head), hexutil.Uint64(5)},
+			want:   payloadBodies(t, blocks[len(blocks)-1]),
+		},
+		{
+			name:   "range after the head",
+			method: "engine_getPayloadBodiesByRangeV1",
+			args:   []interface{}{hexutil.Uint64(head + 1), hexutil.Uint64(5)},
+			want:   `[]`,
+		},
+		{
+			name:   "range from genesis",
+			method: "engine_getPayloadBodiesByRangeV1",
+			args:   []interface{}{hexutil.Uint64(0), hexutil.Uint64(1)},
+			code:   -32602,
+		},
+		{
+			name:   "too large range",
+			method: "engine_getPayloadBodiesByRangeV1",
+			args:   []interface{}{hexutil.Uint64(1), hexutil.Uint64(1025)},
+			code:   privateapi.TooLargeRequestErr.Code,
+		},
+		{
+			name:   "hashes",
+			method: "engine_getPayloadBodiesByHashV1",
+			args:   []interface{}{[]common.Hash{blocks[1].Hash(), {0xff}, blocks[0].Hash()}},
+			want:   payloadBodies(t, blocks[1], nil, blocks[0]),
+		},
+		{
+			name:   "payload of a fork before cancun",
+			method: "engine_newPayloadV3",
+			args: []interface{}{
+				&ExecutionPayload{Timestamp: hexutil.Uint64(blocks[0].Time()), BaseFeePerGas: (*hexutil.Big)(common.Big1), ExcessDataGas: (*hexutil.Big)(common.Big0), Transactions: []hexutility.Bytes{}},
+				[]common.Hash{},
+				common.Hash{},
+			},
+			code: privateapi.UnsupportedForkErr.Code,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			result, code := cl.call(tt.method, tt.args...)
+			require.Equal(t, tt.code, code)
+			if tt.code == 0 {
+				require.JSONEq(t, tt.want, string(result))
+			}
+		})
+	}
+}
+
+func TestEngineCancunParams(t *testing.T) {
+	config := *params.TestChainConfig
+	config.ShanghaiTime = common.Big0
+	config.CancunTime = common.Big0
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	m := stages.MockWithGenesis(t, &types.Genesis{Config: &config}, key, false)
+	br := snapshotsync.NewBlockReaderWithSnapshots(m.BlockSnapshots, m.TransactionsV3)
+	base := NewBaseApi(nil, kvcache.New(kvcache.DefaultCoherentConfig), br, m.HistoryV3Components(), false, rpccfg.DefaultEvmCallTimeout, m.Engine, m.Dirs)
+	// the requests are refused before reaching the execution
+	cl := newMockCL(t, NewEngineAPI(base, m.DB, nil, false))
+
+	payload := &ExecutionPayload{
+		ParentHash:    m.Genesis.Hash(),
+		BlockNumber:   1,
+		GasLimit:      hexutil.Uint64(m.Genesis.GasLimit()),
+		Timestamp:     12,
+		BaseFeePerGas: (*hexutil.Big)(common.Big1),
+		ExcessDataGas: (*hexutil.Big)(common.Big0),
+		Transactions:  []hexutility.Bytes{},
+		Withdrawals:   []*types.Withdrawal{},
+	}
+	forkChoiceState := &ForkChoiceState{HeadHash: m.Genesis.Hash(), SafeBlockHash: m.Genesis.Hash(), FinalizedBlockHash: m.Genesis.Hash()}
+	beaconRoot := common.Hash{1}
+	for _, tt := range []struct {
+		name   string
+		method string
+		args   []interface{}
+		want   string
+		code   int
+	}{
+		{
+			name:   "cancun payload of V2",
+			method: "engine_newPayloadV2",
+			args:   []interface{}{payload},
+			code:   privateapi.UnsupportedForkErr.Code,
+		},
+		{
+			name:   "missing versioned hashes",
+			method: "engine_newPayloadV3",
+			args:   []interface{}{payload, nil, beaconRoot},
+			code:   -32602,
+		},
+		{
+			name:   "missing parent beacon block root",
+			method: "engine_newPayloadV3",
+			args:   []interface{}{payload, []common.Hash{}, nil},
+			code:   -32602,
+		},
+		{
+			name:   "unexpected versioned hashes",
+			method: "engine_newPayloadV3",
+			args:   []interface{}{payload, []common.Hash{{1}}, beaconRoot},
+			want:   `{"status":"INVALID","validationError":"invalid blob versioned hashes"}`,
+		},
+		{
+			name:   "attributes without parent beacon block root",
+			method: "engine_forkchoiceUpdatedV3",
+			args: []interface{}{forkChoiceState, &PayloadAttributes{
+				Timestamp:   12,
+				Withdrawals: []*types.Withdrawal{},
+			}},
+			code: -32602,
+		},
+		{
+			name:   "attributes of V2 with parent beacon block root",
+			method: "engine_forkchoiceUpdatedV2",
+			args: []interface{}{forkChoiceState, &PayloadAttributes{
+				Timestamp:             12,
+				Withdrawals:           []*types.Withdrawal{},
+				ParentBeaconBlockRoot: &beaconRoot,
+			}},
+			code: -32602,
+		},
+		{
+			name:   "cancun attributes of V2",
+			method: "engine_forkchoiceUpdatedV2",
+			args: []interface{}{forkChoiceState, &PayloadAttributes{
+				Timestamp:   12,
+				Withdrawals: []*types.Withdrawal{},
+			}},
+			code: privateapi.UnsupportedForkErr.Code,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			result, code := cl.call(tt.method, tt.args...)
+			require.Equal(t, tt.code, code)
+			if tt.code == 0 {
+				require.JSONEq(t, tt.want, string(result))
+			}
+		})
+	}
+
+	// the versioned hashes of the transactions which can't be decoded can't be the expected ones
+	undecodable := *payload
+	undecodable.Transactions = []hexutility.Bytes{{0x03, 0x01}}
+	result, code := cl.call("engine_newPayloadV3", &undecodable, []common.Hash{}, beaconRoot)
+	require.Zero(t, code)
+	var status map[string]interface{}
+	require.NoError(t, json.Unmarshal(result, &status))
+	require.Equal(t, "INVALID", status["status"])
+	require.NotEmpty(t, status["validationError"])
+
+	capabilities, code := cl.call("engine_exchangeCapabilities", []string{"engine_newPayloadV3", "engine_forkchoiceUpdatedV3", "engine_getPayloadV3"})
+	require.Zero(t, code)
+	// the V3 methods aren't advertised until the blocks commit to the parent beacon block root
+	for _, method := range []string{"engine_newPayloadV3", "engine_forkchoiceUpdatedV3", "engine_getPayloadV3"} {
+		require.NotContains(t, string(capabilities), method)
+	}
+}
+
+func TestEngineCancunPayload(t *testing.T) {
+	config := *params.TestChainConfig
+	config.TerminalTotalDifficulty = common.Big0
+	config.ShanghaiTime = common.Big0
+	config.CancunTime = common.Big0
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	m := stages.MockWithEverything(t, &types.Genesis{Config: &config}, key, prune.DefaultMode, merge.New(ethash.NewFaker()), true /* withTxPool */, false /* withPosDownloader */)
+	hd := m.HeaderDownload()
+
+	// a first cycle switches the headers stage to proof-of-stake
+	_, err = stages.StageLoopStep(m.Ctx, m.ChainConfig, m.DB, m.Sync, m.Notifications, false /* initialCycle */, m.UpdateHead, m.Log, nil)
+	require.NoError(t, err)
+	// then the stage loop runs for each request of the consensus layer, like the node does
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		for {
+			if interrupt, _, _ := hd.BeaconRequestList.WaitForRequest(true /* onlyNew */, false /* noWait */); interrupt == engineapi.Stopping {
+				return
+			}
+			headBlockHash, err := stages.StageLoopStep(m.Ctx, m.ChainConfig, m.DB, m.Sync, m.Notifications, false /* initialCycle */, m.UpdateHead, m.Log, nil)
+			stages.SendPayloadStatus(hd, headBlockHash, err)
+		}
+	}()
+	t.Cleanup(func() {
+		hd.BeaconRequestList.Interrupt(engineapi.Stopping)
+		<-stopped
+	})
+
+	br := snapshotsync.NewBlockReaderWithSnapshots(m.BlockSnapshots, m.TransactionsV3)
+	backendServer := privateapi.NewEthBackendServer(m.Ctx, nil, m.DB, m.Notifications.Events, br, m.ChainConfig, m.BuildBlock, hd, true /* proposing */, m.Log)
+	backend := rpcservices.NewRemoteBackend(direct.NewEthBackendClientDirect(backendServer), m.DB, br)
+	base := NewBaseApi(nil, kvcache.New(kvcache.DefaultCoherentConfig), br, m.HistoryV3Components(), false, rpccfg.DefaultEvmCallTimeout, m.Engine, m.Dirs)
+	cl := newMockCL(t, NewEngineAPI(base, m.DB, backend, false))
+
+	genesis := m.Genesis.Hash()
+	beaconRoot := common.Hash{1}
+	result, code := cl.call("engine_forkchoiceUpdatedV3",
+		&ForkChoiceState{HeadHash: genesis, SafeBlockHash: genesis, FinalizedBlockHash: genesis},
+		&PayloadAttributes{
+			Timestamp:             hexutil.Uint64(m.Genesis.Time() + 12),
+			SuggestedFeeRecipient: common.Address{1},
+			Withdrawals:           []*types.Withdrawal{},
+			ParentBeaconBlockRoot: &beaconRoot,
+		})
+	require.Zero(t, code)
+	var forkChoiceResponse struct {
+		PayloadStatus struct {
+			Status          string      `json:"status"`
+			LatestValidHash common.Hash `json:"latestValidHash"`
+		} `json:"payloadStatus"`
+		PayloadId hexutility.Bytes `json:"payloadId"`
+	}
+	require.NoError(t, json.Unmarshal(result, &forkChoiceResponse))
+	require.Equal(t, "VALID", forkChoiceResponse.PayloadStatus.Status)
+	require.Equal(t, genesis, forkChoiceResponse.PayloadStatus.LatestValidHash)
+	require.NotEmpty(t, forkChoiceResponse.PayloadId)
+
+	result, code = cl.call("engine_getPayloadV3", forkChoiceResponse.PayloadId)
+	require.Zero(t, code)
+	var payloadResponse GetPayloadV3Response
+	require.NoError(t, json.Unmarshal(result, &payloadResponse))
+	payload := payloadResponse.ExecutionPayload
+	require.Equal(t, genesis, payload.ParentHash)
+	require.Equal(t, hexutil.Uint64(1), payload.BlockNumber)
+	require.NotNil(t, payload.ExcessDataGas)
+	require.Empty(t, payloadResponse.BlobsBundle.Blobs)
+
+	result, code = cl.call("engine_newPayloadV3", payload, []common.Hash{}, beaconRoot)
+	require.Zero(t, code)
+	require.JSONEq(t, `{"status":"VALID","latestValidHash":"`+payload.BlockHash.Hex()+`"}`, string(result))
+}
@@ -0,0 +1,64 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"golang.org/x/sync/semaphore"
+
+	"github.com/ledgerwatch/erigon/core/vm"
+	"github.com/ledgerwatch/erigon/rpc"
+	"github.com/ledgerwatch/erigon/turbo/rpchelper"
+)
+
+// analysisSem bounds the number of contracts analysed at once, the analysis being CPU bound.
+var analysisSem = semaphore.NewWeighted(int64(runtime.GOMAXPROCS(0)))
+
+// AnalyzeContract implements erigon_analyzeContract. Returns the control flow graph of the code of the contract at the
+// given address and block, recovered by abstract interpretation, with the function selectors of its dispatcher.
+// The analysis is bounded by the EVM call timeout, like eth_call.
+func (api *ErigonImpl) AnalyzeContract(ctx context.Context, address libcommon.Address, blockNrOrHash rpc.BlockNumberOrHash) (*vm.ContractAnalysis, error) {
+	if api.evmCallTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, api.evmCallTimeout)
+		defer cancel()
+	}
+	if err := analysisSem.Acquire(ctx, 1); err != nil {
+		return nil, err
+	}
+	defer analysisSem.Release(1)
+
+	tx, err := api.db.BeginRo(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("analyzeContract cannot open tx: %w", err)
+	}
+	defer tx.Rollback()
+	chainConfig, err := api.chainConfig(tx)
+	if err != nil {
+		return nil, fmt.Errorf("read chain config: %w", err)
+	}
+	reader, err := rpchelper.CreateStateReader(ctx, tx, blockNrOrHash, 0, api.filters, api.stateCache, api.historyV3(tx), chainConfig.ChainName)
+	if err != nil {
+		return nil, err
+	}
+
+	acc, err := reader.ReadAccountData(address)
+	if err != nil {
+		return nil, err
+	}
+	var code []byte
+	if acc != nil {
+		if code, err = reader.ReadAccountCode(address, acc.Incarnation, acc.CodeHash); err != nil {
+			return nil, err
+		}
+	}
+	tx.Rollback()
+
+	analysis := vm.AnalyzeContract(ctx, code)
+	if ctx.Err() != nil {
+		return nil, fmt.Errorf("analysis aborted (timeout = %v)", api.evmCallTimeout)
+	}
+	return analysis, nil
+}
@@ -12,6 +12,7 @@ import (
 
 	"github.com/ledgerwatch/erigon/common/hexutil"
 	"github.com/ledgerwatch/erigon/core/types"
+	"github.com/ledgerwatch/erigon/core/vm"
 	"github.com/ledgerwatch/erigon/p2p"
 	"github.com/ledgerwatch/erigon/rpc"
 	"github.com/ledgerwatch/erigon/turbo/rpchelper"
@@ -42,6 +43,9 @@ type ErigonAPI interface {
 
 	// NodeInfo returns a collection of metadata known about the host.
 	NodeInfo(ctx context.Context) ([]p2p.NodeInfo, error)
+
+	// Contract analysis (see ./erigon_analysis.go)
+	AnalyzeContract(ctx context.Context, address common.Address, blockNrOrHash rpc.BlockNumberOrHash) (*vm.ContractAnalysis, error)
 }
 
 // ErigonImpl is implementation of the ErigonAPI interface
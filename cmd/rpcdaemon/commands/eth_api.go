@@ -28,7 +28,9 @@ import (
 	"github.com/ledgerwatch/erigon/common/math"
 	"github.com/ledgerwatch/erigon/consensus"
 	"github.com/ledgerwatch/erigon/consensus/misc"
+	"github.com/ledgerwatch/erigon/core"
 	"github.com/ledgerwatch/erigon/core/rawdb"
+	"github.com/ledgerwatch/erigon/core/state"
 	"github.com/ledgerwatch/erigon/core/types"
 	"github.com/ledgerwatch/erigon/core/types/accounts"
 	ethFilters "github.com/ledgerwatch/erigon/eth/filters"
@@ -295,6 +297,34 @@ func (api *BaseAPI) checkPruneHistory(tx kv.Tx, block uint64) error {
 	return nil
 }
 
+// systemCallAt returns a system call on the state after the given block, read the same way
+// eth_call reads it. The consensus APIs use it to call the engine contracts.
+func (api *BaseAPI) systemCallAt(tx kv.Tx, blockNum uint64) (consensus.SystemCall, error) {
+	if err := api.checkPruneHistory(tx, blockNum); err != nil {
+		return nil, err
+	}
+	chainConfig, err := api.chainConfig(tx)
+	if err != nil {
+		return nil, err
+	}
+	header, err := api.headerByRPCNumber(rpc.BlockNumber(blockNum), tx)
+	if err != nil {
+		return nil, err
+	}
+	if header == nil {
+		return nil, fmt.Errorf("block %d not found", blockNum)
+	}
+	stateReader, err := rpchelper.CreateHistoryStateReader(tx, blockNum+1, 0, api.historyV3(tx), chainConfig.ChainName)
+	if err != nil {
+		return nil, err
+	}
+	ibs := state.New(stateReader)
+	engine := api.engine()
+	return func(contract common.Address, data []byte) ([]byte, error) {
+		return core.SysCallContract(contract, data, chainConfig, ibs, header, engine, true /* constCall */, header.ExcessDataGas)
+	}, nil
+}
+
 func (api *BaseAPI) pruneMode(tx kv.Tx) (*prune.Mode, error) {
 	p := api._pruneMode.Load()
 	if p != nil {
@@ -508,7 +508,7 @@ func (e *intraBlockExec) changeBlock(header *types.Header) {
 	e.blockHash = header.Hash()
 	e.header = header
 	e.rules = e.chainConfig.Rules(e.blockNum, header.Time)
-	e.signer = types.MakeSigner(e.chainConfig, e.blockNum)
+	e.signer = types.MakeSigner(e.chainConfig, e.blockNum, header.Time)
 	e.vmConfig.SkipAnalysis = core.SkipAnalysis(e.chainConfig, e.blockNum)
 }
 
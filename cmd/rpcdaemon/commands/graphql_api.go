@@ -1,34 +1,54 @@
 package commands
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"math/big"
 
+	jsoniter "github.com/json-iterator/go"
 	"github.com/ledgerwatch/erigon-lib/common"
 	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/ledgerwatch/erigon-lib/kv/rawdbv3"
+	"github.com/ledgerwatch/log/v3"
+
+	"github.com/ledgerwatch/erigon/common/debug"
 	"github.com/ledgerwatch/erigon/common/hexutil"
 	"github.com/ledgerwatch/erigon/core/rawdb"
+	"github.com/ledgerwatch/erigon/core/state"
 	"github.com/ledgerwatch/erigon/core/types"
+	"github.com/ledgerwatch/erigon/eth/filters"
+	"github.com/ledgerwatch/erigon/eth/tracers"
 	"github.com/ledgerwatch/erigon/rpc"
 	"github.com/ledgerwatch/erigon/turbo/adapter/ethapi"
 	"github.com/ledgerwatch/erigon/turbo/rpchelper"
 )
 
+// StorageRangeMaxResults is the maximum number of storage slots returned per storageRange query
+const StorageRangeMaxResults = 1024
+
 type GraphQLAPI interface {
 	GetBlockDetails(ctx context.Context, number rpc.BlockNumber) (map[string]interface{}, error)
 	GetChainID(ctx context.Context) (*big.Int, error)
+	GetPendingBlock(ctx context.Context) (map[string]interface{}, error)
+	GetStorageRange(ctx context.Context, number rpc.BlockNumber, address common.Address, start []byte, limit int) (StorageRangeResult, error)
+	GetCallTrace(ctx context.Context, hash common.Hash) (json.RawMessage, error)
+	SubscribeNewHeads(ctx context.Context) (<-chan *types.Header, error)
+	SubscribeLogs(ctx context.Context, crit filters.FilterCriteria) (<-chan *types.Log, error)
 }
 
 type GraphQLAPIImpl struct {
 	*BaseAPI
-	db kv.RoDB
+	db       kv.RoDB
+	debugAPI *PrivateDebugAPIImpl // traces the transactions
 }
 
-func NewGraphQLAPI(base *BaseAPI, db kv.RoDB) *GraphQLAPIImpl {
+func NewGraphQLAPI(base *BaseAPI, db kv.RoDB, debugAPI *PrivateDebugAPIImpl) *GraphQLAPIImpl {
 	return &GraphQLAPIImpl{
-		BaseAPI: base,
-		db:      db,
+		BaseAPI:  base,
+		db:       db,
+		debugAPI: debugAPI,
 	}
 }
 
@@ -139,3 +159,138 @@ func (api *GraphQLAPIImpl) delegateGetBlockByNumber(tx kv.Tx, b *types.Block, nu
 
 	return response, err
 }
+
+// GetPendingBlock returns the pending block with its transactions, nil if there is none.
+func (api *GraphQLAPIImpl) GetPendingBlock(ctx context.Context) (map[string]interface{}, error) {
+	block := api.pendingBlock()
+	if block == nil {
+		return nil, nil
+	}
+	response, err := ethapi.RPCMarshalBlock(block, true, true, map[string]interface{}{})
+	if err != nil {
+		return nil, err
+	}
+	response["transactionCount"] = block.Transactions().Len()
+	return response, nil
+}
+
+// GetStorageRange returns at most limit storage slots of the contract, from the start slot on,
+// at the end of the given block. The limit is capped by StorageRangeMaxResults.
+func (api *GraphQLAPIImpl) GetStorageRange(ctx context.Context, number rpc.BlockNumber, address common.Address, start []byte, limit int) (StorageRangeResult, error) {
+	if limit > StorageRangeMaxResults || limit <= 0 {
+		limit = StorageRangeMaxResults
+	}
+	tx, err := api.db.BeginRo(ctx)
+	if err != nil {
+		return StorageRangeResult{}, err
+	}
+	defer tx.Rollback()
+
+	if number == rpc.PendingBlockNumber {
+		return StorageRangeResult{}, fmt.Errorf("storage range for pending block not supported")
+	}
+	blockNumber, _, _, err := rpchelper.GetBlockNumber(rpc.BlockNumberOrHashWithNumber(number), tx, api.filters)
+	if err != nil {
+		return StorageRangeResult{}, err
+	}
+	if err := api.BaseAPI.checkPruneHistory(tx, blockNumber); err != nil {
+		return StorageRangeResult{}, err
+	}
+
+	if api.historyV3(tx) {
+		maxTxNum, err := rawdbv3.TxNums.Max(tx, blockNumber)
+		if err != nil {
+			return StorageRangeResult{}, err
+		}
+		return storageRangeAtV3(tx.(kv.TemporalTx), address, start, maxTxNum+1, limit)
+	}
+	// the state at the beginning of the next block
+	return storageRangeAt(state.NewPlainState(tx, blockNumber+1, nil), address, start, limit)
+}
+
+// GetCallTrace returns the call tree of the transaction built by the native call tracer, nil
+// if the transaction is not found.
+func (api *GraphQLAPIImpl) GetCallTrace(ctx context.Context, hash common.Hash) (json.RawMessage, error) {
+	tracer := "callTracer"
+	var buf bytes.Buffer
+	stream := jsoniter.NewStream(jsoniter.ConfigDefault, &buf, 4096)
+	if err := api.debugAPI.TraceTransaction(ctx, hash, &tracers.TraceConfig{Tracer: &tracer}, stream); err != nil {
+		return nil, err
+	}
+	if err := stream.Flush(); err != nil {
+		return nil, err
+	}
+	if bytes.Equal(buf.Bytes(), []byte("null")) {
+		return nil, nil
+	}
+	return buf.Bytes(), nil
+}
+
+// SubscribeNewHeads sends the headers of the new canonical blocks until the context is done.
+func (api *GraphQLAPIImpl) SubscribeNewHeads(ctx context.Context) (<-chan *types.Header, error) {
+	if api.filters == nil {
+		return nil, rpc.ErrNotificationsUnsupported
+	}
+	out := make(chan *types.Header, 1)
+	go func() {
+		defer debug.LogPanic()
+		defer close(out)
+		headers, id := api.filters.SubscribeNewHeads(32)
+		defer api.filters.UnsubscribeHeads(id)
+		for {
+			select {
+			case h, ok := <-headers:
+				if !ok {
+					log.Warn("new heads channel was closed")
+					return
+				}
+				if h == nil {
+					continue
+				}
+				select {
+				case out <- h:
+				case <-ctx.Done():
+					return
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out, nil
+}
+
+// SubscribeLogs sends the logs of the new canonical blocks matching the criteria until the
+// context is done.
+func (api *GraphQLAPIImpl) SubscribeLogs(ctx context.Context, crit filters.FilterCriteria) (<-chan *types.Log, error) {
+	if api.filters == nil {
+		return nil, rpc.ErrNotificationsUnsupported
+	}
+	out := make(chan *types.Log, 1)
+	go func() {
+		defer debug.LogPanic()
+		defer close(out)
+		logs, id := api.filters.SubscribeLogs(128, crit)
+		defer api.filters.UnsubscribeLogs(id)
+		for {
+			select {
+			case l, ok := <-logs:
+				if !ok {
+					log.Warn("log channel was closed")
+					return
+				}
+				if l == nil {
+					continue
+				}
+				select {
+				case out <- l:
+				case <-ctx.Done():
+					return
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out, nil
+}
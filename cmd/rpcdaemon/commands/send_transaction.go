@@ -50,8 +50,8 @@ func (api *APIImpl) SendRawTransaction(ctx context.Context, encodedTx hexutility
 	defer tx.Rollback()
 
 	// Print a log with full txn details for manual investigations and interventions
-	blockNum := rawdb.ReadCurrentBlockNumber(tx)
-	if blockNum == nil {
+	header := rawdb.ReadCurrentHeader(tx)
+	if header == nil {
 		return common.Hash{}, err
 	}
 	cc, err := api.chainConfig(tx)
@@ -66,7 +66,7 @@ func (api *APIImpl) SendRawTransaction(ctx context.Context, encodedTx hexutility
 		return common.Hash{}, fmt.Errorf("invalid chain id, expected: %d got: %d", chainId, *txnChainId)
 	}
 
-	signer := types.MakeSigner(cc, *blockNum)
+	signer := types.MakeSigner(cc, header.Number.Uint64(), header.Time)
 	from, err := txn.Sender(*signer)
 	if err != nil {
 		return common.Hash{}, err
@@ -95,7 +95,7 @@ func (api *TraceAPIImpl) Transaction(ctx context.Context, txHash common.Hash, ga
 	hash := block.Hash()
 
 	// Returns an array of trace arrays, one trace array for each transaction
-	traces, err := api.callManyTransactions(ctx, tx, block, []string{TraceTypeTrace}, txIndex, *gasBailOut, types.MakeSigner(chainConfig, blockNumber), chainConfig)
+	traces, err := api.callManyTransactions(ctx, tx, block, []string{TraceTypeTrace}, txIndex, *gasBailOut, types.MakeSigner(chainConfig, blockNumber, block.Time()), chainConfig)
 	if err != nil {
 		return nil, err
 	}
@@ -189,7 +189,7 @@ func (api *TraceAPIImpl) Block(ctx context.Context, blockNr rpc.BlockNumber, gas
 		return nil, err
 	}
 	txnIndex := -1 // all tx indices
-	traces, err := api.callManyTransactions(ctx, tx, block, []string{TraceTypeTrace}, txnIndex, *gasBailOut /* gasBailOut */, types.MakeSigner(cfg, blockNum), cfg)
+	traces, err := api.callManyTransactions(ctx, tx, block, []string{TraceTypeTrace}, txnIndex, *gasBailOut /* gasBailOut */, types.MakeSigner(cfg, blockNum, block.Time()), cfg)
 	if err != nil {
 		return nil, err
 	}
@@ -455,7 +455,7 @@ func (api *TraceAPIImpl) Filter(ctx context.Context, req TraceFilterRequest, gas
 			isPos = header.Difficulty.Cmp(common.Big0) == 0 || header.Difficulty.Cmp(chainConfig.TerminalTotalDifficulty) >= 0
 		}
 		txs := block.Transactions()
-		t, tErr := api.callManyTransactions(ctx, dbtx, block, []string{TraceTypeTrace}, -1 /* all tx indices */, *gasBailOut, types.MakeSigner(chainConfig, b), chainConfig)
+		t, tErr := api.callManyTransactions(ctx, dbtx, block, []string{TraceTypeTrace}, -1 /* all tx indices */, *gasBailOut, types.MakeSigner(chainConfig, b, block.Time()), chainConfig)
 		if tErr != nil {
 			if first {
 				first = false
@@ -690,7 +690,7 @@ func (api *TraceAPIImpl) filterV3(ctx context.Context, dbtx kv.TemporalTx, fromB
 			}
 
 			lastBlockHash = lastHeader.Hash()
-			lastSigner = types.MakeSigner(chainConfig, blockNum)
+			lastSigner = types.MakeSigner(chainConfig, blockNum, lastHeader.Time)
 			lastRules = chainConfig.Rules(blockNum, lastHeader.Time)
 		}
 		if isFnalTxn {
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"math/big"
+	"path/filepath"
 	"time"
 
 	"github.com/holiman/uint256"
@@ -27,6 +28,9 @@ import (
 	"github.com/ledgerwatch/erigon/turbo/transactions"
 )
 
+// parallelTraceMinTxs is the number of transactions from which blocks are traced in parallel.
+var parallelTraceMinTxs = 16
+
 // TraceBlockByNumber implements debug_traceBlockByNumber. Returns Geth style block traces.
 func (api *PrivateDebugAPIImpl) TraceBlockByNumber(ctx context.Context, blockNum rpc.BlockNumber, config *tracers.TraceConfig, stream *jsoniter.Stream) error {
 	return api.traceBlock(ctx, rpc.BlockNumberOrHashWithNumber(blockNum), config, stream)
@@ -38,6 +42,7 @@ func (api *PrivateDebugAPIImpl) TraceBlockByHash(ctx context.Context, hash commo
 }
 
 func (api *PrivateDebugAPIImpl) traceBlock(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash, config *tracers.TraceConfig, stream *jsoniter.Stream) error {
+	config = api.withSourcesDir(config)
 	tx, err := api.db.BeginRo(ctx)
 	if err != nil {
 		stream.WriteNil()
@@ -103,15 +108,14 @@ func (api *PrivateDebugAPIImpl) traceBlock(ctx context.Context, blockNrOrHash rp
 	}
 	engine := api.engine()
 
-	_, blockCtx, _, ibs, _, err := transactions.ComputeTxEnv(ctx, engine, block, chainConfig, api._blockReader, tx, 0, api.historyV3(tx))
+	_, blockCtx, _, ibs, reader, err := transactions.ComputeTxEnv(ctx, engine, block, chainConfig, api._blockReader, tx, 0, api.historyV3(tx))
 	if err != nil {
 		stream.WriteNil()
 		return err
 	}
 
-	signer := types.MakeSigner(chainConfig, block.NumberU64())
+	signer := types.MakeSigner(chainConfig, block.NumberU64(), block.Time())
 	rules := chainConfig.Rules(block.NumberU64(), block.Time())
-	stream.WriteArrayStart()
 
 	borTx, _, _, _ := rawdb.ReadBorTransactionForBlock(tx, block)
 	txns := block.Transactions()
@@ -119,6 +123,17 @@ func (api *PrivateDebugAPIImpl) traceBlock(ctx context.Context, blockNrOrHash rp
 		txns = append(txns, borTx)
 	}
 
+	// the state sync transactions of Bor are only traced sequentially
+	var traces []transactions.TxTrace
+	if api.ParallelTraceWorkers > 1 && len(txns) >= parallelTraceMinTxs && len(txns) == len(block.Transactions()) {
+		traces, err = transactions.TraceTxsParallel(ctx, api.db, engine, block, chainConfig, blockCtx, ibs, reader, excessDataGas, api.historyV3(tx), config, api.evmCallTimeout, stream.Pool(), api.ParallelTraceWorkers)
+		if err != nil {
+			stream.WriteNil()
+			return err
+		}
+	}
+
+	stream.WriteArrayStart()
 	for idx, txn := range txns {
 		stream.WriteObjectStart()
 		stream.WriteObjectField("result")
@@ -128,31 +143,36 @@ func (api *PrivateDebugAPIImpl) traceBlock(ctx context.Context, blockNrOrHash rp
 			stream.WriteNil()
 			return ctx.Err()
 		}
-		ibs.SetTxContext(txn.Hash(), block.Hash(), idx)
-		msg, _ := txn.AsMessage(*signer, block.BaseFee(), rules)
-
-		if msg.FeeCap().IsZero() && engine != nil {
-			syscall := func(contract common.Address, data []byte) ([]byte, error) {
-				return core.SysCallContract(contract, data, chainConfig, ibs, block.Header(), engine, true /* constCall */, excessDataGas)
+		if traces != nil {
+			stream.Write(traces[idx].Output)
+			err = traces[idx].Err
+		} else {
+			ibs.SetTxContext(txn.Hash(), block.Hash(), idx)
+			msg, _ := txn.AsMessage(*signer, block.BaseFee(), rules)
+
+			if msg.FeeCap().IsZero() && engine != nil {
+				syscall := func(contract common.Address, data []byte) ([]byte, error) {
+					return core.SysCallContract(contract, data, chainConfig, ibs, block.Header(), engine, true /* constCall */, excessDataGas)
+				}
+				msg.SetIsFree(engine.IsServiceTransaction(msg.From(), syscall))
 			}
-			msg.SetIsFree(engine.IsServiceTransaction(msg.From(), syscall))
-		}
 
-		txCtx := evmtypes.TxContext{
-			TxHash:   txn.Hash(),
-			Origin:   msg.From(),
-			GasPrice: msg.GasPrice(),
-		}
+			txCtx := evmtypes.TxContext{
+				TxHash:   txn.Hash(),
+				Origin:   msg.From(),
+				GasPrice: msg.GasPrice(),
+			}
 
-		if borTx != nil && idx == len(txns)-1 {
-			if *config.BorTraceEnabled {
-				config.BorTx = newBoolPtr(true)
+			if borTx != nil && idx == len(txns)-1 {
+				if *config.BorTraceEnabled {
+					config.BorTx = newBoolPtr(true)
+				}
 			}
-		}
 
-		err = transactions.TraceTx(ctx, msg, blockCtx, txCtx, ibs, config, chainConfig, stream, api.evmCallTimeout)
-		if err == nil {
-			err = ibs.FinalizeTx(rules, state.NewNoopWriter())
+			err = transactions.TraceTx(ctx, msg, blockCtx, txCtx, ibs, config, chainConfig, stream, api.evmCallTimeout)
+			if err == nil {
+				err = ibs.FinalizeTx(rules, state.NewNoopWriter())
+			}
 		}
 		stream.WriteObjectEnd()
 
@@ -178,6 +198,7 @@ func (api *PrivateDebugAPIImpl) traceBlock(ctx context.Context, blockNrOrHash rp
 
 // TraceTransaction implements debug_traceTransaction. Returns Geth style transaction traces.
 func (api *PrivateDebugAPIImpl) TraceTransaction(ctx context.Context, hash common.Hash, config *tracers.TraceConfig, stream *jsoniter.Stream) error {
+	config = api.withSourcesDir(config)
 	tx, err := api.db.BeginRo(ctx)
 	if err != nil {
 		stream.WriteNil()
@@ -264,6 +285,7 @@ func (api *PrivateDebugAPIImpl) TraceTransaction(ctx context.Context, hash commo
 }
 
 func (api *PrivateDebugAPIImpl) TraceCall(ctx context.Context, args ethapi.CallArgs, blockNrOrHash rpc.BlockNumberOrHash, config *tracers.TraceConfig, stream *jsoniter.Stream) error {
+	config = api.withSourcesDir(config)
 	dbtx, err := api.db.BeginRo(ctx)
 	if err != nil {
 		return fmt.Errorf("create ro transaction: %v", err)
@@ -335,6 +357,7 @@ func (api *PrivateDebugAPIImpl) TraceCallMany(ctx context.Context, bundles []Bun
 		baseFee            uint256.Int
 	)
 
+	config = api.withSourcesDir(config)
 	if config == nil {
 		config = &tracers.TraceConfig{}
 	}
@@ -444,7 +467,7 @@ func (api *PrivateDebugAPIImpl) TraceCallMany(ctx context.Context, bundles []Bun
 
 	// Get a new instance of the EVM
 	evm = vm.NewEVM(blockCtx, txCtx, st, chainConfig, vm.Config{Debug: false})
-	signer := types.MakeSigner(chainConfig, blockNum)
+	signer := types.MakeSigner(chainConfig, blockNum, blockCtx.Time)
 	rules := chainConfig.Rules(blockNum, blockCtx.Time)
 
 	// Setup the gas pool (also for unmetered requests)
@@ -524,3 +547,16 @@ func newBoolPtr(bb bool) *bool {
 	b := bb
 	return &b
 }
+
+// withSourcesDir lets the struct logger read the Solidity sources missing from the request
+// from the sources directory of the node, <datadir>/sources.
+func (api *PrivateDebugAPIImpl) withSourcesDir(config *tracers.TraceConfig) *tracers.TraceConfig {
+	if config == nil || config.LogConfig == nil || config.LogConfig.Sources == nil || api.dirs.DataDir == "" {
+		return config
+	}
+	cfg, logCfg, sources := *config, *config.LogConfig, *config.LogConfig.Sources
+	sources.Dir = filepath.Join(api.dirs.DataDir, "sources")
+	logCfg.Sources = &sources
+	cfg.LogConfig = &logCfg
+	return &cfg
+}
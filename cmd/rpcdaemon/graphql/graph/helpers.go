@@ -5,14 +5,17 @@ import (
 	"fmt"
 	"reflect"
 	"strconv"
+	"strings"
 
 	"github.com/holiman/uint256"
 
 	libcommon "github.com/ledgerwatch/erigon-lib/common"
 	"github.com/ledgerwatch/erigon-lib/common/hexutility"
 
+	"github.com/ledgerwatch/erigon/cmd/rpcdaemon/graphql/graph/model"
 	"github.com/ledgerwatch/erigon/common/hexutil"
 	"github.com/ledgerwatch/erigon/core/types"
+	"github.com/ledgerwatch/erigon/turbo/adapter/ethapi"
 )
 
 func convertDataToStringP(abstractMap map[string]interface{}, field string) *string {
@@ -134,3 +137,156 @@ func convertStrHexToDec(hexString *string) *string {
 
 	return &result
 }
+
+func convertLog(rlog *types.Log) *model.Log {
+	tlog := &model.Log{
+		Index: int(rlog.Index),
+		Data:  "0x" + hex.EncodeToString(rlog.Data),
+	}
+	tlog.Account = &model.Account{}
+	tlog.Account.Address = strings.ToLower(rlog.Address.String())
+
+	for _, rtopic := range rlog.Topics {
+		tlog.Topics = append(tlog.Topics, rtopic.String())
+	}
+
+	tlog.Transaction = &model.Transaction{}
+	tlog.Transaction.Hash = rlog.TxHash.String()
+	index := int(rlog.TxIndex)
+	tlog.Transaction.Index = &index
+
+	return tlog
+}
+
+func convertFilterCriteria(filter *model.BlockFilterCriteria) (map[libcommon.Address]struct{}, [][]libcommon.Hash) {
+	addresses := map[libcommon.Address]struct{}{}
+	var topics [][]libcommon.Hash
+	if filter == nil {
+		return addresses, topics
+	}
+	for _, address := range filter.Addresses {
+		addresses[libcommon.HexToAddress(address)] = struct{}{}
+	}
+	for _, topicSet := range filter.Topics {
+		hashes := make([]libcommon.Hash, 0, len(topicSet))
+		for _, topic := range topicSet {
+			hashes = append(hashes, libcommon.HexToHash(topic))
+		}
+		topics = append(topics, hashes)
+	}
+	return addresses, topics
+}
+
+func convertHeader(header *types.Header) *model.Block {
+	block := &model.Block{}
+	block.Number = header.Number.Uint64()
+	block.Hash = header.Hash().String()
+	block.Parent = &model.Block{}
+	block.Parent.Hash = header.ParentHash.String()
+	block.Nonce = "0x" + fmt.Sprintf("%016x", header.Nonce.Uint64())
+	block.TransactionsRoot = header.TxHash.String()
+	block.StateRoot = header.Root.String()
+	block.ReceiptsRoot = header.ReceiptHash.String()
+	block.Miner = &model.Account{}
+	block.Miner.Address = strings.ToLower(header.Coinbase.String())
+	block.ExtraData = "0x" + hex.EncodeToString(header.Extra)
+	block.GasLimit = header.GasLimit
+	block.GasUsed = header.GasUsed
+	if header.BaseFee != nil {
+		baseFee := (*hexutil.Big)(header.BaseFee).String()
+		block.BaseFeePerGas = &baseFee
+	}
+	block.Timestamp = hexutil.Uint64(header.Time).String()
+	block.LogsBloom = "0x" + hex.EncodeToString(header.Bloom.Bytes())
+	block.MixHash = header.MixDigest.String()
+	block.Difficulty = (*hexutil.Big)(header.Difficulty).String()
+	block.OmmerHash = header.UncleHash.String()
+	block.Ommers = []*model.Block{}
+	block.Transactions = []*model.Transaction{}
+	return block
+}
+
+func convertRPCTransaction(rpcTx *ethapi.RPCTransaction) *model.Transaction {
+	trans := &model.Transaction{}
+	trans.Hash = rpcTx.Hash.String()
+	trans.Nonce = rpcTx.Nonce.String()
+	if rpcTx.TransactionIndex != nil {
+		index := int(*rpcTx.TransactionIndex)
+		trans.Index = &index
+	}
+	trans.From = &model.Account{}
+	trans.From.Address = strings.ToLower(rpcTx.From.String())
+	trans.To = &model.Account{}
+	// To address could be nil in case of contract creation
+	if rpcTx.To != nil {
+		trans.To.Address = strings.ToLower(rpcTx.To.String())
+	}
+	trans.Value = rpcTx.Value.String()
+	if rpcTx.GasPrice != nil {
+		trans.GasPrice = rpcTx.GasPrice.String()
+	}
+	if rpcTx.FeeCap != nil {
+		feeCap := rpcTx.FeeCap.String()
+		trans.MaxFeePerGas = &feeCap
+	}
+	if rpcTx.Tip != nil {
+		tip := rpcTx.Tip.String()
+		trans.MaxPriorityFeePerGas = &tip
+	}
+	trans.Gas = uint64(rpcTx.Gas)
+	trans.InputData = rpcTx.Input.String()
+	txType := int(rpcTx.Type)
+	trans.Type = &txType
+	trans.R = rpcTx.R.String()
+	trans.S = rpcTx.S.String()
+	trans.V = rpcTx.V.String()
+	trans.Logs = []*model.Log{}
+	return trans
+}
+
+// callFrame is a call frame of the native call tracer, which encodes its numbers in hex.
+type callFrame struct {
+	Type         string             `json:"type"`
+	From         libcommon.Address  `json:"from"`
+	To           *libcommon.Address `json:"to"`
+	Value        *hexutil.Big       `json:"value"`
+	Gas          hexutil.Uint64     `json:"gas"`
+	GasUsed      hexutil.Uint64     `json:"gasUsed"`
+	Input        hexutility.Bytes   `json:"input"`
+	Output       *hexutility.Bytes  `json:"output"`
+	Error        string             `json:"error"`
+	RevertReason string             `json:"revertReason"`
+	Calls        []callFrame        `json:"calls"`
+}
+
+func convertCallFrame(frame *callFrame) *model.CallFrame {
+	result := &model.CallFrame{
+		Type:    frame.Type,
+		From:    strings.ToLower(frame.From.String()),
+		Gas:     uint64(frame.Gas),
+		GasUsed: uint64(frame.GasUsed),
+		Input:   frame.Input.String(),
+	}
+	if frame.To != nil {
+		to := strings.ToLower(frame.To.String())
+		result.To = &to
+	}
+	if frame.Value != nil {
+		value := frame.Value.String()
+		result.Value = &value
+	}
+	if frame.Output != nil {
+		output := frame.Output.String()
+		result.Output = &output
+	}
+	if frame.Error != "" {
+		result.Error = &frame.Error
+	}
+	if frame.RevertReason != "" {
+		result.RevertReason = &frame.RevertReason
+	}
+	for i := range frame.Calls {
+		result.Calls = append(result.Calls, convertCallFrame(&frame.Calls[i]))
+	}
+	return result
+}
@@ -8,11 +8,12 @@ type AccessTuple struct {
 }
 
 type Account struct {
-	Address          string `json:"address"`
-	Balance          string `json:"balance"`
-	TransactionCount uint64 `json:"transactionCount"`
-	Code             string `json:"code"`
-	Storage          string `json:"storage"`
+	Address          string        `json:"address"`
+	Balance          string        `json:"balance"`
+	TransactionCount uint64        `json:"transactionCount"`
+	Code             string        `json:"code"`
+	Storage          string        `json:"storage"`
+	StorageRange     *StorageRange `json:"storageRange"`
 }
 
 type Block struct {
@@ -65,6 +66,20 @@ type CallData struct {
 	Data                 *string `json:"data"`
 }
 
+type CallFrame struct {
+	Type         string       `json:"type"`
+	From         string       `json:"from"`
+	To           *string      `json:"to"`
+	Value        *string      `json:"value"`
+	Gas          uint64       `json:"gas"`
+	GasUsed      uint64       `json:"gasUsed"`
+	Input        string       `json:"input"`
+	Output       *string      `json:"output"`
+	Error        *string      `json:"error"`
+	RevertReason *string      `json:"revertReason"`
+	Calls        []*CallFrame `json:"calls"`
+}
+
 type CallResult struct {
 	Data    string `json:"data"`
 	GasUsed uint64 `json:"gasUsed"`
@@ -94,6 +109,16 @@ type Pending struct {
 	EstimateGas      uint64         `json:"estimateGas"`
 }
 
+type StorageRange struct {
+	Slots   []*StorageSlot `json:"slots"`
+	NextKey *string        `json:"nextKey"`
+}
+
+type StorageSlot struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
 type SyncState struct {
 	StartingBlock uint64 `json:"startingBlock"`
 	CurrentBlock  uint64 `json:"currentBlock"`
@@ -127,4 +152,5 @@ type Transaction struct {
 	AccessList           []*AccessTuple `json:"accessList"`
 	Raw                  string         `json:"raw"`
 	RawReceipt           string         `json:"rawReceipt"`
+	Trace                *CallFrame     `json:"trace"`
 }
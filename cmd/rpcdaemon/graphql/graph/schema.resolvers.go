@@ -6,17 +6,86 @@ package graph
 
 import (
 	"context"
-	"encoding/hex"
+	"encoding/json"
 	"fmt"
+	"sort"
 	"strconv"
 	"strings"
 
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+
 	"github.com/ledgerwatch/erigon/cmd/rpcdaemon/graphql/graph/model"
 	"github.com/ledgerwatch/erigon/common/hexutil"
 	"github.com/ledgerwatch/erigon/core/types"
+	"github.com/ledgerwatch/erigon/eth/filters"
 	"github.com/ledgerwatch/erigon/rpc"
+	"github.com/ledgerwatch/erigon/turbo/adapter/ethapi"
 )
 
+// StorageRange is the resolver for the storageRange field.
+func (r *accountResolver) StorageRange(ctx context.Context, obj *model.Account, start *string, limit int, block *uint64) (*model.StorageRange, error) {
+	blockNumber := rpc.LatestBlockNumber
+	if block != nil {
+		blockNumber = rpc.BlockNumber(*block)
+	}
+	var startKey []byte
+	if start != nil {
+		startKey = libcommon.HexToHash(*start).Bytes()
+	}
+
+	res, err := r.GraphQLAPI.GetStorageRange(ctx, blockNumber, libcommon.HexToAddress(obj.Address), startKey, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	// the range is walked in the order of the slots, not of their hashes the storage is keyed by
+	storageRange := &model.StorageRange{Slots: make([]*model.StorageSlot, 0, len(res.Storage))}
+	for seckey, entry := range res.Storage {
+		slot := &model.StorageSlot{Key: seckey.String(), Value: entry.Value.String()}
+		if entry.Key != nil {
+			slot.Key = entry.Key.String()
+		}
+		storageRange.Slots = append(storageRange.Slots, slot)
+	}
+	sort.Slice(storageRange.Slots, func(i, j int) bool { return storageRange.Slots[i].Key < storageRange.Slots[j].Key })
+	if res.NextKey != nil {
+		nextKey := res.NextKey.String()
+		storageRange.NextKey = &nextKey
+	}
+
+	return storageRange, ctx.Err()
+}
+
+// Logs is the resolver for the logs field.
+func (r *blockResolver) Logs(ctx context.Context, obj *model.Block, filter model.BlockFilterCriteria, first *int, after *int) ([]*model.Log, error) {
+	res, err := r.GraphQLAPI.GetBlockDetails(ctx, rpc.BlockNumber(obj.Number))
+	if err != nil {
+		return nil, err
+	}
+
+	var logs types.Logs
+	if absRcp, ok := res["receipts"]; ok {
+		for _, transReceipt := range absRcp.([]map[string]interface{}) {
+			logs = append(logs, transReceipt["logs"].(types.Logs)...)
+		}
+	}
+	addresses, topics := convertFilterCriteria(&filter)
+	logs = logs.Filter(addresses, topics)
+
+	result := make([]*model.Log, 0, len(logs))
+	for _, rlog := range logs {
+		if after != nil && int(rlog.Index) <= *after {
+			continue
+		}
+		if first != nil && len(result) >= *first {
+			break
+		}
+		result = append(result, convertLog(rlog))
+	}
+
+	return result, ctx.Err()
+}
+
 // SendRawTransaction is the resolver for the sendRawTransaction field.
 func (r *mutationResolver) SendRawTransaction(ctx context.Context, data string) (string, error) {
 	panic(fmt.Errorf("not implemented: SendRawTransaction - sendRawTransaction"))
@@ -128,18 +197,7 @@ func (r *queryResolver) Block(ctx context.Context, number *string, hash *string)
 
 			trans.Logs = make([]*model.Log, 0)
 			for _, rlog := range transReceipt["logs"].(types.Logs) {
-				tlog := model.Log{
-					Index: int(rlog.Index),
-					Data:  "0x" + hex.EncodeToString(rlog.Data),
-				}
-				tlog.Account = &model.Account{}
-				tlog.Account.Address = strings.ToLower(rlog.Address.String())
-
-				for _, rtopic := range rlog.Topics {
-					tlog.Topics = append(tlog.Topics, rtopic.String())
-				}
-
-				trans.Logs = append(trans.Logs, &tlog)
+				trans.Logs = append(trans.Logs, convertLog(rlog))
 			}
 
 			trans.From = &model.Account{}
@@ -166,7 +224,24 @@ func (r *queryResolver) Blocks(ctx context.Context, from *uint64, to *uint64) ([
 
 // Pending is the resolver for the pending field.
 func (r *queryResolver) Pending(ctx context.Context) (*model.Pending, error) {
-	panic(fmt.Errorf("not implemented: Pending - pending"))
+	res, err := r.GraphQLAPI.GetPendingBlock(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	pending := &model.Pending{}
+	pending.Transactions = []*model.Transaction{}
+	if res == nil {
+		// No block is being mined
+		return pending, ctx.Err()
+	}
+
+	pending.TransactionCount = *convertDataToIntP(res, "transactionCount")
+	for _, abstractTx := range res["transactions"].([]interface{}) {
+		pending.Transactions = append(pending.Transactions, convertRPCTransaction(abstractTx.(*ethapi.RPCTransaction)))
+	}
+
+	return pending, ctx.Err()
 }
 
 // Transaction is the resolver for the transaction field.
@@ -201,11 +276,96 @@ func (r *queryResolver) ChainID(ctx context.Context) (string, error) {
 	return "0x" + strconv.FormatUint(chainID.Uint64(), 16), err
 }
 
+// NewHeads is the resolver for the newHeads field.
+func (r *subscriptionResolver) NewHeads(ctx context.Context) (<-chan *model.Block, error) {
+	headers, err := r.GraphQLAPI.SubscribeNewHeads(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	blocks := make(chan *model.Block, 1)
+	go func() {
+		defer close(blocks)
+		for header := range headers {
+			select {
+			case blocks <- convertHeader(header):
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return blocks, nil
+}
+
+// Logs is the resolver for the logs field.
+func (r *subscriptionResolver) Logs(ctx context.Context, filter *model.BlockFilterCriteria) (<-chan *model.Log, error) {
+	addresses, topics := convertFilterCriteria(filter)
+	crit := filters.FilterCriteria{Topics: topics}
+	for address := range addresses {
+		crit.Addresses = append(crit.Addresses, address)
+	}
+
+	logs, err := r.GraphQLAPI.SubscribeLogs(ctx, crit)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(chan *model.Log, 1)
+	go func() {
+		defer close(result)
+		for rlog := range logs {
+			select {
+			case result <- convertLog(rlog):
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return result, nil
+}
+
+// Trace is the resolver for the trace field.
+func (r *transactionResolver) Trace(ctx context.Context, obj *model.Transaction) (*model.CallFrame, error) {
+	res, err := r.GraphQLAPI.GetCallTrace(ctx, libcommon.HexToHash(obj.Hash))
+	if err != nil {
+		return nil, err
+	}
+	if res == nil {
+		// The transaction is not mined yet
+		return nil, ctx.Err()
+	}
+
+	var frame callFrame
+	if err := json.Unmarshal(res, &frame); err != nil {
+		return nil, err
+	}
+
+	return convertCallFrame(&frame), ctx.Err()
+}
+
+// Account returns AccountResolver implementation.
+func (r *Resolver) Account() AccountResolver { return &accountResolver{r} }
+
+// Block returns BlockResolver implementation.
+func (r *Resolver) Block() BlockResolver { return &blockResolver{r} }
+
 // Mutation returns MutationResolver implementation.
 func (r *Resolver) Mutation() MutationResolver { return &mutationResolver{r} }
 
 // Query returns QueryResolver implementation.
 func (r *Resolver) Query() QueryResolver { return &queryResolver{r} }
 
+// Subscription returns SubscriptionResolver implementation.
+func (r *Resolver) Subscription() SubscriptionResolver { return &subscriptionResolver{r} }
+
+// Transaction returns TransactionResolver implementation.
+func (r *Resolver) Transaction() TransactionResolver { return &transactionResolver{r} }
+
+type accountResolver struct{ *Resolver }
+type blockResolver struct{ *Resolver }
 type mutationResolver struct{ *Resolver }
 type queryResolver struct{ *Resolver }
+type subscriptionResolver struct{ *Resolver }
+type transactionResolver struct{ *Resolver }
@@ -1,11 +1,33 @@
 package graphql
 
 import (
+	"encoding/json"
+	"fmt"
 	"io"
 	"net/http"
+	"net/http/httptest"
 	"regexp"
 	"strings"
 	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/common/hexutility"
+	"github.com/ledgerwatch/erigon-lib/gointerfaces"
+	"github.com/ledgerwatch/erigon-lib/gointerfaces/remote"
+	types2 "github.com/ledgerwatch/erigon-lib/gointerfaces/types"
+	"github.com/ledgerwatch/erigon-lib/kv/kvcache"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ledgerwatch/erigon/cmd/rpcdaemon/commands"
+	"github.com/ledgerwatch/erigon/cmd/rpcdaemon/rpcdaemontest"
+	"github.com/ledgerwatch/erigon/core"
+	"github.com/ledgerwatch/erigon/rlp"
+	"github.com/ledgerwatch/erigon/rpc"
+	"github.com/ledgerwatch/erigon/rpc/rpccfg"
+	"github.com/ledgerwatch/erigon/turbo/rpchelper"
+	"github.com/ledgerwatch/erigon/turbo/snapshotsync"
 )
 
 func TestGraphQLQueryBlock(t *testing.T) {
@@ -80,6 +102,17 @@ func TestGraphQLQueryBlock(t *testing.T) {
 			want: `{"data":{"block":null}}`,
 			code: 200,
 		},
+		{ // Should return at most one log of genesis block
+			body: `{"query": "{block(number:0){logs(filter:{}, first:1){index}}}","variables": null}`,
+			want: `{"data":{"block":{"logs":[]}}}`,
+			code: 200,
+		},
+		{
+			body: `{"query": "{pending{transactionCount}}","variables": null}`,
+			want: `{"data":{"pending":{"transactionCount":\d+}}}`,
+			code: 200,
+			comp: "regexp",
+		},
 		{
 			body: `{"query": "{bleh{number}}","variables": null}"`,
 			want: `{"errors":[{"message":"Cannot query field \"bleh\" on type \"Query\".","locations":[{"line":1,"column":2}],"extensions":{"code":"GRAPHQL_VALIDATION_FAILED"}}],"data":null}`,
@@ -126,3 +159,282 @@ func TestGraphQLQueryBlock(t *testing.T) {
 		}
 	}
 }
+
+// createTestServer serves the GraphQL API over the chain of rpcdaemontest.CreateTestSentry, the
+// events of the returned filters being sent to the subscriptions.
+func createTestServer(t *testing.T) (*httptest.Server, *rpchelper.Filters, *core.ChainPack) {
+	m, chain, _ := rpcdaemontest.CreateTestSentry(t)
+	ff := rpchelper.New(m.Ctx, nil, nil, nil, func() {}, m.Log)
+	br := snapshotsync.NewBlockReaderWithSnapshots(m.BlockSnapshots, m.TransactionsV3)
+	stateCache := kvcache.New(kvcache.DefaultCoherentConfig)
+	base := commands.NewBaseApi(ff, stateCache, br, m.HistoryV3Components(), false, rpccfg.DefaultEvmCallTimeout, m.Engine, m.Dirs)
+	api := commands.NewGraphQLAPI(base, m.DB, commands.NewPrivateDebugAPI(base, m.DB, 0))
+	srv := httptest.NewServer(CreateHandler([]rpc.API{{Namespace: "graphql", Service: api}}))
+	t.Cleanup(srv.Close)
+	return srv, ff, chain
+}
+
+func query(t *testing.T, srv *httptest.Server, query string, data interface{}) {
+	t.Helper()
+	body, err := json.Marshal(map[string]string{"query": query})
+	require.NoError(t, err)
+	resp, err := http.Post(srv.URL, "application/json", strings.NewReader(string(body)))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	var res struct {
+		Data   json.RawMessage `json:"data"`
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&res))
+	require.Empty(t, res.Errors, query)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.NoError(t, json.Unmarshal(res.Data, data))
+}
+
+func TestGraphQLLogsPagination(t *testing.T) {
+	srv, _, _ := createTestServer(t)
+	var all struct {
+		Block struct {
+			Logs []struct{ Index int }
+		}
+	}
+	// block 7 mints tokens, then transfers them to 32 accounts
+	query(t, srv, `{block(number:"7"){logs(filter:{}){index}}}`, &all)
+	require.Greater(t, len(all.Block.Logs), 10)
+	for i, l := range all.Block.Logs {
+		require.Equal(t, i, l.Index)
+	}
+
+	var page struct {
+		Block struct {
+			Logs []struct{ Index int }
+		}
+	}
+	query(t, srv, `{block(number:"7"){logs(filter:{},first:5){index}}}`, &page)
+	require.Equal(t, all.Block.Logs[:5], page.Block.Logs)
+
+	var paged []struct{ Index int }
+	after := -1
+	for {
+		query(t, srv, fmt.Sprintf(`{block(number:"7"){logs(filter:{},first:4,after:%d){index}}}`, after), &page)
+		require.LessOrEqual(t, len(page.Block.Logs), 4)
+		if len(page.Block.Logs) == 0 {
+			break
+		}
+		paged = append(paged, page.Block.Logs...)
+		after = page.Block.Logs[len(page.Block.Logs)-1].Index
+	}
+	require.Equal(t, all.Block.Logs, paged)
+
+	query(t, srv, fmt.Sprintf(`{block(number:"7"){logs(filter:{},after:%d){index}}}`, len(all.Block.Logs)-1), &page)
+	require.Empty(t, page.Block.Logs)
+}
+
+func TestGraphQLTransactionTrace(t *testing.T) {
+	srv, _, chain := createTestServer(t)
+	var res struct {
+		Block struct {
+			Transactions []struct {
+				Hash    string
+				GasUsed uint64
+				From    struct{ Address string }
+				To      struct{ Address string }
+				Trace   struct {
+					Type    string
+					From    string
+					To      string
+					GasUsed uint64
+					Input   string
+					Calls   []struct{ Type string }
+				}
+			}
+		}
+	}
+	// block 4 mints tokens
+	query(t, srv, `{block(number:"4"){transactions{hash gasUsed from{address} to{address} trace{type from to gasUsed input calls{type}}}}}`, &res)
+	txn := chain.Blocks[3].Transactions()[0]
+	require.Len(t, res.Block.Transactions, 1)
+	tx := res.Block.Transactions[0]
+	require.Equal(t, txn.Hash().String(), tx.Hash)
+	require.Equal(t, "CALL", tx.Trace.Type)
+	require.Equal(t, tx.From.Address, tx.Trace.From)
+	require.Equal(t, strings.ToLower(txn.GetTo().String()), tx.Trace.To)
+	require.Equal(t, tx.To.Address, tx.Trace.To)
+	require.Equal(t, tx.GasUsed, tx.Trace.GasUsed)
+	require.Equal(t, hexutility.Bytes(txn.GetData()).String(), tx.Trace.Input)
+	require.Empty(t, tx.Trace.Calls)
+}
+
+func TestGraphQLStorageRange(t *testing.T) {
+	srv, _, _ := createTestServer(t)
+	type storageRange struct {
+		Slots []struct {
+			Key   string
+			Value string
+		}
+		NextKey *string
+	}
+	var res struct {
+		Block struct {
+			Transactions []struct {
+				To struct {
+					StorageRange storageRange
+				}
+			}
+		}
+	}
+	// the second transaction of block 8 is a transfer of the tokens deployed in block 7,
+	// which holds the balances of more than 30 accounts
+	rangeQuery := func(start string, limit int) storageRange {
+		query(t, srv, fmt.Sprintf(`{block(number:"8"){transactions{to{storageRange(%slimit:%d,block:8){slots{key value} nextKey}}}}}`, start, limit), &res)
+		require.Len(t, res.Block.Transactions, 2)
+		return res.Block.Transactions[1].To.StorageRange
+	}
+	all := rangeQuery("", 100)
+	require.Nil(t, all.NextKey)
+	require.Greater(t, len(all.Slots), 10)
+	for i := 1; i < len(all.Slots); i++ {
+		require.Less(t, all.Slots[i-1].Key, all.Slots[i].Key)
+	}
+
+	var paged storageRange
+	start := ""
+	for {
+		page := rangeQuery(start, 5)
+		require.LessOrEqual(t, len(page.Slots), 5)
+		paged.Slots = append(paged.Slots, page.Slots...)
+		if page.NextKey == nil {
+			break
+		}
+		require.Len(t, page.Slots, 5)
+		require.Less(t, page.Slots[4].Key, *page.NextKey)
+		start = fmt.Sprintf("start:%q,", *page.NextKey)
+	}
+	require.Equal(t, all.Slots, paged.Slots)
+
+	// the limits out of range are capped
+	require.Equal(t, all, rangeQuery("", 0))
+	require.Equal(t, all, rangeQuery("", commands.StorageRangeMaxResults+1))
+}
+
+// subscribe starts the subscription over WebSocket and returns the channel of its payloads.
+func subscribe(t *testing.T, srv *httptest.Server, query string) <-chan json.RawMessage {
+	dialer := websocket.Dialer{Subprotocols: []string{"graphql-ws"}}
+	conn, _, err := dialer.Dial("ws"+strings.TrimPrefix(srv.URL, "http"), nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	type message struct {
+		ID      string          `json:"id,omitempty"`
+		Type    string          `json:"type"`
+		Payload json.RawMessage `json:"payload,omitempty"`
+	}
+	require.NoError(t, conn.WriteJSON(message{Type: "connection_init"}))
+	var msg message
+	require.NoError(t, conn.ReadJSON(&msg))
+	require.Equal(t, "connection_ack", msg.Type)
+	payload, err := json.Marshal(map[string]string{"query": query})
+	require.NoError(t, err)
+	require.NoError(t, conn.WriteJSON(message{ID: "1", Type: "start", Payload: payload}))
+
+	payloads := make(chan json.RawMessage, 16)
+	go func() {
+		defer close(payloads)
+		for {
+			var msg message
+			if err := conn.ReadJSON(&msg); err != nil {
+				return
+			}
+			if msg.Type == "data" {
+				payloads <- msg.Payload
+			}
+		}
+	}()
+	return payloads
+}
+
+// receive sends the events until the subscription, which starts asynchronously, sends a payload.
+func receive(t *testing.T, payloads <-chan json.RawMessage, send func(), data interface{}) {
+	t.Helper()
+	timeout := time.After(10 * time.Second)
+	for {
+		send()
+		select {
+		case payload, ok := <-payloads:
+			require.True(t, ok, "subscription closed")
+			var res struct {
+				Data   json.RawMessage `json:"data"`
+				Errors []struct {
+					Message string `json:"message"`
+				} `json:"errors"`
+			}
+			require.NoError(t, json.Unmarshal(payload, &res))
+			require.Empty(t, res.Errors)
+			require.NoError(t, json.Unmarshal(res.Data, data))
+			return
+		case <-time.After(100 * time.Millisecond):
+		case <-timeout:
+			t.Fatal("no payload received")
+		}
+	}
+}
+
+func TestGraphQLSubscribeNewHeads(t *testing.T) {
+	srv, ff, chain := createTestServer(t)
+	payloads := subscribe(t, srv, `subscription{newHeads{number hash parent{hash} gasUsed}}`)
+
+	header := chain.Headers[2]
+	data, err := rlp.EncodeToBytes(header)
+	require.NoError(t, err)
+	var res struct {
+		NewHeads struct {
+			Number  uint64
+			Hash    string
+			GasUsed uint64
+			Parent  struct{ Hash string }
+		}
+	}
+	receive(t, payloads, func() { ff.OnNewEvent(&remote.SubscribeReply{Type: remote.Event_HEADER, Data: data}) }, &res)
+	require.Equal(t, header.Number.Uint64(), res.NewHeads.Number)
+	require.Equal(t, header.Hash().String(), res.NewHeads.Hash)
+	require.Equal(t, header.ParentHash.String(), res.NewHeads.Parent.Hash)
+	require.Equal(t, header.GasUsed, res.NewHeads.GasUsed)
+}
+
+func TestGraphQLSubscribeLogs(t *testing.T) {
+	srv, ff, _ := createTestServer(t)
+	address := libcommon.HexToAddress("0xdac17f958d2ee523a2206206994597c13d831ec7")
+	topic := libcommon.HexToHash("0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef")
+	payloads := subscribe(t, srv, fmt.Sprintf(`subscription{logs(filter:{addresses:["%s"]}){index data topics account{address} transaction{hash}}}`, address))
+
+	newLog := func(address libcommon.Address, index uint64) *remote.SubscribeLogsReply {
+		return &remote.SubscribeLogsReply{
+			Address:         gointerfaces.ConvertAddressToH160(address),
+			BlockHash:       gointerfaces.ConvertHashToH256([32]byte{1}),
+			BlockNumber:     1,
+			Data:            []byte{0xff},
+			LogIndex:        index,
+			Topics:          []*types2.H256{gointerfaces.ConvertHashToH256(topic)},
+			TransactionHash: gointerfaces.ConvertHashToH256([32]byte{2}),
+		}
+	}
+	var res struct {
+		Logs struct {
+			Index   int
+			Data    string
+			Topics  []string
+			Account struct{ Address string }
+		}
+	}
+	receive(t, payloads, func() {
+		// the logs of the other contracts are filtered out
+		ff.OnNewLogs(newLog(libcommon.Address{1}, 1))
+		ff.OnNewLogs(newLog(address, 2))
+	}, &res)
+	require.Equal(t, 2, res.Logs.Index)
+	require.Equal(t, "0xff", res.Logs.Data)
+	require.Equal(t, []string{topic.String()}, res.Logs.Topics)
+	require.Equal(t, strings.ToLower(address.String()), res.Logs.Account.Address)
+}
@@ -657,6 +657,7 @@ type GrpcServer struct {
 	discoveryDNS         []string
 	GoodPeers            sync.Map
 	statusData           *proto_sentry.StatusData
+	statusForkFilter     forkid.Filter // Built from statusData, checks the fork IDs of the dial candidates
 	P2pServer            *p2p.Server
 	TxSubscribed         uint32 // Set to non-zero if downloader is subscribed to transaction messages
 	lock                 sync.RWMutex
@@ -961,7 +962,9 @@ func (ss *GrpcServer) SetStatus(ctx context.Context, statusData *proto_sentry.St
 			}
 		}
 
-		srv, err := makeP2PServer(*ss.p2p, genesisHash, ss.Protocols)
+		p2pConfig := *ss.p2p
+		p2pConfig.DialCandidateFilter = eth.NewNodeFilter(ss.forkFilter, false)
+		srv, err := makeP2PServer(p2pConfig, genesisHash, ss.Protocols)
 		if err != nil {
 			return reply, err
 		}
@@ -979,10 +982,19 @@ func (ss *GrpcServer) SetStatus(ctx context.Context, statusData *proto_sentry.St
 	if ss.statusData == nil || statusData.MaxBlockHeight != 0 {
 		// Not overwrite statusData if the message contains zero MaxBlock (comes from standalone transaction pool)
 		ss.statusData = statusData
+		ss.statusForkFilter = forkid.NewFilterFromForks(statusData.ForkData.HeightForks, statusData.ForkData.TimeForks, genesisHash, statusData.MaxBlockHeight, statusData.MaxBlockTime)
 	}
 	return reply, nil
 }
 
+// forkFilter validates remote fork IDs against the latest status received from core.
+// The filter is built once per status update rather than for each dial candidate.
+func (ss *GrpcServer) forkFilter() forkid.Filter {
+	ss.lock.RLock()
+	defer ss.lock.RUnlock()
+	return ss.statusForkFilter
+}
+
 func (ss *GrpcServer) Peers(_ context.Context, _ *emptypb.Empty) (*proto_sentry.PeersReply, error) {
 	if ss.P2pServer == nil {
 		return nil, errors.New("p2p server was not started")
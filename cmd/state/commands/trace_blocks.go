@@ -0,0 +1,291 @@
+package commands
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"sync/atomic"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/common/datadir"
+	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/ledgerwatch/erigon-lib/kv/kvcache"
+	"github.com/ledgerwatch/erigon-lib/kv/kvcfg"
+	kv2 "github.com/ledgerwatch/erigon-lib/kv/mdbx"
+	"github.com/ledgerwatch/log/v3"
+	"github.com/spf13/cobra"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/ledgerwatch/erigon/cmd/hack/tool/fromdb"
+	rpccommands "github.com/ledgerwatch/erigon/cmd/rpcdaemon/commands"
+	"github.com/ledgerwatch/erigon/consensus"
+	"github.com/ledgerwatch/erigon/eth/ethconfig"
+	"github.com/ledgerwatch/erigon/eth/stagedsync/stages"
+	"github.com/ledgerwatch/erigon/eth/tracers"
+	_ "github.com/ledgerwatch/erigon/eth/tracers/js"
+	_ "github.com/ledgerwatch/erigon/eth/tracers/native"
+	"github.com/ledgerwatch/erigon/rpc"
+	"github.com/ledgerwatch/erigon/rpc/rpccfg"
+	"github.com/ledgerwatch/erigon/turbo/debug"
+	"github.com/ledgerwatch/erigon/turbo/services"
+	"github.com/ledgerwatch/erigon/turbo/snapshotsync"
+)
+
+var (
+	traceFrom         uint64
+	traceTo           uint64
+	traceChunkSize    uint64
+	traceWorkers      int
+	traceTracer       string
+	traceTracerConfig string
+	traceOutput       string
+)
+
+func init() {
+	withDataDir(traceBlocksCmd)
+	traceBlocksCmd.Flags().Uint64Var(&traceFrom, "from", 0, "first block to trace")
+	traceBlocksCmd.Flags().Uint64Var(&traceTo, "to", 0, "last block to trace, 0 for the last executed block")
+	traceBlocksCmd.Flags().Uint64Var(&traceChunkSize, "chunk", 1000, "number of blocks per output file, the unit of checkpointing")
+	traceBlocksCmd.Flags().IntVar(&traceWorkers, "workers", runtime.NumCPU(), "number of chunks traced in parallel")
+	traceBlocksCmd.Flags().StringVar(&traceTracer, "tracer", "callTracer", "name or JS code of the tracer, as given to debug_traceTransaction")
+	traceBlocksCmd.Flags().StringVar(&traceTracerConfig, "tracerConfig", "", "JSON config of the tracer")
+	traceBlocksCmd.Flags().StringVar(&traceOutput, "output", "traces", "directory of the output files")
+	must(traceBlocksCmd.MarkFlagDirname("output"))
+	rootCmd.AddCommand(traceBlocksCmd)
+}
+
+var traceBlocksCmd = &cobra.Command{
+	Use:   "traceBlocks",
+	Short: "Re-executes a range of historical blocks in parallel and writes the traces of their transactions as newline-delimited JSON",
+	Long: `Re-executes a range of historical blocks in parallel and writes the traces of their transactions as newline-delimited JSON.
+
+The range is split in chunks written to <output>/traces-<from>-<to>.ndjson, a line per block with the traces
+debug_traceBlockByNumber returns. The files only appear once complete, so an interrupted run is resumed by
+running the same command again: the chunks with a file are skipped. Without --to, only the complete chunks
+up to the last executed block are traced, so that the chunks keep their files as the node executes blocks.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var logger log.Logger
+		var err error
+		if logger, err = debug.SetupCobra(cmd, "trace_blocks"); err != nil {
+			logger.Error("Setting up", "error", err)
+			return err
+		}
+		tracerConfig := json.RawMessage("{}")
+		if traceTracerConfig != "" {
+			tracerConfig = json.RawMessage(traceTracerConfig)
+		}
+		return TraceBlocks(cmd.Context(), logger, chaindata, traceOutput, traceFrom, traceTo, traceChunkSize, traceWorkers, traceTracer, tracerConfig)
+	},
+}
+
+// blockTraces is a line of the output.
+type blockTraces struct {
+	Number uint64          `json:"number"`
+	Hash   libcommon.Hash  `json:"hash"`
+	Traces json.RawMessage `json:"traces"` // as returned by debug_traceBlockByNumber
+}
+
+// blockTracer traces blocks from the historical state, the way debug_traceBlockByNumber does.
+type blockTracer struct {
+	db              kv.RoDB
+	blockReader     services.FullBlockReader
+	api             *rpccommands.PrivateDebugAPIImpl
+	config          *tracers.TraceConfig
+	tracedBlocks    atomic.Uint64
+	remainingChunks atomic.Int64
+}
+
+// TraceBlocks traces the blocks from..to with any of the tracers of debug_traceTransaction,
+// skipping the chunks traced by a previous run.
+func TraceBlocks(ctx context.Context, logger log.Logger, chaindata, output string, from, to, chunkSize uint64, workers int, tracer string, tracerConfig json.RawMessage) error {
+	db, err := kv2.NewMDBX(logger).Path(chaindata).Readonly().Open()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	allSnapshots := snapshotsync.NewRoSnapshots(ethconfig.NewSnapCfg(true, false, true), path.Join(datadirCli, "snapshots"), logger)
+	defer allSnapshots.Close()
+	if err := allSnapshots.ReopenFolder(); err != nil {
+		return fmt.Errorf("reopen snapshot segments: %w", err)
+	}
+	engine := initConsensusEngine(fromdb.ChainConfig(db), allSnapshots, logger)
+	return traceBlocks(ctx, logger, db, allSnapshots, engine, datadir.New(datadirCli), output, from, to, chunkSize, workers, tracer, tracerConfig)
+}
+
+func traceBlocks(ctx context.Context, logger log.Logger, db kv.RoDB, snapshots *snapshotsync.RoSnapshots, engine consensus.Engine, dirs datadir.Dirs,
+	output string, from, to, chunkSize uint64, workers int, tracer string, tracerConfig json.RawMessage) error {
+	if chunkSize == 0 {
+		return fmt.Errorf("chunk size must be positive")
+	}
+	if _, err := tracers.New(tracer, &tracers.Context{}, tracerConfig); err != nil {
+		return fmt.Errorf("tracer %q: %w", tracer, err)
+	}
+	blockReader := snapshotsync.NewBlockReaderWithSnapshots(snapshots, kvcfg.TransactionsV3.FromDB(db))
+	base := rpccommands.NewBaseApi(nil, kvcache.NewDummy(), blockReader, nil, false, rpccfg.DefaultEvmCallTimeout, engine, dirs)
+	t := &blockTracer{
+		db:          db,
+		blockReader: blockReader,
+		api:         rpccommands.NewPrivateDebugAPI(base, db, 0),
+		config:      &tracers.TraceConfig{Tracer: &tracer, TracerConfig: &tracerConfig},
+	}
+
+	var execAt uint64
+	if err := db.View(ctx, func(tx kv.Tx) (err error) {
+		execAt, err = stages.GetStageProgress(tx, stages.Execution)
+		return err
+	}); err != nil {
+		return err
+	}
+	if to == 0 {
+		// the last chunk ends where a chunk does, for its file to be found by the next runs
+		if execAt+1 < from+chunkSize {
+			return fmt.Errorf("nothing to trace: no complete chunk of %d blocks from %d, the last executed block is %d", chunkSize, from, execAt)
+		}
+		to = from + (execAt+1-from)/chunkSize*chunkSize - 1
+	} else if to > execAt {
+		to = execAt
+	}
+	if from > to {
+		return fmt.Errorf("nothing to trace: from %d is above to %d", from, to)
+	}
+	if err := os.MkdirAll(output, 0755); err != nil {
+		return err
+	}
+	// chunks interrupted by a previous run are traced again
+	stale, err := filepath.Glob(filepath.Join(output, "*.tmp"))
+	if err != nil {
+		return err
+	}
+	for _, file := range stale {
+		if err := os.Remove(file); err != nil {
+			return err
+		}
+	}
+	var chunks [][2]uint64
+	for start := from; start <= to; start += chunkSize {
+		end := start + chunkSize - 1
+		if end > to || end < start {
+			end = to
+		}
+		if _, err := os.Stat(chunkFile(output, start, end)); err == nil {
+			continue
+		}
+		chunks = append(chunks, [2]uint64{start, end})
+		if end == to {
+			break
+		}
+	}
+	logger.Info("Tracing blocks", "from", from, "to", to, "chunks", len(chunks), "tracer", tracer, "workers", workers)
+	t.remainingChunks.Store(int64(len(chunks)))
+
+	startTime := time.Now()
+	logEvery := time.NewTicker(30 * time.Second)
+	defer logEvery.Stop()
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		for {
+			select {
+			case <-done:
+				return
+			case <-logEvery.C:
+				blocks := t.tracedBlocks.Load()
+				logger.Info("Traced", "blocks", blocks, "chunks left", t.remainingChunks.Load(),
+					"blocks/s", fmt.Sprintf("%.2f", float64(blocks)/time.Since(startTime).Seconds()))
+			}
+		}
+	}()
+
+	g, ctx := errgroup.WithContext(ctx)
+	g.SetLimit(workers)
+	for _, chunk := range chunks {
+		chunk := chunk
+		if ctx.Err() != nil {
+			break
+		}
+		g.Go(func() error {
+			if err := t.traceChunk(ctx, output, chunk[0], chunk[1]); err != nil {
+				return err
+			}
+			t.remainingChunks.Add(-1)
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return err
+	}
+	logger.Info("Traced", "blocks", t.tracedBlocks.Load(), "duration", time.Since(startTime))
+	return nil
+}
+
+func chunkFile(output string, from, to uint64) string {
+	return filepath.Join(output, fmt.Sprintf("traces-%09d-%09d.ndjson", from, to))
+}
+
+// traceChunk writes the traces of the blocks from..to, renaming the file in place once
+// complete so that it doubles as the checkpoint.
+func (t *blockTracer) traceChunk(ctx context.Context, output string, from, to uint64) error {
+	tx, err := t.db.BeginRo(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	file := chunkFile(output, from, to)
+	f, err := os.Create(file + ".tmp")
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	w := bufio.NewWriter(f)
+	enc := json.NewEncoder(w)
+	for blockNum := from; blockNum <= to; blockNum++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		traces, err := t.traceBlock(ctx, tx, blockNum)
+		if err != nil {
+			return err
+		}
+		if err := enc.Encode(traces); err != nil {
+			return err
+		}
+		t.tracedBlocks.Add(1)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(file+".tmp", file)
+}
+
+// traceBlock traces the transactions of the block with debug_traceBlockByNumber, which runs the
+// system calls of the consensus engine before them.
+func (t *blockTracer) traceBlock(ctx context.Context, tx kv.Tx, blockNum uint64) (*blockTraces, error) {
+	blockHash, err := t.blockReader.CanonicalHash(ctx, tx, blockNum)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	stream := jsoniter.NewStream(jsoniter.ConfigDefault, &buf, 4096)
+	if err := t.api.TraceBlockByNumber(ctx, rpc.BlockNumber(blockNum), t.config, stream); err != nil {
+		return nil, fmt.Errorf("block %d: %w", blockNum, err)
+	}
+	if err := stream.Flush(); err != nil {
+		return nil, err
+	}
+	return &blockTraces{Number: blockNum, Hash: blockHash, Traces: buf.Bytes()}, nil
+}
@@ -0,0 +1,75 @@
+package commands
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/ledgerwatch/erigon-lib/kv/kvcache"
+	"github.com/stretchr/testify/require"
+
+	rpccommands "github.com/ledgerwatch/erigon/cmd/rpcdaemon/commands"
+	"github.com/ledgerwatch/erigon/cmd/rpcdaemon/rpcdaemontest"
+	"github.com/ledgerwatch/erigon/eth/stagedsync/stages"
+	"github.com/ledgerwatch/erigon/eth/tracers"
+	"github.com/ledgerwatch/erigon/rpc"
+	"github.com/ledgerwatch/erigon/rpc/rpccfg"
+	"github.com/ledgerwatch/erigon/turbo/snapshotsync"
+)
+
+func TestTraceBlocks(t *testing.T) {
+	m, _, _ := rpcdaemontest.CreateTestSentry(t)
+	var execAt uint64
+	require.NoError(t, m.DB.View(m.Ctx, func(tx kv.Tx) (err error) {
+		execAt, err = stages.GetStageProgress(tx, stages.Execution)
+		return err
+	}))
+	const chunkSize = 3
+	tracer, tracerConfig := "callTracer", json.RawMessage(`{"withLog":true}`)
+	output := t.TempDir()
+	require.NoError(t, traceBlocks(m.Ctx, m.Log, m.DB, m.BlockSnapshots, m.Engine, m.Dirs, output, 1, 0, chunkSize, 2, tracer, tracerConfig))
+
+	// without --to only the complete chunks are traced
+	files, err := filepath.Glob(filepath.Join(output, "*.ndjson"))
+	require.NoError(t, err)
+	require.Len(t, files, int(execAt/chunkSize))
+	to := uint64(len(files)) * chunkSize
+
+	br := snapshotsync.NewBlockReaderWithSnapshots(m.BlockSnapshots, m.TransactionsV3)
+	base := rpccommands.NewBaseApi(nil, kvcache.NewDummy(), br, nil, false, rpccfg.DefaultEvmCallTimeout, m.Engine, m.Dirs)
+	api := rpccommands.NewPrivateDebugAPI(base, m.DB, 0)
+	config := &tracers.TraceConfig{Tracer: &tracer, TracerConfig: &tracerConfig}
+	for start := uint64(1); start <= to; start += chunkSize {
+		f, err := os.Open(chunkFile(output, start, start+chunkSize-1))
+		require.NoError(t, err)
+		scanner := bufio.NewScanner(f)
+		scanner.Buffer(nil, 1<<24)
+		for blockNum := start; blockNum < start+chunkSize; blockNum++ {
+			require.True(t, scanner.Scan(), "block %d", blockNum)
+			var traces blockTraces
+			require.NoError(t, json.Unmarshal(scanner.Bytes(), &traces))
+			require.Equal(t, blockNum, traces.Number)
+
+			var buf bytes.Buffer
+			stream := jsoniter.NewStream(jsoniter.ConfigDefault, &buf, 4096)
+			require.NoError(t, api.TraceBlockByNumber(m.Ctx, rpc.BlockNumber(blockNum), config, stream))
+			require.NoError(t, stream.Flush())
+			require.JSONEq(t, buf.String(), string(traces.Traces), "block %d", blockNum)
+		}
+		require.False(t, scanner.Scan())
+		require.NoError(t, f.Close())
+	}
+
+	// the next run finds the chunks by their names and leaves them alone
+	stat, err := os.Stat(files[0])
+	require.NoError(t, err)
+	require.NoError(t, traceBlocks(m.Ctx, m.Log, m.DB, m.BlockSnapshots, m.Engine, m.Dirs, output, 1, 0, chunkSize, 2, tracer, tracerConfig))
+	restat, err := os.Stat(files[0])
+	require.NoError(t, err)
+	require.Equal(t, stat.ModTime(), restat.ModTime())
+}
@@ -367,6 +367,26 @@ var (
 		Usage: "Maximum number of bytes returned from eth_call or similar invocations",
 		Value: 100_000,
 	}
+	RpcJSTracerStepLimit = cli.Uint64Flag{
+		Name:  "rpc.tracer.js.steps",
+		Usage: "Maximum number of EVM steps a JS tracer may trace (0 = unlimited)",
+	}
+	RpcJSTracerTimeLimit = cli.DurationFlag{
+		Name:  "rpc.tracer.js.time",
+		Usage: "Maximum time a JS tracer may spend running its code (0 = unlimited)",
+	}
+	RpcJSTracerMemoryLimit = cli.Uint64Flag{
+		Name:  "rpc.tracer.js.memory",
+		Usage: "Maximum number of bytes a JS tracer may copy out of the EVM and return (0 = unlimited)",
+	}
+	RpcTraceParallelWorkersFlag = cli.IntFlag{
+		Name:  "rpc.tracer.parallel",
+		Usage: "Number of workers tracing the transactions of large blocks concurrently in debug_traceBlock* (0 = sequential)",
+	}
+	RpcJSTracersDir = cli.StringFlag{
+		Name:  "rpc.tracer.js.dir",
+		Usage: "Directory of .js tracers to register under their file names",
+	}
 	HTTPTraceFlag = cli.BoolFlag{
 		Name:  "http.trace",
 		Usage: "Trace HTTP requests with INFO level",
@@ -561,6 +581,10 @@ var (
 		Name:  "v5disc",
 		Usage: "Enables the experimental RLPx V5 (Topic Discovery) mechanism",
 	}
+	DiscoveryV5TopicsFlag = cli.StringFlag{
+		Name:  "v5disc.topics",
+		Usage: "Comma separated list of topics to advertise in the node record. V5 discovery peers advertising one of them are dialed (requires --v5disc)",
+	}
 	NetrestrictFlag = cli.StringFlag{
 		Name:  "netrestrict",
 		Usage: "Restricts network communication to the given IP networks (CIDR masks)",
@@ -652,6 +676,10 @@ var (
 		Name:  ethconfig.FlagSnapStop,
 		Usage: "Workaround to stop producing new snapshots, if you meet some snapshots-related critical bug. It will stop move historical data from DB to new immutable snapshots. DB will grow and may slightly slow-down - and removing this flag in future will not fix this effect (db size will not greatly reduce).",
 	}
+	SnapCheckFlag = cli.BoolFlag{
+		Name:  ethconfig.FlagSnapCheck,
+		Usage: "Check the content of the block snapshots in the background after startup. Corrupted segments are closed, moved to snapshots/quarantine and downloaded again, segments with wrong indices get them rebuilt on next start",
+	}
 	TorrentVerbosityFlag = cli.IntFlag{
 		Name:  "torrent.verbosity",
 		Value: 2,
@@ -685,6 +713,10 @@ var (
 		Name:  "downloader.verify",
 		Usage: "verify snapshots on startup. it will not report founded problems but just re-download broken pieces",
 	}
+	DownloaderHTTPMirrorFlag = cli.StringFlag{
+		Name:  "downloader.http.mirror",
+		Usage: "Download the snapshots from this HTTP(S) mirror instead of BitTorrent, for example: https://snapshots.example.com/mainnet/ (see: erigon snapshots serve)",
+	}
 	DisableIPV6 = cli.BoolFlag{
 		Name:  "downloader.disable.ipv6",
 		Usage: "Turns off ipv6 for the downlaoder",
@@ -1098,6 +1130,9 @@ func SetP2PConfig(ctx *cli.Context, cfg *p2p.Config, nodeName, datadir string, l
 	if ctx.IsSet(DiscoveryV5Flag.Name) {
 		cfg.DiscoveryV5 = ctx.Bool(DiscoveryV5Flag.Name)
 	}
+	if topics := ctx.String(DiscoveryV5TopicsFlag.Name); topics != "" {
+		cfg.DiscoveryV5Topics = SplitAndTrim(topics)
+	}
 
 	if ctx.IsSet(MetricsEnabledFlag.Name) {
 		cfg.MetricsEnabled = ctx.Bool(MetricsEnabledFlag.Name)
@@ -1456,7 +1491,9 @@ func SetEthConfig(ctx *cli.Context, nodeConfig *nodecfg.Config, cfg *ethconfig.C
 	cfg.Snapshot.Produce = !ctx.Bool(SnapStopFlag.Name)
 	cfg.Snapshot.NoDownloader = ctx.Bool(NoDownloaderFlag.Name)
 	cfg.Snapshot.Verify = ctx.Bool(DownloaderVerifyFlag.Name)
+	cfg.Snapshot.Check = ctx.Bool(SnapCheckFlag.Name)
 	cfg.Snapshot.DownloaderAddr = strings.TrimSpace(ctx.String(DownloaderAddrFlag.Name))
+	cfg.Snapshot.HTTPMirror = strings.TrimSpace(ctx.String(DownloaderHTTPMirrorFlag.Name))
 	if cfg.Snapshot.DownloaderAddr == "" {
 		downloadRateStr := ctx.String(TorrentDownloadRateFlag.Name)
 		uploadRateStr := ctx.String(TorrentUploadRateFlag.Name)
@@ -1634,6 +1671,8 @@ func CobraFlags(cmd *cobra.Command, urfaveCliFlagsLists ...[]cli.Flag) {
 				flags.String(f.Name, f.Value, f.Usage)
 			case *cli.BoolFlag:
 				flags.Bool(f.Name, false, f.Usage)
+			case *cli.Float64Flag:
+				flags.Float64(f.Name, f.Value, f.Usage)
 			default:
 				panic(fmt.Errorf("unexpected type: %T", flag))
 			}
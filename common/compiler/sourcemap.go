@@ -0,0 +1,280 @@
+package compiler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// StandardInput is the part of the solc standard JSON input the source maps need: the
+// contents of the sources, without them only the byte offsets of the locations are known.
+type StandardInput struct {
+	Sources map[string]struct {
+		Content string `json:"content"`
+	} `json:"sources"`
+}
+
+// StandardOutput is the part of the solc standard JSON output the source maps are built of.
+type StandardOutput struct {
+	Sources map[string]struct {
+		ID  int             `json:"id"`
+		AST json.RawMessage `json:"ast"`
+	} `json:"sources"`
+	Contracts map[string]map[string]struct {
+		EVM struct {
+			Bytecode         StandardBytecode `json:"bytecode"`
+			DeployedBytecode StandardBytecode `json:"deployedBytecode"`
+		} `json:"evm"`
+	} `json:"contracts"`
+}
+
+// StandardBytecode is the code of a contract along with its source map.
+type StandardBytecode struct {
+	Object    string `json:"object"`
+	SourceMap string `json:"sourceMap"`
+}
+
+// SourceMapEntry is the source range an instruction was generated from, as described in
+// https://docs.soliditylang.org/en/latest/internals/source_mappings.html
+type SourceMapEntry struct {
+	Start, Length int
+	File          int  // -1 for compiler generated code
+	Jump          byte // 'i' into a function, 'o' out of a function or '-' for a regular jump
+}
+
+// ParseSourceMap decompresses a solc source map, it has an entry per instruction.
+func ParseSourceMap(srcMap string) ([]SourceMapEntry, error) {
+	if srcMap == "" {
+		return nil, nil
+	}
+	items := strings.Split(srcMap, ";")
+	entries := make([]SourceMapEntry, len(items))
+	prev := SourceMapEntry{File: -1, Jump: '-'}
+	for i, item := range items {
+		entry := prev
+		for j, field := range strings.Split(item, ":") {
+			if field == "" {
+				continue
+			}
+			var err error
+			switch j {
+			case 0:
+				entry.Start, err = strconv.Atoi(field)
+			case 1:
+				entry.Length, err = strconv.Atoi(field)
+			case 2:
+				entry.File, err = strconv.Atoi(field)
+			case 3:
+				entry.Jump = field[0]
+			}
+			if err != nil {
+				return nil, fmt.Errorf("source map entry %d: %w", i, err)
+			}
+		}
+		entries[i], prev = entry, entry
+	}
+	return entries, nil
+}
+
+// SourceLocation is the place in the sources an instruction was generated from.
+type SourceLocation struct {
+	File     string `json:"file"`
+	Line     int    `json:"line,omitempty"` // 1-based, unknown without the source contents
+	Column   int    `json:"column,omitempty"`
+	Start    int    `json:"start"` // byte offset in the file
+	Length   int    `json:"length"`
+	Function string `json:"function,omitempty"` // <contract>.<function>
+}
+
+// SourceFunction is a function or modifier of the sources, found in the AST.
+type SourceFunction struct {
+	Name          string
+	Start, Length int
+	File          int
+}
+
+type sourceFile struct {
+	name  string
+	lines []int // offsets the lines start at, nil if the content is unknown
+}
+
+// SourceMap resolves the program counters of a contract compiled by solc to its sources.
+type SourceMap struct {
+	entries      []SourceMapEntry
+	instructions map[uint64]int // the instruction index by pc
+	files        map[int]*sourceFile
+	functions    []SourceFunction // sorted by length, the innermost function first
+}
+
+// NewSourceMap builds the source map of the contract <source unit>:<contract> out of
+// the solc output, for the deployed code or the creation code. The name can be left
+// empty if the output holds a single contract with code.
+func NewSourceMap(output *StandardOutput, input *StandardInput, name string, code []byte, creation bool) (*SourceMap, error) {
+	bytecode, err := output.bytecode(name, creation)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := ParseSourceMap(bytecode.SourceMap)
+	if err != nil {
+		return nil, err
+	}
+	m := &SourceMap{
+		entries:      entries,
+		instructions: instructionIndices(code),
+		files:        make(map[int]*sourceFile),
+	}
+	for file, source := range output.Sources {
+		f := &sourceFile{name: file}
+		if input != nil {
+			if in, ok := input.Sources[file]; ok {
+				f.lines = lineOffsets(in.Content)
+			}
+		}
+		m.files[source.ID] = f
+		if len(source.AST) > 0 {
+			var ast interface{}
+			if err := json.Unmarshal(source.AST, &ast); err != nil {
+				return nil, fmt.Errorf("ast of %s: %w", file, err)
+			}
+			m.functions = appendFunctions(m.functions, ast, "")
+		}
+	}
+	sort.SliceStable(m.functions, func(i, j int) bool {
+		return m.functions[i].Length < m.functions[j].Length
+	})
+	return m, nil
+}
+
+func (o *StandardOutput) bytecode(name string, creation bool) (*StandardBytecode, error) {
+	var found *StandardBytecode
+	for unit, contracts := range o.Contracts {
+		for contract, c := range contracts {
+			bytecode := c.EVM.DeployedBytecode
+			if creation {
+				bytecode = c.EVM.Bytecode
+			}
+			if name != "" {
+				if name == unit+":"+contract {
+					return &bytecode, nil
+				}
+				continue
+			}
+			if bytecode.Object == "" {
+				continue
+			}
+			if found != nil {
+				return nil, errors.New("the output holds several contracts, the contract name is required")
+			}
+			found = &bytecode
+		}
+	}
+	if found == nil {
+		return nil, fmt.Errorf("contract %q not found in the output", name)
+	}
+	return found, nil
+}
+
+// Location returns the source location of the instruction at the given pc along with
+// its jump type, nil if it has none.
+func (m *SourceMap) Location(pc uint64) (*SourceLocation, byte) {
+	idx, ok := m.instructions[pc]
+	if !ok || idx >= len(m.entries) {
+		return nil, '-'
+	}
+	entry := m.entries[idx]
+	file, ok := m.files[entry.File]
+	if !ok {
+		return nil, entry.Jump
+	}
+	loc := &SourceLocation{File: file.name, Start: entry.Start, Length: entry.Length}
+	if file.lines != nil {
+		line := sort.Search(len(file.lines), func(i int) bool { return file.lines[i] > entry.Start })
+		loc.Line, loc.Column = line, entry.Start-file.lines[line-1]+1
+	}
+	for _, fn := range m.functions {
+		if fn.File == entry.File && fn.Start <= entry.Start && entry.Start+entry.Length <= fn.Start+fn.Length {
+			loc.Function = fn.Name
+			break
+		}
+	}
+	return loc, entry.Jump
+}
+
+// instructionIndices maps the pc of each instruction of the code to its index, the
+// push data isn't counted as instructions.
+func instructionIndices(code []byte) map[uint64]int {
+	indices := make(map[uint64]int, len(code))
+	for pc, idx := 0, 0; pc < len(code); idx++ {
+		indices[uint64(pc)] = idx
+		if op := code[pc]; op >= 0x60 && op <= 0x7f { // PUSH1 to PUSH32
+			pc += int(op-0x60) + 1
+		}
+		pc++
+	}
+	return indices
+}
+
+func lineOffsets(content string) []int {
+	lines := []int{0}
+	for i := 0; i < len(content); i++ {
+		if content[i] == '\n' {
+			lines = append(lines, i+1)
+		}
+	}
+	return lines
+}
+
+// appendFunctions collects the functions and modifiers of an AST node and its children.
+func appendFunctions(functions []SourceFunction, node interface{}, contract string) []SourceFunction {
+	switch node := node.(type) {
+	case []interface{}:
+		for _, child := range node {
+			functions = appendFunctions(functions, child, contract)
+		}
+	case map[string]interface{}:
+		name, _ := node["name"].(string)
+		switch node["nodeType"] {
+		case "ContractDefinition":
+			contract = name
+		case "FunctionDefinition", "ModifierDefinition":
+			if name == "" {
+				// constructor, fallback or receive
+				name, _ = node["kind"].(string)
+			}
+			if src, ok := node["src"].(string); ok {
+				if fn, err := parseSrc(src); err == nil {
+					fn.Name = name
+					if contract != "" {
+						fn.Name = contract + "." + name
+					}
+					functions = append(functions, fn)
+				}
+			}
+		}
+		for _, child := range node {
+			functions = appendFunctions(functions, child, contract)
+		}
+	}
+	return functions
+}
+
+// parseSrc parses the <start>:<length>:<file> source range of an AST node.
+func parseSrc(src string) (SourceFunction, error) {
+	var fn SourceFunction
+	fields := strings.Split(src, ":")
+	if len(fields) != 3 {
+		return fn, fmt.Errorf("invalid source range %q", src)
+	}
+	var err error
+	if fn.Start, err = strconv.Atoi(fields[0]); err != nil {
+		return fn, err
+	}
+	if fn.Length, err = strconv.Atoi(fields[1]); err != nil {
+		return fn, err
+	}
+	fn.File, err = strconv.Atoi(fields[2])
+	return fn, err
+}
@@ -0,0 +1,92 @@
+package compiler
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+const testSourceMapSource = "contract C {\n    function f() public {\n        g();\n    }\n    function g() internal {}\n}\n"
+
+const testSourceMapOutput = `{
+  "sources": {
+    "C.sol": {
+      "id": 0,
+      "ast": {"nodeType": "SourceUnit", "src": "0:88:0", "nodes": [
+        {"nodeType": "ContractDefinition", "name": "C", "src": "0:88:0", "nodes": [
+          {"nodeType": "FunctionDefinition", "name": "f", "kind": "function", "src": "17:40:0"},
+          {"nodeType": "FunctionDefinition", "name": "g", "kind": "function", "src": "62:24:0"}
+        ]}
+      ]}
+    }
+  },
+  "contracts": {
+    "C.sol": {
+      "C": {"evm": {"deployedBytecode": {"object": "60806040525b00", "sourceMap": "0:88:0:-;;;47:3:0:i;62:24::-"}}}
+    }
+  }
+}`
+
+func TestParseSourceMap(t *testing.T) {
+	entries, err := ParseSourceMap("1:2:1;:9;2:1:2;;-1:1:-1:o")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []SourceMapEntry{
+		{Start: 1, Length: 2, File: 1, Jump: '-'},
+		{Start: 1, Length: 9, File: 1, Jump: '-'},
+		{Start: 2, Length: 1, File: 2, Jump: '-'},
+		{Start: 2, Length: 1, File: 2, Jump: '-'},
+		{Start: -1, Length: 1, File: -1, Jump: 'o'},
+	}
+	if !reflect.DeepEqual(entries, want) {
+		t.Fatalf("have %v, want %v", entries, want)
+	}
+	if _, err := ParseSourceMap("1:x:1"); err == nil {
+		t.Fatal("expected an error for an invalid entry")
+	}
+}
+
+func TestSourceMapLocation(t *testing.T) {
+	var output StandardOutput
+	if err := json.Unmarshal([]byte(testSourceMapOutput), &output); err != nil {
+		t.Fatal(err)
+	}
+	var input StandardInput
+	input.Sources = map[string]struct {
+		Content string `json:"content"`
+	}{"C.sol": {Content: testSourceMapSource}}
+	code := []byte{0x60, 0x80, 0x60, 0x40, 0x52, 0x5b, 0x00}
+
+	m, err := NewSourceMap(&output, &input, "", code, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		pc   uint64
+		want *SourceLocation
+		jump byte
+	}{
+		{0, &SourceLocation{File: "C.sol", Line: 1, Column: 1, Start: 0, Length: 88}, '-'},
+		{1, nil, '-'}, // push data
+		{5, &SourceLocation{File: "C.sol", Line: 3, Column: 9, Start: 47, Length: 3, Function: "C.f"}, 'i'},
+		{6, &SourceLocation{File: "C.sol", Line: 5, Column: 5, Start: 62, Length: 24, Function: "C.g"}, '-'},
+	}
+	for _, test := range tests {
+		loc, jump := m.Location(test.pc)
+		if !reflect.DeepEqual(loc, test.want) || jump != test.jump {
+			t.Errorf("pc %d: have %+v %c, want %+v %c", test.pc, loc, jump, test.want, test.jump)
+		}
+	}
+
+	// without the sources only the offsets are known
+	if m, err = NewSourceMap(&output, nil, "C.sol:C", code, false); err != nil {
+		t.Fatal(err)
+	}
+	if loc, _ := m.Location(5); loc.Line != 0 || loc.Start != 47 || loc.Function != "C.f" {
+		t.Errorf("have %+v", loc)
+	}
+	if _, err = NewSourceMap(&output, nil, "C.sol:D", code, false); err == nil {
+		t.Error("expected an error for an unknown contract")
+	}
+}
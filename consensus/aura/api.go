@@ -0,0 +1,295 @@
+package aura
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/common/hexutility"
+	"github.com/ledgerwatch/erigon-lib/kv"
+
+	"github.com/ledgerwatch/erigon/common/hexutil"
+	"github.com/ledgerwatch/erigon/consensus"
+	"github.com/ledgerwatch/erigon/core/rawdb"
+	"github.com/ledgerwatch/erigon/core/types"
+	"github.com/ledgerwatch/erigon/rlp"
+	"github.com/ledgerwatch/erigon/rpc"
+)
+
+var (
+	errNotAura      = errors.New("not an aura engine")
+	errUnknownBlock = errors.New("unknown block")
+	errNoEpoch      = errors.New("no epoch transition found")
+)
+
+// SystemCallAt returns a system call on the state after the given block. The API reads
+// the validator sets which are kept in the validator contracts with it.
+type SystemCallAt func(tx kv.Tx, blockNum uint64) (consensus.SystemCall, error)
+
+// API is a user facing RPC API to inspect the validator set transitions and the
+// finality tracked by the authority round engine.
+type API struct {
+	db           kv.RoDB
+	aura         *AuRa
+	systemCallAt SystemCallAt
+}
+
+// EpochTransitionInfo is an enacted validator set change together with its proofs.
+type EpochTransitionInfo struct {
+	BlockNumber   hexutil.Uint64      `json:"blockNumber"`
+	BlockHash     libcommon.Hash      `json:"blockHash"`
+	SignalNumber  hexutil.Uint64      `json:"signalNumber"`
+	SetProof      hexutility.Bytes    `json:"setProof"`
+	FinalityProof hexutility.Bytes    `json:"finalityProof"`
+	Validators    []libcommon.Address `json:"validators,omitempty"`
+}
+
+// PendingTransitionInfo is a validator set change which was signalled but not finalized yet.
+type PendingTransitionInfo struct {
+	BlockNumber hexutil.Uint64      `json:"blockNumber"`
+	BlockHash   libcommon.Hash      `json:"blockHash"`
+	Proof       hexutility.Bytes    `json:"proof"`
+	Validators  []libcommon.Address `json:"validators,omitempty"`
+}
+
+// ValidatorSetInfo is the validator set expected to sign a block.
+type ValidatorSetInfo struct {
+	BlockNumber      hexutil.Uint64      `json:"blockNumber"`
+	TransitionNumber hexutil.Uint64      `json:"transitionNumber"`
+	Validators       []libcommon.Address `json:"validators"`
+}
+
+// FinalizedBlockInfo is the latest block finalized by the validators as seen from the head.
+type FinalizedBlockInfo struct {
+	Number     hexutil.Uint64      `json:"number"`
+	Hash       libcommon.Hash      `json:"hash"`
+	HeadNumber hexutil.Uint64      `json:"headNumber"`
+	HeadHash   libcommon.Hash      `json:"headHash"`
+	Signers    []libcommon.Address `json:"signers"`
+}
+
+// NewAuraAPI returns the `aura` RPC namespace. It can be registered for any engine, in which
+// case all of its methods fail unless the engine is AuRa.
+func NewAuraAPI(db kv.RoDB, engine consensus.EngineReader, systemCallAt SystemCallAt) rpc.API {
+	var c *AuRa
+	if casted, ok := engine.(*AuRa); ok {
+		c = casted
+	}
+
+	return rpc.API{
+		Namespace: "aura",
+		Version:   "1.0",
+		Service:   &API{db: db, aura: c, systemCallAt: systemCallAt},
+		Public:    false,
+	}
+}
+
+// GetValidators retrieves the validator set which signs the given block.
+func (api *API) GetValidators(ctx context.Context, number *rpc.BlockNumber) (*ValidatorSetInfo, error) {
+	if api.aura == nil {
+		return nil, errNotAura
+	}
+	tx, err := api.db.BeginRo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	header, err := api.header(tx, number)
+	if err != nil {
+		return nil, err
+	}
+	transition, err := api.transitionAt(tx, header.Number.Uint64())
+	if err != nil {
+		return nil, err
+	}
+	return &ValidatorSetInfo{
+		BlockNumber:      hexutil.Uint64(header.Number.Uint64()),
+		TransitionNumber: transition.BlockNumber,
+		Validators:       transition.Validators,
+	}, nil
+}
+
+// GetEpochTransition retrieves the epoch transition in effect at the given block, along with
+// the validator set and finality proofs stored for it.
+func (api *API) GetEpochTransition(ctx context.Context, number *rpc.BlockNumber) (*EpochTransitionInfo, error) {
+	if api.aura == nil {
+		return nil, errNotAura
+	}
+	tx, err := api.db.BeginRo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	header, err := api.header(tx, number)
+	if err != nil {
+		return nil, err
+	}
+	return api.transitionAt(tx, header.Number.Uint64())
+}
+
+// GetPendingTransitions retrieves the validator set changes signalled after the epoch
+// transition in effect at the head, which are waiting to be finalized.
+func (api *API) GetPendingTransitions(ctx context.Context) ([]*PendingTransitionInfo, error) {
+	if api.aura == nil {
+		return nil, errNotAura
+	}
+	tx, err := api.db.BeginRo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	header, err := api.header(tx, nil)
+	if err != nil {
+		return nil, err
+	}
+	transition, err := api.transitionAt(tx, header.Number.Uint64())
+	if err != nil {
+		return nil, err
+	}
+
+	pending := []*PendingTransitionInfo{}
+	if err := api.aura.e.ForEachPendingEpoch(uint64(transition.SignalNumber)+1, func(blockNum uint64, blockHash libcommon.Hash, proof []byte) error {
+		if blockNum > header.Number.Uint64() {
+			return nil
+		}
+		validators, _ := epochValidators(api.aura.cfg.Validators, false, blockNum, proof, api.stateCall(tx, blockNum))
+		pending = append(pending, &PendingTransitionInfo{
+			BlockNumber: hexutil.Uint64(blockNum),
+			BlockHash:   blockHash,
+			Proof:       libcommon.Copy(proof),
+			Validators:  validators,
+		})
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return pending, nil
+}
+
+// GetFinalizedBlock retrieves the latest block finalized by the current validator set,
+// or nil if no block was finalized since the last epoch transition.
+func (api *API) GetFinalizedBlock(ctx context.Context) (*FinalizedBlockInfo, error) {
+	if api.aura == nil {
+		return nil, errNotAura
+	}
+	tx, err := api.db.BeginRo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	head := rawdb.ReadCurrentHeader(tx)
+	if head == nil {
+		return nil, errUnknownBlock
+	}
+	transition, err := api.transitionAt(tx, head.Number.Uint64())
+	if err != nil {
+		return nil, err
+	}
+
+	f := NewRollingFinality(transition.Validators)
+	finalized, ok, err := f.latestFinalized(func(hash libcommon.Hash) ([]libcommon.Address, libcommon.Hash, libcommon.Hash, uint64, bool) {
+		h, err := rawdb.ReadHeaderByHash(tx, hash)
+		if err != nil || h == nil {
+			return nil, libcommon.Hash{}, libcommon.Hash{}, 0, false
+		}
+		return []libcommon.Address{h.Coinbase}, h.Hash(), h.ParentHash, h.Number.Uint64(), true
+	}, head.Hash(), transition.BlockHash)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, nil
+	}
+
+	signers := make([]libcommon.Address, 0, len(f.signCount))
+	for signer := range f.signCount {
+		signers = append(signers, signer)
+	}
+	sortAddresses(signers)
+	return &FinalizedBlockInfo{
+		Number:     hexutil.Uint64(finalized.number),
+		Hash:       finalized.hash,
+		HeadNumber: hexutil.Uint64(head.Number.Uint64()),
+		HeadHash:   head.Hash(),
+		Signers:    signers,
+	}, nil
+}
+
+func (api *API) header(tx kv.Tx, number *rpc.BlockNumber) (*types.Header, error) {
+	var header *types.Header
+	if number == nil || *number == rpc.LatestBlockNumber {
+		header = rawdb.ReadCurrentHeader(tx)
+	} else {
+		header = rawdb.ReadHeaderByNumber(tx, uint64(number.Int64()))
+	}
+	if header == nil {
+		return nil, errUnknownBlock
+	}
+	return header, nil
+}
+
+// transitionAt finds the epoch transition whose validator set signs the block with the given
+// number. Like epochTransitionFor, it is looked up by the parent of the block.
+func (api *API) transitionAt(tx kv.Tx, number uint64) (*EpochTransitionInfo, error) {
+	if number > 0 {
+		number--
+	}
+	num, hash, proofRlp, err := api.aura.e.FindBeforeOrEqualNumber(number)
+	if err != nil {
+		return nil, err
+	}
+	if proofRlp == nil {
+		return nil, errNoEpoch
+	}
+	proof := &EpochTransitionProof{}
+	if err := rlp.DecodeBytes(proofRlp, proof); err != nil {
+		return nil, fmt.Errorf("epoch transition %d: %w", num, err)
+	}
+	validators, err := epochValidators(api.aura.cfg.Validators, proof.SignalNumber == 0, proof.SignalNumber, proof.SetProof, api.stateCall(tx, proof.SignalNumber))
+	if err != nil {
+		return nil, fmt.Errorf("validator set of epoch %d: %w", num, err)
+	}
+	return &EpochTransitionInfo{
+		BlockNumber:   hexutil.Uint64(num),
+		BlockHash:     hash,
+		SignalNumber:  hexutil.Uint64(proof.SignalNumber),
+		SetProof:      proof.SetProof,
+		FinalityProof: proof.FinalityProof,
+		Validators:    validators,
+	}, nil
+}
+
+// stateCall returns a system call on the state after the given block. The state is only
+// opened by the validator sets which call their contract.
+func (api *API) stateCall(tx kv.Tx, blockNum uint64) consensus.SystemCall {
+	return func(contract libcommon.Address, data []byte) ([]byte, error) {
+		call, err := api.systemCallAt(tx, blockNum)
+		if err != nil {
+			return nil, err
+		}
+		return call(contract, data)
+	}
+}
+
+// epochValidators extracts the validator list out of a validator set proof. The sets read
+// from the validator contract at the start of their epoch are read with call.
+func epochValidators(validators ValidatorSet, first bool, num uint64, setProof []byte, call consensus.SystemCall) ([]libcommon.Address, error) {
+	set, _, err := validators.epochSet(first, num, setProof, call)
+	if err != nil {
+		return nil, err
+	}
+	return set.validators, nil
+}
+
+func sortAddresses(addrs []libcommon.Address) {
+	sort.Slice(addrs, func(i, j int) bool {
+		return bytes.Compare(addrs[i][:], addrs[j][:]) < 0
+	})
+}
@@ -0,0 +1,58 @@
+package aura
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ledgerwatch/erigon/core/types"
+	"github.com/ledgerwatch/erigon/rlp"
+)
+
+func TestEpochValidators(t *testing.T) {
+	list := NewSimpleList([]libcommon.Address{{1}, {2}})
+	safe := NewValidatorSafeContract(libcommon.Address{0xaa}, nil, nil)
+	multi := NewMulti(map[uint64]ValidatorSet{0: list, 10: safe})
+
+	validators, err := epochValidators(multi, true, 0, nil, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, list.validators, validators)
+
+	// the first set of the contract is read from its state
+	contractSet := []libcommon.Address{{4}, {5}}
+	call := func(contract libcommon.Address, data []byte) ([]byte, error) {
+		assert.Equal(t, libcommon.Address{0xaa}, contract)
+		return safe.abi.Methods["getValidators"].Outputs.Pack(contractSet)
+	}
+	proof, err := rlp.EncodeToBytes(&FirstValidatorSetProof{ContractAddress: libcommon.Address{0xaa}, Header: &types.Header{Number: big.NewInt(10)}})
+	assert.NoError(t, err)
+	validators, err = epochValidators(multi, false, 10, proof, call)
+	assert.NoError(t, err)
+	assert.Equal(t, contractSet, validators)
+	validators, err = epochValidators(&ValidatorContract{validators: safe}, true, 10, proof, call)
+	assert.NoError(t, err)
+	assert.Equal(t, contractSet, validators)
+
+	// a failed call is an error
+	errCall := errors.New("history has been pruned for this block")
+	_, err = epochValidators(safe, true, 10, proof, func(libcommon.Address, []byte) ([]byte, error) {
+		return nil, errCall
+	})
+	assert.ErrorIs(t, err, errCall)
+
+	// at genesis it is the block author
+	proof, err = rlp.EncodeToBytes(&FirstValidatorSetProof{ContractAddress: libcommon.Address{0xaa}, Header: &types.Header{Number: big.NewInt(0), Coinbase: libcommon.Address{3}}})
+	assert.NoError(t, err)
+	validators, err = epochValidators(safe, true, 0, proof, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []libcommon.Address{{3}}, validators)
+
+	// a proof without the change event is an error
+	proof, err = rlp.EncodeToBytes(&ValidatorSetProof{Header: &types.Header{Number: big.NewInt(12)}, Receipts: types.Receipts{}})
+	assert.NoError(t, err)
+	_, err = epochValidators(multi, false, 12, proof, nil)
+	assert.Error(t, err)
+}
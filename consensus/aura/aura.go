@@ -448,6 +448,12 @@ func (cr *NonTransactionalEpochReader) FindBeforeOrEqualNumber(number uint64) (b
 	})
 }
 
+func (cr *NonTransactionalEpochReader) ForEachPendingEpoch(from uint64, walker func(blockNum uint64, blockHash libcommon.Hash, transitionProof []byte) error) error {
+	return cr.db.View(context.Background(), func(tx kv.Tx) error {
+		return rawdb.ForEachPendingEpoch(tx, from, walker)
+	})
+}
+
 // A helper accumulator function mapping a step duration and a step duration transition timestamp
 // to the corresponding step number and the correct starting second of the step.
 func nextStepTimeDuration(info StepDurationInfo, time uint64) (uint64, uint64, bool) {
@@ -1780,3 +1786,29 @@ func (f *RollingFinality) buildAncestrySubChain(get func(hash libcommon.Hash) ([
 	}
 	return nil
 }
+
+// latestFinalized walks the chain backwards from the given head until the blocks seen so far
+// are signed by a majority of the validator set, which makes the oldest of them the latest
+// finalized block. Returns false if the epoch transition is reached before that happens.
+func (f *RollingFinality) latestFinalized(get func(hash libcommon.Hash) ([]libcommon.Address, libcommon.Hash, libcommon.Hash, uint64, bool), headHash, epochTransitionHash libcommon.Hash) (unAssembledHeader, bool, error) {
+	f.clear()
+
+	hash := headHash
+	for {
+		signers, blockHash, parentHash, blockNum, ok := get(hash)
+		if !ok || blockHash == epochTransitionHash {
+			return unAssembledHeader{}, false, nil
+		}
+		for i := range signers {
+			if !f.hasSigner(signers[i]) {
+				return unAssembledHeader{}, false, fmt.Errorf("unknown validator: blockNum=%d", blockNum)
+			}
+		}
+		f.addSigners(signers)
+		f.headers.PushFront(&unAssembledHeader{hash: blockHash, number: blockNum, signers: signers})
+		if f.isFinalized() {
+			return *f.headers.Front(), true, nil
+		}
+		hash = parentHash
+	}
+}
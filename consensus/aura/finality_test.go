@@ -68,5 +68,35 @@ func TestRollingFinality(t *testing.T) {
 		assert.Equal(t, libcommon.Hash{11}, f.headers.Front().hash)
 		assert.Equal(t, libcommon.Hash{11}, *f.lastPushed)
 	})
+	t.Run("LatestFinalized", func(t *testing.T) {
+		signers := []libcommon.Address{{0}, {1}, {2}, {3}, {4}, {5}}
+		newGet := func() func(hash libcommon.Hash) ([]libcommon.Address, libcommon.Hash, libcommon.Hash, uint64, bool) {
+			i := 12
+			return func(hash libcommon.Hash) ([]libcommon.Address, libcommon.Hash, libcommon.Hash, uint64, bool) {
+				i--
+				if i == -1 {
+					return nil, libcommon.Hash{}, libcommon.Hash{}, 0, false
+				}
+				return []libcommon.Address{signers[i%6]}, libcommon.Hash{byte(i)}, libcommon.Hash{byte(i - 1)}, uint64(i), true
+			}
+		}
+
+		// blocks 11..8 are signed by 4 / 6 validators, which finalizes block 8.
+		f := NewRollingFinality(signers)
+		finalized, ok, err := f.latestFinalized(newGet(), libcommon.Hash{11}, libcommon.Hash{99})
+		assert.NoError(t, err)
+		assert.True(t, ok)
+		assert.Equal(t, libcommon.Hash{8}, finalized.hash)
+		assert.Equal(t, uint64(8), finalized.number)
+
+		// nothing is finalized after the epoch transition at block 9.
+		f = NewRollingFinality(signers)
+		_, ok, err = f.latestFinalized(newGet(), libcommon.Hash{11}, libcommon.Hash{9})
+		assert.NoError(t, err)
+		assert.False(t, ok)
 
+		f = NewRollingFinality(signers[:3])
+		_, _, err = f.latestFinalized(newGet(), libcommon.Hash{11}, libcommon.Hash{99})
+		assert.Error(t, err)
+	})
 }
@@ -454,9 +454,9 @@ func (s *ValidatorSafeContract) epochSet(firstInEpoch bool, num uint64, setProof
 		if num == 0 {
 			return *NewSimpleList([]libcommon.Address{proof.Header.Coinbase}), proof.Header.ParentHash, nil
 		}
-		l, ok := s.getListSyscall(call)
-		if !ok {
-			panic(1)
+		l, err := s.getListSyscall(call)
+		if err != nil {
+			return SimpleList{}, libcommon.Hash{}, fmt.Errorf("[ValidatorSafeContract.epochSet] %w", err)
 		}
 
 		//addresses, err := checkFirstValidatorSetProof(s.contractAddress, oldHeader, state_items)
@@ -479,7 +479,7 @@ func (s *ValidatorSafeContract) epochSet(firstInEpoch bool, num uint64, setProof
 	}
 	ll, ok := s.extractFromEvent(proof.Header, proof.Receipts)
 	if !ok {
-		panic(1)
+		return SimpleList{}, libcommon.Hash{}, fmt.Errorf("[ValidatorSafeContract.epochSet] no validator set change event in proof of block %d", proof.Header.Number.Uint64())
 	}
 
 	// ensure receipts match header.
@@ -632,21 +632,21 @@ func (s *ValidatorSafeContract) getList(caller consensus.Call) (*SimpleList, boo
 	return NewSimpleList(out0), true
 }
 
-func (s *ValidatorSafeContract) getListSyscall(caller consensus.SystemCall) (*SimpleList, bool) {
+func (s *ValidatorSafeContract) getListSyscall(caller consensus.SystemCall) (*SimpleList, error) {
 	packed, err := s.abi.Pack("getValidators")
 	if err != nil {
 		panic(err)
 	}
 	out, err := caller(s.contractAddress, packed)
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
 	res, err := s.abi.Unpack("getValidators", out)
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
 	out0 := *abi.ConvertType(res[0], new([]libcommon.Address)).(*[]libcommon.Address)
-	return NewSimpleList(out0), true
+	return NewSimpleList(out0), nil
 }
 
 func (s *ValidatorSafeContract) genesisEpochData(header *types.Header, call consensus.SystemCall) ([]byte, error) {
@@ -258,7 +258,21 @@ func (api *API) GetRootHash(start uint64, end uint64) (string, error) {
 	wg.Wait()
 	close(concurrent)
 
-	headers := make([][32]byte, NextPowerOfTwo(length))
+	rootHash, err := headersRootHash(blockHeaders)
+	if err != nil {
+		return "", err
+	}
+
+	root := hex.EncodeToString(rootHash)
+	api.rootHashCache.Add(key, root)
+
+	return root, nil
+}
+
+// headersRootHash returns the merkle root of the given consecutive block headers, as
+// committed to by Heimdall checkpoints.
+func headersRootHash(blockHeaders []*types.Header) ([]byte, error) {
+	headers := make([][32]byte, NextPowerOfTwo(uint64(len(blockHeaders))))
 
 	for i := 0; i < len(blockHeaders); i++ {
 		blockHeader := blockHeaders[i]
@@ -277,13 +291,10 @@ func (api *API) GetRootHash(start uint64, end uint64) (string, error) {
 
 	tree := merkle.NewTreeWithOpts(merkle.TreeOptions{EnableHashSorting: false, DisableHashLeaves: true})
 	if err := tree.Generate(Convert(headers), sha3.NewLegacyKeccak256()); err != nil {
-		return "", err
+		return nil, err
 	}
 
-	root := hex.EncodeToString(tree.Root().Hash)
-	api.rootHashCache.Add(key, root)
-
-	return root, nil
+	return tree.Root().Hash, nil
 }
 
 func (api *API) initializeRootHashCache() error {
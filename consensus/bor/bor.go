@@ -27,6 +27,7 @@ import (
 	"github.com/ledgerwatch/erigon/common"
 	"github.com/ledgerwatch/erigon/consensus"
 	"github.com/ledgerwatch/erigon/consensus/bor/clerk"
+	"github.com/ledgerwatch/erigon/consensus/bor/finality"
 	"github.com/ledgerwatch/erigon/consensus/bor/heimdall/span"
 	"github.com/ledgerwatch/erigon/consensus/bor/statefull"
 	"github.com/ledgerwatch/erigon/consensus/bor/valset"
@@ -240,6 +241,7 @@ type Bor struct {
 	spanner                Spanner
 	GenesisContractsClient GenesisContract
 	HeimdallClient         IHeimdallClient
+	whitelist              *finality.Whitelist
 
 	// scope event.SubscriptionScope
 	// The fields below are for testing only
@@ -275,6 +277,11 @@ func New(
 	// Allocate the snapshot caches and create the engine
 	recents, _ := lru.NewARC[libcommon.Hash, *Snapshot](inmemorySnapshots)
 	signatures, _ := lru.NewARC[libcommon.Hash, libcommon.Address](inmemorySignatures)
+	whitelist, err := finality.NewWhitelist(db)
+	if err != nil {
+		logger.Warn("[bor] failed to load whitelisted checkpoint and milestone", "err", err)
+		whitelist, _ = finality.NewWhitelist(nil)
+	}
 	c := &Bor{
 		chainConfig:            chainConfig,
 		config:                 borConfig,
@@ -284,6 +291,7 @@ func New(
 		spanner:                spanner,
 		GenesisContractsClient: genesisContracts,
 		HeimdallClient:         heimdallClient,
+		whitelist:              whitelist,
 		spanCache:              btree.New(32),
 		execCtx:                context.Background(),
 		logger:                 logger,
@@ -364,6 +372,11 @@ func (c *Bor) verifyHeader(chain consensus.ChainHeaderReader, header *types.Head
 		return consensus.ErrFutureBlock
 	}
 
+	// Don't accept blocks which would reorg past a whitelisted checkpoint or milestone
+	if err := c.whitelist.IsValidHeader(chain, header); err != nil {
+		return err
+	}
+
 	if err := validateHeaderExtraField(header.Extra); err != nil {
 		return err
 	}
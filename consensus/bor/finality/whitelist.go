@@ -0,0 +1,189 @@
+package finality
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/kv"
+
+	"github.com/ledgerwatch/erigon/core/types"
+)
+
+var (
+	// ErrReorgPastFinality is returned for headers which conflict with a whitelisted
+	// checkpoint or milestone.
+	ErrReorgPastFinality = errors.New("reorg past whitelisted block")
+
+	checkpointKey = []byte("finality-checkpoint")
+	milestoneKey  = []byte("finality-milestone")
+)
+
+// Entry is a block which Heimdall agreed on and which matched the local chain.
+type Entry struct {
+	Number uint64         `json:"number"`
+	Hash   libcommon.Hash `json:"hash"`
+}
+
+// HeaderReader gives access to the canonical chain.
+type HeaderReader interface {
+	GetHeaderByNumber(number uint64) *types.Header
+}
+
+// Whitelist keeps the latest whitelisted checkpoint and milestone, persisted in the bor
+// database, and rejects headers which would reorg the chain past them.
+type Whitelist struct {
+	db kv.RwDB
+
+	lock       sync.RWMutex
+	checkpoint *Entry
+	milestone  *Entry
+}
+
+// NewWhitelist creates a whitelist, loading the entries persisted in the given database.
+// A nil database keeps the whitelist in memory only.
+func NewWhitelist(db kv.RwDB) (*Whitelist, error) {
+	w := &Whitelist{db: db}
+	if db == nil {
+		return w, nil
+	}
+	if err := db.View(context.Background(), func(tx kv.Tx) (err error) {
+		if w.checkpoint, err = ReadCheckpoint(tx); err != nil {
+			return err
+		}
+		w.milestone, err = ReadMilestone(tx)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// Checkpoint returns the last whitelisted checkpoint end block, or nil if there is none.
+func (w *Whitelist) Checkpoint() *Entry {
+	w.lock.RLock()
+	defer w.lock.RUnlock()
+	return copyEntry(w.checkpoint)
+}
+
+// Milestone returns the last whitelisted milestone end block, or nil if there is none.
+func (w *Whitelist) Milestone() *Entry {
+	w.lock.RLock()
+	defer w.lock.RUnlock()
+	return copyEntry(w.milestone)
+}
+
+// Finalized returns the highest whitelisted block, or nil if there is none.
+func (w *Whitelist) Finalized() *Entry {
+	w.lock.RLock()
+	defer w.lock.RUnlock()
+	return copyEntry(latest(w.checkpoint, w.milestone))
+}
+
+// ProcessCheckpoint whitelists the end block of a checkpoint. Checkpoints older than the
+// whitelisted one are ignored. Returns true if the whitelist changed.
+func (w *Whitelist) ProcessCheckpoint(e Entry) (bool, error) {
+	return w.process(&w.checkpoint, checkpointKey, e)
+}
+
+// ProcessMilestone whitelists the end block of a milestone. Milestones older than the
+// whitelisted one are ignored. Returns true if the whitelist changed.
+func (w *Whitelist) ProcessMilestone(e Entry) (bool, error) {
+	return w.process(&w.milestone, milestoneKey, e)
+}
+
+func (w *Whitelist) process(current **Entry, key []byte, e Entry) (bool, error) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	if *current != nil && (*current).Number >= e.Number {
+		return false, nil
+	}
+	if w.db != nil {
+		if err := w.db.Update(context.Background(), func(tx kv.RwTx) error {
+			return writeEntry(tx, key, e)
+		}); err != nil {
+			return false, err
+		}
+	}
+	*current = &e
+	return true, nil
+}
+
+// IsValidHeader checks that the header doesn't conflict with the highest whitelisted block:
+// the whitelisted block itself can't be replaced, and neither can the canonical blocks before it.
+func (w *Whitelist) IsValidHeader(chain HeaderReader, header *types.Header) error {
+	finalized := w.Finalized()
+	if finalized == nil {
+		return nil
+	}
+	number := header.Number.Uint64()
+	if number > finalized.Number {
+		return nil
+	}
+	if number == finalized.Number {
+		if hash := header.Hash(); hash != finalized.Hash {
+			return fmt.Errorf("%w: block %d %x, whitelisted %x", ErrReorgPastFinality, number, hash, finalized.Hash)
+		}
+		return nil
+	}
+	canonical := chain.GetHeaderByNumber(number)
+	if canonical == nil {
+		return nil
+	}
+	if hash := header.Hash(); hash != canonical.Hash() {
+		return fmt.Errorf("%w: block %d %x is below whitelisted block %d", ErrReorgPastFinality, number, hash, finalized.Number)
+	}
+	return nil
+}
+
+// ReadCheckpoint reads the last whitelisted checkpoint end block from the bor database.
+func ReadCheckpoint(tx kv.Getter) (*Entry, error) {
+	return readEntry(tx, checkpointKey)
+}
+
+// ReadMilestone reads the last whitelisted milestone end block from the bor database.
+func ReadMilestone(tx kv.Getter) (*Entry, error) {
+	return readEntry(tx, milestoneKey)
+}
+
+func readEntry(tx kv.Getter, key []byte) (*Entry, error) {
+	blob, err := tx.GetOne(kv.BorSeparate, key)
+	if err != nil {
+		return nil, err
+	}
+	if blob == nil {
+		return nil, nil
+	}
+	e := new(Entry)
+	if err := json.Unmarshal(blob, e); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+func writeEntry(tx kv.Putter, key []byte, e Entry) error {
+	blob, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	return tx.Put(kv.BorSeparate, key, blob)
+}
+
+func latest(a, b *Entry) *Entry {
+	if a == nil || (b != nil && b.Number > a.Number) {
+		return b
+	}
+	return a
+}
+
+func copyEntry(e *Entry) *Entry {
+	if e == nil {
+		return nil
+	}
+	c := *e
+	return &c
+}
@@ -0,0 +1,71 @@
+package finality
+
+import (
+	"math/big"
+	"testing"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/kv/memdb"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ledgerwatch/erigon/core/types"
+)
+
+type testChain map[uint64]*types.Header
+
+func (c testChain) GetHeaderByNumber(number uint64) *types.Header { return c[number] }
+
+func TestWhitelistPersistence(t *testing.T) {
+	db := memdb.NewTestDB(t)
+
+	w, err := NewWhitelist(db)
+	require.NoError(t, err)
+	require.Nil(t, w.Finalized())
+
+	changed, err := w.ProcessCheckpoint(Entry{Number: 10, Hash: libcommon.Hash{10}})
+	require.NoError(t, err)
+	require.True(t, changed)
+	changed, err = w.ProcessMilestone(Entry{Number: 20, Hash: libcommon.Hash{20}})
+	require.NoError(t, err)
+	require.True(t, changed)
+
+	// stale entries are ignored
+	changed, err = w.ProcessMilestone(Entry{Number: 15, Hash: libcommon.Hash{15}})
+	require.NoError(t, err)
+	require.False(t, changed)
+
+	w, err = NewWhitelist(db)
+	require.NoError(t, err)
+	require.Equal(t, &Entry{Number: 10, Hash: libcommon.Hash{10}}, w.Checkpoint())
+	require.Equal(t, &Entry{Number: 20, Hash: libcommon.Hash{20}}, w.Milestone())
+	require.Equal(t, &Entry{Number: 20, Hash: libcommon.Hash{20}}, w.Finalized())
+}
+
+func TestIsValidHeader(t *testing.T) {
+	chain := testChain{}
+	for i := uint64(0); i <= 5; i++ {
+		chain[i] = &types.Header{Number: new(big.Int).SetUint64(i), Extra: []byte("canonical")}
+	}
+	fork := func(number uint64) *types.Header {
+		return &types.Header{Number: new(big.Int).SetUint64(number), Extra: []byte("fork")}
+	}
+
+	w, err := NewWhitelist(nil)
+	require.NoError(t, err)
+
+	// nothing is whitelisted yet
+	require.NoError(t, w.IsValidHeader(chain, fork(2)))
+
+	_, err = w.ProcessMilestone(Entry{Number: 3, Hash: chain[3].Hash()})
+	require.NoError(t, err)
+
+	require.NoError(t, w.IsValidHeader(chain, chain[2]))
+	require.NoError(t, w.IsValidHeader(chain, chain[3]))
+	require.NoError(t, w.IsValidHeader(chain, fork(4)))
+	require.ErrorIs(t, w.IsValidHeader(chain, fork(3)), ErrReorgPastFinality)
+	require.ErrorIs(t, w.IsValidHeader(chain, fork(2)), ErrReorgPastFinality)
+
+	// headers which are not known locally yet can't be checked
+	delete(chain, 1)
+	require.NoError(t, w.IsValidHeader(chain, fork(1)))
+}
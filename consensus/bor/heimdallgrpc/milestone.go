@@ -0,0 +1,20 @@
+package heimdallgrpc
+
+import (
+	"context"
+	"errors"
+
+	"github.com/ledgerwatch/erigon/consensus/bor/heimdall/milestone"
+)
+
+// ErrMilestonesNotSupported is returned because the Heimdall gRPC API has no milestone endpoints,
+// milestones are only available over the REST API.
+var ErrMilestonesNotSupported = errors.New("milestones are not supported by the Heimdall gRPC API")
+
+func (h *HeimdallGRPCClient) FetchMilestone(ctx context.Context) (*milestone.Milestone, error) {
+	return nil, ErrMilestonesNotSupported
+}
+
+func (h *HeimdallGRPCClient) FetchMilestoneCount(ctx context.Context) (int64, error) {
+	return 0, ErrMilestonesNotSupported
+}
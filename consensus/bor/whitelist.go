@@ -0,0 +1,161 @@
+package bor
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ledgerwatch/erigon-lib/kv"
+
+	"github.com/ledgerwatch/erigon/consensus/bor/finality"
+	"github.com/ledgerwatch/erigon/core/rawdb"
+	"github.com/ledgerwatch/erigon/core/types"
+)
+
+const (
+	checkpointWhitelistInterval = 100 * time.Second
+	milestoneWhitelistInterval  = 12 * time.Second
+)
+
+var (
+	// errLocalChainBehind is returned when the local chain hasn't reached the end of a
+	// checkpoint or milestone yet, so it is tried again later.
+	errLocalChainBehind = errors.New("local chain is behind heimdall")
+
+	errCheckpointMismatch = errors.New("checkpoint root hash doesn't match the local chain")
+	errMilestoneMismatch  = errors.New("milestone hash doesn't match the local chain")
+)
+
+// Whitelist returns the checkpoint and milestone whitelist used in header verification.
+func (c *Bor) Whitelist() *finality.Whitelist {
+	return c.whitelist
+}
+
+// RunFinalityService fetches checkpoints and milestones from Heimdall and whitelists them once
+// the local chain is found to match, until the context is cancelled. The whitelisted block is
+// also stored as the finalized block in the chain database, so that the "finalized" block tag
+// resolves to it.
+func (c *Bor) RunFinalityService(ctx context.Context, chainDB kv.RwDB) {
+	if c.HeimdallClient == nil {
+		return
+	}
+
+	checkpointTicker := time.NewTicker(checkpointWhitelistInterval)
+	defer checkpointTicker.Stop()
+	milestoneTicker := time.NewTicker(milestoneWhitelistInterval)
+	defer milestoneTicker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-checkpointTicker.C:
+			if err := c.whitelistCheckpoint(ctx, chainDB); err != nil && !errors.Is(err, errLocalChainBehind) {
+				c.logger.Warn("[bor] failed to whitelist checkpoint", "err", err)
+			}
+		case <-milestoneTicker.C:
+			if err := c.whitelistMilestone(ctx, chainDB); err != nil && !errors.Is(err, errLocalChainBehind) {
+				c.logger.Debug("[bor] failed to whitelist milestone", "err", err)
+			}
+		}
+	}
+}
+
+// whitelistCheckpoint fetches the latest checkpoint and whitelists its end block if the root
+// hash of the local headers it covers matches.
+func (c *Bor) whitelistCheckpoint(ctx context.Context, chainDB kv.RwDB) error {
+	ctx, cancel := context.WithTimeout(ctx, checkpointWhitelistInterval)
+	defer cancel()
+
+	checkpoint, err := c.HeimdallClient.FetchCheckpoint(ctx, -1)
+	if err != nil {
+		return err
+	}
+	start, end := checkpoint.StartBlock.Uint64(), checkpoint.EndBlock.Uint64()
+	if start > end || end-start+1 > MaxCheckpointLength {
+		return &MaxCheckpointLengthExceededError{Start: start, End: end}
+	}
+	if current := c.whitelist.Checkpoint(); current != nil && current.Number >= end {
+		return nil
+	}
+
+	var headers []*types.Header
+	if err := chainDB.View(ctx, func(tx kv.Tx) error {
+		headers = make([]*types.Header, 0, end-start+1)
+		for number := start; number <= end; number++ {
+			header := rawdb.ReadHeaderByNumber(tx, number)
+			if header == nil {
+				return fmt.Errorf("%w: checkpoint end %d", errLocalChainBehind, end)
+			}
+			headers = append(headers, header)
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	rootHash, err := headersRootHash(headers)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(rootHash, checkpoint.RootHash[:]) {
+		return fmt.Errorf("%w: blocks %d-%d, local %x, heimdall %x", errCheckpointMismatch, start, end, rootHash, checkpoint.RootHash)
+	}
+
+	changed, err := c.whitelist.ProcessCheckpoint(finality.Entry{Number: end, Hash: headers[len(headers)-1].Hash()})
+	if err != nil || !changed {
+		return err
+	}
+	c.logger.Debug("[bor] whitelisted checkpoint", "start", start, "end", end)
+	return c.storeFinalized(ctx, chainDB)
+}
+
+// whitelistMilestone fetches the latest milestone and whitelists its end block if the local
+// header at that height has the same hash.
+func (c *Bor) whitelistMilestone(ctx context.Context, chainDB kv.RwDB) error {
+	ctx, cancel := context.WithTimeout(ctx, milestoneWhitelistInterval)
+	defer cancel()
+
+	milestone, err := c.HeimdallClient.FetchMilestone(ctx)
+	if err != nil {
+		return err
+	}
+	end := milestone.EndBlock.Uint64()
+	if current := c.whitelist.Milestone(); current != nil && current.Number >= end {
+		return nil
+	}
+
+	var header *types.Header
+	if err := chainDB.View(ctx, func(tx kv.Tx) error {
+		header = rawdb.ReadHeaderByNumber(tx, end)
+		return nil
+	}); err != nil {
+		return err
+	}
+	if header == nil {
+		return fmt.Errorf("%w: milestone end %d", errLocalChainBehind, end)
+	}
+	if hash := header.Hash(); hash != milestone.Hash {
+		return fmt.Errorf("%w: block %d, local %x, heimdall %x", errMilestoneMismatch, end, hash, milestone.Hash)
+	}
+
+	changed, err := c.whitelist.ProcessMilestone(finality.Entry{Number: end, Hash: milestone.Hash})
+	if err != nil || !changed {
+		return err
+	}
+	c.logger.Debug("[bor] whitelisted milestone", "end", end, "hash", milestone.Hash)
+	return c.storeFinalized(ctx, chainDB)
+}
+
+func (c *Bor) storeFinalized(ctx context.Context, chainDB kv.RwDB) error {
+	finalized := c.whitelist.Finalized()
+	if finalized == nil {
+		return nil
+	}
+	return chainDB.Update(ctx, func(tx kv.RwTx) error {
+		rawdb.WriteForkchoiceFinalized(tx, finalized.Hash)
+		return nil
+	})
+}
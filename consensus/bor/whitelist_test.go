@@ -0,0 +1,131 @@
+package bor
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/ledgerwatch/erigon-lib/kv/memdb"
+	"github.com/ledgerwatch/log/v3"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ledgerwatch/erigon/consensus/bor/finality"
+	"github.com/ledgerwatch/erigon/consensus/bor/heimdall/checkpoint"
+	"github.com/ledgerwatch/erigon/consensus/bor/heimdall/milestone"
+	"github.com/ledgerwatch/erigon/core/rawdb"
+	"github.com/ledgerwatch/erigon/core/types"
+	"github.com/ledgerwatch/erigon/tests/bor/mocks"
+)
+
+func newWhitelistTestChain(t *testing.T, length uint64) (kv.RwDB, []*types.Header) {
+	t.Helper()
+
+	chainDB := memdb.NewTestDB(t)
+	headers := make([]*types.Header, length)
+	require.NoError(t, chainDB.Update(context.Background(), func(tx kv.RwTx) error {
+		for i := range headers {
+			headers[i] = &types.Header{Number: big.NewInt(int64(i)), Time: uint64(i) * 2}
+			if i > 0 {
+				headers[i].ParentHash = headers[i-1].Hash()
+			}
+			rawdb.WriteHeader(tx, headers[i])
+			if err := rawdb.WriteCanonicalHash(tx, headers[i].Hash(), uint64(i)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}))
+	return chainDB, headers
+}
+
+func newWhitelistTestBor(t *testing.T, heimdallClient IHeimdallClient) *Bor {
+	t.Helper()
+
+	whitelist, err := finality.NewWhitelist(memdb.NewTestDB(t))
+	require.NoError(t, err)
+	return &Bor{HeimdallClient: heimdallClient, whitelist: whitelist, logger: log.New()}
+}
+
+func TestWhitelistMilestone(t *testing.T) {
+	ctx := context.Background()
+	chainDB, headers := newWhitelistTestChain(t, 10)
+
+	ctrl := gomock.NewController(t)
+	heimdallClient := mocks.NewMockIHeimdallClient(ctrl)
+	c := newWhitelistTestBor(t, heimdallClient)
+
+	// the local chain hasn't reached the milestone yet
+	heimdallClient.EXPECT().FetchMilestone(gomock.Any()).Return(&milestone.Milestone{
+		StartBlock: big.NewInt(8), EndBlock: big.NewInt(12), Hash: libcommon.Hash{12},
+	}, nil)
+	require.ErrorIs(t, c.whitelistMilestone(ctx, chainDB), errLocalChainBehind)
+	require.Nil(t, c.whitelist.Milestone())
+
+	// the local chain is on another fork
+	heimdallClient.EXPECT().FetchMilestone(gomock.Any()).Return(&milestone.Milestone{
+		StartBlock: big.NewInt(3), EndBlock: big.NewInt(5), Hash: libcommon.Hash{5},
+	}, nil)
+	require.ErrorIs(t, c.whitelistMilestone(ctx, chainDB), errMilestoneMismatch)
+	require.Nil(t, c.whitelist.Milestone())
+
+	heimdallClient.EXPECT().FetchMilestone(gomock.Any()).Return(&milestone.Milestone{
+		StartBlock: big.NewInt(3), EndBlock: big.NewInt(5), Hash: headers[5].Hash(),
+	}, nil)
+	require.NoError(t, c.whitelistMilestone(ctx, chainDB))
+	require.Equal(t, &finality.Entry{Number: 5, Hash: headers[5].Hash()}, c.whitelist.Milestone())
+
+	// the finalized block tag now resolves to the milestone
+	require.NoError(t, chainDB.View(ctx, func(tx kv.Tx) error {
+		require.Equal(t, headers[5].Hash(), rawdb.ReadForkchoiceFinalized(tx))
+		return nil
+	}))
+
+	// and reorgs past it are rejected
+	fork := &types.Header{Number: big.NewInt(4), ParentHash: headers[3].Hash(), Extra: []byte("fork")}
+	require.ErrorIs(t, c.whitelist.IsValidHeader(headerReader{chainDB}, fork), finality.ErrReorgPastFinality)
+}
+
+func TestWhitelistCheckpoint(t *testing.T) {
+	ctx := context.Background()
+	chainDB, headers := newWhitelistTestChain(t, 10)
+
+	ctrl := gomock.NewController(t)
+	heimdallClient := mocks.NewMockIHeimdallClient(ctrl)
+	c := newWhitelistTestBor(t, heimdallClient)
+
+	rootHash, err := headersRootHash(headers[0:8])
+	require.NoError(t, err)
+
+	heimdallClient.EXPECT().FetchCheckpoint(gomock.Any(), int64(-1)).Return(&checkpoint.Checkpoint{
+		StartBlock: big.NewInt(0), EndBlock: big.NewInt(7), RootHash: libcommon.Hash{1},
+	}, nil)
+	require.ErrorIs(t, c.whitelistCheckpoint(ctx, chainDB), errCheckpointMismatch)
+	require.Nil(t, c.whitelist.Checkpoint())
+
+	heimdallClient.EXPECT().FetchCheckpoint(gomock.Any(), int64(-1)).Return(&checkpoint.Checkpoint{
+		StartBlock: big.NewInt(0), EndBlock: big.NewInt(7), RootHash: libcommon.BytesToHash(rootHash),
+	}, nil)
+	require.NoError(t, c.whitelistCheckpoint(ctx, chainDB))
+	require.Equal(t, &finality.Entry{Number: 7, Hash: headers[7].Hash()}, c.whitelist.Checkpoint())
+	require.Equal(t, c.whitelist.Checkpoint(), c.whitelist.Finalized())
+
+	heimdallClient.EXPECT().FetchCheckpoint(gomock.Any(), int64(-1)).Return(&checkpoint.Checkpoint{
+		StartBlock: big.NewInt(8), EndBlock: big.NewInt(15), RootHash: libcommon.Hash{1},
+	}, nil)
+	require.ErrorIs(t, c.whitelistCheckpoint(ctx, chainDB), errLocalChainBehind)
+}
+
+type headerReader struct {
+	db kv.RoDB
+}
+
+func (r headerReader) GetHeaderByNumber(number uint64) (header *types.Header) {
+	_ = r.db.View(context.Background(), func(tx kv.Tx) error {
+		header = rawdb.ReadHeaderByNumber(tx, number)
+		return nil
+	})
+	return header
+}
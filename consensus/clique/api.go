@@ -39,6 +39,9 @@ type API struct {
 
 // GetSnapshot retrieves the state snapshot at a given block.
 func (api *API) GetSnapshot(ctx context.Context, number *rpc.BlockNumber) (*Snapshot, error) {
+	if api.clique == nil {
+		return nil, errNotClique
+	}
 	tx, err := api.db.BeginRo(ctx)
 	if err != nil {
 		return nil, err
@@ -68,6 +71,9 @@ func (api *API) GetSnapshot(ctx context.Context, number *rpc.BlockNumber) (*Snap
 
 // GetSnapshotAtHash retrieves the state snapshot at a given block.
 func (api *API) GetSnapshotAtHash(ctx context.Context, hash libcommon.Hash) (*Snapshot, error) {
+	if api.clique == nil {
+		return nil, errNotClique
+	}
 	tx, err := api.db.BeginRo(ctx)
 	if err != nil {
 		return nil, err
@@ -90,6 +96,9 @@ func (api *API) GetSnapshotAtHash(ctx context.Context, hash libcommon.Hash) (*Sn
 
 // GetSigners retrieves the list of authorized signers at the specified block.
 func (api *API) GetSigners(ctx context.Context, number *rpc.BlockNumber) ([]libcommon.Address, error) {
+	if api.clique == nil {
+		return nil, errNotClique
+	}
 	tx, err := api.db.BeginRo(ctx)
 	if err != nil {
 		return nil, err
@@ -118,6 +127,9 @@ func (api *API) GetSigners(ctx context.Context, number *rpc.BlockNumber) ([]libc
 
 // GetSignersAtHash retrieves the list of authorized signers at the specified block.
 func (api *API) GetSignersAtHash(ctx context.Context, hash libcommon.Hash) ([]libcommon.Address, error) {
+	if api.clique == nil {
+		return nil, errNotClique
+	}
 	tx, err := api.db.BeginRo(ctx)
 	if err != nil {
 		return nil, err
@@ -137,33 +149,29 @@ func (api *API) GetSignersAtHash(ctx context.Context, hash libcommon.Hash) ([]li
 }
 
 // Proposals returns the current proposals the node tries to uphold and vote on.
-func (api *API) Proposals() map[libcommon.Address]bool {
-	api.clique.lock.RLock()
-	defer api.clique.lock.RUnlock()
-
-	proposals := make(map[libcommon.Address]bool)
-	for address, auth := range api.clique.proposals {
-		proposals[address] = auth
+func (api *API) Proposals() (map[libcommon.Address]bool, error) {
+	if api.clique == nil {
+		return nil, errNotClique
 	}
-	return proposals
+	return api.clique.Proposals()
+}
+
+// SignerAPI adds the voting of the signer to the API. It's only served where the
+// consensus DB is writable, that is on the node.
+type SignerAPI struct {
+	*API
 }
 
 // Propose injects a new authorization proposal that the signer will attempt to
 // push through.
-func (api *API) Propose(address libcommon.Address, auth bool) {
-	api.clique.lock.Lock()
-	defer api.clique.lock.Unlock()
-
-	api.clique.proposals[address] = auth
+func (api *SignerAPI) Propose(address libcommon.Address, auth bool) error {
+	return api.clique.Propose(address, auth)
 }
 
 // Discard drops a currently running proposal, stopping the signer from casting
 // further votes (either for or against).
-func (api *API) Discard(address libcommon.Address) {
-	api.clique.lock.Lock()
-	defer api.clique.lock.Unlock()
-
-	delete(api.clique.proposals, address)
+func (api *SignerAPI) Discard(address libcommon.Address) error {
+	return api.clique.Discard(address)
 }
 
 type status struct {
@@ -177,6 +185,9 @@ type status struct {
 // - the number of signers,
 // - the percentage of in-turn blocks
 func (api *API) Status(ctx context.Context) (*status, error) {
+	if api.clique == nil {
+		return nil, errNotClique
+	}
 	tx, err := api.db.BeginRo(ctx)
 	if err != nil {
 		return nil, err
@@ -80,6 +80,14 @@ var (
 	// that is not part of the local blockchain.
 	errUnknownBlock = errors.New("unknown block")
 
+	// errNotClique is returned by the RPC API when the chain is not driven by the
+	// clique engine.
+	errNotClique = errors.New("clique consensus engine is not available")
+
+	// errReadOnlyProposals is returned by the RPC API when the votes can't be changed,
+	// as the consensus DB is opened read-only by a standalone rpcdaemon.
+	errReadOnlyProposals = errors.New("clique proposals can only be changed on the node")
+
 	// errInvalidCheckpointBeneficiary is returned if a checkpoint/epoch transition
 	// block has a beneficiary set to non-zeroes.
 	errInvalidCheckpointBeneficiary = errors.New("beneficiary in checkpoint block non-zero")
@@ -225,6 +233,10 @@ func New(cfg *chain.Config, snapshotConfig *params.ConsensusSnapshotConfig, cliq
 		logger:         logger,
 	}
 
+	if err := c.reloadProposals(); err != nil {
+		logger.Error("on Clique init while reading proposals", "err", err)
+	}
+
 	// warm the cache
 	snapNum, err := lastSnapshot(cliqueDB, logger)
 	if err != nil {
@@ -306,6 +318,9 @@ func (c *Clique) Prepare(chain consensus.ChainHeaderReader, header *types.Header
 	if err != nil {
 		return err
 	}
+	if err := c.reloadProposals(); err != nil {
+		c.logger.Warn("[clique] Failed to reload proposals, using the cached ones", "err", err)
+	}
 	c.lock.RLock()
 	if number%c.config.Epoch != 0 {
 		// Gather all the proposals that make sense voting on
@@ -542,10 +557,15 @@ func NewCliqueAPI(db kv.RoDB, engine consensus.EngineReader) rpc.API {
 		c = casted
 	}
 
+	api := &API{db: db, clique: c}
+	var service interface{} = api
+	if c != nil && !c.DB.ReadOnly() {
+		service = &SignerAPI{API: api}
+	}
 	return rpc.API{
 		Namespace: "clique",
 		Version:   "1.0",
-		Service:   &API{db: db, clique: c},
+		Service:   service,
 		Public:    false,
 	}
 }
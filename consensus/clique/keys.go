@@ -29,3 +29,10 @@ func EncodeBlockNumber(number uint64) []byte {
 	binary.BigEndian.PutUint64(enc, number)
 	return enc
 }
+
+// ProposalKey = "proposal-" + address, stored in kv.CliqueBucket
+func ProposalKey(address libcommon.Address) []byte {
+	return append(append([]byte{}, proposalPrefix...), address.Bytes()...)
+}
+
+var proposalPrefix = []byte("proposal-")
@@ -0,0 +1,105 @@
+package clique
+
+import (
+	"context"
+	"fmt"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/kv"
+)
+
+// Proposals are kept in the consensus DB next to the snapshots. This way votes survive
+// restarts, and a standalone rpcdaemon sharing the datadir, which opens the DB read-only,
+// can list them. They are cast and discarded on the node.
+
+func readProposals(db kv.RoDB) (map[libcommon.Address]bool, error) {
+	proposals := make(map[libcommon.Address]bool)
+	if err := db.View(context.Background(), func(tx kv.Tx) error {
+		return tx.ForPrefix(kv.CliqueBucket, proposalPrefix, func(k, v []byte) error {
+			if len(k) != len(proposalPrefix)+libcommon.AddressLength || len(v) != 1 {
+				return fmt.Errorf("invalid clique proposal record %x => %x", k, v)
+			}
+			proposals[libcommon.BytesToAddress(k[len(proposalPrefix):])] = v[0] == 1
+			return nil
+		})
+	}); err != nil {
+		return nil, err
+	}
+	return proposals, nil
+}
+
+func writeProposal(db kv.RwDB, address libcommon.Address, authorize bool) error {
+	value := []byte{0}
+	if authorize {
+		value[0] = 1
+	}
+	return db.Update(context.Background(), func(tx kv.RwTx) error {
+		return tx.Put(kv.CliqueBucket, ProposalKey(address), value)
+	})
+}
+
+func deleteProposal(db kv.RwDB, address libcommon.Address) error {
+	return db.Update(context.Background(), func(tx kv.RwTx) error {
+		return tx.Delete(kv.CliqueBucket, ProposalKey(address))
+	})
+}
+
+// Proposals returns the current proposals the node tries to uphold and vote on.
+func (c *Clique) Proposals() (map[libcommon.Address]bool, error) {
+	if err := c.reloadProposals(); err != nil {
+		return nil, err
+	}
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	proposals := make(map[libcommon.Address]bool, len(c.proposals))
+	for address, auth := range c.proposals {
+		proposals[address] = auth
+	}
+	return proposals, nil
+}
+
+// Propose injects a new authorization proposal that the signer will attempt to
+// push through.
+func (c *Clique) Propose(address libcommon.Address, auth bool) error {
+	if c.DB.ReadOnly() {
+		return errReadOnlyProposals
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if err := writeProposal(c.DB, address, auth); err != nil {
+		return err
+	}
+	c.proposals[address] = auth
+	return nil
+}
+
+// Discard drops a currently running proposal, stopping the signer from casting
+// further votes (either for or against).
+func (c *Clique) Discard(address libcommon.Address) error {
+	if c.DB.ReadOnly() {
+		return errReadOnlyProposals
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if err := deleteProposal(c.DB, address); err != nil {
+		return err
+	}
+	delete(c.proposals, address)
+	return nil
+}
+
+// reloadProposals replaces the in-memory proposals with the persisted ones, picking up
+// votes cast by other processes sharing the consensus DB.
+func (c *Clique) reloadProposals() error {
+	proposals, err := readProposals(c.DB)
+	if err != nil {
+		return err
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.proposals = proposals
+	return nil
+}
@@ -0,0 +1,74 @@
+package clique_test
+
+import (
+	"testing"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/ledgerwatch/erigon-lib/kv/mdbx"
+	"github.com/ledgerwatch/erigon-lib/kv/memdb"
+	"github.com/ledgerwatch/log/v3"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ledgerwatch/erigon/consensus/clique"
+	"github.com/ledgerwatch/erigon/params"
+)
+
+func TestProposalsPersistence(t *testing.T) {
+	cliqueDB := memdb.NewTestDB(t)
+	engine := clique.New(params.AllCliqueProtocolChanges, params.CliqueSnapshot, cliqueDB, log.New())
+
+	authorized := libcommon.HexToAddress("0x1000000000000000000000000000000000000001")
+	dropped := libcommon.HexToAddress("0x2000000000000000000000000000000000000002")
+	discarded := libcommon.HexToAddress("0x3000000000000000000000000000000000000003")
+	require.NoError(t, engine.Propose(authorized, true))
+	require.NoError(t, engine.Propose(dropped, false))
+	require.NoError(t, engine.Propose(discarded, true))
+	require.NoError(t, engine.Discard(discarded))
+	_, ok := clique.NewCliqueAPI(cliqueDB, engine).Service.(*clique.SignerAPI)
+	require.True(t, ok)
+
+	want := map[libcommon.Address]bool{authorized: true, dropped: false}
+	proposals, err := engine.Proposals()
+	require.NoError(t, err)
+	require.Equal(t, want, proposals)
+
+	// A second engine sharing the DB (e.g. a restarted node) sees the same votes, and
+	// its changes are visible to the first one.
+	other := clique.New(params.AllCliqueProtocolChanges, params.CliqueSnapshot, cliqueDB, log.New())
+	proposals, err = other.Proposals()
+	require.NoError(t, err)
+	require.Equal(t, want, proposals)
+
+	require.NoError(t, other.Discard(dropped))
+	proposals, err = engine.Proposals()
+	require.NoError(t, err)
+	require.Equal(t, map[libcommon.Address]bool{authorized: true}, proposals)
+}
+
+func TestReadOnlyProposals(t *testing.T) {
+	dir := t.TempDir()
+	authorized := libcommon.HexToAddress("0x1000000000000000000000000000000000000001")
+	cliqueDB := mdbx.NewMDBX(log.New()).Path(dir).Label(kv.ConsensusDB).MustOpen()
+	require.NoError(t, clique.New(params.AllCliqueProtocolChanges, params.CliqueSnapshot, cliqueDB, log.New()).Propose(authorized, true))
+	cliqueDB.Close()
+
+	// a standalone rpcdaemon lists the votes of the node, but can't change them
+	cliqueDB = mdbx.NewMDBX(log.New()).Path(dir).Label(kv.ConsensusDB).Readonly().MustOpen()
+	defer cliqueDB.Close()
+	engine := clique.New(params.AllCliqueProtocolChanges, params.CliqueSnapshot, cliqueDB, log.New())
+	proposals, err := engine.Proposals()
+	require.NoError(t, err)
+	require.Equal(t, map[libcommon.Address]bool{authorized: true}, proposals)
+	require.Error(t, engine.Propose(authorized, false))
+	require.Error(t, engine.Discard(authorized))
+	proposals, err = engine.Proposals()
+	require.NoError(t, err)
+	require.Equal(t, map[libcommon.Address]bool{authorized: true}, proposals)
+
+	// so clique_propose and clique_discard are left out of its API
+	_, ok := clique.NewCliqueAPI(cliqueDB, engine).Service.(*clique.SignerAPI)
+	require.False(t, ok)
+	_, ok = clique.NewCliqueAPI(cliqueDB, engine).Service.(*clique.API)
+	require.True(t, ok)
+}
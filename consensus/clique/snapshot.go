@@ -139,8 +139,12 @@ func lastSnapshot(db kv.RwDB, logger log.Logger) (uint64, error) {
 	return lastNum, nil
 }
 
-// store inserts the snapshot into the database.
+// store inserts the snapshot into the database, unless it is opened read-only by
+// another process than the node.
 func (s *Snapshot) store(db kv.RwDB) error {
+	if db.ReadOnly() {
+		return nil
+	}
 	blob, err := json.Marshal(s)
 	if err != nil {
 		return err
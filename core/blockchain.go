@@ -135,6 +135,10 @@ func ExecuteBlockEphemerally(
 
 			vmConfig.Tracer = nil
 		}
+		if ibsErr := ibs.Error(); ibsErr != nil {
+			// the state could not be read, which says nothing of the validity of the transaction
+			return nil, fmt.Errorf("could not read state for tx %d from block %d [%v]: %w", i, block.NumberU64(), tx.Hash().Hex(), ibsErr)
+		}
 		if err != nil {
 			if !vmConfig.StatelessExec {
 				return nil, fmt.Errorf("could not apply tx %d from block %d [%v]: %w", i, block.NumberU64(), tx.Hash().Hex(), err)
@@ -150,18 +154,18 @@ func ExecuteBlockEphemerally(
 
 	receiptSha := types.DeriveSha(receipts)
 	if !vmConfig.StatelessExec && chainConfig.IsByzantium(header.Number.Uint64()) && !vmConfig.NoReceipts && receiptSha != block.ReceiptHash() {
-		return nil, fmt.Errorf("mismatched receipt headers for block %d (%s != %s)", block.NumberU64(), receiptSha.Hex(), block.ReceiptHash().Hex())
+		return nil, &RootMismatchError{Field: "receiptsRoot", Have: receiptSha, Want: block.ReceiptHash()}
 	}
 
 	if !vmConfig.StatelessExec && *usedGas != header.GasUsed {
-		return nil, fmt.Errorf("gas used by execution: %d, in header: %d", *usedGas, header.GasUsed)
+		return nil, &GasUsedMismatchError{Have: *usedGas, Want: header.GasUsed}
 	}
 
 	var bloom types.Bloom
 	if !vmConfig.NoReceipts {
 		bloom = types.CreateBloom(receipts)
 		if !vmConfig.StatelessExec && bloom != header.Bloom {
-			return nil, fmt.Errorf("bloom computed by execution: %x, in header: %x", bloom, header.Bloom)
+			return nil, &BloomMismatchError{Have: bloom, Want: header.Bloom}
 		}
 	}
 	if !vmConfig.ReadOnly {
@@ -246,6 +250,10 @@ func ExecuteBlockEphemerallyBor(
 
 			vmConfig.Tracer = nil
 		}
+		if ibsErr := ibs.Error(); ibsErr != nil {
+			// the state could not be read, which says nothing of the validity of the transaction
+			return nil, fmt.Errorf("could not read state for tx %d from block %d [%v]: %w", i, block.NumberU64(), tx.Hash().Hex(), ibsErr)
+		}
 		if err != nil {
 			if !vmConfig.StatelessExec {
 				return nil, fmt.Errorf("could not apply tx %d from block %d [%v]: %w", i, block.NumberU64(), tx.Hash().Hex(), err)
@@ -261,18 +269,18 @@ func ExecuteBlockEphemerallyBor(
 
 	receiptSha := types.DeriveSha(receipts)
 	if !vmConfig.StatelessExec && chainConfig.IsByzantium(header.Number.Uint64()) && !vmConfig.NoReceipts && receiptSha != block.ReceiptHash() {
-		return nil, fmt.Errorf("mismatched receipt headers for block %d (%s != %s)", block.NumberU64(), receiptSha.Hex(), block.ReceiptHash().Hex())
+		return nil, &RootMismatchError{Field: "receiptsRoot", Have: receiptSha, Want: block.ReceiptHash()}
 	}
 
 	if !vmConfig.StatelessExec && *usedGas != header.GasUsed {
-		return nil, fmt.Errorf("gas used by execution: %d, in header: %d", *usedGas, header.GasUsed)
+		return nil, &GasUsedMismatchError{Have: *usedGas, Want: header.GasUsed}
 	}
 
 	var bloom types.Bloom
 	if !vmConfig.NoReceipts {
 		bloom = types.CreateBloom(receipts)
 		if !vmConfig.StatelessExec && bloom != header.Bloom {
-			return nil, fmt.Errorf("bloom computed by execution: %x, in header: %x", bloom, header.Bloom)
+			return nil, &BloomMismatchError{Have: bloom, Want: header.Bloom}
 		}
 	}
 	if !vmConfig.ReadOnly {
@@ -445,6 +453,9 @@ func FinalizeBlockExecution(
 	if err != nil {
 		return nil, nil, nil, err
 	}
+	if err := ibs.Error(); err != nil {
+		return nil, nil, nil, fmt.Errorf("reading state for block %d failed: %w", header.Number.Uint64(), err)
+	}
 
 	if err := ibs.CommitBlock(cc.Rules(header.Number.Uint64(), header.Time), stateWriter); err != nil {
 		return nil, nil, nil, fmt.Errorf("committing block %d failed: %w", header.Number.Uint64(), err)
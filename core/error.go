@@ -18,7 +18,11 @@ package core
 
 import (
 	"errors"
+	"fmt"
 
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+
+	"github.com/ledgerwatch/erigon/consensus"
 	"github.com/ledgerwatch/erigon/core/types"
 )
 
@@ -108,3 +112,76 @@ var (
 	// See EIP-3607: Reject transactions from senders with deployed code.
 	ErrSenderNoEOA = errors.New("sender not an eoa")
 )
+
+// BadBlockError is returned when a block breaks the consensus rules, as opposed to the errors
+// met while processing it, like a database failure, after which the block may still be valid.
+type BadBlockError struct {
+	Number uint64
+	Hash   libcommon.Hash
+	Err    error
+}
+
+func (e *BadBlockError) Error() string { return e.Err.Error() }
+
+func (e *BadBlockError) Unwrap() error { return e.Err }
+
+// RootMismatchError is returned when a root computed while executing a block, like its state or
+// receipts root, differs from the one in its header.
+type RootMismatchError struct {
+	Field string // stateRoot or receiptsRoot
+	Have  libcommon.Hash
+	Want  libcommon.Hash
+}
+
+func (e *RootMismatchError) Error() string {
+	return fmt.Sprintf("%s computed by execution: %x, in header: %x", e.Field, e.Have, e.Want)
+}
+
+// GasUsedMismatchError is returned when the gas used by the execution of a block differs from its header.
+type GasUsedMismatchError struct {
+	Have uint64
+	Want uint64
+}
+
+func (e *GasUsedMismatchError) Error() string {
+	return fmt.Sprintf("gas used by execution: %d, in header: %d", e.Have, e.Want)
+}
+
+// BloomMismatchError is returned when the logs bloom computed by the execution of a block differs
+// from its header.
+type BloomMismatchError struct {
+	Have types.Bloom
+	Want types.Bloom
+}
+
+func (e *BloomMismatchError) Error() string {
+	return fmt.Sprintf("bloom computed by execution: %x, in header: %x", e.Have, e.Want)
+}
+
+// blockValidationErrors are the errors of the transactions and headers which break the consensus rules.
+var blockValidationErrors = []error{
+	ErrNonceTooLow, ErrNonceTooHigh, ErrNonceMax, ErrGasLimitReached, ErrDataGasLimitReached,
+	ErrMaxInitCodeSizeExceeded, ErrInsufficientFunds, ErrGasUintOverflow, ErrIntrinsicGas,
+	ErrTxTypeNotSupported, ErrFeeCapTooLow, ErrSenderNoEOA, ErrTipAboveFeeCap, ErrMaxFeePerDataGas,
+	ErrTipVeryHigh, ErrFeeCapVeryHigh,
+	types.ErrInvalidSig, types.ErrInvalidChainId, types.ErrUnexpectedProtection, types.ErrInvalidTxType,
+	consensus.ErrInvalidNumber, consensus.ErrUnexpectedWithdrawals,
+}
+
+// IsBlockValidationError reports whether the error returned by the execution of a block means the
+// block is invalid, as opposed to the failures of the node executing it, like a database error or
+// a cancelled context, which should be returned as is and not wrapped in a BadBlockError.
+func IsBlockValidationError(err error) bool {
+	var rootErr *RootMismatchError
+	var gasUsedErr *GasUsedMismatchError
+	var bloomErr *BloomMismatchError
+	if errors.As(err, &rootErr) || errors.As(err, &gasUsedErr) || errors.As(err, &bloomErr) {
+		return true
+	}
+	for _, validationErr := range blockValidationErrors {
+		if errors.Is(err, validationErr) {
+			return true
+		}
+	}
+	return false
+}
@@ -24,6 +24,7 @@ import (
 	"fmt"
 	"math"
 	"math/big"
+	"sort"
 	"time"
 
 	"github.com/ledgerwatch/erigon-lib/kv/kvcfg"
@@ -1602,6 +1603,13 @@ func ReadPendingEpoch(tx kv.Tx, blockNum uint64, blockHash libcommon.Hash) (tran
 	return tx.GetOne(kv.PendingEpoch, k)
 }
 
+// ForEachPendingEpoch walks the pending epoch transitions signalled at or after the given block.
+func ForEachPendingEpoch(tx kv.Tx, from uint64, walker func(blockNum uint64, blockHash libcommon.Hash, transitionProof []byte) error) error {
+	return tx.ForEach(kv.PendingEpoch, hexutility.EncodeTs(from), func(k, v []byte) error {
+		return walker(binary.BigEndian.Uint64(k), libcommon.BytesToHash(k[8:]), v)
+	})
+}
+
 func WritePendingEpoch(tx kv.RwTx, blockNum uint64, blockHash libcommon.Hash, transitionProof []byte) (err error) {
 	k := make([]byte, 8+32)
 	binary.BigEndian.PutUint64(k, blockNum)
@@ -1685,6 +1693,65 @@ func WriteSnapshots(tx kv.RwTx, list, histList []string) error {
 	return nil
 }
 
+var BadBlocksKey = []byte("bad_blocks")
+
+// badBlocksLimit is the number of the highest bad blocks kept in the db.
+const badBlocksLimit = 10
+
+// BadBlock is a block found invalid, with its last valid ancestor and the validation error.
+type BadBlock struct {
+	Header          *types.Header
+	Body            *types.RawBody
+	LastValidHash   libcommon.Hash
+	ValidationError string
+}
+
+// ReadBadBlocks returns the bad blocks kept in the db, highest first.
+func ReadBadBlocks(db kv.Getter) ([]*BadBlock, error) {
+	v, err := db.GetOne(kv.DatabaseInfo, BadBlocksKey)
+	if err != nil {
+		return nil, err
+	}
+	if len(v) == 0 {
+		return nil, nil
+	}
+	var badBlocks []*BadBlock
+	if err := rlp.DecodeBytes(v, &badBlocks); err != nil {
+		return nil, fmt.Errorf("invalid bad blocks RLP: %w", err)
+	}
+	return badBlocks, nil
+}
+
+// WriteBadBlock adds the block to the bad blocks kept in the db, dropping the lowest ones past
+// badBlocksLimit. A block without body is written with an empty one.
+func WriteBadBlock(db kv.GetPut, badBlock *BadBlock) error {
+	badBlocks, err := ReadBadBlocks(db)
+	if err != nil {
+		return err
+	}
+	hash := badBlock.Header.Hash()
+	for _, b := range badBlocks {
+		if b.Header.Hash() == hash {
+			return nil
+		}
+	}
+	if badBlock.Body == nil {
+		badBlock = &BadBlock{Header: badBlock.Header, Body: &types.RawBody{}, LastValidHash: badBlock.LastValidHash, ValidationError: badBlock.ValidationError}
+	}
+	badBlocks = append(badBlocks, badBlock)
+	sort.SliceStable(badBlocks, func(i, j int) bool {
+		return badBlocks[i].Header.Number.Uint64() > badBlocks[j].Header.Number.Uint64()
+	})
+	if len(badBlocks) > badBlocksLimit {
+		badBlocks = badBlocks[:badBlocksLimit]
+	}
+	v, err := rlp.EncodeToBytes(badBlocks)
+	if err != nil {
+		return fmt.Errorf("failed to RLP encode bad blocks: %w", err)
+	}
+	return db.Put(kv.DatabaseInfo, BadBlocksKey, v)
+}
+
 // PruneTable has `limit` parameter to avoid too large data deletes per one sync cycle - better delete by small portions to reduce db.FreeList size
 func PruneTable(tx kv.RwTx, table string, pruneTo uint64, ctx context.Context, limit int) error {
 	c, err := tx.RwCursor(table)
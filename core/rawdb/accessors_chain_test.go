@@ -84,7 +84,7 @@ func TestBodyStorage(t *testing.T) {
 	}
 
 	// prepare db so it works with our test
-	signer1 := types.MakeSigner(params.MainnetChainConfig, 1)
+	signer1 := types.MakeSigner(params.MainnetChainConfig, 1, 0)
 	body := &types.Body{
 		Transactions: []types.Transaction{
 			mustSign(types.NewTransaction(1, testAddr, u256.Num1, 1, u256.Num1, nil), *signer1),
@@ -633,3 +633,32 @@ func checkReceiptsRLP(have, want types.Receipts) error {
 	}
 	return nil
 }
+
+func TestBadBlockStorage(t *testing.T) {
+	_, tx := memdb.NewTestTx(t)
+
+	badBlocks, err := ReadBadBlocks(tx)
+	require.NoError(t, err)
+	require.Empty(t, badBlocks)
+
+	body := &types.RawBody{Transactions: [][]byte{{0x01}}}
+	for i := 1; i <= badBlocksLimit+2; i++ {
+		header := &types.Header{Number: big.NewInt(int64(i)), Extra: []byte("bad block")}
+		require.NoError(t, WriteBadBlock(tx, &BadBlock{Header: header, Body: body, LastValidHash: libcommon.Hash{byte(i)}, ValidationError: "invalid state root"}))
+		// writing it again is a noop
+		require.NoError(t, WriteBadBlock(tx, &BadBlock{Header: header, ValidationError: "invalid state root"}))
+	}
+	header := &types.Header{Number: big.NewInt(badBlocksLimit + 3)}
+	require.NoError(t, WriteBadBlock(tx, &BadBlock{Header: header, ValidationError: "invalid block number"}))
+
+	badBlocks, err = ReadBadBlocks(tx)
+	require.NoError(t, err)
+	require.Len(t, badBlocks, badBlocksLimit)
+	require.Equal(t, header.Hash(), badBlocks[0].Header.Hash())
+	require.Empty(t, badBlocks[0].Body.Transactions)
+	require.Equal(t, "invalid block number", badBlocks[0].ValidationError)
+	require.Equal(t, uint64(badBlocksLimit+2), badBlocks[1].Header.Number.Uint64())
+	require.Equal(t, body.Transactions, badBlocks[1].Body.Transactions)
+	require.Equal(t, libcommon.Hash{badBlocksLimit + 2}, badBlocks[1].LastValidHash)
+	require.Equal(t, uint64(4), badBlocks[badBlocksLimit-1].Header.Number.Uint64())
+}
@@ -0,0 +1,211 @@
+package state
+
+import (
+	"bytes"
+	"encoding/binary"
+	"sync"
+
+	"github.com/holiman/uint256"
+	"github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/kv"
+
+	"github.com/ledgerwatch/erigon/common/dbutils"
+	"github.com/ledgerwatch/erigon/core/types/accounts"
+)
+
+// OverlayState holds the writes of the transactions of a block committed so far, on top of
+// the state at the start of the block. It lets the transactions of a block run
+// speculatively in parallel, like the exec3 workers: each one reads through an
+// OverlayReader recording its read set, and they are committed in order once their reads
+// are checked to be still valid, like StateV3.ReadsValid does.
+type OverlayState struct {
+	lock   sync.RWMutex
+	tables map[string]map[string][]byte // kv.PlainState, StorageTable, kv.IncarnationMap and kv.Code
+}
+
+func NewOverlayState() *OverlayState {
+	return &OverlayState{tables: map[string]map[string][]byte{
+		kv.PlainState:     {},
+		StorageTable:      {},
+		kv.IncarnationMap: {},
+		kv.Code:           {},
+	}}
+}
+
+func (o *OverlayState) get(table, key string) ([]byte, bool) {
+	o.lock.RLock()
+	defer o.lock.RUnlock()
+	v, ok := o.tables[table][key]
+	return v, ok
+}
+
+func (o *OverlayState) put(table, key string, val []byte) {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+	o.tables[table][key] = val
+}
+
+// Writer returns the writer committing to the overlay.
+func (o *OverlayState) Writer() StateWriter { return &overlayWriter{o: o} }
+
+// NewReader returns a reader of the overlay falling back to base, which records the
+// values it reads.
+func (o *OverlayState) NewReader(base StateReader) *OverlayReader {
+	return &OverlayReader{o: o, base: base}
+}
+
+// ReadsValid tells whether the values read by r are still the current ones. The values
+// read from the state at the start of the block stay valid as long as no transaction
+// wrote them.
+func (o *OverlayState) ReadsValid(r *OverlayReader) bool {
+	o.lock.RLock()
+	defer o.lock.RUnlock()
+	for _, read := range r.reads {
+		v, ok := o.tables[read.table][read.key]
+		if ok != read.found || !bytes.Equal(v, read.val) {
+			return false
+		}
+	}
+	return true
+}
+
+// Commit applies the writes of the transaction which read through r if its reads are
+// still valid, returning false otherwise. From then on the reads of r, including those of
+// apply, go to base and are not recorded anymore. Commits must be done in the order of
+// the transactions, from a single goroutine.
+func (o *OverlayState) Commit(r *OverlayReader, base StateReader, apply func(StateWriter) error) (bool, error) {
+	if !o.ReadsValid(r) {
+		return false, nil
+	}
+	r.base, r.reads, r.discardReads = base, nil, true
+	if err := apply(o.Writer()); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+type overlayRead struct {
+	table string
+	key   string
+	found bool // whether the value came from the overlay rather than the base state
+	val   []byte
+}
+
+// OverlayReader reads the overlay falling back to the state at the start of the block.
+// The code is looked up by hash and never changes, so its reads are not recorded.
+type OverlayReader struct {
+	o            *OverlayState
+	base         StateReader
+	reads        []overlayRead
+	discardReads bool
+}
+
+func (r *OverlayReader) read(table, key string) ([]byte, bool) {
+	v, ok := r.o.get(table, key)
+	if !r.discardReads {
+		r.reads = append(r.reads, overlayRead{table: table, key: key, found: ok, val: v})
+	}
+	return v, ok
+}
+
+func (r *OverlayReader) ReadAccountData(address common.Address) (*accounts.Account, error) {
+	enc, ok := r.read(kv.PlainState, string(address.Bytes()))
+	if !ok {
+		return r.base.ReadAccountData(address)
+	}
+	if len(enc) == 0 {
+		return nil, nil
+	}
+	var a accounts.Account
+	if err := a.DecodeForStorage(enc); err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+func (r *OverlayReader) ReadAccountStorage(address common.Address, incarnation uint64, key *common.Hash) ([]byte, error) {
+	composite := dbutils.PlainGenerateCompositeStorageKey(address.Bytes(), incarnation, key.Bytes())
+	enc, ok := r.read(StorageTable, string(composite))
+	if !ok {
+		return r.base.ReadAccountStorage(address, incarnation, key)
+	}
+	if len(enc) == 0 {
+		return nil, nil
+	}
+	return enc, nil
+}
+
+func (r *OverlayReader) ReadAccountCode(address common.Address, incarnation uint64, codeHash common.Hash) ([]byte, error) {
+	if code, ok := r.o.get(kv.Code, string(codeHash.Bytes())); ok {
+		return code, nil
+	}
+	return r.base.ReadAccountCode(address, incarnation, codeHash)
+}
+
+func (r *OverlayReader) ReadAccountCodeSize(address common.Address, incarnation uint64, codeHash common.Hash) (int, error) {
+	if code, ok := r.o.get(kv.Code, string(codeHash.Bytes())); ok {
+		return len(code), nil
+	}
+	return r.base.ReadAccountCodeSize(address, incarnation, codeHash)
+}
+
+// ReadAccountIncarnation returns the highest of the incarnations of the base state and of
+// the block, so that recreated contracts never reuse the storage of a previous one.
+func (r *OverlayReader) ReadAccountIncarnation(address common.Address) (uint64, error) {
+	enc, ok := r.read(kv.IncarnationMap, string(address.Bytes()))
+	inc, err := r.base.ReadAccountIncarnation(address)
+	if err != nil {
+		return 0, err
+	}
+	if ok {
+		if written := binary.BigEndian.Uint64(enc); written > inc {
+			inc = written
+		}
+	}
+	return inc, nil
+}
+
+type overlayWriter struct {
+	o *OverlayState
+}
+
+func (w *overlayWriter) setIncarnation(address common.Address, incarnation uint64) {
+	w.o.lock.Lock()
+	defer w.o.lock.Unlock()
+	key := string(address.Bytes())
+	if enc, ok := w.o.tables[kv.IncarnationMap][key]; ok && binary.BigEndian.Uint64(enc) >= incarnation {
+		return
+	}
+	enc := make([]byte, 8)
+	binary.BigEndian.PutUint64(enc, incarnation)
+	w.o.tables[kv.IncarnationMap][key] = enc
+}
+
+func (w *overlayWriter) UpdateAccountData(address common.Address, original, account *accounts.Account) error {
+	enc := make([]byte, account.EncodingLengthForStorage())
+	account.EncodeForStorage(enc)
+	w.o.put(kv.PlainState, string(address.Bytes()), enc)
+	w.setIncarnation(address, account.Incarnation)
+	return nil
+}
+
+func (w *overlayWriter) UpdateAccountCode(address common.Address, incarnation uint64, codeHash common.Hash, code []byte) error {
+	w.o.put(kv.Code, string(codeHash.Bytes()), code)
+	return nil
+}
+
+func (w *overlayWriter) DeleteAccount(address common.Address, original *accounts.Account) error {
+	w.o.put(kv.PlainState, string(address.Bytes()), nil)
+	w.setIncarnation(address, original.Incarnation)
+	return nil
+}
+
+func (w *overlayWriter) WriteAccountStorage(address common.Address, incarnation uint64, key *common.Hash, original, value *uint256.Int) error {
+	composite := dbutils.PlainGenerateCompositeStorageKey(address.Bytes(), incarnation, key.Bytes())
+	w.o.put(StorageTable, string(composite), value.Bytes())
+	return nil
+}
+
+func (w *overlayWriter) CreateContract(address common.Address) error {
+	return nil
+}
@@ -0,0 +1,253 @@
+package state
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/holiman/uint256"
+	"github.com/ledgerwatch/erigon-lib/chain"
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/ledgerwatch/erigon-lib/kv/memdb"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ledgerwatch/erigon/core/types/accounts"
+)
+
+var (
+	overlayAccount  = libcommon.HexToAddress("0x1000000000000000000000000000000000000001")
+	overlayContract = libcommon.HexToAddress("0x2000000000000000000000000000000000000002")
+	overlaySlot     = libcommon.HexToHash("0x01")
+	otherSlot       = libcommon.HexToHash("0x02")
+)
+
+// newOverlayBase returns the state at the start of the block: an account with a balance of 1,
+// and a contract of incarnation 1 whose first slot holds 5.
+func newOverlayBase(t *testing.T) StateReader {
+	_, tx := memdb.NewTestTx(t)
+	w := NewPlainStateWriterNoHistory(tx)
+	require.NoError(t, w.UpdateAccountData(overlayAccount, nil, &accounts.Account{Initialised: true, Balance: *uint256.NewInt(1)}))
+	require.NoError(t, w.UpdateAccountData(overlayContract, nil, &accounts.Account{Initialised: true, Incarnation: 1}))
+	require.NoError(t, w.WriteAccountStorage(overlayContract, 1, &overlaySlot, uint256.NewInt(0), uint256.NewInt(5)))
+	return NewPlainStateReader(tx)
+}
+
+func commitBalance(t *testing.T, o *OverlayState, r *OverlayReader, base StateReader, balance uint64) bool {
+	ok, err := o.Commit(r, base, func(w StateWriter) error {
+		return w.UpdateAccountData(overlayAccount, nil, &accounts.Account{Initialised: true, Balance: *uint256.NewInt(balance)})
+	})
+	require.NoError(t, err)
+	return ok
+}
+
+func TestOverlayStateWriteAfterRead(t *testing.T) {
+	base := newOverlayBase(t)
+	o := NewOverlayState()
+
+	// two transactions run speculatively on the state at the start of the block
+	r0, r1 := o.NewReader(base), o.NewReader(base)
+	acc, err := r0.ReadAccountData(overlayAccount)
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), acc.Balance.Uint64())
+	acc, err = r1.ReadAccountData(overlayAccount)
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), acc.Balance.Uint64())
+	require.True(t, o.ReadsValid(r0))
+	require.True(t, o.ReadsValid(r1))
+
+	// the first one commits, which invalidates the read of the second one
+	require.True(t, commitBalance(t, o, r0, base, 2))
+	require.False(t, o.ReadsValid(r1))
+	applied := false
+	ok, err := o.Commit(r1, base, func(StateWriter) error {
+		applied = true
+		return nil
+	})
+	require.NoError(t, err)
+	require.False(t, ok)
+	require.False(t, applied)
+
+	// run again, it reads the write of the first one
+	r1 = o.NewReader(base)
+	acc, err = r1.ReadAccountData(overlayAccount)
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), acc.Balance.Uint64())
+	require.True(t, o.ReadsValid(r1))
+	require.True(t, commitBalance(t, o, r1, base, 3))
+
+	// the reads are dropped once committed, so the later writes don't invalidate them
+	require.True(t, o.ReadsValid(r0))
+	require.True(t, o.ReadsValid(r1))
+}
+
+func TestOverlayStateBalanceIncrease(t *testing.T) {
+	base := newOverlayBase(t)
+	o := NewOverlayState()
+	rules := &chain.Rules{}
+
+	// two transactions crediting the account, like the fees to the coinbase, without reading it
+	r0, r1 := o.NewReader(base), o.NewReader(base)
+	ibs0, ibs1 := New(r0), New(r1)
+	ibs0.AddBalance(overlayAccount, uint256.NewInt(2))
+	ibs1.AddBalance(overlayAccount, uint256.NewInt(3))
+	// and one reading it
+	r2 := o.NewReader(base)
+	require.Equal(t, uint64(1), New(r2).GetBalance(overlayAccount).Uint64())
+
+	// the credits are applied to the committed balance, so they don't conflict
+	for _, tx := range []struct {
+		r   *OverlayReader
+		ibs *IntraBlockState
+	}{{r0, ibs0}, {r1, ibs1}} {
+		ok, err := o.Commit(tx.r, base, func(w StateWriter) error { return tx.ibs.FinalizeTx(rules, w) })
+		require.NoError(t, err)
+		require.True(t, ok)
+	}
+	acc, err := o.NewReader(base).ReadAccountData(overlayAccount)
+	require.NoError(t, err)
+	require.Equal(t, uint64(6), acc.Balance.Uint64())
+	require.False(t, o.ReadsValid(r2))
+}
+
+func TestOverlayStateStorageReads(t *testing.T) {
+	base := newOverlayBase(t)
+	o := NewOverlayState()
+	writeSlot := func(slot libcommon.Hash, value uint64) {
+		ok, err := o.Commit(o.NewReader(base), base, func(w StateWriter) error {
+			return w.WriteAccountStorage(overlayContract, 1, &slot, nil, uint256.NewInt(value))
+		})
+		require.NoError(t, err)
+		require.True(t, ok)
+	}
+
+	r := o.NewReader(base)
+	v, err := r.ReadAccountStorage(overlayContract, 1, &overlaySlot)
+	require.NoError(t, err)
+	require.Equal(t, []byte{5}, v)
+	v, err = r.ReadAccountStorage(overlayContract, 1, &otherSlot)
+	require.NoError(t, err)
+	require.Empty(t, v)
+
+	// the writes of the slots of another incarnation don't conflict
+	ok, err := o.Commit(o.NewReader(base), base, func(w StateWriter) error {
+		return w.WriteAccountStorage(overlayContract, 2, &overlaySlot, nil, uint256.NewInt(6))
+	})
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.True(t, o.ReadsValid(r))
+
+	// neither does writing the value read from the overlay again
+	writeSlot(overlaySlot, 6)
+	r = o.NewReader(base)
+	v, err = r.ReadAccountStorage(overlayContract, 1, &overlaySlot)
+	require.NoError(t, err)
+	require.Equal(t, []byte{6}, v)
+	_, err = r.ReadAccountStorage(overlayContract, 1, &otherSlot)
+	require.NoError(t, err)
+	writeSlot(overlaySlot, 6)
+	require.True(t, o.ReadsValid(r))
+
+	// a slot read as empty is written
+	writeSlot(otherSlot, 7)
+	require.False(t, o.ReadsValid(r))
+
+	// clearing a slot is a write too
+	r = o.NewReader(base)
+	_, err = r.ReadAccountStorage(overlayContract, 1, &overlaySlot)
+	require.NoError(t, err)
+	writeSlot(overlaySlot, 0)
+	require.False(t, o.ReadsValid(r))
+	v, err = o.NewReader(base).ReadAccountStorage(overlayContract, 1, &overlaySlot)
+	require.NoError(t, err)
+	require.Empty(t, v)
+}
+
+func TestOverlayStateDeleteAccount(t *testing.T) {
+	base := newOverlayBase(t)
+	o := NewOverlayState()
+
+	r := o.NewReader(base)
+	acc, err := r.ReadAccountData(overlayContract)
+	require.NoError(t, err)
+	require.NotNil(t, acc)
+
+	ok, err := o.Commit(o.NewReader(base), base, func(w StateWriter) error {
+		return w.DeleteAccount(overlayContract, acc)
+	})
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.False(t, o.ReadsValid(r))
+
+	acc, err = o.NewReader(base).ReadAccountData(overlayContract)
+	require.NoError(t, err)
+	require.Nil(t, acc)
+}
+
+func TestOverlayStateIncarnation(t *testing.T) {
+	_, tx := memdb.NewTestTx(t)
+	// the contract was self-destructed at incarnation 3 in a previous block
+	enc := make([]byte, 8)
+	binary.BigEndian.PutUint64(enc, 3)
+	require.NoError(t, tx.Put(kv.IncarnationMap, overlayContract.Bytes(), enc))
+	base := NewPlainStateReader(tx)
+	o := NewOverlayState()
+	createContract := func(incarnation uint64) {
+		ok, err := o.Commit(o.NewReader(base), base, func(w StateWriter) error {
+			return w.UpdateAccountData(overlayContract, nil, &accounts.Account{Initialised: true, Incarnation: incarnation})
+		})
+		require.NoError(t, err)
+		require.True(t, ok)
+	}
+
+	r := o.NewReader(base)
+	inc, err := r.ReadAccountIncarnation(overlayContract)
+	require.NoError(t, err)
+	require.Equal(t, uint64(3), inc)
+
+	// a lower incarnation than the one of the base state is not returned
+	createContract(1)
+	inc, err = o.NewReader(base).ReadAccountIncarnation(overlayContract)
+	require.NoError(t, err)
+	require.Equal(t, uint64(3), inc)
+	require.False(t, o.ReadsValid(r))
+
+	createContract(4)
+	inc, err = o.NewReader(base).ReadAccountIncarnation(overlayContract)
+	require.NoError(t, err)
+	require.Equal(t, uint64(4), inc)
+
+	// nor a lower one than the ones of the block
+	r = o.NewReader(base)
+	_, err = r.ReadAccountIncarnation(overlayContract)
+	require.NoError(t, err)
+	createContract(2)
+	require.True(t, o.ReadsValid(r))
+	inc, err = o.NewReader(base).ReadAccountIncarnation(overlayContract)
+	require.NoError(t, err)
+	require.Equal(t, uint64(4), inc)
+}
+
+func TestOverlayStateCode(t *testing.T) {
+	base := newOverlayBase(t)
+	o := NewOverlayState()
+	code := []byte{0x60, 0x00}
+	codeHash := libcommon.BytesToHash([]byte{0xc0, 0xde})
+
+	r := o.NewReader(base)
+	_, err := r.ReadAccountCode(overlayContract, 1, codeHash)
+	require.NoError(t, err)
+	ok, err := o.Commit(o.NewReader(base), base, func(w StateWriter) error {
+		return w.UpdateAccountCode(overlayContract, 1, codeHash, code)
+	})
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	// the code is looked up by hash, so its reads never conflict
+	require.True(t, o.ReadsValid(r))
+	read, err := r.ReadAccountCode(overlayContract, 1, codeHash)
+	require.NoError(t, err)
+	require.Equal(t, code, read)
+	size, err := r.ReadAccountCodeSize(overlayContract, 1, codeHash)
+	require.NoError(t, err)
+	require.Equal(t, len(code), size)
+}
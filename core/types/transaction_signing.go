@@ -33,8 +33,8 @@ import (
 
 var ErrInvalidChainId = errors.New("invalid chain id for signer")
 
-// MakeSigner returns a Signer based on the given chain config and block number.
-func MakeSigner(config *chain.Config, blockNumber uint64) *Signer {
+// MakeSigner returns a Signer based on the given chain config, block number and block time.
+func MakeSigner(config *chain.Config, blockNumber uint64, blockTime uint64) *Signer {
 	var signer Signer
 	var chainId uint256.Int
 	if config.ChainID != nil {
@@ -45,6 +45,14 @@ func MakeSigner(config *chain.Config, blockNumber uint64) *Signer {
 	}
 	signer.unprotected = true
 	switch {
+	case config.IsCancun(blockTime):
+		// All transaction types are still supported
+		signer.protected = true
+		signer.accesslist = true
+		signer.dynamicfee = true
+		signer.blob = true
+		signer.chainID.Set(&chainId)
+		signer.chainIDMul.Mul(&chainId, u256.Num2)
 	case config.IsLondon(blockNumber):
 		// All transaction types are still supported
 		signer.protected = true
@@ -93,6 +101,9 @@ func LatestSigner(config *chain.Config) *Signer {
 	signer.chainID.Set(chainId)
 	signer.chainIDMul.Mul(chainId, u256.Num2)
 	if config.ChainID != nil {
+		if config.CancunTime != nil {
+			signer.blob = true
+		}
 		if config.LondonBlock != nil {
 			signer.dynamicfee = true
 		}
@@ -128,6 +139,7 @@ func LatestSignerForChainID(chainID *big.Int) *Signer {
 	signer.protected = true
 	signer.accesslist = true
 	signer.dynamicfee = true
+	signer.blob = true
 	return &signer
 }
 
@@ -174,10 +186,11 @@ type Signer struct {
 	protected           bool // Whether this signer should allow transactions with replay protection via chainId
 	accesslist          bool // Whether this signer should allow transactions with access list, superseeds protected
 	dynamicfee          bool // Whether this signer should allow transactions with basefee and tip (instead of gasprice), superseeds accesslist
+	blob                bool // Whether this signer should allow blob transactions, superseeds dynamicfee
 }
 
 func (sg Signer) String() string {
-	return fmt.Sprintf("Signer[chainId=%s,malleable=%t,unprotected=%t,protected=%t,accesslist=%t,dynamicfee=%t", &sg.chainID, sg.maleable, sg.unprotected, sg.protected, sg.accesslist, sg.dynamicfee)
+	return fmt.Sprintf("Signer[chainId=%s,malleable=%t,unprotected=%t,protected=%t,accesslist=%t,dynamicfee=%t,blob=%t", &sg.chainID, sg.maleable, sg.unprotected, sg.protected, sg.accesslist, sg.dynamicfee, sg.blob)
 }
 
 // Sender returns the sender address of the transaction.
@@ -240,6 +253,16 @@ func (sg Signer) SenderWithContext(context *secp256k1.Context, tx Transaction) (
 		// id, add 27 to become equivalent to unprotected Homestead signatures.
 		V.Add(&t.V, u256.Num27)
 		R, S = &t.R, &t.S
+	case *SignedBlobTx:
+		if !sg.blob {
+			return libcommon.Address{}, fmt.Errorf("blob tx is not supported by signer %s", sg)
+		}
+		if !t.GetChainID().Eq(&sg.chainID) {
+			return libcommon.Address{}, ErrInvalidChainId
+		}
+		// Blob txs use 0 and 1 as their recovery id as well
+		V.Add(t.Signature.GetV(), u256.Num27)
+		R, S = t.Signature.GetR(), t.Signature.GetS()
 	default:
 		return libcommon.Address{}, ErrTxTypeNotSupported
 	}
@@ -272,6 +295,11 @@ func (sg Signer) SignatureValues(tx Transaction, sig []byte) (R, S, V *uint256.I
 			return nil, nil, nil, ErrInvalidChainId
 		}
 		R, S, V = decodeSignature(sig)
+	case *SignedBlobTx:
+		if chainID := t.GetChainID(); !chainID.IsZero() && !chainID.Eq(&sg.chainID) {
+			return nil, nil, nil, ErrInvalidChainId
+		}
+		R, S, V = decodeSignature(sig)
 	default:
 		return nil, nil, nil, ErrTxTypeNotSupported
 	}
@@ -289,7 +317,8 @@ func (sg Signer) Equal(other Signer) bool {
 		sg.unprotected == other.unprotected &&
 		sg.protected == other.protected &&
 		sg.accesslist == other.accesslist &&
-		sg.dynamicfee == other.dynamicfee
+		sg.dynamicfee == other.dynamicfee &&
+		sg.blob == other.blob
 }
 
 func decodeSignature(sig []byte) (r, s, v *uint256.Int) {
@@ -25,6 +25,7 @@ import (
 
 	"github.com/ledgerwatch/erigon/common"
 	"github.com/ledgerwatch/erigon/crypto"
+	"github.com/ledgerwatch/erigon/params"
 )
 
 func TestEIP1559Signing(t *testing.T) {
@@ -47,6 +48,56 @@ func TestEIP1559Signing(t *testing.T) {
 	}
 }
 
+func TestBlobTxSigning(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	addr := crypto.PubkeyToAddress(key.PublicKey)
+
+	chainId := uint256.NewInt(18)
+	signer := LatestSignerForChainID(chainId.ToBig())
+	unsigned := &SignedBlobTx{}
+	unsigned.Message.To = AddressOptionalSSZ{Address: (*AddressSSZ)(&addr)}
+	unsigned.Message.BlobVersionedHashes = VersionedHashesView{libcommon.Hash{0x01}}
+	tx, err := SignTx(unsigned, *signer, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	from, err := tx.Sender(*signer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if from != addr {
+		t.Errorf("exected from and address to be equal. Got %x want %x", from, addr)
+	}
+}
+
+func TestBlobTxSigningBeforeCancun(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	addr := crypto.PubkeyToAddress(key.PublicKey)
+
+	config := *params.TestChainConfig
+	config.ShanghaiTime = big.NewInt(0)
+	config.CancunTime = big.NewInt(1000)
+	unsigned := &SignedBlobTx{}
+	unsigned.Message.To = AddressOptionalSSZ{Address: (*AddressSSZ)(&addr)}
+	unsigned.Message.BlobVersionedHashes = VersionedHashesView{libcommon.Hash{0x01}}
+	tx, err := SignTx(unsigned, *LatestSigner(&config), key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := tx.Sender(*MakeSigner(&config, 1, 999)); err == nil {
+		t.Errorf("expected blob tx to be rejected by a Shanghai signer")
+	}
+	from, err := tx.Sender(*MakeSigner(&config, 1, 1000))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if from != addr {
+		t.Errorf("exected from and address to be equal. Got %x want %x", from, addr)
+	}
+}
+
 func TestEIP155Signing(t *testing.T) {
 	key, _ := crypto.GenerateKey()
 	addr := crypto.PubkeyToAddress(key.PublicKey)
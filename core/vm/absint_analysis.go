@@ -0,0 +1,229 @@
+package vm
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/ledgerwatch/erigon-lib/common/hexutility"
+
+	"github.com/ledgerwatch/erigon/visual"
+)
+
+// Limits of the abstract interpretation done by AnalyzeContract, to bound its time and
+// memory on any contract.
+const (
+	analysisCounterLimit  = 1 << 20
+	analysisMaxStackLen   = 1024
+	analysisMaxStackCount = 1 << 16
+)
+
+// ContractAnalysis is the control flow graph of a contract recovered from its bytecode by
+// abstract interpretation (see GenCfg).
+type ContractAnalysis struct {
+	CodeSize int `json:"codeSize"`
+	// Complete tells whether all the jumps were resolved. Otherwise the edges and the
+	// reachable code are those found before the analysis stopped, and Error tells why.
+	Complete bool   `json:"complete"`
+	Error    string `json:"error,omitempty"`
+
+	Blocks      []AnalysisBlock `json:"blocks"`
+	Edges       []AnalysisEdge  `json:"edges"`
+	Unreachable []CodeRange     `json:"unreachable"`
+	BadJumps    []int           `json:"badJumps,omitempty"`
+	// Selectors are the function selectors found in the dispatcher, by matching the
+	// comparisons of 4 byte constants
+	Selectors []hexutility.Bytes `json:"selectors"`
+	// Coverage is the percentage of the instructions found reachable
+	Coverage int `json:"coverage"`
+	// Dot is the graph of the blocks in the DOT language, see WriteDot
+	Dot string `json:"dot,omitempty"`
+}
+
+// AnalysisBlock is a basic block, from a JUMPDEST or the instruction following a JUMPI to
+// the next JUMP or JUMPI, or up to the next block.
+type AnalysisBlock struct {
+	Start     int  `json:"start"`
+	End       int  `json:"end"` // pc of the last instruction
+	Reachable bool `json:"reachable"`
+}
+
+// AnalysisEdge links the basic blocks starting at From and To.
+type AnalysisEdge struct {
+	From int  `json:"from"`
+	To   int  `json:"to"`
+	Jump bool `json:"jump"` // false for the fall through to the next block
+}
+
+// CodeRange is a range of code, from the pc of its first instruction to the one of its last.
+type CodeRange struct {
+	Start int `json:"start"`
+	End   int `json:"end"`
+}
+
+// AnalyzeContract recovers the control flow graph of the code of a contract. Once ctx is
+// done, the analysis stops and returns what was found so far.
+func AnalyzeContract(ctx context.Context, code []byte) (analysis *ContractAnalysis) {
+	analysis = &ContractAnalysis{
+		CodeSize:    len(code),
+		Blocks:      []AnalysisBlock{},
+		Edges:       []AnalysisEdge{},
+		Unreachable: []CodeRange{},
+		Selectors:   []hexutility.Bytes{},
+	}
+	if len(code) == 0 {
+		analysis.Complete = true
+		return analysis
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			analysis.Complete = false
+			analysis.Error = fmt.Sprintf("analysis failed: %v", r)
+		}
+	}()
+
+	cfg, err := GenCfgContext(ctx, code, analysisCounterLimit, analysisMaxStackLen, analysisMaxStackCount, &CfgMetrics{})
+	if err != nil {
+		analysis.Error = err.Error()
+		if reason := cfg.Metrics.GetBadJumpReason(); reason != "" && reason != "Unknown" {
+			analysis.Error += ": " + reason
+		}
+	}
+	analysis.Complete = err == nil && cfg.Metrics.Valid
+	program := cfg.Program
+	program.Stmts[0].covered = true
+
+	// basic blocks and the block of each instruction. The blocks of the program go on until
+	// a jump, through the entries of the following blocks, which are cut here.
+	blockOf := make(map[int]int, len(program.Stmts))
+	for _, block := range program.Blocks {
+		end, reachable := block.Entrypc, false
+		for i, stmt := range block.Stmts {
+			if i > 0 && stmt.isBlockEntry {
+				break
+			}
+			blockOf[stmt.pc] = block.Entrypc
+			if !stmt.inferredAsData {
+				end = stmt.pc
+				reachable = reachable || stmt.covered
+			}
+		}
+		analysis.Blocks = append(analysis.Blocks, AnalysisBlock{Start: block.Entrypc, End: end, Reachable: reachable})
+	}
+
+	// edges between the blocks, from the edges between the instructions
+	edges := map[AnalysisEdge]bool{}
+	for pc1, pc0s := range cfg.PrevEdgeMap {
+		if _, ok := program.Entry2block[pc1]; !ok {
+			continue
+		}
+		for pc0 := range pc0s {
+			from, ok := blockOf[pc0]
+			if !ok {
+				continue
+			}
+			stmt := program.Stmts[pc0]
+			isJump := (stmt.opcode == JUMP || stmt.opcode == JUMPI) && pc1 != pc0+stmt.numBytes
+			edges[AnalysisEdge{From: from, To: pc1, Jump: isJump}] = true
+		}
+	}
+	for e := range edges {
+		analysis.Edges = append(analysis.Edges, e)
+	}
+	sort.Slice(analysis.Edges, func(i, j int) bool {
+		if analysis.Edges[i].From != analysis.Edges[j].From {
+			return analysis.Edges[i].From < analysis.Edges[j].From
+		}
+		return analysis.Edges[i].To < analysis.Edges[j].To
+	})
+
+	for pc := range cfg.BadJumps {
+		analysis.BadJumps = append(analysis.BadJumps, pc)
+	}
+	sort.Ints(analysis.BadJumps)
+
+	// unreachable code, ignoring the push data
+	var instructions, covered int
+	var last *CodeRange
+	for _, stmt := range program.Stmts {
+		if stmt.inferredAsData {
+			continue
+		}
+		instructions++
+		if stmt.covered {
+			covered++
+			last = nil
+			continue
+		}
+		if last == nil {
+			analysis.Unreachable = append(analysis.Unreachable, CodeRange{Start: stmt.pc, End: stmt.pc})
+			last = &analysis.Unreachable[len(analysis.Unreachable)-1]
+		}
+		last.End = stmt.pc
+	}
+	if instructions > 0 {
+		analysis.Coverage = covered * 100 / instructions
+	}
+
+	analysis.Selectors = findSelectors(program)
+	analysis.Dot = analysis.dot()
+	return analysis
+}
+
+// findSelectors returns the 4 byte constants compared by the dispatcher: PUSH4 followed by
+// EQ, or by DUP2 and EQ or XOR as generated by Vyper. Selectors starting with a zero byte
+// are pushed with PUSH3.
+func findSelectors(program *Program) []hexutility.Bytes {
+	selectors := []hexutility.Bytes{}
+	seen := map[string]bool{}
+	stmts := program.Stmts
+	for pc := 0; pc < len(stmts); pc++ {
+		stmt := stmts[pc]
+		if stmt.inferredAsData || (stmt.opcode != PUSH4 && stmt.opcode != PUSH3) {
+			continue
+		}
+		next := pc + stmt.numBytes
+		if next >= len(stmts) {
+			break
+		}
+		match := stmts[next].opcode == EQ
+		if !match && stmts[next].opcode == DUP2 && next+1 < len(stmts) {
+			match = stmts[next+1].opcode == EQ || stmts[next+1].opcode == XOR
+		}
+		if !match {
+			continue
+		}
+		b := stmt.value.Bytes32()
+		selector := hexutility.Bytes(b[28:])
+		if !seen[string(selector)] {
+			seen[string(selector)] = true
+			selectors = append(selectors, selector)
+		}
+	}
+	return selectors
+}
+
+// WriteDot writes the control flow graph in the DOT language, with the unreachable blocks
+// filled.
+func (analysis *ContractAnalysis) WriteDot(w io.Writer) {
+	visual.StartGraph(w, false)
+	for _, block := range analysis.Blocks {
+		visual.Circle(w, blockNodeName(block.Start), fmt.Sprintf("%d - %d", block.Start, block.End), !block.Reachable)
+	}
+	for _, e := range analysis.Edges {
+		visual.Arrow(w, blockNodeName(e.From), blockNodeName(e.To), !e.Jump)
+	}
+	visual.EndGraph(w)
+}
+
+func (analysis *ContractAnalysis) dot() string {
+	var sb strings.Builder
+	analysis.WriteDot(&sb)
+	return sb.String()
+}
+
+func blockNodeName(pc int) string {
+	return fmt.Sprintf("b%d", pc)
+}
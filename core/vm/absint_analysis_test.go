@@ -0,0 +1,80 @@
+package vm
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ledgerwatch/erigon-lib/common/hexutility"
+)
+
+func TestAnalyzeContract(t *testing.T) {
+	// a dispatcher with a single function, reverting otherwise, and a dead function
+	code := hexutility.MustDecodeHex("0x" +
+		"600035" + "60e01c" + "80" + "63aabbccdd" + "14" + "6014" + "57" + // 0: selector == 0xaabbccdd, jump to 20
+		"6000" + "80" + "fd" + // 16: revert
+		"5b" + "00" + // 20: stop
+		"5b" + "6001" + "00") // 22: unreachable
+
+	analysis := AnalyzeContract(context.Background(), code)
+	if !analysis.Complete {
+		t.Fatalf("incomplete analysis: %s", analysis.Error)
+	}
+	wantBlocks := []AnalysisBlock{{0, 15, true}, {16, 19, true}, {20, 21, true}, {22, 25, false}}
+	if !reflect.DeepEqual(analysis.Blocks, wantBlocks) {
+		t.Errorf("blocks: have %v, want %v", analysis.Blocks, wantBlocks)
+	}
+	wantEdges := []AnalysisEdge{{0, 16, false}, {0, 20, true}}
+	if !reflect.DeepEqual(analysis.Edges, wantEdges) {
+		t.Errorf("edges: have %v, want %v", analysis.Edges, wantEdges)
+	}
+	wantUnreachable := []CodeRange{{22, 25}}
+	if !reflect.DeepEqual(analysis.Unreachable, wantUnreachable) {
+		t.Errorf("unreachable: have %v, want %v", analysis.Unreachable, wantUnreachable)
+	}
+	if len(analysis.Selectors) != 1 || analysis.Selectors[0].String() != "0xaabbccdd" {
+		t.Errorf("selectors: have %v, want [0xaabbccdd]", analysis.Selectors)
+	}
+	if !strings.Contains(analysis.Dot, "b0 -> b20") {
+		t.Errorf("missing jump in dot graph:\n%s", analysis.Dot)
+	}
+
+	// the destination of the jump is read from the calldata
+	analysis = AnalyzeContract(context.Background(), hexutility.MustDecodeHex("0x60003556"))
+	if analysis.Complete || !reflect.DeepEqual(analysis.BadJumps, []int{3}) {
+		t.Errorf("unresolved jump: have complete %v, bad jumps %v", analysis.Complete, analysis.BadJumps)
+	}
+
+	// the analysis stops with its context
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	analysis = AnalyzeContract(ctx, code)
+	if analysis.Complete || analysis.Error != context.Canceled.Error() {
+		t.Errorf("cancelled analysis: have complete %v, error %q", analysis.Complete, analysis.Error)
+	}
+}
+
+func TestGenCfgHalts(t *testing.T) {
+	code := hexutility.MustDecodeHex("0x" +
+		"6001" + "6008" + "57" + // 0: jump to 8 if 1
+		"00" + // 5: stop
+		"6002" + // 6: unreachable
+		"5b" + "5f" + "00") // 8: push0, stop
+
+	cfg, err := GenCfg(code, 0, 64, 128, &CfgMetrics{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !cfg.Metrics.Valid {
+		t.Fatalf("invalid cfg: %s", cfg.Metrics.GetBadJumpReason())
+	}
+	for pc, covered := range map[int]bool{4: true, 5: true, 6: false, 9: true, 10: true} {
+		if cfg.Program.Stmts[pc].covered != covered {
+			t.Errorf("pc %d: have covered %v, want %v", pc, cfg.Program.Stmts[pc].covered, covered)
+		}
+	}
+	if !CheckCfg(code, cfg.GenerateProof()) {
+		t.Error("the proof of the cfg doesn't check")
+	}
+}
@@ -16,12 +16,13 @@ type CfgOpSem struct {
 	opNum    int
 	numPush  int
 	numPop   int
+	ends     bool
 }
 
 type CfgAbsSem map[OpCode]*CfgOpSem
 
 func NewCfgAbsSem() *CfgAbsSem {
-	jt := newIstanbulInstructionSet()
+	jt := newCancunInstructionSet()
 
 	sem := CfgAbsSem{}
 
@@ -36,6 +37,7 @@ func NewCfgAbsSem() *CfgAbsSem {
 		opsem.opNum = op.opNum
 		opsem.numPush = op.numPush
 		opsem.numPop = op.numPop
+		opsem.ends = halts(op, OpCode(opcode))
 
 		if opsem.isPush {
 			opsem.numBytes = op.opNum + 1
@@ -83,7 +85,7 @@ func resolveCheck(sem *CfgAbsSem, code []byte, st0 *astate, pc0 int) (map[int]bo
 	succs := make(map[int]bool)
 	jumps := make(map[int]bool)
 
-	if opsem == nil {
+	if opsem == nil || opsem.ends {
 		return succs, jumps, nil
 	}
 
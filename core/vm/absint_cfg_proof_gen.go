@@ -2,6 +2,7 @@ package vm
 
 import (
 	"bufio"
+	"context"
 	"encoding/hex"
 	"errors"
 	"fmt"
@@ -72,8 +73,20 @@ func (program *Program) isJumpDest(value *uint256.Int) bool {
 	return stmt.opcode == JUMPDEST
 }
 
+// halts tells whether the execution stops at the instruction, so that it has no successors.
+func halts(op *operation, opcode OpCode) bool {
+	if op == nil || op.undefined {
+		return true
+	}
+	switch opcode {
+	case STOP, RETURN, REVERT, SELFDESTRUCT, INVALID:
+		return true
+	}
+	return false
+}
+
 func toProgram(code []byte) *Program {
-	jt := newIstanbulInstructionSet()
+	jt := newCancunInstructionSet()
 
 	program := &Program{Code: code}
 
@@ -87,7 +100,7 @@ func toProgram(code []byte) *Program {
 		op := OpCode(code[pc])
 		stmt.opcode = op
 		stmt.operation = jt[op]
-		stmt.ends = stmt.operation == nil
+		stmt.ends = halts(stmt.operation, op)
 		//fmt.Printf("%v %v %v", pc, stmt.opcode, stmt.operation.valid)
 
 		if op.IsPush() {
@@ -633,6 +646,11 @@ func pushNewEdges(workList []edge, edges []edge) []edge {
 }
 
 func GenCfg(code []byte, anlyCounterLimit int, maxStackLen int, maxStackCount int, metrics *CfgMetrics) (cfg *Cfg, err error) {
+	return GenCfgContext(context.Background(), code, anlyCounterLimit, maxStackLen, maxStackCount, metrics)
+}
+
+// GenCfgContext is GenCfg stopping with the error of ctx once it is done.
+func GenCfgContext(ctx context.Context, code []byte, anlyCounterLimit int, maxStackLen int, maxStackCount int, metrics *CfgMetrics) (cfg *Cfg, err error) {
 	program := toProgram(code)
 	cfg = &Cfg{Metrics: metrics}
 	cfg.BadJumps = make(map[int]bool)
@@ -657,6 +675,10 @@ func GenCfg(code []byte, anlyCounterLimit int, maxStackLen int, maxStackCount in
 	workList = pushNewEdges(workList, edgesR1)
 
 	for len(workList) > 0 {
+		if err := ctx.Err(); err != nil {
+			cfg.Metrics.Timeout = true
+			return cfg, err
+		}
 		if anlyCounterLimit > 0 && cfg.Metrics.AnlyCounter > anlyCounterLimit {
 			cfg.Metrics.AnlyCounterLimit = true
 			return cfg, errors.New("reached analysis counter limit")
@@ -47,8 +47,9 @@ type Contract struct {
 	CallerAddress libcommon.Address
 	caller        ContractRef
 	self          ContractRef
-	jumpdests     map[libcommon.Hash][]uint64 // Aggregated result of JUMPDEST analysis.
-	analysis      []uint64                    // Locally cached result of JUMPDEST analysis
+	jumpdests     map[libcommon.Hash][]uint64   // Aggregated result of JUMPDEST analysis.
+	containers    map[libcommon.Hash]*Container // Aggregated result of EOF container parsing.
+	analysis      []uint64                      // Locally cached result of JUMPDEST analysis
 	skipAnalysis  bool
 
 	Code     []byte
@@ -58,6 +59,17 @@ type Contract struct {
 
 	Gas   uint64
 	value *uint256.Int
+
+	// Container is the EOF container of the code being executed, nil for legacy code
+	Container   *Container
+	codeSection uint64
+	returnStack []returnContext
+}
+
+// returnContext is the position in the calling code section to return to after a CALLF.
+type returnContext struct {
+	section uint64
+	pc      uint64
 }
 
 // NewContract returns a new contract environment for the execution of EVM.
@@ -67,8 +79,10 @@ func NewContract(caller ContractRef, object ContractRef, value *uint256.Int, gas
 	if parent, ok := caller.(*Contract); ok {
 		// Reuse JUMPDEST analysis from parent context if available.
 		c.jumpdests = parent.jumpdests
+		c.containers = parent.containers
 	} else {
 		c.jumpdests = make(map[libcommon.Hash][]uint64)
+		c.containers = make(map[libcommon.Hash]*Container)
 	}
 
 	// Gas should be a pointer so it can safely be reduced through the run
@@ -136,6 +150,25 @@ func (c *Contract) isCode(udest uint64) bool {
 	return isCodeFromAnalysis(c.analysis, udest)
 }
 
+// parseContainer parses the code as an EOF container. Like the JUMPDEST analysis, the
+// containers of regular contracts are kept in the parent context by code hash, while
+// initcode is parsed on each run.
+func (c *Contract) parseContainer() (*Container, error) {
+	if c.CodeHash != (libcommon.Hash{}) {
+		if container, exist := c.containers[c.CodeHash]; exist {
+			return container, nil
+		}
+	}
+	container := &Container{}
+	if err := container.UnmarshalBinary(c.Code); err != nil {
+		return nil, err
+	}
+	if c.CodeHash != (libcommon.Hash{}) {
+		c.containers[c.CodeHash] = container
+	}
+	return container, nil
+}
+
 // AsDelegate sets the contract to be a delegate call and returns the current
 // contract (for chaining calls)
 func (c *Contract) AsDelegate() *Contract {
@@ -150,8 +150,17 @@ func init() {
 	}
 }
 
-// ActivePrecompiles returns the precompiles enabled with the current configuration.
+// ActivePrecompiles returns the precompiles enabled with the current configuration,
+// including the ones registered for the chain.
 func ActivePrecompiles(rules *chain.Rules) []libcommon.Address {
+	if custom := activeCustomPrecompiles(rules); len(custom) > 0 {
+		precompiles := Precompiles(rules)
+		addresses := make([]libcommon.Address, 0, len(precompiles))
+		for addr := range precompiles {
+			addresses = append(addresses, addr)
+		}
+		return addresses
+	}
 	switch {
 	case rules.IsCancun:
 		return PrecompiledAddressesCancun
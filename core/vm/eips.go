@@ -17,6 +17,7 @@
 package vm
 
 import (
+	"encoding/binary"
 	"fmt"
 	"sort"
 
@@ -264,3 +265,116 @@ func opDataHash(pc *uint64, interpreter *EVMInterpreter, scope *ScopeContext) ([
 	}
 	return nil, nil
 }
+
+// enableEOF applies the EOF v1 instructions to the given jump table, for the code of EOF
+// containers:
+// - EIP-4200: adds RJUMP, RJUMPI and RJUMPV
+// - EIP-4750: adds CALLF and RETF, and disables JUMP, JUMPI and PC
+func enableEOF(jt *JumpTable) {
+	undefined := &operation{
+		execute:   opUndefined,
+		undefined: true,
+	}
+	jt[JUMP] = undefined
+	jt[JUMPI] = undefined
+	jt[PC] = undefined
+
+	jt[RJUMP] = &operation{
+		execute:     opRjump,
+		constantGas: GasQuickStep,
+		numPop:      0,
+		numPush:     0,
+	}
+	jt[RJUMPI] = &operation{
+		execute:     opRjumpi,
+		constantGas: GasFastishStep,
+		numPop:      1,
+		numPush:     0,
+	}
+	jt[RJUMPV] = &operation{
+		execute:     opRjumpv,
+		constantGas: GasFastishStep,
+		numPop:      1,
+		numPush:     0,
+	}
+	jt[CALLF] = &operation{
+		execute:     opCallf,
+		constantGas: GasFastStep,
+		numPop:      0,
+		numPush:     0,
+	}
+	jt[RETF] = &operation{
+		execute:     opRetf,
+		constantGas: GasFastestStep,
+		numPop:      0,
+		numPush:     0,
+	}
+}
+
+// parseInt16 returns the signed 16 bit immediate at the start of b.
+func parseInt16(b []byte) int64 {
+	return int64(int16(binary.BigEndian.Uint16(b)))
+}
+
+// opRjump implements the RJUMP opcode
+func opRjump(pc *uint64, interpreter *EVMInterpreter, scope *ScopeContext) ([]byte, error) {
+	offset := parseInt16(scope.Contract.Code[*pc+1:])
+	// the offset is relative to the next instruction, and pc will be increased by the interpreter loop
+	*pc = uint64(int64(*pc+3) + offset - 1)
+	return nil, nil
+}
+
+// opRjumpi implements the RJUMPI opcode
+func opRjumpi(pc *uint64, interpreter *EVMInterpreter, scope *ScopeContext) ([]byte, error) {
+	cond := scope.Stack.Pop()
+	if cond.IsZero() {
+		*pc += 2 // skip the immediate
+		return nil, nil
+	}
+	return opRjump(pc, interpreter, scope)
+}
+
+// opRjumpv implements the RJUMPV opcode
+func opRjumpv(pc *uint64, interpreter *EVMInterpreter, scope *ScopeContext) ([]byte, error) {
+	code := scope.Contract.Code
+	idx := scope.Stack.Pop()
+	count := uint64(code[*pc+1])
+	if !idx.LtUint64(count) {
+		*pc += 1 + 2*count // skip the immediates
+		return nil, nil
+	}
+	offset := parseInt16(code[*pc+2+2*idx.Uint64():])
+	*pc = uint64(int64(*pc+2+2*count) + offset - 1)
+	return nil, nil
+}
+
+// opCallf implements the CALLF opcode
+func opCallf(pc *uint64, interpreter *EVMInterpreter, scope *ScopeContext) ([]byte, error) {
+	contract := scope.Contract
+	section := binary.BigEndian.Uint16(contract.Code[*pc+1:])
+	typ := contract.Container.Types[section]
+	if limit := int(params.StackLimit); scope.Stack.Len()+int(typ.MaxStackHeight)-int(typ.Input) > limit {
+		return nil, &ErrStackOverflow{stackLen: scope.Stack.Len(), limit: limit}
+	}
+	if len(contract.returnStack) >= int(params.StackLimit) {
+		return nil, ErrReturnStackExceeded
+	}
+	contract.returnStack = append(contract.returnStack, returnContext{section: contract.codeSection, pc: *pc + 3})
+	contract.codeSection = uint64(section)
+	*pc = contract.Container.codeOffset(int(section)) - 1
+	return nil, nil
+}
+
+// opRetf implements the RETF opcode
+func opRetf(pc *uint64, interpreter *EVMInterpreter, scope *ScopeContext) ([]byte, error) {
+	contract := scope.Contract
+	if len(contract.returnStack) == 0 {
+		// returning from the first code section ends the execution
+		return nil, errStopToken
+	}
+	ctx := contract.returnStack[len(contract.returnStack)-1]
+	contract.returnStack = contract.returnStack[:len(contract.returnStack)-1]
+	contract.codeSection = ctx.section
+	*pc = ctx.pc - 1
+	return nil, nil
+}
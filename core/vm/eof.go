@@ -0,0 +1,223 @@
+package vm
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// EOF v1 container format, https://eips.ethereum.org/EIPS/eip-3540
+const (
+	eof1Version = 1
+
+	kindTypes = 1
+	kindCode  = 2
+	kindData  = 3
+
+	eofMagicLen   = 2
+	eofVersionLen = 1
+
+	maxCodeSections     = 1024
+	maxInputItems       = 127
+	maxOutputItems      = 127
+	maxStackHeight      = 1023
+	functionMetadataLen = 4
+)
+
+var eofMagic = []byte{0xEF, 0x00}
+
+var (
+	ErrInvalidEOF             = errors.New("invalid eof")
+	ErrInvalidMagic           = errors.New("invalid magic")
+	ErrInvalidVersion         = errors.New("invalid version")
+	ErrMissingTypeHeader      = errors.New("missing type header")
+	ErrInvalidTypeSize        = errors.New("invalid type section size")
+	ErrMissingCodeHeader      = errors.New("missing code header")
+	ErrInvalidCodeHeader      = errors.New("invalid code header")
+	ErrInvalidCodeSize        = errors.New("invalid code size")
+	ErrMissingDataHeader      = errors.New("missing data header")
+	ErrMissingTerminator      = errors.New("missing header terminator")
+	ErrTooManyInputs          = errors.New("invalid type content, too many inputs")
+	ErrTooManyOutputs         = errors.New("invalid type content, too many outputs")
+	ErrInvalidSection0Type    = errors.New("invalid section 0 type, input and output should be zero")
+	ErrTooLargeMaxStackHeight = errors.New("invalid type content, max stack height exceeds limit")
+	ErrInvalidContainerSize   = errors.New("invalid container size")
+)
+
+// FunctionMetadata is an entry of the type section, describing a code section.
+type FunctionMetadata struct {
+	Input          uint8
+	Output         uint8
+	MaxStackHeight uint16
+}
+
+// Container is an EOF container object.
+type Container struct {
+	Types []*FunctionMetadata
+	Code  [][]byte
+	Data  []byte
+}
+
+// hasEOFMagic reports whether code starts with the EOF magic.
+func hasEOFMagic(code []byte) bool {
+	return len(code) >= eofMagicLen && bytes.Equal(code[:eofMagicLen], eofMagic)
+}
+
+// headerSize is the size of the header of a container with n code sections.
+func headerSize(n int) int {
+	return eofMagicLen + eofVersionLen +
+		3 + // type section header
+		3 + 2*n + // code section header
+		3 + // data section header
+		1 // terminator
+}
+
+// codeOffset returns the offset of the code section i in the encoded container, which is
+// where the program counter starts when executing it.
+func (c *Container) codeOffset(i int) uint64 {
+	offset := headerSize(len(c.Code)) + len(c.Types)*functionMetadataLen
+	for _, code := range c.Code[:i] {
+		offset += len(code)
+	}
+	return uint64(offset)
+}
+
+// MarshalBinary encodes an EOF container into binary format.
+func (c *Container) MarshalBinary() []byte {
+	b := make([]byte, 0, int(c.codeOffset(len(c.Code)))+len(c.Data))
+	b = append(b, eofMagic...)
+	b = append(b, eof1Version)
+	b = append(b, kindTypes)
+	b = binary.BigEndian.AppendUint16(b, uint16(len(c.Types)*functionMetadataLen))
+	b = append(b, kindCode)
+	b = binary.BigEndian.AppendUint16(b, uint16(len(c.Code)))
+	for _, code := range c.Code {
+		b = binary.BigEndian.AppendUint16(b, uint16(len(code)))
+	}
+	b = append(b, kindData)
+	b = binary.BigEndian.AppendUint16(b, uint16(len(c.Data)))
+	b = append(b, 0) // terminator
+	for _, ty := range c.Types {
+		b = append(b, ty.Input, ty.Output)
+		b = binary.BigEndian.AppendUint16(b, ty.MaxStackHeight)
+	}
+	for _, code := range c.Code {
+		b = append(b, code...)
+	}
+	b = append(b, c.Data...)
+	return b
+}
+
+// UnmarshalBinary decodes an EOF container. The code and data sections refer to b.
+func (c *Container) UnmarshalBinary(b []byte) error {
+	if !hasEOFMagic(b) {
+		return fmt.Errorf("%w: want %x", ErrInvalidMagic, eofMagic)
+	}
+	if len(b) < eofMagicLen+eofVersionLen || b[eofMagicLen] != eof1Version {
+		return fmt.Errorf("%w: want %d", ErrInvalidVersion, eof1Version)
+	}
+	offset := eofMagicLen + eofVersionLen
+
+	// type section header
+	kind, typesSize, err := parseSectionHeader(b, offset)
+	if err != nil || kind != kindTypes {
+		return ErrMissingTypeHeader
+	}
+	offset += 3
+
+	// code section header
+	if offset >= len(b) || b[offset] != kindCode {
+		return ErrMissingCodeHeader
+	}
+	codeSizes, err := parseSectionSizes(b, offset+1)
+	if err != nil {
+		return err
+	}
+	offset += 3 + 2*len(codeSizes)
+	if typesSize != len(codeSizes)*functionMetadataLen {
+		return fmt.Errorf("%w: have %d, want %d", ErrInvalidTypeSize, typesSize, len(codeSizes)*functionMetadataLen)
+	}
+
+	// data section header
+	kind, dataSize, err := parseSectionHeader(b, offset)
+	if err != nil || kind != kindData {
+		return ErrMissingDataHeader
+	}
+	offset += 3
+
+	if offset >= len(b) || b[offset] != 0 {
+		return ErrMissingTerminator
+	}
+	offset++
+
+	expected := offset + typesSize + dataSize
+	for _, size := range codeSizes {
+		expected += size
+	}
+	if len(b) != expected {
+		return fmt.Errorf("%w: have %d, want %d", ErrInvalidContainerSize, len(b), expected)
+	}
+
+	types := make([]*FunctionMetadata, 0, len(codeSizes))
+	for i := range codeSizes {
+		ty := &FunctionMetadata{
+			Input:          b[offset],
+			Output:         b[offset+1],
+			MaxStackHeight: binary.BigEndian.Uint16(b[offset+2:]),
+		}
+		switch {
+		case ty.Input > maxInputItems:
+			return fmt.Errorf("%w: section %d, have %d", ErrTooManyInputs, i, ty.Input)
+		case ty.Output > maxOutputItems:
+			return fmt.Errorf("%w: section %d, have %d", ErrTooManyOutputs, i, ty.Output)
+		case ty.MaxStackHeight > maxStackHeight:
+			return fmt.Errorf("%w: section %d, have %d", ErrTooLargeMaxStackHeight, i, ty.MaxStackHeight)
+		}
+		types = append(types, ty)
+		offset += functionMetadataLen
+	}
+	if types[0].Input != 0 || types[0].Output != 0 {
+		return fmt.Errorf("%w: have %d, %d", ErrInvalidSection0Type, types[0].Input, types[0].Output)
+	}
+
+	code := make([][]byte, len(codeSizes))
+	for i, size := range codeSizes {
+		code[i] = b[offset : offset+size]
+		offset += size
+	}
+
+	c.Types, c.Code, c.Data = types, code, b[offset:]
+	return nil
+}
+
+// parseSectionHeader parses the kind and the size of a section header at offset.
+func parseSectionHeader(b []byte, offset int) (kind, size int, err error) {
+	if offset+3 > len(b) {
+		return 0, 0, ErrInvalidEOF
+	}
+	return int(b[offset]), int(binary.BigEndian.Uint16(b[offset+1:])), nil
+}
+
+// parseSectionSizes parses the number of sections at offset followed by their sizes.
+func parseSectionSizes(b []byte, offset int) ([]int, error) {
+	if offset+2 > len(b) {
+		return nil, ErrInvalidCodeHeader
+	}
+	n := int(binary.BigEndian.Uint16(b[offset:]))
+	if n == 0 || n > maxCodeSections {
+		return nil, fmt.Errorf("%w: %d code sections", ErrInvalidCodeHeader, n)
+	}
+	offset += 2
+	if offset+2*n > len(b) {
+		return nil, ErrInvalidCodeHeader
+	}
+	sizes := make([]int, n)
+	for i := range sizes {
+		sizes[i] = int(binary.BigEndian.Uint16(b[offset+2*i:]))
+		if sizes[i] == 0 {
+			return nil, fmt.Errorf("%w: section %d is empty", ErrInvalidCodeSize, i)
+		}
+	}
+	return sizes, nil
+}
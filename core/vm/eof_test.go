@@ -0,0 +1,222 @@
+package vm
+
+import (
+	"errors"
+	"testing"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+
+	"github.com/ledgerwatch/erigon/common"
+	"github.com/ledgerwatch/erigon/common/u256"
+)
+
+func TestEOFMarshaling(t *testing.T) {
+	for i, test := range []struct {
+		want Container
+		err  error
+	}{
+		{
+			want: Container{
+				Types: []*FunctionMetadata{{Input: 0, Output: 0, MaxStackHeight: 1}},
+				Code:  [][]byte{common.Hex2Bytes("604200")},
+				Data:  []byte{0x01, 0x02, 0x03},
+			},
+		},
+		{
+			want: Container{
+				Types: []*FunctionMetadata{{Input: 0, Output: 0, MaxStackHeight: 1}},
+				Code:  [][]byte{common.Hex2Bytes("604200")},
+				Data:  []byte{},
+			},
+		},
+		{
+			want: Container{
+				Types: []*FunctionMetadata{
+					{Input: 0, Output: 0, MaxStackHeight: 1},
+					{Input: 2, Output: 3, MaxStackHeight: 4},
+					{Input: 1, Output: 1, MaxStackHeight: 1},
+				},
+				Code: [][]byte{
+					common.Hex2Bytes("604200"),
+					common.Hex2Bytes("6042604200"),
+					common.Hex2Bytes("00"),
+				},
+				Data: []byte{},
+			},
+		},
+	} {
+		var (
+			b   = test.want.MarshalBinary()
+			got Container
+		)
+		if err := got.UnmarshalBinary(b); err != nil && err != test.err {
+			t.Fatalf("test %d: got error \"%v\", want \"%v\"", i, err, test.err)
+		}
+		if len(got.Code) != len(test.want.Code) || len(got.Types) != len(test.want.Types) {
+			t.Fatalf("test %d: got %d sections, want %d", i, len(got.Code), len(test.want.Code))
+		}
+		for j := range got.Code {
+			if *got.Types[j] != *test.want.Types[j] {
+				t.Fatalf("test %d: section %d: got type %v, want %v", i, j, got.Types[j], test.want.Types[j])
+			}
+			if common.Bytes2Hex(got.Code[j]) != common.Bytes2Hex(test.want.Code[j]) {
+				t.Fatalf("test %d: section %d: got code %x, want %x", i, j, got.Code[j], test.want.Code[j])
+			}
+			if want := uint64(len(b) - len(test.want.Data)); j == len(got.Code)-1 && got.codeOffset(j)+uint64(len(got.Code[j])) != want {
+				t.Fatalf("test %d: code ends at %d, want %d", i, got.codeOffset(j)+uint64(len(got.Code[j])), want)
+			}
+		}
+		if common.Bytes2Hex(got.Data) != common.Bytes2Hex(test.want.Data) {
+			t.Fatalf("test %d: got data %x, want %x", i, got.Data, test.want.Data)
+		}
+	}
+}
+
+func TestEOFContainerCache(t *testing.T) {
+	code := (&Container{
+		Types: []*FunctionMetadata{{Input: 0, Output: 0, MaxStackHeight: 1}},
+		Code:  [][]byte{common.Hex2Bytes("604200")},
+		Data:  []byte{},
+	}).MarshalBinary()
+
+	parent := NewContract(AccountRef(libcommon.Address{}), AccountRef(libcommon.Address{1}), u256.Num0, 0, false)
+	parent.SetCallCode(&libcommon.Address{1}, libcommon.Hash{1}, code)
+	want, err := parent.parseContainer()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// calls down the tree reuse the container of the same code
+	child := NewContract(parent, AccountRef(libcommon.Address{2}), u256.Num0, 0, false)
+	child.SetCallCode(&libcommon.Address{2}, libcommon.Hash{1}, code)
+	if got, err := child.parseContainer(); err != nil || got != want {
+		t.Fatalf("got container %p (err %v), want the cached %p", got, err, want)
+	}
+
+	// initcode has no code hash and isn't cached
+	child.SetCallCode(nil, libcommon.Hash{}, code)
+	if got, err := child.parseContainer(); err != nil || got == want {
+		t.Fatalf("got container %p (err %v), want a new one", got, err)
+	}
+}
+
+func TestEOFUnmarshalingErrors(t *testing.T) {
+	for i, test := range []struct {
+		code string
+		err  error
+	}{
+		{"", ErrInvalidMagic},
+		{"ef01", ErrInvalidMagic},
+		{"ef0002", ErrInvalidVersion},
+		{"ef0001", ErrMissingTypeHeader},
+		{"ef0001020004", ErrMissingTypeHeader},
+		{"ef0001010004", ErrMissingCodeHeader},
+		{"ef000101000402000003", ErrInvalidCodeHeader},
+		{"ef00010100040200010000", ErrInvalidCodeSize},
+		{"ef0001010008020001000103000000", ErrInvalidTypeSize},
+		{"ef00010100040200010001", ErrMissingDataHeader},
+		{"ef0001010004020001000103000001", ErrMissingTerminator},
+		{"ef0001010004020001000103000000000000", ErrInvalidContainerSize},
+		{"ef0001010004020001000103000000000000000000", ErrInvalidContainerSize},
+		{"ef000101000402000100010300000001000000" + "00", ErrInvalidSection0Type},
+		{"ef000101000402000100010300000000000400" + "00", ErrTooLargeMaxStackHeight},
+		{"ef000101000402000100010300000000000000" + "00", nil},
+	} {
+		var c Container
+		err := c.UnmarshalBinary(common.FromHex(test.code))
+		if !errors.Is(err, test.err) {
+			t.Errorf("test %d: got error \"%v\", want \"%v\"", i, err, test.err)
+		}
+	}
+}
+
+func TestEOFValidation(t *testing.T) {
+	for i, test := range []struct {
+		code     string
+		metadata []*FunctionMetadata
+		err      error
+	}{
+		{"00", []*FunctionMetadata{{0, 0, 0}}, nil},
+		{"3050", []*FunctionMetadata{{0, 0, 1}}, ErrInvalidCodeTermination},
+		{"305000", []*FunctionMetadata{{0, 0, 1}}, nil},
+		{"305000", []*FunctionMetadata{{0, 0, 2}}, ErrInvalidMaxStackHeight},
+		{"5000", []*FunctionMetadata{{0, 0, 0}}, ErrEOFStackUnderflow},
+		{"0c00", []*FunctionMetadata{{0, 0, 0}}, ErrUndefinedInstruction},
+		{"fe", []*FunctionMetadata{{0, 0, 0}}, nil},
+		{"600000", []*FunctionMetadata{{0, 0, 1}}, nil},
+		{"60", []*FunctionMetadata{{0, 0, 1}}, ErrTruncatedImmediate},
+		// JUMP, JUMPI and PC are not defined in EOF code
+		{"600056", []*FunctionMetadata{{0, 0, 1}}, ErrUndefinedInstruction},
+		{"5800", []*FunctionMetadata{{0, 0, 1}}, ErrUndefinedInstruction},
+		// RJUMP backwards into itself
+		{"5cfffd", []*FunctionMetadata{{0, 0, 0}}, nil},
+		// RJUMP out of the code
+		{"5c000100", []*FunctionMetadata{{0, 0, 0}}, ErrInvalidJumpDest},
+		// RJUMP over unreachable code
+		{"5c00013000", []*FunctionMetadata{{0, 0, 0}}, ErrUnreachableCode},
+		// RJUMP into an immediate
+		{"5cffff00", []*FunctionMetadata{{0, 0, 0}}, ErrInvalidJumpDest},
+		{"5c0005", []*FunctionMetadata{{0, 0, 0}}, ErrInvalidJumpDest},
+		// RJUMPI reaching STOP with different stack heights
+		{"60015d0002600100", []*FunctionMetadata{{0, 0, 1}}, ErrConflictingStack},
+		{"60015d00013000", []*FunctionMetadata{{0, 0, 1}}, ErrConflictingStack},
+		{"60015d000100" + "00", []*FunctionMetadata{{0, 0, 1}}, nil},
+		// RJUMPV with two branches
+		{"60005e0200010002" + "00" + "00" + "00", []*FunctionMetadata{{0, 0, 1}}, nil},
+		{"60005e00" + "00", []*FunctionMetadata{{0, 0, 1}}, ErrInvalidBranchCount},
+		{"60005e0100", []*FunctionMetadata{{0, 0, 1}}, ErrTruncatedImmediate},
+		// CALLF and RETF
+		{"b0000100", []*FunctionMetadata{{0, 0, 1}, {0, 1, 1}}, nil},
+		{"b0000200", []*FunctionMetadata{{0, 0, 1}, {0, 1, 1}}, ErrInvalidSectionArgument},
+		{"b0000100", []*FunctionMetadata{{0, 0, 0}, {0, 1, 1}}, ErrInvalidMaxStackHeight},
+		{"b000", []*FunctionMetadata{{0, 0, 0}}, ErrTruncatedImmediate},
+	} {
+		container := &Container{
+			Types: test.metadata,
+			Code:  [][]byte{common.FromHex(test.code)},
+		}
+		err := validateCode(container.Code[0], 0, container.Types, &eofInstructionSet)
+		if !errors.Is(err, test.err) {
+			t.Errorf("test %d (%s): got error \"%v\", want \"%v\"", i, test.code, err, test.err)
+		}
+	}
+}
+
+func TestEOFValidationOfSections(t *testing.T) {
+	for i, test := range []struct {
+		container Container
+		err       error
+	}{
+		{
+			// section 1 pushes a value returned to section 0
+			container: Container{
+				Types: []*FunctionMetadata{{0, 0, 1}, {0, 1, 1}},
+				Code:  [][]byte{common.FromHex("b000015000"), common.FromHex("6001b1")},
+			},
+		},
+		{
+			// section 1 returns two values while declaring one output
+			container: Container{
+				Types: []*FunctionMetadata{{0, 0, 1}, {0, 1, 2}},
+				Code:  [][]byte{common.FromHex("b000015000"), common.FromHex("60016001b1")},
+			},
+			err: ErrInvalidOutputs,
+		},
+		{
+			// section 1 consumes its input
+			container: Container{
+				Types: []*FunctionMetadata{{0, 0, 1}, {1, 0, 1}},
+				Code:  [][]byte{common.FromHex("6001b0000100"), common.FromHex("50b1")},
+			},
+		},
+	} {
+		if err := test.container.ValidateCode(&eofInstructionSet); !errors.Is(err, test.err) {
+			t.Errorf("test %d: got error \"%v\", want \"%v\"", i, err, test.err)
+		}
+		if test.err == nil {
+			if _, err := ParseAndValidateEOF(test.container.MarshalBinary()); err != nil {
+				t.Errorf("test %d: %v", i, err)
+			}
+		}
+	}
+}
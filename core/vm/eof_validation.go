@@ -0,0 +1,210 @@
+package vm
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/ledgerwatch/erigon/params"
+)
+
+var (
+	ErrUndefinedInstruction     = errors.New("undefined instruction")
+	ErrTruncatedImmediate       = errors.New("truncated immediate")
+	ErrInvalidSectionArgument   = errors.New("invalid section argument")
+	ErrInvalidJumpDest          = errors.New("invalid relative jump destination")
+	ErrInvalidBranchCount       = errors.New("invalid number of branches in jump table")
+	ErrInvalidCodeTermination   = errors.New("invalid code termination")
+	ErrConflictingStack         = errors.New("conflicting stack height")
+	ErrEOFStackUnderflow        = errors.New("stack underflow")
+	ErrEOFStackOverflow         = errors.New("stack overflow")
+	ErrInvalidOutputs           = errors.New("invalid number of outputs")
+	ErrInvalidMaxStackHeight    = errors.New("invalid max stack height")
+	ErrUnreachableCode          = errors.New("unreachable code")
+	ErrEOFInitcodeCreatesLegacy = errors.New("eof initcode creating legacy code")
+)
+
+// ParseAndValidateEOF decodes an EOF container and validates its code sections with the
+// EOF instructions.
+func ParseAndValidateEOF(b []byte) (*Container, error) {
+	var c Container
+	if err := c.UnmarshalBinary(b); err != nil {
+		return nil, err
+	}
+	if err := c.ValidateCode(&eofInstructionSet); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// ValidateCode validates each code section of the container against the EOF rules:
+// EIP-3670 for the instructions, EIP-4200 and EIP-4750 for the relative jumps and
+// function calls, and EIP-5450 for the stack heights.
+func (c *Container) ValidateCode(jt *JumpTable) error {
+	for i, code := range c.Code {
+		if err := validateCode(code, i, c.Types, jt); err != nil {
+			return fmt.Errorf("code section %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// validateCode validates the code section of the given index.
+func validateCode(code []byte, section int, metadata []*FunctionMetadata, jt *JumpTable) error {
+	var (
+		op       OpCode
+		analysis = make([]bool, len(code)) // immediate bytes
+	)
+	for pc := 0; pc < len(code); {
+		op = OpCode(code[pc])
+		// INVALID is the designated invalid instruction, it is not undefined
+		if jt[op].undefined && op != INVALID {
+			return fmt.Errorf("%w: %v at pc %d", ErrUndefinedInstruction, op, pc)
+		}
+		size := immediateSize(code, pc)
+		if pc+size >= len(code) && size > 0 {
+			return fmt.Errorf("%w: %v at pc %d", ErrTruncatedImmediate, op, pc)
+		}
+		switch op {
+		case RJUMPV:
+			if code[pc+1] == 0 {
+				return fmt.Errorf("%w: pc %d", ErrInvalidBranchCount, pc)
+			}
+		case CALLF:
+			if arg := int(binary.BigEndian.Uint16(code[pc+1:])); arg >= len(metadata) {
+				return fmt.Errorf("%w: arg %d, have %d sections, pc %d", ErrInvalidSectionArgument, arg, len(metadata), pc)
+			}
+		}
+		for i := pc + 1; i <= pc+size; i++ {
+			analysis[i] = true
+		}
+		pc += 1 + size
+	}
+	if !isTerminal(op) && op != RJUMP {
+		return fmt.Errorf("%w: %v", ErrInvalidCodeTermination, op)
+	}
+	for pc := 0; pc < len(code); pc += 1 + immediateSize(code, pc) {
+		for _, dest := range jumpTargets(code, pc) {
+			if dest < 0 || dest >= len(code) || analysis[dest] {
+				return fmt.Errorf("%w: destination %d, pc %d", ErrInvalidJumpDest, dest, pc)
+			}
+		}
+	}
+	return validateStackHeights(code, section, metadata, jt)
+}
+
+// validateStackHeights checks the stack heights of the code section of the given index,
+// as specified by EIP-5450: each instruction has a single stack height whatever the path
+// leading to it, which never underflows, all of the code is reachable and the maximum
+// height is the declared one.
+func validateStackHeights(code []byte, section int, metadata []*FunctionMetadata, jt *JumpTable) error {
+	heights := make(map[int]int)
+	worklist := []struct{ pc, height int }{{0, int(metadata[section].Input)}}
+	maxHeight := int(metadata[section].Input)
+	for len(worklist) > 0 {
+		pc, height := worklist[len(worklist)-1].pc, worklist[len(worklist)-1].height
+		worklist = worklist[:len(worklist)-1]
+		for pc < len(code) {
+			if want, ok := heights[pc]; ok {
+				if height != want {
+					return fmt.Errorf("%w: have %d, want %d, pc %d", ErrConflictingStack, height, want, pc)
+				}
+				break
+			}
+			heights[pc] = height
+
+			op := OpCode(code[pc])
+			numPop, numPush := jt[op].numPop, jt[op].numPush
+			switch op {
+			case CALLF:
+				callee := metadata[binary.BigEndian.Uint16(code[pc+1:])]
+				numPop, numPush = int(callee.Input), int(callee.Output)
+				if height+int(callee.MaxStackHeight)-int(callee.Input) > int(params.StackLimit) {
+					return fmt.Errorf("%w: pc %d", ErrEOFStackOverflow, pc)
+				}
+			case RETF:
+				if height != int(metadata[section].Output) {
+					return fmt.Errorf("%w: have %d, want %d, pc %d", ErrInvalidOutputs, height, metadata[section].Output, pc)
+				}
+			}
+			if height < numPop {
+				return fmt.Errorf("%w: %v requires %d items, have %d, pc %d", ErrEOFStackUnderflow, op, numPop, height, pc)
+			}
+			height += numPush - numPop
+			if height > maxHeight {
+				maxHeight = height
+			}
+			if maxHeight > maxStackHeight {
+				return fmt.Errorf("%w: pc %d", ErrEOFStackOverflow, pc)
+			}
+
+			for _, dest := range jumpTargets(code, pc) {
+				worklist = append(worklist, struct{ pc, height int }{dest, height})
+			}
+			if isTerminal(op) || op == RJUMP {
+				break
+			}
+			pc += 1 + immediateSize(code, pc)
+		}
+	}
+	if len(heights) != countInstructions(code) {
+		return ErrUnreachableCode
+	}
+	if maxHeight != int(metadata[section].MaxStackHeight) {
+		return fmt.Errorf("%w: computed %d, declared %d", ErrInvalidMaxStackHeight, maxHeight, metadata[section].MaxStackHeight)
+	}
+	return nil
+}
+
+// isTerminal reports whether the instruction ends the execution of a code section.
+func isTerminal(op OpCode) bool {
+	switch op {
+	case STOP, RETURN, REVERT, INVALID, SELFDESTRUCT, RETF:
+		return true
+	}
+	return false
+}
+
+// immediateSize returns the size of the immediate data of the instruction at pc.
+func immediateSize(code []byte, pc int) int {
+	op := OpCode(code[pc])
+	switch {
+	case op >= PUSH1 && op <= PUSH32:
+		return int(op-PUSH1) + 1
+	case op == RJUMP, op == RJUMPI, op == CALLF:
+		return 2
+	case op == RJUMPV:
+		if pc+1 >= len(code) {
+			return 1
+		}
+		return 1 + 2*int(code[pc+1])
+	}
+	return 0
+}
+
+// jumpTargets returns the destinations of the relative jump at pc, none for any other
+// instruction.
+func jumpTargets(code []byte, pc int) []int {
+	switch OpCode(code[pc]) {
+	case RJUMP, RJUMPI:
+		return []int{pc + 3 + int(int16(binary.BigEndian.Uint16(code[pc+1:])))}
+	case RJUMPV:
+		count := int(code[pc+1])
+		next := pc + 2 + 2*count
+		targets := make([]int, count)
+		for i := range targets {
+			targets[i] = next + int(int16(binary.BigEndian.Uint16(code[pc+2+2*i:])))
+		}
+		return targets
+	}
+	return nil
+}
+
+// countInstructions returns the number of instructions of the code.
+func countInstructions(code []byte) int {
+	var n int
+	for pc := 0; pc < len(code); pc += 1 + immediateSize(code, pc) {
+		n++
+	}
+	return n
+}
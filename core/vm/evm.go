@@ -35,20 +35,7 @@ import (
 var emptyCodeHash = crypto.Keccak256Hash(nil)
 
 func (evm *EVM) precompile(addr libcommon.Address) (PrecompiledContract, bool) {
-	var precompiles map[libcommon.Address]PrecompiledContract
-	switch {
-	case evm.chainRules.IsCancun:
-		precompiles = PrecompiledContractsCancun
-	case evm.chainRules.IsBerlin:
-		precompiles = PrecompiledContractsBerlin
-	case evm.chainRules.IsIstanbul:
-		precompiles = PrecompiledContractsIstanbul
-	case evm.chainRules.IsByzantium:
-		precompiles = PrecompiledContractsByzantium
-	default:
-		precompiles = PrecompiledContractsHomestead
-	}
-	p, ok := precompiles[addr]
+	p, ok := evm.precompiles[addr]
 	return p, ok
 }
 
@@ -77,6 +64,8 @@ type EVM struct {
 	chainConfig *chain.Config
 	// chain rules contains the chain rules for the current epoch
 	chainRules *chain.Rules
+	// precompiles are the precompiled contracts enabled by the chain rules
+	precompiles map[libcommon.Address]PrecompiledContract
 	// virtual machine configuration options used to initialise the
 	// evm.
 	config Config
@@ -103,6 +92,7 @@ func NewEVM(blockCtx evmtypes.BlockContext, txCtx evmtypes.TxContext, state evmt
 		chainConfig:     chainConfig,
 		chainRules:      chainConfig.Rules(blockCtx.BlockNumber, blockCtx.Time),
 	}
+	evm.precompiles = Precompiles(evm.chainRules)
 
 	evm.interpreter = NewEVMInterpreter(evm, vmConfig)
 
@@ -125,6 +115,7 @@ func (evm *EVM) ResetBetweenBlocks(blockCtx evmtypes.BlockContext, txCtx evmtype
 	evm.intraBlockState = ibs
 	evm.config = vmConfig
 	evm.chainRules = chainRules
+	evm.precompiles = Precompiles(chainRules)
 
 	evm.interpreter = NewEVMInterpreter(evm, vmConfig)
 
@@ -233,7 +224,7 @@ func (evm *EVM) call(typ OpCode, caller ContractRef, addr libcommon.Address, inp
 
 	// It is allowed to call precompiles, even via delegatecall
 	if isPrecompile {
-		ret, gas, err = RunPrecompiledContract(p, input, gas)
+		ret, gas, err = evm.runPrecompile(p, typ, caller, addr, input, gas, value)
 	} else if len(code) == 0 {
 		// If the account has no code, we can abort here
 		// The depth-check is already done, and precompiles handled above
@@ -392,7 +383,14 @@ func (evm *EVM) create(caller ContractRef, codeAndHash *codeAndHash, gas uint64,
 		return nil, address, gas, nil
 	}
 
-	ret, err = run(evm, contract, nil, false)
+	// EIP-3540: EOF initcode must be a valid container, and creates EOF code only
+	isEOFInitcode := evm.chainRules.IsPrague && hasEOFMagic(codeAndHash.code)
+	if isEOFInitcode {
+		_, err = ParseAndValidateEOF(codeAndHash.code)
+	}
+	if err == nil {
+		ret, err = run(evm, contract, nil, false)
+	}
 
 	// EIP-170: Contract code size limit
 	if err == nil && evm.chainRules.IsSpuriousDragon && len(ret) > params.MaxCodeSize {
@@ -403,9 +401,16 @@ func (evm *EVM) create(caller ContractRef, codeAndHash *codeAndHash, gas uint64,
 		}
 	}
 
-	// Reject code starting with 0xEF if EIP-3541 is enabled.
-	if err == nil && evm.chainRules.IsLondon && len(ret) >= 1 && ret[0] == 0xEF {
+	// Reject code starting with 0xEF if EIP-3541 is enabled, unless it is a valid EOF
+	// container once EOF is enabled.
+	if err == nil && evm.chainRules.IsPrague && hasEOFMagic(ret) {
+		if _, eofErr := ParseAndValidateEOF(ret); eofErr != nil {
+			err = ErrInvalidCode
+		}
+	} else if err == nil && evm.chainRules.IsLondon && len(ret) >= 1 && ret[0] == 0xEF {
 		err = ErrInvalidCode
+	} else if err == nil && isEOFInitcode {
+		err = ErrEOFInitcodeCreatesLegacy
 	}
 	// if the contract creation ran successfully and no errors were returned
 	// calculate the gas required to store the code. If the code could not
@@ -92,6 +92,7 @@ type keccakState interface {
 type EVMInterpreter struct {
 	*VM
 	jt    *JumpTable // EVM instruction table
+	eofJt *JumpTable // EVM instruction table for the code of EOF containers, nil before EOF is enabled
 	depth int
 }
 
@@ -122,10 +123,11 @@ func copyJumpTable(jt *JumpTable) *JumpTable {
 
 // NewEVMInterpreter returns a new instance of the Interpreter.
 func NewEVMInterpreter(evm VMInterpreter, cfg Config) *EVMInterpreter {
-	var jt *JumpTable
+	var jt, eofJt *JumpTable
 	switch {
 	case evm.ChainRules().IsPrague:
 		jt = &pragueInstructionSet
+		eofJt = &eofInstructionSet
 	case evm.ChainRules().IsCancun:
 		jt = &cancunInstructionSet
 	case evm.ChainRules().IsShanghai:
@@ -165,7 +167,8 @@ func NewEVMInterpreter(evm VMInterpreter, cfg Config) *EVMInterpreter {
 			evm: evm,
 			cfg: cfg,
 		},
-		jt: jt,
+		jt:    jt,
+		eofJt: eofJt,
 	}
 }
 
@@ -227,6 +230,17 @@ func (in *EVMInterpreter) Run(contract *Contract, input []byte, readOnly bool) (
 	defer stack.ReturnNormalStack(locStack)
 	contract.Input = input
 
+	// The code of EOF containers runs from the start of its first code section, with the
+	// EOF instructions. It was validated when deployed, and is parsed once per code hash.
+	jt := in.jt
+	if in.eofJt != nil && hasEOFMagic(contract.Code) {
+		if contract.Container, err = contract.parseContainer(); err != nil {
+			return nil, err
+		}
+		jt = in.eofJt
+		_pc = contract.Container.codeOffset(0)
+	}
+
 	if in.cfg.Debug {
 		defer func() {
 			if err != nil {
@@ -255,7 +269,7 @@ func (in *EVMInterpreter) Run(contract *Contract, input []byte, readOnly bool) (
 		// Get the operation from the jump table and validate the stack to ensure there are
 		// enough stack items available to perform the operation.
 		op = contract.GetOp(_pc)
-		operation := in.jt[op]
+		operation := jt[op]
 		cost = operation.constantGas // For tracing
 		// Validate stack
 		if sLen := locStack.Len(); sLen < operation.numPop {
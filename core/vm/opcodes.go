@@ -121,6 +121,9 @@ const (
 	MSIZE    OpCode = 0x59
 	GAS      OpCode = 0x5a
 	JUMPDEST OpCode = 0x5b
+	RJUMP    OpCode = 0x5c
+	RJUMPI   OpCode = 0x5d
+	RJUMPV   OpCode = 0x5e
 	PUSH0    OpCode = 0x5f
 )
 
@@ -217,6 +220,8 @@ const (
 
 // 0xb0 range.
 const (
+	CALLF  OpCode = 0xb0
+	RETF   OpCode = 0xb1
 	TLOAD  OpCode = 0xb3
 	TSTORE OpCode = 0xb4
 )
@@ -301,6 +306,9 @@ var opCodeToString = map[OpCode]string{
 	MSIZE:    "MSIZE",
 	GAS:      "GAS",
 	JUMPDEST: "JUMPDEST",
+	RJUMP:    "RJUMP",
+	RJUMPI:   "RJUMPI",
+	RJUMPV:   "RJUMPV",
 	PUSH0:    "PUSH0",
 
 	// 0x60 range - push.
@@ -377,6 +385,8 @@ var opCodeToString = map[OpCode]string{
 	LOG4:   "LOG4",
 
 	// 0xb0 range.
+	CALLF:  "CALLF",
+	RETF:   "RETF",
 	TLOAD:  "TLOAD",
 	TSTORE: "TSTORE",
 
@@ -470,7 +480,12 @@ var stringToOp = map[string]OpCode{
 	"MSIZE":          MSIZE,
 	"GAS":            GAS,
 	"JUMPDEST":       JUMPDEST,
+	"RJUMP":          RJUMP,
+	"RJUMPI":         RJUMPI,
+	"RJUMPV":         RJUMPV,
 	"PUSH0":          PUSH0,
+	"CALLF":          CALLF,
+	"RETF":           RETF,
 	"TLOAD":          TLOAD,
 	"TSTORE":         TSTORE,
 	"PUSH1":          PUSH1,
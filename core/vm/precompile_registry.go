@@ -0,0 +1,185 @@
+package vm
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/holiman/uint256"
+	"github.com/ledgerwatch/erigon-lib/chain"
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+
+	"github.com/ledgerwatch/erigon/core/vm/evmtypes"
+)
+
+// PrecompileContext gives stateful precompiled contracts access to the call being
+// executed and to the state.
+type PrecompileContext struct {
+	State evmtypes.IntraBlockState
+	Block evmtypes.BlockContext
+	Tx    evmtypes.TxContext
+	Rules *chain.Rules
+
+	Caller  libcommon.Address
+	Address libcommon.Address // address whose storage is accessed, the caller's for DELEGATECALL and CALLCODE
+	Value   *uint256.Int      // nil for DELEGATECALL and STATICCALL
+	// ReadOnly is set in static calls, where the state must not be modified
+	ReadOnly bool
+
+	// Gas is the gas left after RequiredGas was charged
+	Gas uint64
+}
+
+// UseGas charges gas on top of RequiredGas, for costs depending on the state. It returns
+// false if not enough gas is left, in which case the precompile should fail with
+// ErrOutOfGas.
+func (ctx *PrecompileContext) UseGas(gas uint64) bool {
+	if ctx.Gas < gas {
+		return false
+	}
+	ctx.Gas -= gas
+	return true
+}
+
+// StatefulPrecompiledContract is a precompiled contract with access to the state, for
+// custom chains. The EVM calls RunStateful instead of Run; the changes it makes to the
+// state are reverted if it returns an error, like those of a contract.
+type StatefulPrecompiledContract interface {
+	PrecompiledContract
+	RunStateful(ctx *PrecompileContext, input []byte) ([]byte, error)
+}
+
+// CustomPrecompile is a precompiled contract registered for a chain.
+type CustomPrecompile struct {
+	Address  libcommon.Address
+	Contract PrecompiledContract // may be a StatefulPrecompiledContract
+	// Active tells whether the contract is enabled with the given rules, to enable it
+	// from a fork on. Nil means always.
+	Active func(rules *chain.Rules) bool
+}
+
+var ErrPrecompileAlreadyRegistered = errors.New("precompile already registered")
+
+var (
+	customPrecompilesLock sync.RWMutex
+	customPrecompiles     = map[uint64][]CustomPrecompile{} // chain id -> precompiles
+)
+
+// RegisterPrecompile adds a precompiled contract to the chain with the given id, or
+// overrides the one at the same address. It applies to all the EVMs created afterwards,
+// so it is meant to be called before starting the node, e.g. from init functions.
+func RegisterPrecompile(chainID *big.Int, p CustomPrecompile) error {
+	if chainID == nil || !chainID.IsUint64() {
+		return fmt.Errorf("invalid chain id %v", chainID)
+	}
+	if p.Contract == nil {
+		return fmt.Errorf("nil precompiled contract at %x", p.Address)
+	}
+	customPrecompilesLock.Lock()
+	defer customPrecompilesLock.Unlock()
+	id := chainID.Uint64()
+	for _, registered := range customPrecompiles[id] {
+		if registered.Address == p.Address {
+			return fmt.Errorf("%w: chain %d, address %x", ErrPrecompileAlreadyRegistered, id, p.Address)
+		}
+	}
+	customPrecompiles[id] = append(customPrecompiles[id], p)
+	return nil
+}
+
+// UnregisterPrecompiles removes the precompiled contracts registered for the chain with the
+// given id.
+func UnregisterPrecompiles(chainID *big.Int) {
+	customPrecompilesLock.Lock()
+	defer customPrecompilesLock.Unlock()
+	delete(customPrecompiles, chainID.Uint64())
+}
+
+// activeCustomPrecompiles returns the precompiled contracts registered for the chain of
+// the rules and enabled by them.
+func activeCustomPrecompiles(rules *chain.Rules) []CustomPrecompile {
+	if rules.ChainID == nil || !rules.ChainID.IsUint64() {
+		return nil
+	}
+	customPrecompilesLock.RLock()
+	defer customPrecompilesLock.RUnlock()
+	registered := customPrecompiles[rules.ChainID.Uint64()]
+	if len(registered) == 0 {
+		return nil
+	}
+	active := make([]CustomPrecompile, 0, len(registered))
+	for _, p := range registered {
+		if p.Active == nil || p.Active(rules) {
+			active = append(active, p)
+		}
+	}
+	return active
+}
+
+// Precompiles returns the precompiled contracts enabled with the given rules, including
+// the ones registered for the chain.
+func Precompiles(rules *chain.Rules) map[libcommon.Address]PrecompiledContract {
+	var precompiles map[libcommon.Address]PrecompiledContract
+	switch {
+	case rules.IsCancun:
+		precompiles = PrecompiledContractsCancun
+	case rules.IsBerlin:
+		precompiles = PrecompiledContractsBerlin
+	case rules.IsIstanbul:
+		precompiles = PrecompiledContractsIstanbul
+	case rules.IsByzantium:
+		precompiles = PrecompiledContractsByzantium
+	default:
+		precompiles = PrecompiledContractsHomestead
+	}
+	custom := activeCustomPrecompiles(rules)
+	if len(custom) == 0 {
+		return precompiles
+	}
+	merged := make(map[libcommon.Address]PrecompiledContract, len(precompiles)+len(custom))
+	for addr, p := range precompiles {
+		merged[addr] = p
+	}
+	for _, p := range custom {
+		merged[p.Address] = p.Contract
+	}
+	return merged
+}
+
+// runPrecompile runs a precompiled contract called by caller at addr, with the state if it
+// is a StatefulPrecompiledContract.
+func (evm *EVM) runPrecompile(p PrecompiledContract, typ OpCode, caller ContractRef, addr libcommon.Address, input []byte, gas uint64, value *uint256.Int) ([]byte, uint64, error) {
+	sp, ok := p.(StatefulPrecompiledContract)
+	if !ok {
+		return RunPrecompiledContract(p, input, gas)
+	}
+	gasCost := sp.RequiredGas(input)
+	if gas < gasCost {
+		return nil, 0, ErrOutOfGas
+	}
+	ctx := &PrecompileContext{
+		State:   evm.intraBlockState,
+		Block:   evm.context,
+		Tx:      evm.txContext,
+		Rules:   evm.chainRules,
+		Caller:  caller.Address(),
+		Address: addr,
+		Value:   value,
+		Gas:     gas - gasCost,
+	}
+	switch typ {
+	case CALLCODE, DELEGATECALL:
+		ctx.Address = caller.Address()
+	case STATICCALL:
+		ctx.ReadOnly = true
+	}
+	if in, ok := evm.interpreter.(*EVMInterpreter); ok && in.readOnly {
+		ctx.ReadOnly = true
+	}
+	if typ == DELEGATECALL || typ == STATICCALL {
+		ctx.Value = nil
+	}
+	output, err := sp.RunStateful(ctx, input)
+	return output, ctx.Gas, err
+}
@@ -0,0 +1,133 @@
+package vm
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/holiman/uint256"
+	"github.com/ledgerwatch/erigon-lib/chain"
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/kv/memdb"
+
+	"github.com/ledgerwatch/erigon/core/state"
+	"github.com/ledgerwatch/erigon/core/vm/evmtypes"
+	"github.com/ledgerwatch/erigon/params"
+)
+
+// counterPrecompile increments a counter in the storage of the address it is called at.
+type counterPrecompile struct{}
+
+func (c *counterPrecompile) RequiredGas(input []byte) uint64 { return 100 }
+
+func (c *counterPrecompile) Run(input []byte) ([]byte, error) {
+	return nil, errors.New("counter requires state")
+}
+
+func (c *counterPrecompile) RunStateful(ctx *PrecompileContext, input []byte) ([]byte, error) {
+	if ctx.ReadOnly {
+		return nil, ErrWriteProtection
+	}
+	if !ctx.UseGas(params.SstoreSetGas) {
+		return nil, ErrOutOfGas
+	}
+	var counter uint256.Int
+	ctx.State.GetState(ctx.Address, &libcommon.Hash{}, &counter)
+	counter.AddUint64(&counter, 1)
+	ctx.State.SetState(ctx.Address, &libcommon.Hash{}, counter)
+	b := counter.Bytes32()
+	return b[:], nil
+}
+
+// echoPrecompile returns its input.
+type echoPrecompile struct{}
+
+func (c *echoPrecompile) RequiredGas(input []byte) uint64 { return 1 }
+func (c *echoPrecompile) Run(input []byte) ([]byte, error) {
+	return append([]byte("echo:"), input...), nil
+}
+
+func TestPrecompileRegistry(t *testing.T) {
+	config := *params.AllProtocolChanges
+	config.ChainID = big.NewInt(424242)
+	defer UnregisterPrecompiles(config.ChainID)
+
+	counterAddr := libcommon.BytesToAddress([]byte{0x01, 0x00})
+	identityAddr := libcommon.BytesToAddress([]byte{4})
+	if err := RegisterPrecompile(config.ChainID, CustomPrecompile{Address: counterAddr, Contract: &counterPrecompile{}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := RegisterPrecompile(config.ChainID, CustomPrecompile{Address: identityAddr, Contract: &echoPrecompile{}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := RegisterPrecompile(config.ChainID, CustomPrecompile{Address: counterAddr, Contract: &echoPrecompile{}}); !errors.Is(err, ErrPrecompileAlreadyRegistered) {
+		t.Fatalf("registering twice: have %v, want %v", err, ErrPrecompileAlreadyRegistered)
+	}
+	// only activated from Prague on, which the chain does not enable
+	laterAddr := libcommon.BytesToAddress([]byte{0x01, 0x01})
+	if err := RegisterPrecompile(config.ChainID, CustomPrecompile{
+		Address:  laterAddr,
+		Contract: &echoPrecompile{},
+		Active:   func(rules *chain.Rules) bool { return rules.IsPrague },
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	rules := config.Rules(0, 0)
+	active := map[libcommon.Address]bool{}
+	for _, addr := range ActivePrecompiles(rules) {
+		active[addr] = true
+	}
+	if !active[counterAddr] || !active[identityAddr] || active[laterAddr] {
+		t.Fatalf("unexpected active precompiles %v", active)
+	}
+	if len(active) != len(ActivePrecompiles(params.AllProtocolChanges.Rules(0, 0)))+1 {
+		t.Fatalf("have %d active precompiles, want %d", len(active), len(ActivePrecompiles(params.AllProtocolChanges.Rules(0, 0)))+1)
+	}
+	if _, ok := Precompiles(params.AllProtocolChanges.Rules(0, 0))[counterAddr]; ok {
+		t.Fatal("precompile registered for another chain")
+	}
+
+	_, tx := memdb.NewTestTx(t)
+	s := state.New(state.NewPlainStateReader(tx))
+	vmctx := evmtypes.BlockContext{
+		CanTransfer: func(evmtypes.IntraBlockState, libcommon.Address, *uint256.Int) bool { return true },
+		Transfer:    func(evmtypes.IntraBlockState, libcommon.Address, libcommon.Address, *uint256.Int, bool) {},
+	}
+	vmenv := NewEVM(vmctx, evmtypes.TxContext{}, s, &config, Config{})
+	caller := AccountRef(libcommon.Address{})
+
+	// stateful precompile, with its gas function and the gas it charges for the state access
+	for i := uint64(1); i <= 2; i++ {
+		ret, gas, err := vmenv.Call(caller, counterAddr, nil, 100_000, new(uint256.Int), false /* bailout */)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if have := new(uint256.Int).SetBytes(ret).Uint64(); have != i {
+			t.Fatalf("counter: have %d, want %d", have, i)
+		}
+		if used := 100_000 - gas; used != 100+params.SstoreSetGas {
+			t.Fatalf("gas used: have %d, want %d", used, 100+params.SstoreSetGas)
+		}
+	}
+	if _, _, err := vmenv.Call(caller, counterAddr, nil, 1_000, new(uint256.Int), false /* bailout */); !errors.Is(err, ErrOutOfGas) {
+		t.Fatalf("have %v, want %v", err, ErrOutOfGas)
+	}
+	if _, _, err := vmenv.StaticCall(caller, counterAddr, nil, 100_000); !errors.Is(err, ErrWriteProtection) {
+		t.Fatalf("have %v, want %v", err, ErrWriteProtection)
+	}
+	var counter uint256.Int
+	s.GetState(counterAddr, &libcommon.Hash{}, &counter)
+	if counter.Uint64() != 2 {
+		t.Fatalf("counter after failed calls: have %d, want 2", counter.Uint64())
+	}
+
+	// overridden precompile
+	ret, _, err := vmenv.Call(caller, identityAddr, []byte("hi"), 100_000, new(uint256.Int), false /* bailout */)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(ret) != "echo:hi" {
+		t.Fatalf("overridden precompile: have %q", ret)
+	}
+}
@@ -105,6 +105,47 @@ func TestExecute(t *testing.T) {
 	}
 }
 
+func TestExecuteEOF(t *testing.T) {
+	container := &vm.Container{
+		Types: []*vm.FunctionMetadata{
+			{Input: 0, Output: 0, MaxStackHeight: 2},
+			{Input: 0, Output: 1, MaxStackHeight: 1},
+		},
+		Code: [][]byte{
+			{
+				byte(vm.CALLF), 0, 1,
+				byte(vm.PUSH1), 0,
+				byte(vm.MSTORE),
+				byte(vm.PUSH1), 32,
+				byte(vm.PUSH1), 0,
+				byte(vm.RETURN),
+			},
+			{
+				byte(vm.PUSH1), 1,
+				byte(vm.RJUMPI), 0, 3,
+				byte(vm.PUSH1), 7,
+				byte(vm.RETF),
+				byte(vm.PUSH1), 10,
+				byte(vm.RETF),
+			},
+		},
+		Data: []byte{},
+	}
+	code := container.MarshalBinary()
+	if _, err := vm.ParseAndValidateEOF(code); err != nil {
+		t.Fatal("invalid container", err)
+	}
+	ret, _, err := Execute(code, nil, nil, 0)
+	if err != nil {
+		t.Fatal("didn't expect error", err)
+	}
+
+	num := new(big.Int).SetBytes(ret)
+	if num.Cmp(big.NewInt(10)) != 0 {
+		t.Error("Expected 10, got", num)
+	}
+}
+
 func TestCall(t *testing.T) {
 	_, tx := memdb.NewTestTx(t)
 	state := state.New(state.NewDbStateReader(tx))
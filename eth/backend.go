@@ -106,6 +106,7 @@ import (
 	"github.com/ledgerwatch/erigon/turbo/services"
 	"github.com/ledgerwatch/erigon/turbo/shards"
 	"github.com/ledgerwatch/erigon/turbo/snapshotsync"
+	"github.com/ledgerwatch/erigon/turbo/snapshotsync/httpdownloader"
 	"github.com/ledgerwatch/erigon/turbo/snapshotsync/snap"
 	stages2 "github.com/ledgerwatch/erigon/turbo/stages"
 	"github.com/ledgerwatch/erigon/turbo/stages/headerdownload"
@@ -980,6 +981,13 @@ func (s *Ethereum) setUpBlockReader(ctx context.Context, dirs datadir.Dirs, snCo
 		if snConfig.DownloaderAddr != "" {
 			// connect to external Downloader
 			s.downloaderClient, err = downloadergrpc.NewClient(ctx, snConfig.DownloaderAddr)
+		} else if snConfig.HTTPMirror != "" {
+			// download from a mirror, where BitTorrent is not available
+			mirrorServer, err := httpdownloader.New(ctx, snConfig.HTTPMirror, dirs.Snap, httpdownloader.DefaultWorkers, s.logger)
+			if err != nil {
+				return nil, nil, nil, err
+			}
+			s.downloaderClient = direct.NewDownloaderClient(mirrorServer)
 		} else {
 			// start embedded Downloader
 			s.downloader, err = downloader3.New(ctx, downloaderCfg)
@@ -1051,6 +1059,10 @@ func (s *Ethereum) Start() error {
 	go stages2.StageLoop(s.sentryCtx, s.chainConfig, s.chainDB, s.stagedSync, s.sentriesClient.Hd,
 		s.notifications, s.sentriesClient.UpdateHead, s.waitForStageLoopStop, s.config.Sync.LoopThrottle, s.logger, nil)
 
+	if borEngine, ok := s.engine.(*bor.Bor); ok {
+		go borEngine.RunFinalityService(s.sentryCtx, s.chainDB)
+	}
+
 	return nil
 }
 
@@ -136,7 +136,9 @@ type Snapshot struct {
 	Produce        bool // produce new snapshots
 	NoDownloader   bool // possible to use snapshots without calling Downloader
 	Verify         bool // verify snapshots on startup
+	Check          bool // check the content of the segments in the background after startup, see snapshotsync.VerifySegments
 	DownloaderAddr string
+	HTTPMirror     string // download the snapshots from this HTTP(S) mirror instead of BitTorrent
 }
 
 func (s Snapshot) String() string {
@@ -156,6 +158,7 @@ func (s Snapshot) String() string {
 var (
 	FlagSnapKeepBlocks = "snap.keepblocks"
 	FlagSnapStop       = "snap.stop"
+	FlagSnapCheck      = "snap.check"
 )
 
 func NewSnapCfg(enabled, keepBlocks, produce bool) Snapshot {
@@ -0,0 +1,549 @@
+package integrity
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/common/hexutility"
+	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/ledgerwatch/erigon-lib/kv/bitmapdb"
+	"github.com/ledgerwatch/erigon-lib/kv/kvcfg"
+	"github.com/ledgerwatch/erigon-lib/kv/temporal/historyv2"
+	"github.com/ledgerwatch/log/v3"
+
+	"github.com/ledgerwatch/erigon/common/dbutils"
+	"github.com/ledgerwatch/erigon/core/types"
+	"github.com/ledgerwatch/erigon/eth/stagedsync/stages"
+	"github.com/ledgerwatch/erigon/rlp"
+	"github.com/ledgerwatch/erigon/turbo/trie"
+)
+
+// Names of the checks of CheckDB, in the order they run.
+const (
+	CheckCanonicalHeaders = "canonical_headers"
+	CheckTxsBeginEnd      = "txs_begin_end"
+	CheckTxLookup         = "tx_lookup"
+	CheckHistoryIndex     = "history_index"
+	CheckStateRoot        = "state_root"
+)
+
+var AllDBChecks = []string{CheckCanonicalHeaders, CheckTxsBeginEnd, CheckTxLookup, CheckHistoryIndex, CheckStateRoot}
+
+// MaxProblemsPerCheck stops a check after so many problems, the first ones are enough to repair.
+const MaxProblemsPerCheck = 100
+
+// Problem is a broken invariant of the chain db. Stage is the stage which wrote the wrong data,
+// it and the stages depending on it have to be unwound to UnwindTo, and run again.
+type Problem struct {
+	Block    uint64           `json:"block"`
+	Table    string           `json:"table"`
+	Message  string           `json:"message"`
+	Stage    stages.SyncStage `json:"stage"`
+	UnwindTo uint64           `json:"unwindTo"`
+	Repair   []Unwind         `json:"repair"`
+}
+
+// Unwind is a step of the repair of a problem, in the order they have to run.
+type Unwind struct {
+	Stage   stages.SyncStage `json:"stage"`
+	Command string           `json:"command"`
+}
+
+type CheckResult struct {
+	Name      string        `json:"name"`
+	From      uint64        `json:"from"`
+	To        uint64        `json:"to"`
+	Skipped   string        `json:"skipped,omitempty"`
+	Problems  []Problem     `json:"problems"`
+	Truncated bool          `json:"truncated,omitempty"`
+	Duration  time.Duration `json:"duration"`
+}
+
+type CheckDBCfg struct {
+	Checks   []string // AllDBChecks if empty
+	From, To uint64   // blocks to check, To is the progress of the stages if 0
+	// CommandArgs are added to the integration commands of the repairs, like "--datadir=... --chain=..."
+	CommandArgs string
+}
+
+// stageCommands are the integration commands unwinding the stages, in unwind order.
+var stageCommands = []struct {
+	stages  []stages.SyncStage
+	command string
+}{
+	{[]stages.SyncStage{stages.TxLookup}, "stage_tx_lookup"},
+	{[]stages.SyncStage{stages.CallTraces}, "stage_call_traces"},
+	{[]stages.SyncStage{stages.LogIndex}, "stage_log_index"},
+	{[]stages.SyncStage{stages.AccountHistoryIndex, stages.StorageHistoryIndex}, "stage_history"},
+	{[]stages.SyncStage{stages.IntermediateHashes}, "stage_trie"},
+	{[]stages.SyncStage{stages.HashState}, "stage_hash_state"},
+	{[]stages.SyncStage{stages.Execution}, "stage_exec"},
+	{[]stages.SyncStage{stages.Senders}, "stage_senders"},
+	{[]stages.SyncStage{stages.Bodies}, "stage_bodies"},
+	{[]stages.SyncStage{stages.Headers}, "stage_headers"},
+}
+
+// dependentStages are the stages reading the data written by a stage.
+var dependentStages = map[stages.SyncStage][]stages.SyncStage{
+	stages.Headers:             {stages.Bodies, stages.Senders, stages.Execution, stages.HashState, stages.IntermediateHashes, stages.AccountHistoryIndex, stages.StorageHistoryIndex, stages.LogIndex, stages.CallTraces, stages.TxLookup},
+	stages.Bodies:              {stages.Senders, stages.Execution, stages.HashState, stages.IntermediateHashes, stages.AccountHistoryIndex, stages.StorageHistoryIndex, stages.LogIndex, stages.CallTraces, stages.TxLookup},
+	stages.Senders:             {stages.Execution, stages.HashState, stages.IntermediateHashes, stages.AccountHistoryIndex, stages.StorageHistoryIndex, stages.LogIndex, stages.CallTraces},
+	stages.Execution:           {stages.HashState, stages.IntermediateHashes, stages.AccountHistoryIndex, stages.StorageHistoryIndex, stages.LogIndex, stages.CallTraces},
+	stages.HashState:           {stages.IntermediateHashes},
+	stages.AccountHistoryIndex: {stages.StorageHistoryIndex},
+	stages.StorageHistoryIndex: {stages.AccountHistoryIndex},
+}
+
+var errTooManyProblems = errors.New("too many problems")
+
+type dbChecker struct {
+	ctx      context.Context
+	tx       kv.Tx
+	cfg      CheckDBCfg
+	progress map[stages.SyncStage]uint64
+	frozen   uint64 // the blocks up to it are in snapshots
+	result   *CheckResult
+	logEvery *time.Ticker
+	logger   log.Logger
+}
+
+// CheckDB walks the invariants across the tables of the chain db, see AllDBChecks. Blocks in
+// snapshots are not checked. Each problem comes with the stage unwinds repairing it.
+func CheckDB(ctx context.Context, tx kv.Tx, cfg CheckDBCfg, logger log.Logger) ([]CheckResult, error) {
+	c := &dbChecker{ctx: ctx, tx: tx, cfg: cfg, progress: map[stages.SyncStage]uint64{}, logger: logger}
+	for _, stage := range stages.AllStages {
+		progress, err := stages.GetStageProgress(tx, stage)
+		if err != nil {
+			return nil, err
+		}
+		c.progress[stage] = progress
+	}
+	c.frozen = c.progress[stages.Snapshots]
+	c.logEvery = time.NewTicker(20 * time.Second)
+	defer c.logEvery.Stop()
+
+	checks := map[string]func() error{
+		CheckCanonicalHeaders: c.canonicalHeaders,
+		CheckTxsBeginEnd:      c.txsBeginEnd,
+		CheckTxLookup:         c.txLookup,
+		CheckHistoryIndex:     c.historyIndex,
+		CheckStateRoot:        c.stateRoot,
+	}
+	names := cfg.Checks
+	if len(names) == 0 {
+		names = AllDBChecks
+	}
+	results := make([]CheckResult, 0, len(names))
+	for _, name := range names {
+		check, ok := checks[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown check %q, known: %v", name, AllDBChecks)
+		}
+		start := time.Now()
+		c.result = &CheckResult{Name: name}
+		if err := check(); err != nil && !errors.Is(err, errTooManyProblems) {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		c.result.Duration = time.Since(start)
+		for i := range c.result.Problems {
+			c.result.Problems[i].Repair = c.repair(c.result.Problems[i])
+		}
+		logger.Info("[check_db] done", "check", name, "problems", len(c.result.Problems), "skipped", c.result.Skipped, "took", c.result.Duration)
+		results = append(results, *c.result)
+	}
+	return results, nil
+}
+
+// blocks sets the range of the current check to the blocks in the db written by the stage,
+// after its pruning. ok is false if there are none.
+func (c *dbChecker) blocks(stage stages.SyncStage) (from, to uint64, ok bool, err error) {
+	if from, to, err = c.blockRange(stage); err != nil {
+		return 0, 0, false, err
+	}
+	c.result.From, c.result.To = from, to
+	if from > to {
+		c.result.Skipped = fmt.Sprintf("no blocks of stage %s in the db", stage)
+		return from, to, false, nil
+	}
+	return from, to, true, nil
+}
+
+func (c *dbChecker) blockRange(stage stages.SyncStage) (from, to uint64, err error) {
+	pruned, err := stages.GetStagePruneProgress(c.tx, stage)
+	if err != nil {
+		return 0, 0, err
+	}
+	from, to = c.frozen+1, c.progress[stage]
+	if pruned > from {
+		from = pruned
+	}
+	if c.cfg.From > from {
+		from = c.cfg.From
+	}
+	if c.cfg.To != 0 && c.cfg.To < to {
+		to = c.cfg.To
+	}
+	return from, to, nil
+}
+
+func (c *dbChecker) report(block uint64, table string, stage stages.SyncStage, format string, args ...interface{}) error {
+	unwindTo := uint64(0)
+	if block > 0 {
+		unwindTo = block - 1
+	}
+	c.result.Problems = append(c.result.Problems, Problem{Block: block, Table: table, Message: fmt.Sprintf(format, args...), Stage: stage, UnwindTo: unwindTo})
+	if len(c.result.Problems) >= MaxProblemsPerCheck {
+		c.result.Truncated = true
+		return errTooManyProblems
+	}
+	return nil
+}
+
+func (c *dbChecker) tick(block uint64) error {
+	select {
+	case <-c.ctx.Done():
+		return c.ctx.Err()
+	case <-c.logEvery.C:
+		c.logger.Info("[check_db] progress", "check", c.result.Name, "block", block, "to", c.result.To, "problems", len(c.result.Problems))
+	default:
+	}
+	return nil
+}
+
+// repair lists the unwinds of the stage of the problem and of its dependent stages, having
+// written blocks after the block to unwind to. Unwinding to 0 means resetting the stage.
+func (c *dbChecker) repair(p Problem) []Unwind {
+	unwound := map[stages.SyncStage]bool{p.Stage: true}
+	for _, stage := range dependentStages[p.Stage] {
+		unwound[stage] = true
+	}
+	var steps []Unwind
+	for _, sc := range stageCommands {
+		progress, stage := uint64(0), stages.SyncStage("")
+		for _, s := range sc.stages {
+			if unwound[s] && c.progress[s] > p.UnwindTo && c.progress[s] >= progress {
+				progress, stage = c.progress[s], s
+			}
+		}
+		if stage == "" {
+			continue
+		}
+		flag := fmt.Sprintf("--unwind=%d", progress-p.UnwindTo)
+		if p.UnwindTo == 0 {
+			flag = "--reset"
+		}
+		command := "integration " + sc.command
+		if c.cfg.CommandArgs != "" {
+			command += " " + c.cfg.CommandArgs
+		}
+		steps = append(steps, Unwind{Stage: stage, Command: command + " " + flag})
+	}
+	return steps
+}
+
+// canonicalHeaders checks that the canonical hashes are the ones of the headers of their
+// number, chained by their parent hashes, and indexed by HeaderNumber.
+func (c *dbChecker) canonicalHeaders() error {
+	from, to, ok, err := c.blocks(stages.Headers)
+	if err != nil || !ok {
+		return err
+	}
+	var parent libcommon.Hash
+	if from > c.frozen+1 {
+		if parent, err = readCanonicalHash(c.tx, from-1); err != nil {
+			return err
+		}
+	}
+	for n := from; n <= to; n++ {
+		if err := c.tick(n); err != nil {
+			return err
+		}
+		hash, err := readCanonicalHash(c.tx, n)
+		if err != nil {
+			return err
+		}
+		if hash == (libcommon.Hash{}) {
+			if err := c.report(n, kv.HeaderCanonical, stages.Headers, "no canonical hash"); err != nil {
+				return err
+			}
+			parent = hash
+			continue
+		}
+		raw, err := c.tx.GetOne(kv.Headers, dbutils.HeaderKey(n, hash))
+		if err != nil {
+			return err
+		}
+		header, table, problem := new(types.Header), kv.Headers, ""
+		if len(raw) == 0 {
+			problem = fmt.Sprintf("no header of canonical hash %x", hash)
+		} else if err := rlp.DecodeBytes(raw, header); err != nil {
+			problem = fmt.Sprintf("header %x can't be decoded: %v", hash, err)
+		} else if header.Number == nil || header.Number.Uint64() != n {
+			problem = fmt.Sprintf("header %x has number %v", hash, header.Number)
+		} else if header.Hash() != hash {
+			problem = fmt.Sprintf("header of canonical hash %x has hash %x", hash, header.Hash())
+		} else if parent != (libcommon.Hash{}) && header.ParentHash != parent {
+			table, problem = kv.HeaderCanonical, fmt.Sprintf("header %x has parent %x, the canonical hash of block %d is %x", hash, header.ParentHash, n-1, parent)
+		}
+		parent = hash
+		if problem != "" {
+			if err := c.report(n, table, stages.Headers, "%s", problem); err != nil {
+				return err
+			}
+			continue
+		}
+		number, err := c.tx.GetOne(kv.HeaderNumber, hash[:])
+		if err != nil {
+			return err
+		}
+		if len(number) != 8 || binary.BigEndian.Uint64(number) != n {
+			if err := c.report(n, kv.HeaderNumber, stages.Headers, "canonical hash %x has number %x", hash, number); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// txsBeginEnd checks that the canonical bodies have their system txs, that their tx ranges
+// don't overlap (there are gaps after reorgs), and that their txs are in EthTx.
+func (c *dbChecker) txsBeginEnd() error {
+	if v3, err := kvcfg.TransactionsV3.Enabled(c.tx); err != nil {
+		return err
+	} else if v3 {
+		c.result.Skipped = "transactions v3"
+		return nil
+	}
+	from, to, ok, err := c.blocks(stages.Bodies)
+	if err != nil || !ok {
+		return err
+	}
+	var end uint64 // the first tx id after the previous canonical body
+	for n := from; n <= to; n++ {
+		if err := c.tick(n); err != nil {
+			return err
+		}
+		body, err := c.canonicalBody(n, stages.Bodies)
+		if err != nil {
+			return err
+		}
+		if body == nil {
+			continue
+		}
+		if body.TxAmount < 2 {
+			if err := c.report(n, kv.BlockBody, stages.Bodies, "body has %d txs, without the 2 system txs", body.TxAmount); err != nil {
+				return err
+			}
+			continue
+		}
+		if body.BaseTxId < end {
+			if err := c.report(n, kv.BlockBody, stages.Bodies, "txs %d-%d overlap with the previous block, ending at %d", body.BaseTxId, body.BaseTxId+uint64(body.TxAmount)-1, end-1); err != nil {
+				return err
+			}
+		}
+		end = body.BaseTxId + uint64(body.TxAmount)
+
+		found := uint32(0)
+		if body.TxAmount > 2 {
+			if err := c.tx.ForAmount(kv.EthTx, hexutility.EncodeTs(body.BaseTxId+1), body.TxAmount-2, func(k, v []byte) error {
+				found++
+				return nil
+			}); err != nil {
+				return err
+			}
+		}
+		if found != body.TxAmount-2 {
+			if err := c.report(n, kv.EthTx, stages.Bodies, "%d of the %d txs from %d are missing", body.TxAmount-2-found, body.TxAmount-2, body.BaseTxId+1); err != nil {
+				return err
+			}
+		}
+	}
+	sequence, err := c.tx.ReadSequence(kv.EthTx)
+	if err != nil {
+		return err
+	}
+	if sequence < end {
+		return c.report(to, kv.EthTx, stages.Bodies, "sequence of %s is %d, block %d ends at %d", kv.EthTx, sequence, to, end)
+	}
+	return nil
+}
+
+// txLookup checks that the txs of the canonical bodies are in TxLookup, at their block.
+func (c *dbChecker) txLookup() error {
+	if v3, err := kvcfg.TransactionsV3.Enabled(c.tx); err != nil {
+		return err
+	} else if v3 {
+		c.result.Skipped = "transactions v3"
+		return nil
+	}
+	from, to, ok, err := c.blocks(stages.TxLookup)
+	if err != nil || !ok {
+		return err
+	}
+	for n := from; n <= to; n++ {
+		if err := c.tick(n); err != nil {
+			return err
+		}
+		body, err := c.canonicalBody(n, stages.TxLookup)
+		if err != nil {
+			return err
+		}
+		if body == nil || body.TxAmount <= 2 {
+			continue
+		}
+		if err := c.tx.ForAmount(kv.EthTx, hexutility.EncodeTs(body.BaseTxId+1), body.TxAmount-2, func(k, v []byte) error {
+			txn, err := types.UnmarshalTransactionFromBinary(v)
+			if err != nil {
+				return c.report(n, kv.EthTx, stages.Bodies, "tx %d can't be decoded: %v", binary.BigEndian.Uint64(k), err)
+			}
+			have, err := c.tx.GetOne(kv.TxLookup, txn.Hash().Bytes())
+			if err != nil {
+				return err
+			}
+			if have == nil {
+				return c.report(n, kv.TxLookup, stages.TxLookup, "no entry of tx %x", txn.Hash())
+			}
+			if block := new(big.Int).SetBytes(have).Uint64(); block != n {
+				return c.report(n, kv.TxLookup, stages.TxLookup, "tx %x is at block %d", txn.Hash(), block)
+			}
+			return nil
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// historyIndex checks that the keys of the change sets are in the history indices at their block.
+func (c *dbChecker) historyIndex() error {
+	if v3, err := kvcfg.HistoryV3.Enabled(c.tx); err != nil {
+		return err
+	} else if v3 {
+		c.result.Skipped = "history v3"
+		return nil
+	}
+	errStop := errors.New("stop")
+	checked := false
+	for _, h := range []struct {
+		changeSet, index string
+		stage            stages.SyncStage
+	}{
+		{kv.AccountChangeSet, kv.AccountsHistory, stages.AccountHistoryIndex},
+		{kv.StorageChangeSet, kv.StorageHistory, stages.StorageHistoryIndex},
+	} {
+		from, to, err := c.blockRange(h.stage)
+		if err != nil {
+			return err
+		}
+		if from > to {
+			continue
+		}
+		if !checked || from < c.result.From {
+			c.result.From = from
+		}
+		if to > c.result.To {
+			c.result.To = to
+		}
+		checked = true
+		if err := historyv2.ForEach(c.tx, h.changeSet, hexutility.EncodeTs(from), func(blockN uint64, k, v []byte) error {
+			if blockN > to {
+				return errStop
+			}
+			if err := c.tick(blockN); err != nil {
+				return err
+			}
+			bm, err := bitmapdb.Get64(c.tx, h.index, dbutils.CompositeKeyWithoutIncarnation(k), blockN-1, blockN+1)
+			if err != nil {
+				return err
+			}
+			if !bm.Contains(blockN) {
+				return c.report(blockN, h.index, h.stage, "key %x of %s is not in the index", k, h.changeSet)
+			}
+			return nil
+		}); err != nil && !errors.Is(err, errStop) {
+			return err
+		}
+	}
+	if !checked {
+		c.result.Skipped = "no blocks of the history indices in the db"
+	}
+	return nil
+}
+
+// stateRoot checks that IntermediateHashes reproduces the state root of the header of the
+// last executed block.
+func (c *dbChecker) stateRoot() error {
+	if v3, err := kvcfg.HistoryV3.Enabled(c.tx); err != nil {
+		return err
+	} else if v3 {
+		c.result.Skipped = "history v3"
+		return nil
+	}
+	block := c.progress[stages.Execution]
+	c.result.From, c.result.To = block, block
+	if block == 0 || c.progress[stages.HashState] != block || c.progress[stages.IntermediateHashes] != block {
+		c.result.Skipped = fmt.Sprintf("stages %s, %s and %s are not at the same block", stages.Execution, stages.HashState, stages.IntermediateHashes)
+		return nil
+	}
+	if block < c.cfg.From || (c.cfg.To != 0 && block > c.cfg.To) {
+		c.result.Skipped = fmt.Sprintf("stage %s is at block %d, out of the range", stages.Execution, block)
+		return nil
+	}
+	hash, err := readCanonicalHash(c.tx, block)
+	if err != nil {
+		return err
+	}
+	raw, err := c.tx.GetOne(kv.Headers, dbutils.HeaderKey(block, hash))
+	if err != nil {
+		return err
+	}
+	header := new(types.Header)
+	if len(raw) == 0 || rlp.DecodeBytes(raw, header) != nil {
+		c.result.Skipped = fmt.Sprintf("no header of block %d", block)
+		return nil
+	}
+	root, err := trie.CalcRoot("check_db", c.tx)
+	if err != nil {
+		return err
+	}
+	if root != header.Root {
+		// the intermediate hashes can't be unwound from a wrong state
+		c.result.Problems = append(c.result.Problems, Problem{Block: block, Table: kv.TrieOfAccounts, Stage: stages.IntermediateHashes,
+			Message: fmt.Sprintf("state root is %x, the one of the header is %x (if resetting %s doesn't repair it, %s is wrong)", root, header.Root, stages.IntermediateHashes, stages.HashState)})
+	}
+	return nil
+}
+
+// canonicalBody reads the canonical body of a block, reporting a problem of the stage if
+// there is none. It returns nil then.
+func (c *dbChecker) canonicalBody(n uint64, stage stages.SyncStage) (*types.BodyForStorage, error) {
+	hash, err := readCanonicalHash(c.tx, n)
+	if err != nil {
+		return nil, err
+	}
+	v, err := c.tx.GetOne(kv.BlockBody, dbutils.BlockBodyKey(n, hash))
+	if err != nil {
+		return nil, err
+	}
+	if len(v) == 0 {
+		return nil, c.report(n, kv.BlockBody, stage, "no body of canonical hash %x", hash)
+	}
+	body := new(types.BodyForStorage)
+	if err := rlp.DecodeBytes(v, body); err != nil {
+		return nil, c.report(n, kv.BlockBody, stages.Bodies, "body can't be decoded: %v", err)
+	}
+	return body, nil
+}
+
+func readCanonicalHash(tx kv.Getter, n uint64) (libcommon.Hash, error) {
+	v, err := tx.GetOne(kv.HeaderCanonical, hexutility.EncodeTs(n))
+	if err != nil {
+		return libcommon.Hash{}, err
+	}
+	return libcommon.BytesToHash(v), nil
+}
@@ -0,0 +1,85 @@
+package integrity
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/ledgerwatch/erigon-lib/kv/memdb"
+	"github.com/ledgerwatch/log/v3"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ledgerwatch/erigon/core/rawdb"
+	"github.com/ledgerwatch/erigon/core/types"
+	"github.com/ledgerwatch/erigon/eth/stagedsync/stages"
+)
+
+func TestCheckDB(t *testing.T) {
+	_, tx := memdb.NewTestTx(t)
+	logger := log.New()
+	cfg := CheckDBCfg{Checks: []string{CheckCanonicalHeaders, CheckTxsBeginEnd, CheckTxLookup, CheckHistoryIndex, CheckStateRoot}, CommandArgs: "--datadir=dir"}
+
+	var parent libcommon.Hash
+	hashes := map[uint64]libcommon.Hash{}
+	for n := uint64(0); n <= 3; n++ {
+		header := &types.Header{Number: big.NewInt(int64(n)), ParentHash: parent}
+		rawdb.WriteHeader(tx, header)
+		require.NoError(t, rawdb.WriteCanonicalHash(tx, header.Hash(), n))
+		require.NoError(t, rawdb.WriteBodyForStorage(tx, header.Hash(), n, &types.BodyForStorage{BaseTxId: n * 2, TxAmount: 2}))
+		parent, hashes[n] = header.Hash(), header.Hash()
+	}
+	_, err := tx.IncrementSequence(kv.EthTx, 8)
+	require.NoError(t, err)
+	for _, stage := range []stages.SyncStage{stages.Headers, stages.Bodies, stages.Senders, stages.Execution, stages.TxLookup} {
+		require.NoError(t, stages.SaveStageProgress(tx, stage, 3))
+	}
+
+	results, err := CheckDB(context.Background(), tx, cfg, logger)
+	require.NoError(t, err)
+	require.Len(t, results, 5)
+	for _, r := range results {
+		require.Empty(t, r.Problems, r.Name)
+	}
+	require.Equal(t, uint64(1), results[0].From)
+	require.Equal(t, uint64(3), results[0].To)
+	require.NotEmpty(t, results[4].Skipped) // no IntermediateHashes
+
+	// the txs of block 3 overlap with the ones of block 2
+	require.NoError(t, rawdb.WriteBodyForStorage(tx, hashes[3], 3, &types.BodyForStorage{BaseTxId: 5, TxAmount: 2}))
+	results, err = CheckDB(context.Background(), tx, cfg, logger)
+	require.NoError(t, err)
+	require.Empty(t, results[0].Problems)
+	require.Len(t, results[1].Problems, 1)
+	problem := results[1].Problems[0]
+	require.Equal(t, uint64(3), problem.Block)
+	require.Equal(t, stages.Bodies, problem.Stage)
+	require.Equal(t, uint64(2), problem.UnwindTo)
+	require.Equal(t, []Unwind{
+		{stages.TxLookup, "integration stage_tx_lookup --datadir=dir --unwind=1"},
+		{stages.Execution, "integration stage_exec --datadir=dir --unwind=1"},
+		{stages.Senders, "integration stage_senders --datadir=dir --unwind=1"},
+		{stages.Bodies, "integration stage_bodies --datadir=dir --unwind=1"},
+	}, problem.Repair)
+
+	// a canonical hash without header, breaking the chain
+	require.NoError(t, rawdb.WriteCanonicalHash(tx, libcommon.Hash{1}, 2))
+	results, err = CheckDB(context.Background(), tx, CheckDBCfg{Checks: []string{CheckCanonicalHeaders}}, logger)
+	require.NoError(t, err)
+	require.Len(t, results[0].Problems, 2)
+	require.Equal(t, uint64(2), results[0].Problems[0].Block)
+	require.Equal(t, kv.Headers, results[0].Problems[0].Table)
+	require.Equal(t, uint64(3), results[0].Problems[1].Block)
+	require.Equal(t, stages.Headers, results[0].Problems[0].Stage)
+	require.Len(t, results[0].Problems[0].Repair, 5) // headers, bodies, senders, execution, tx lookup
+
+	// blocks in snapshots are not checked
+	require.NoError(t, stages.SaveStageProgress(tx, stages.Snapshots, 2))
+	results, err = CheckDB(context.Background(), tx, CheckDBCfg{Checks: []string{CheckCanonicalHeaders}}, logger)
+	require.NoError(t, err)
+	require.Len(t, results[0].Problems, 1)
+
+	_, err = CheckDB(context.Background(), tx, CheckDBCfg{Checks: []string{"unknown"}}, logger)
+	require.Error(t, err)
+}
@@ -22,6 +22,7 @@ import (
 	libcommon "github.com/ledgerwatch/erigon-lib/common"
 
 	"github.com/ledgerwatch/erigon/core/forkid"
+	"github.com/ledgerwatch/erigon/p2p/enode"
 	"github.com/ledgerwatch/erigon/p2p/enr"
 	"github.com/ledgerwatch/erigon/rlp"
 )
@@ -56,3 +57,25 @@ func LoadENRForkID(r *enr.Record) (*forkid.ID, error) {
 	}
 	return &entry.ForkID, nil
 }
+
+// NewNodeFilter returns a dial candidate filter which drops nodes advertising an
+// `eth` ENR entry with a fork ID that the local chain would reject in the handshake.
+// Nodes without the entry (e.g. discv4 nodes whose record was never requested) are
+// only dropped if requireEntry is set. forkFilter is called per node, so it can
+// follow the moving chain head; a nil filter accepts every node.
+func NewNodeFilter(forkFilter func() forkid.Filter, requireEntry bool) func(*enode.Node) bool {
+	return func(n *enode.Node) bool {
+		id, err := LoadENRForkID(n.Record())
+		if err != nil {
+			return false
+		}
+		if id == nil {
+			return !requireEntry
+		}
+		filter := forkFilter()
+		if filter == nil {
+			return true
+		}
+		return filter(*id) == nil
+	}
+}
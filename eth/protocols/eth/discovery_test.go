@@ -0,0 +1,50 @@
+package eth
+
+import (
+	"testing"
+
+	"github.com/ledgerwatch/erigon/core/forkid"
+	"github.com/ledgerwatch/erigon/p2p/enode"
+	"github.com/ledgerwatch/erigon/p2p/enr"
+	"github.com/ledgerwatch/erigon/params"
+)
+
+func TestNodeFilter(t *testing.T) {
+	const headHeight, headTime = 17034870, 1681338455
+	forkFilter := func() forkid.Filter {
+		heightForks, timeForks := forkid.GatherForks(params.MainnetChainConfig)
+		return forkid.NewFilterFromForks(heightForks, timeForks, params.MainnetGenesisHash, headHeight, headTime)
+	}
+	makeNode := func(id *forkid.ID) *enode.Node {
+		var r enr.Record
+		if id != nil {
+			r.Set(&enrEntry{ForkID: *id})
+		}
+		return enode.SignNull(&r, enode.ID{})
+	}
+	compatible := forkid.NewID(params.MainnetChainConfig, params.MainnetGenesisHash, headHeight, headTime)
+	incompatible := forkid.ID{Hash: [4]byte{0xde, 0xad, 0xbe, 0xef}}
+
+	tests := []struct {
+		node         *enode.Node
+		requireEntry bool
+		want         bool
+	}{
+		{makeNode(&compatible), false, true},
+		{makeNode(&compatible), true, true},
+		{makeNode(&incompatible), false, false},
+		{makeNode(nil), false, true},
+		{makeNode(nil), true, false},
+	}
+	for i, tt := range tests {
+		if have := NewNodeFilter(forkFilter, tt.requireEntry)(tt.node); have != tt.want {
+			t.Errorf("test %d: filter mismatch: have %v, want %v", i, have, tt.want)
+		}
+	}
+
+	// Without a known chain state every advertised fork ID is accepted.
+	noState := func() forkid.Filter { return nil }
+	if !NewNodeFilter(noState, false)(makeNode(&incompatible)) {
+		t.Errorf("node rejected without local fork filter")
+	}
+}
@@ -307,6 +307,198 @@ func StateStages(ctx context.Context, headers HeadersCfg, bodies BodiesCfg, bloc
 	}
 }
 
+// PruneStages are the stages whose data depends on the prune mode, to prune an existing db offline by Sync.RunPrune
+// in PruneStagesOrder. They have nothing to do forward and on unwind.
+func PruneStages(ctx context.Context, exec ExecuteBlockCfg, history HistoryCfg, logIndex LogIndexCfg, callTraces CallTracesCfg, txLookup TxLookupCfg) []*Stage {
+	noForward := func(firstCycle bool, badBlockUnwind bool, s *StageState, u Unwinder, tx kv.RwTx, logger log.Logger) error {
+		return nil
+	}
+	noUnwind := func(firstCycle bool, u *UnwindState, s *StageState, tx kv.RwTx, logger log.Logger) error {
+		return nil
+	}
+	return []*Stage{
+		{
+			ID:          stages.Execution,
+			Description: "Prune change sets, receipts and call traces",
+			Forward:     noForward,
+			Unwind:      noUnwind,
+			Prune: func(firstCycle bool, p *PruneState, tx kv.RwTx, logger log.Logger) error {
+				return PruneExecutionStage(p, tx, exec, ctx, firstCycle)
+			},
+		},
+		{
+			ID:          stages.CallTraces,
+			Description: "Prune call traces index",
+			Forward:     noForward,
+			Unwind:      noUnwind,
+			Prune: func(firstCycle bool, p *PruneState, tx kv.RwTx, logger log.Logger) error {
+				return PruneCallTraces(p, tx, callTraces, ctx, logger)
+			},
+		},
+		{
+			ID:          stages.AccountHistoryIndex,
+			Description: "Prune account history index",
+			Forward:     noForward,
+			Unwind:      noUnwind,
+			Prune: func(firstCycle bool, p *PruneState, tx kv.RwTx, logger log.Logger) error {
+				return PruneAccountHistoryIndex(p, tx, history, ctx, logger)
+			},
+		},
+		{
+			ID:          stages.StorageHistoryIndex,
+			Description: "Prune storage history index",
+			Forward:     noForward,
+			Unwind:      noUnwind,
+			Prune: func(firstCycle bool, p *PruneState, tx kv.RwTx, logger log.Logger) error {
+				return PruneStorageHistoryIndex(p, tx, history, ctx, logger)
+			},
+		},
+		{
+			ID:          stages.LogIndex,
+			Description: "Prune receipt logs index",
+			Forward:     noForward,
+			Unwind:      noUnwind,
+			Prune: func(firstCycle bool, p *PruneState, tx kv.RwTx, logger log.Logger) error {
+				return PruneLogIndex(p, tx, logIndex, ctx, logger)
+			},
+		},
+		{
+			ID:          stages.TxLookup,
+			Description: "Prune tx lookup index",
+			Forward:     noForward,
+			Unwind:      noUnwind,
+			Prune: func(firstCycle bool, p *PruneState, tx kv.RwTx, logger log.Logger) error {
+				return PruneTxLookup(p, tx, txLookup, ctx, firstCycle, logger)
+			},
+		},
+	}
+}
+
+// ReplicaStages are the stages of a replica following a primary Erigon: the rows of the stages up to Execution are
+// copied from the primary, the following stages are run on them.
+func ReplicaStages(ctx context.Context, exec ExecuteBlockCfg, hashState HashStateCfg, trieCfg TrieCfg, history HistoryCfg, logIndex LogIndexCfg, callTraces CallTracesCfg, txLookup TxLookupCfg, finish FinishCfg) []*Stage {
+	return []*Stage{
+		{
+			ID:          stages.Execution,
+			Description: "Copy blocks and state from the primary",
+			Forward: func(firstCycle bool, badBlockUnwind bool, s *StageState, u Unwinder, tx kv.RwTx, logger log.Logger) error {
+				return nil
+			},
+			Unwind: func(firstCycle bool, u *UnwindState, s *StageState, tx kv.RwTx, logger log.Logger) error {
+				return u.Done(tx)
+			},
+			Prune: func(firstCycle bool, p *PruneState, tx kv.RwTx, logger log.Logger) error {
+				return PruneExecutionStage(p, tx, exec, ctx, firstCycle)
+			},
+		},
+		{
+			ID:          stages.HashState,
+			Description: "Hash the key in the state",
+			Forward: func(firstCycle bool, badBlockUnwind bool, s *StageState, u Unwinder, tx kv.RwTx, logger log.Logger) error {
+				return SpawnHashStateStage(s, tx, hashState, ctx, logger)
+			},
+			Unwind: func(firstCycle bool, u *UnwindState, s *StageState, tx kv.RwTx, logger log.Logger) error {
+				return UnwindHashStateStage(u, s, tx, hashState, ctx, logger)
+			},
+			Prune: func(firstCycle bool, p *PruneState, tx kv.RwTx, logger log.Logger) error {
+				return PruneHashStateStage(p, tx, hashState, ctx)
+			},
+		},
+		{
+			ID:          stages.IntermediateHashes,
+			Description: "Generate intermediate hashes and computing state root",
+			Forward: func(firstCycle bool, badBlockUnwind bool, s *StageState, u Unwinder, tx kv.RwTx, logger log.Logger) error {
+				_, err := SpawnIntermediateHashesStage(s, u, tx, trieCfg, ctx, logger)
+				return err
+			},
+			Unwind: func(firstCycle bool, u *UnwindState, s *StageState, tx kv.RwTx, logger log.Logger) error {
+				return UnwindIntermediateHashesStage(u, s, tx, trieCfg, ctx, logger)
+			},
+			Prune: func(firstCycle bool, p *PruneState, tx kv.RwTx, logger log.Logger) error {
+				return PruneIntermediateHashesStage(p, tx, trieCfg, ctx)
+			},
+		},
+		{
+			ID:          stages.CallTraces,
+			Description: "Generate call traces index",
+			Forward: func(firstCycle bool, badBlockUnwind bool, s *StageState, u Unwinder, tx kv.RwTx, logger log.Logger) error {
+				return SpawnCallTraces(s, tx, callTraces, ctx, logger)
+			},
+			Unwind: func(firstCycle bool, u *UnwindState, s *StageState, tx kv.RwTx, logger log.Logger) error {
+				return UnwindCallTraces(u, s, tx, callTraces, ctx, logger)
+			},
+			Prune: func(firstCycle bool, p *PruneState, tx kv.RwTx, logger log.Logger) error {
+				return PruneCallTraces(p, tx, callTraces, ctx, logger)
+			},
+		},
+		{
+			ID:          stages.AccountHistoryIndex,
+			Description: "Generate account history index",
+			Forward: func(firstCycle bool, badBlockUnwind bool, s *StageState, u Unwinder, tx kv.RwTx, logger log.Logger) error {
+				return SpawnAccountHistoryIndex(s, tx, history, ctx, logger)
+			},
+			Unwind: func(firstCycle bool, u *UnwindState, s *StageState, tx kv.RwTx, logger log.Logger) error {
+				return UnwindAccountHistoryIndex(u, s, tx, history, ctx)
+			},
+			Prune: func(firstCycle bool, p *PruneState, tx kv.RwTx, logger log.Logger) error {
+				return PruneAccountHistoryIndex(p, tx, history, ctx, logger)
+			},
+		},
+		{
+			ID:          stages.StorageHistoryIndex,
+			Description: "Generate storage history index",
+			Forward: func(firstCycle bool, badBlockUnwind bool, s *StageState, u Unwinder, tx kv.RwTx, logger log.Logger) error {
+				return SpawnStorageHistoryIndex(s, tx, history, ctx, logger)
+			},
+			Unwind: func(firstCycle bool, u *UnwindState, s *StageState, tx kv.RwTx, logger log.Logger) error {
+				return UnwindStorageHistoryIndex(u, s, tx, history, ctx)
+			},
+			Prune: func(firstCycle bool, p *PruneState, tx kv.RwTx, logger log.Logger) error {
+				return PruneStorageHistoryIndex(p, tx, history, ctx, logger)
+			},
+		},
+		{
+			ID:          stages.LogIndex,
+			Description: "Generate receipt logs index",
+			Forward: func(firstCycle bool, badBlockUnwind bool, s *StageState, u Unwinder, tx kv.RwTx, logger log.Logger) error {
+				return SpawnLogIndex(s, tx, logIndex, ctx, 0, logger)
+			},
+			Unwind: func(firstCycle bool, u *UnwindState, s *StageState, tx kv.RwTx, logger log.Logger) error {
+				return UnwindLogIndex(u, s, tx, logIndex, ctx)
+			},
+			Prune: func(firstCycle bool, p *PruneState, tx kv.RwTx, logger log.Logger) error {
+				return PruneLogIndex(p, tx, logIndex, ctx, logger)
+			},
+		},
+		{
+			ID:          stages.TxLookup,
+			Description: "Generate tx lookup index",
+			Forward: func(firstCycle bool, badBlockUnwind bool, s *StageState, u Unwinder, tx kv.RwTx, logger log.Logger) error {
+				return SpawnTxLookup(s, tx, 0 /* toBlock */, txLookup, ctx, logger)
+			},
+			Unwind: func(firstCycle bool, u *UnwindState, s *StageState, tx kv.RwTx, logger log.Logger) error {
+				return UnwindTxLookup(u, s, tx, txLookup, ctx, logger)
+			},
+			Prune: func(firstCycle bool, p *PruneState, tx kv.RwTx, logger log.Logger) error {
+				return PruneTxLookup(p, tx, txLookup, ctx, firstCycle, logger)
+			},
+		},
+		{
+			ID:          stages.Finish,
+			Description: "Final: update current block for the RPC API",
+			Forward: func(firstCycle bool, badBlockUnwind bool, s *StageState, _ Unwinder, tx kv.RwTx, logger log.Logger) error {
+				return FinishForward(s, tx, finish, firstCycle)
+			},
+			Unwind: func(firstCycle bool, u *UnwindState, s *StageState, tx kv.RwTx, logger log.Logger) error {
+				return UnwindFinish(u, tx, finish, ctx)
+			},
+			Prune: func(firstCycle bool, p *PruneState, tx kv.RwTx, logger log.Logger) error {
+				return PruneFinish(p, tx, finish, ctx)
+			},
+		},
+	}
+}
+
 var DefaultForwardOrder = UnwindOrder{
 	stages.Snapshots,
 	stages.Headers,
@@ -388,5 +580,42 @@ var DefaultPruneOrder = PruneOrder{
 	stages.Headers,
 }
 
+// PruneStagesOrder prunes the indices before the change sets, receipts and call traces they are read from
+var PruneStagesOrder = PruneOrder{
+	stages.TxLookup,
+	stages.LogIndex,
+	stages.StorageHistoryIndex,
+	stages.AccountHistoryIndex,
+	stages.CallTraces,
+	stages.Execution,
+}
+
+var ReplicaUnwindOrder = UnwindOrder{
+	stages.Finish,
+	stages.TxLookup,
+	stages.LogIndex,
+	stages.StorageHistoryIndex,
+	stages.AccountHistoryIndex,
+	stages.CallTraces,
+
+	// Unwinding of IHashes needs to happen after unwinding HashState
+	stages.HashState,
+	stages.IntermediateHashes,
+
+	stages.Execution,
+}
+
+var ReplicaPruneOrder = PruneOrder{
+	stages.Finish,
+	stages.TxLookup,
+	stages.LogIndex,
+	stages.StorageHistoryIndex,
+	stages.AccountHistoryIndex,
+	stages.CallTraces,
+	stages.HashState,
+	stages.IntermediateHashes,
+	stages.Execution,
+}
+
 var MiningUnwindOrder = UnwindOrder{} // nothing to unwind in mining - because mining does not commit db changes
 var MiningPruneOrder = PruneOrder{}   // nothing to unwind in mining - because mining does not commit db changes
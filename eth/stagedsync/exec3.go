@@ -520,7 +520,7 @@ Loop:
 		txs := b.Transactions()
 		header := b.HeaderNoCopy()
 		skipAnalysis := core.SkipAnalysis(chainConfig, blockNum)
-		signer := *types.MakeSigner(chainConfig, blockNum)
+		signer := *types.MakeSigner(chainConfig, blockNum, header.Time)
 
 		f := core.GetHashFn(header, getHeaderFunc)
 		getHashFnMute := &sync.Mutex{}
@@ -618,7 +618,7 @@ Loop:
 						gasUsed += txTask.UsedGas
 						if gasUsed != txTask.Header.GasUsed {
 							if txTask.BlockNum > 0 { //Disable check for genesis. Maybe need somehow improve it in future - to satisfy TestExecutionSpec
-								return fmt.Errorf("gas used by execution: %d, in header: %d, headerNum=%d, %x", gasUsed, txTask.Header.GasUsed, txTask.Header.Number.Uint64(), txTask.Header.Hash())
+								return &core.BadBlockError{Number: txTask.BlockNum, Hash: txTask.Header.Hash(), Err: &core.GasUsedMismatchError{Have: gasUsed, Want: txTask.Header.GasUsed}}
 							}
 						}
 						gasUsed = 0
@@ -638,7 +638,7 @@ Loop:
 							return err
 						}
 					}
-					u.UnwindTo(blockNum-1, header.Hash())
+					u.UnwindToBadBlock(blockNum-1, header.Hash(), err)
 					break Loop
 				}
 
@@ -1045,7 +1045,7 @@ func reconstituteStep(last bool,
 			txs := b.Transactions()
 			header := b.HeaderNoCopy()
 			skipAnalysis := core.SkipAnalysis(chainConfig, bn)
-			signer := *types.MakeSigner(chainConfig, bn)
+			signer := *types.MakeSigner(chainConfig, bn, header.Time)
 
 			f := core.GetHashFn(header, getHeaderFunc)
 			getHashFnMute := &sync.Mutex{}
@@ -76,6 +76,8 @@ func (s *StageState) IntermediateHashesAt(db kv.Getter) (uint64, error) {
 type Unwinder interface {
 	// UnwindTo begins staged sync unwind to the specified block.
 	UnwindTo(unwindPoint uint64, badBlock libcommon.Hash)
+	// UnwindToBadBlock begins staged sync unwind below a block found invalid because of err.
+	UnwindToBadBlock(unwindPoint uint64, badBlock libcommon.Hash, err error)
 }
 
 // UnwindState contains the information about unwind.
@@ -175,6 +175,9 @@ func executeBlock(
 		execRs, err = core.ExecuteBlockEphemerally(cfg.chainConfig, &vmConfig, getHashFn, cfg.engine, block, stateReader, stateWriter, ChainReaderImpl{config: cfg.chainConfig, tx: tx, blockReader: cfg.blockReader}, getTracer)
 	}
 	if err != nil {
+		if core.IsBlockValidationError(err) {
+			return &core.BadBlockError{Number: blockNum, Hash: block.Hash(), Err: err}
+		}
 		return err
 	}
 	receipts = execRs.Receipts
@@ -462,16 +465,22 @@ Loop:
 		writeReceipts := nextStagesExpectData || blockNum > cfg.prune.Receipts.PruneTo(to)
 		writeCallTraces := nextStagesExpectData || blockNum > cfg.prune.CallTraces.PruneTo(to)
 		if err = executeBlock(block, tx, batch, cfg, *cfg.vmConfig, writeChangeSets, writeReceipts, writeCallTraces, initialCycle, stateStream); err != nil {
-			if !errors.Is(err, context.Canceled) {
-				logger.Warn(fmt.Sprintf("[%s] Execution failed", logPrefix), "block", blockNum, "hash", block.Hash().String(), "err", err)
-				if cfg.hd != nil {
-					cfg.hd.ReportBadHeaderPoS(blockHash, block.ParentHash())
-				}
-				if cfg.badBlockHalt {
-					return err
-				}
+			var badBlockErr *core.BadBlockError
+			if !errors.As(err, &badBlockErr) {
+				// the block may still be valid, the execution is retried on the next cycle
+				return err
+			}
+			logger.Warn(fmt.Sprintf("[%s] Execution failed", logPrefix), "block", blockNum, "hash", block.Hash().String(), "err", err)
+			if cfg.hd != nil {
+				cfg.hd.ReportBadHeaderPoS(blockHash, block.ParentHash())
+			}
+			if wErr := rawdb.WriteBadBlock(tx, &rawdb.BadBlock{Header: block.Header(), Body: block.RawBody(), LastValidHash: block.ParentHash(), ValidationError: err.Error()}); wErr != nil {
+				return wErr
+			}
+			if cfg.badBlockHalt {
+				return err
 			}
-			u.UnwindTo(blockNum-1, block.Hash())
+			u.UnwindToBadBlock(blockNum-1, block.Hash(), err)
 			break Loop
 		}
 		stageProgress = blockNum
@@ -571,6 +571,9 @@ func verifyAndSaveNewPoSHeader(
 	if verificationErr := cfg.hd.VerifyHeader(header); verificationErr != nil {
 		logger.Warn("Verification failed for header", "hash", headerHash, "height", headerNumber, "err", verificationErr)
 		cfg.hd.ReportBadHeaderPoS(headerHash, header.ParentHash)
+		if err := cfg.forkValidator.ReportInvalid(tx, header, block.RawBody(), header.ParentHash, verificationErr); err != nil {
+			return nil, false, err
+		}
 		return &engineapi.PayloadStatus{
 			Status:          remote.EngineStatus_INVALID,
 			LatestValidHash: header.ParentHash,
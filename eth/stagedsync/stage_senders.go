@@ -21,6 +21,7 @@ import (
 
 	"github.com/ledgerwatch/erigon/common/dbutils"
 	"github.com/ledgerwatch/erigon/common/debug"
+	"github.com/ledgerwatch/erigon/core"
 	"github.com/ledgerwatch/erigon/core/rawdb"
 	"github.com/ledgerwatch/erigon/core/types"
 	"github.com/ledgerwatch/erigon/eth/stagedsync/stages"
@@ -236,6 +237,11 @@ Loop:
 			logger.Warn(fmt.Sprintf("[%s] ReadCanonicalBodyWithTransactions can't find block", logPrefix), "num", blockNumber, "hash", blockHash)
 			continue
 		}
+		header := rawdb.ReadHeader(tx, blockHash, blockNumber)
+		if header == nil {
+			logger.Warn(fmt.Sprintf("[%s] ReadHeader can't find block", logPrefix), "num", blockNumber, "hash", blockHash)
+			continue
+		}
 
 		select {
 		case recoveryErr := <-errCh:
@@ -246,7 +252,7 @@ Loop:
 				}
 				break Loop
 			}
-		case jobs <- &senderRecoveryJob{body: body, key: k, blockNumber: blockNumber, blockHash: blockHash, index: int(blockNumber - s.BlockNumber - 1)}:
+		case jobs <- &senderRecoveryJob{body: body, key: k, blockNumber: blockNumber, blockTime: header.Time, blockHash: blockHash, index: int(blockNumber - s.BlockNumber - 1)}:
 		}
 	}
 
@@ -263,15 +269,16 @@ Loop:
 	}
 	if minBlockErr != nil {
 		logger.Error(fmt.Sprintf("[%s] Error recovering senders for block %d %x): %v", logPrefix, minBlockNum, minBlockHash, minBlockErr))
+		badBlockErr := &core.BadBlockError{Number: minBlockNum, Hash: minBlockHash, Err: minBlockErr}
 		if cfg.badBlockHalt {
-			return minBlockErr
+			return badBlockErr
 		}
 		minHeader := rawdb.ReadHeader(tx, minBlockHash, minBlockNum)
 		if cfg.hd != nil {
 			cfg.hd.ReportBadHeaderPoS(minBlockHash, minHeader.ParentHash)
 		}
 		if to > s.BlockNumber {
-			u.UnwindTo(minBlockNum-1, minBlockHash)
+			u.UnwindToBadBlock(minBlockNum-1, minBlockHash, badBlockErr)
 		}
 	} else {
 		if err := collectorSenders.Load(tx, kv.Senders, etl.IdentityLoadFunc, etl.TransformArgs{
@@ -307,6 +314,7 @@ type senderRecoveryJob struct {
 	senders     []byte
 	blockHash   libcommon.Hash
 	blockNumber uint64
+	blockTime   uint64
 	index       int
 	err         error
 }
@@ -330,7 +338,7 @@ func recoverSenders(ctx context.Context, logPrefix string, cryptoContext *secp25
 		}
 
 		body := job.body
-		signer := types.MakeSigner(config, job.blockNumber)
+		signer := types.MakeSigner(config, job.blockNumber, job.blockTime)
 		job.senders = make([]byte, len(body.Transactions)*length.Addr)
 		for i, tx := range body.Transactions {
 			from, err := signer.SenderWithContext(cryptoContext, tx)
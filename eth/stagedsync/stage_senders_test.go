@@ -2,6 +2,7 @@ package stagedsync
 
 import (
 	"context"
+	"math/big"
 	"testing"
 
 	libcommon "github.com/ledgerwatch/erigon-lib/common"
@@ -35,9 +36,16 @@ func TestSenders(t *testing.T) {
 		return r
 	}
 
+	var hashes [4]libcommon.Hash
+	for i := uint64(1); i <= 3; i++ {
+		header := &types.Header{Number: new(big.Int).SetUint64(i)}
+		rawdb.WriteHeader(tx, header)
+		hashes[i] = header.Hash()
+	}
+
 	// prepare tx so it works with our test
-	signer1 := types.MakeSigner(params.TestChainConfig, params.TestChainConfig.BerlinBlock.Uint64())
-	require.NoError(rawdb.WriteBody(tx, libcommon.HexToHash("01"), 1, &types.Body{
+	signer1 := types.MakeSigner(params.TestChainConfig, params.TestChainConfig.BerlinBlock.Uint64(), 0)
+	require.NoError(rawdb.WriteBody(tx, hashes[1], 1, &types.Body{
 		Transactions: []types.Transaction{
 			mustSign(&types.AccessListTx{
 				LegacyTx: types.LegacyTx{
@@ -63,10 +71,10 @@ func TestSenders(t *testing.T) {
 			}, *signer1),
 		},
 	}))
-	require.NoError(rawdb.WriteCanonicalHash(tx, libcommon.HexToHash("01"), 1))
+	require.NoError(rawdb.WriteCanonicalHash(tx, hashes[1], 1))
 
-	signer2 := types.MakeSigner(params.TestChainConfig, params.TestChainConfig.BerlinBlock.Uint64())
-	require.NoError(rawdb.WriteBody(tx, libcommon.HexToHash("02"), 2, &types.Body{
+	signer2 := types.MakeSigner(params.TestChainConfig, params.TestChainConfig.BerlinBlock.Uint64(), 0)
+	require.NoError(rawdb.WriteBody(tx, hashes[2], 2, &types.Body{
 		Transactions: []types.Transaction{
 			mustSign(&types.AccessListTx{
 				LegacyTx: types.LegacyTx{
@@ -103,12 +111,12 @@ func TestSenders(t *testing.T) {
 			}, *signer2),
 		},
 	}))
-	require.NoError(rawdb.WriteCanonicalHash(tx, libcommon.HexToHash("02"), 2))
+	require.NoError(rawdb.WriteCanonicalHash(tx, hashes[2], 2))
 
-	require.NoError(rawdb.WriteBody(tx, libcommon.HexToHash("03"), 3, &types.Body{
+	require.NoError(rawdb.WriteBody(tx, hashes[3], 3, &types.Body{
 		Transactions: []types.Transaction{}, Uncles: []*types.Header{{GasLimit: 3}},
 	}))
-	require.NoError(rawdb.WriteCanonicalHash(tx, libcommon.HexToHash("03"), 3))
+	require.NoError(rawdb.WriteCanonicalHash(tx, hashes[3], 3))
 
 	require.NoError(stages.SaveStageProgress(tx, stages.Bodies, 3))
 
@@ -117,26 +125,26 @@ func TestSenders(t *testing.T) {
 	assert.NoError(t, err)
 
 	{
-		found := rawdb.ReadCanonicalBodyWithTransactions(tx, libcommon.HexToHash("01"), 1)
+		found := rawdb.ReadCanonicalBodyWithTransactions(tx, hashes[1], 1)
 		assert.NotNil(t, found)
 		assert.Equal(t, 2, len(found.Transactions))
-		found = rawdb.ReadCanonicalBodyWithTransactions(tx, libcommon.HexToHash("02"), 2)
+		found = rawdb.ReadCanonicalBodyWithTransactions(tx, hashes[2], 2)
 		assert.NotNil(t, found)
 		assert.NotNil(t, 3, len(found.Transactions))
-		found = rawdb.ReadCanonicalBodyWithTransactions(tx, libcommon.HexToHash("03"), 3)
+		found = rawdb.ReadCanonicalBodyWithTransactions(tx, hashes[3], 3)
 		assert.NotNil(t, found)
 		assert.NotNil(t, 0, len(found.Transactions))
 		assert.NotNil(t, 2, len(found.Uncles))
 	}
 
 	{
-		senders, err := rawdb.ReadSenders(tx, libcommon.HexToHash("01"), 1)
+		senders, err := rawdb.ReadSenders(tx, hashes[1], 1)
 		assert.NoError(t, err)
 		assert.Equal(t, 2, len(senders))
-		senders, err = rawdb.ReadSenders(tx, libcommon.HexToHash("02"), 2)
+		senders, err = rawdb.ReadSenders(tx, hashes[2], 2)
 		assert.NoError(t, err)
 		assert.Equal(t, 3, len(senders))
-		senders, err = rawdb.ReadSenders(tx, libcommon.HexToHash("03"), 3)
+		senders, err = rawdb.ReadSenders(tx, hashes[3], 3)
 		assert.NoError(t, err)
 		assert.Equal(t, 0, len(senders))
 	}
@@ -188,6 +188,12 @@ func DownloadAndIndexSnapshotsIfNeed(s *StageState, ctx context.Context, tx kv.R
 	if err := FillDBFromSnapshots(s.LogPrefix(), ctx, tx, cfg.dirs, cfg.snapshots, cfg.blockReader, cfg.chainConfig, cfg.engine, cfg.agg, logger); err != nil {
 		return err
 	}
+
+	if cfg.snapshots.Cfg().Check && cfg.blockRetire != nil {
+		chainID, _ := uint256.FromBig(cfg.chainConfig.ChainID)
+		preverified := snapcfg.KnownCfg(cfg.chainConfig.ChainName, nil, nil).Preverified
+		cfg.blockRetire.VerifyInBackground(ctx, preverified, *chainID)
+	}
 	return nil
 }
 
@@ -9,14 +9,21 @@ import (
 	"github.com/ledgerwatch/erigon-lib/common/dbg"
 	"github.com/ledgerwatch/erigon-lib/kv"
 	"github.com/ledgerwatch/log/v3"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 
 	"github.com/ledgerwatch/erigon/eth/stagedsync/stages"
 )
 
+var tracer = otel.Tracer("github.com/ledgerwatch/erigon/eth/stagedsync")
+
 type Sync struct {
 	unwindPoint     *uint64 // used to run stages
 	prevUnwindPoint *uint64 // used to get value from outside of staged sync after cycle (for example to notify RPCDaemon)
 	badBlock        libcommon.Hash
+	badBlockErr     error // why the last bad block of the cycle was invalid, for the callers
 
 	stages       []*Stage
 	unwindOrder  []*Stage
@@ -36,6 +43,7 @@ type Timing struct {
 
 func (s *Sync) Len() int                 { return len(s.stages) }
 func (s *Sync) PrevUnwindPoint() *uint64 { return s.prevUnwindPoint }
+func (s *Sync) BadBlockErr() error       { return s.badBlockErr }
 
 func (s *Sync) NewUnwindState(id stages.SyncStage, unwindPoint, currentProgress uint64) *UnwindState {
 	return &UnwindState{id, unwindPoint, currentProgress, libcommon.Hash{}, s}
@@ -112,6 +120,11 @@ func (s *Sync) UnwindTo(unwindPoint uint64, badBlock libcommon.Hash) {
 	s.badBlock = badBlock
 }
 
+func (s *Sync) UnwindToBadBlock(unwindPoint uint64, badBlock libcommon.Hash, err error) {
+	s.UnwindTo(unwindPoint, badBlock)
+	s.badBlockErr = err
+}
+
 func (s *Sync) IsDone() bool {
 	return s.currentStage >= uint(len(s.stages)) && s.unwindPoint == nil
 }
@@ -191,15 +204,17 @@ func (s *Sync) StageState(stage stages.SyncStage, tx kv.Tx, db kv.RoDB) (*StageS
 	return &StageState{s, stage, blockNum}, nil
 }
 
-func (s *Sync) RunUnwind(db kv.RwDB, tx kv.RwTx) error {
+func (s *Sync) RunUnwind(db kv.RwDB, tx kv.RwTx) (err error) {
 	if s.unwindPoint == nil {
 		return nil
 	}
+	ctx, span := tracer.Start(context.Background(), "stagedsync.RunUnwind", trace.WithAttributes(attribute.Int64("unwindPoint", int64(*s.unwindPoint))))
+	defer func() { endSpan(span, err) }()
 	for j := 0; j < len(s.unwindOrder); j++ {
 		if s.unwindOrder[j] == nil || s.unwindOrder[j].Disabled || s.unwindOrder[j].Unwind == nil {
 			continue
 		}
-		if err := s.unwindStage(false, s.unwindOrder[j], db, tx); err != nil {
+		if err := s.unwindStage(ctx, false, s.unwindOrder[j], db, tx); err != nil {
 			return err
 		}
 	}
@@ -211,9 +226,12 @@ func (s *Sync) RunUnwind(db kv.RwDB, tx kv.RwTx) error {
 	}
 	return nil
 }
-func (s *Sync) Run(db kv.RwDB, tx kv.RwTx, firstCycle bool) error {
+func (s *Sync) Run(db kv.RwDB, tx kv.RwTx, firstCycle bool) (err error) {
 	s.prevUnwindPoint = nil
+	s.badBlockErr = nil
 	s.timings = s.timings[:0]
+	ctx, span := tracer.Start(context.Background(), "stagedsync.Run", trace.WithAttributes(attribute.Bool("firstCycle", firstCycle)))
+	defer func() { endSpan(span, err) }()
 
 	for !s.IsDone() {
 		var badBlockUnwind bool
@@ -222,7 +240,7 @@ func (s *Sync) Run(db kv.RwDB, tx kv.RwTx, firstCycle bool) error {
 				if s.unwindOrder[j] == nil || s.unwindOrder[j].Disabled || s.unwindOrder[j].Unwind == nil {
 					continue
 				}
-				if err := s.unwindStage(firstCycle, s.unwindOrder[j], db, tx); err != nil {
+				if err := s.unwindStage(ctx, firstCycle, s.unwindOrder[j], db, tx); err != nil {
 					return err
 				}
 			}
@@ -259,7 +277,7 @@ func (s *Sync) Run(db kv.RwDB, tx kv.RwTx, firstCycle bool) error {
 			continue
 		}
 
-		if err := s.runStage(stage, db, tx, firstCycle, badBlockUnwind); err != nil {
+		if err := s.runStage(ctx, stage, db, tx, firstCycle, badBlockUnwind); err != nil {
 			return err
 		}
 
@@ -350,12 +368,14 @@ func PrintTables(db kv.RoDB, tx kv.RwTx) []interface{} {
 	return bucketSizes
 }
 
-func (s *Sync) runStage(stage *Stage, db kv.RwDB, tx kv.RwTx, firstCycle bool, badBlockUnwind bool) (err error) {
+func (s *Sync) runStage(ctx context.Context, stage *Stage, db kv.RwDB, tx kv.RwTx, firstCycle bool, badBlockUnwind bool) (err error) {
 	start := time.Now()
 	stageState, err := s.StageState(stage.ID, tx, db)
 	if err != nil {
 		return err
 	}
+	_, span := tracer.Start(ctx, "stagedsync.Forward", trace.WithAttributes(attribute.String("stage", string(stage.ID)), attribute.Int64("progress", int64(stageState.BlockNumber))))
+	defer func() { endSpan(span, err) }()
 
 	if err = stage.Forward(firstCycle, badBlockUnwind, stageState, s, tx, s.logger); err != nil {
 		wrappedError := fmt.Errorf("[%s] %w", s.LogPrefix(), err)
@@ -374,7 +394,7 @@ func (s *Sync) runStage(stage *Stage, db kv.RwDB, tx kv.RwTx, firstCycle bool, b
 	return nil
 }
 
-func (s *Sync) unwindStage(firstCycle bool, stage *Stage, db kv.RwDB, tx kv.RwTx) error {
+func (s *Sync) unwindStage(ctx context.Context, firstCycle bool, stage *Stage, db kv.RwDB, tx kv.RwTx) (err error) {
 	start := time.Now()
 	s.logger.Trace("Unwind...", "stage", stage.ID)
 	stageState, err := s.StageState(stage.ID, tx, db)
@@ -388,6 +408,8 @@ func (s *Sync) unwindStage(firstCycle bool, stage *Stage, db kv.RwDB, tx kv.RwTx
 	if stageState.BlockNumber <= unwind.UnwindPoint {
 		return nil
 	}
+	_, span := tracer.Start(ctx, "stagedsync.Unwind", trace.WithAttributes(attribute.String("stage", string(stage.ID)), attribute.Int64("progress", int64(stageState.BlockNumber)), attribute.Int64("unwindPoint", int64(unwind.UnwindPoint))))
+	defer func() { endSpan(span, err) }()
 
 	if err = s.SetCurrentStage(stage.ID); err != nil {
 		return err
@@ -481,3 +503,12 @@ func (s *Sync) MockExecFunc(id stages.SyncStage, f ExecFunc) {
 		}
 	}
 }
+
+// endSpan ends the span of a run of the stages, marking it failed with the error.
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
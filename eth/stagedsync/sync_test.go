@@ -618,3 +618,46 @@ func TestSyncInterruptLongUnwind(t *testing.T) {
 func unwindOf(s stages.SyncStage) stages.SyncStage {
 	return stages.SyncStage(append([]byte(s), 0xF0))
 }
+
+func TestBadBlockErr(t *testing.T) {
+	badBlockErr := errors.New("bad block")
+	unwound := false
+	s := []*Stage{
+		{
+			ID:          stages.Headers,
+			Description: "Downloading headers",
+			Forward: func(firstCycle bool, badBlockUnwind bool, s *StageState, u Unwinder, tx kv.RwTx, logger log.Logger) error {
+				if s.BlockNumber == 0 {
+					return s.Update(tx, 100)
+				}
+				return nil
+			},
+			Unwind: func(firstCycle bool, u *UnwindState, s *StageState, tx kv.RwTx, logger log.Logger) error {
+				return u.Done(tx)
+			},
+		},
+		{
+			ID:          stages.Execution,
+			Description: "Executing blocks",
+			Forward: func(firstCycle bool, badBlockUnwind bool, s *StageState, u Unwinder, tx kv.RwTx, logger log.Logger) error {
+				if !unwound {
+					unwound = true
+					u.UnwindToBadBlock(49, libcommon.Hash{0x50}, badBlockErr)
+					return nil
+				}
+				return s.Update(tx, 100)
+			},
+			Unwind: func(firstCycle bool, u *UnwindState, s *StageState, tx kv.RwTx, logger log.Logger) error {
+				return u.Done(tx)
+			},
+		},
+	}
+	state := New(s, []stages.SyncStage{s[1].ID, s[0].ID}, nil, log.New())
+	db, tx := memdb.NewTestTx(t)
+	assert.NoError(t, state.Run(db, tx, true /* initialCycle */))
+	assert.Equal(t, badBlockErr, state.BadBlockErr())
+
+	// the error is forgotten by the next cycle
+	assert.NoError(t, state.Run(db, tx, false /* initialCycle */))
+	assert.NoError(t, state.BadBlockErr())
+}
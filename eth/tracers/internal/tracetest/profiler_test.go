@@ -0,0 +1,124 @@
+package tracetest
+
+import (
+	"encoding/json"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/google/pprof/profile"
+	"github.com/holiman/uint256"
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/common/hexutility"
+	"github.com/ledgerwatch/erigon/turbo/stages"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ledgerwatch/erigon/core"
+	"github.com/ledgerwatch/erigon/core/types"
+	"github.com/ledgerwatch/erigon/core/vm"
+	"github.com/ledgerwatch/erigon/core/vm/evmtypes"
+	"github.com/ledgerwatch/erigon/crypto"
+	"github.com/ledgerwatch/erigon/eth/tracers"
+	"github.com/ledgerwatch/erigon/params"
+	"github.com/ledgerwatch/erigon/tests"
+)
+
+// TestProfilerTracer profiles a call of a contract which calls the identity precompile.
+func TestProfilerTracer(t *testing.T) {
+	var to = libcommon.HexToAddress("0x00000000000000000000000000000000deadbeef")
+	privkey, err := crypto.HexToECDSA("0000000000000000deadbeef00000000000000000000000000000000deadbeef")
+	require.NoError(t, err)
+	signer := types.LatestSigner(params.MainnetChainConfig)
+	tx, err := types.SignNewTx(privkey, *signer, &types.LegacyTx{
+		GasPrice: uint256.NewInt(0),
+		CommonTx: types.CommonTx{
+			Gas:  50000,
+			To:   &to,
+			Data: hexutility.MustDecodeHex("0xa9059cbb"),
+		},
+	})
+	require.NoError(t, err)
+	origin, _ := signer.Sender(tx)
+	txContext := evmtypes.TxContext{
+		Origin:   origin,
+		GasPrice: uint256.NewInt(1),
+	}
+	context := evmtypes.BlockContext{
+		CanTransfer: core.CanTransfer,
+		Transfer:    core.Transfer,
+		Coinbase:    libcommon.Address{},
+		BlockNumber: 8000000,
+		Time:        5,
+		Difficulty:  big.NewInt(0x30000),
+		GasLimit:    uint64(6000000),
+	}
+	var code = []byte{
+		byte(vm.PUSH1), 0x0, byte(vm.PUSH1), 0x0, byte(vm.PUSH1), 0x0, byte(vm.PUSH1), 0x0, // in and outs zero
+		byte(vm.PUSH1), 0x0, byte(vm.PUSH1), 0x4, byte(vm.GAS), // value=0, address=identity, gas=GAS
+		byte(vm.CALL),
+		byte(vm.STOP),
+	}
+	var alloc = types.GenesisAlloc{
+		to: types.GenesisAccount{
+			Nonce: 1,
+			Code:  code,
+		},
+		origin: types.GenesisAccount{
+			Nonce:   0,
+			Balance: big.NewInt(500000000000000),
+		},
+	}
+	rules := params.MainnetChainConfig.Rules(context.BlockNumber, context.Time)
+	m := stages.Mock(t)
+	dbTx, err := m.DB.BeginRw(m.Ctx)
+	require.NoError(t, err)
+	defer dbTx.Rollback()
+
+	statedb, _ := tests.MakePreState(rules, dbTx, alloc, context.BlockNumber)
+	tracer, err := tracers.New("profilerTracer", nil, nil)
+	require.NoError(t, err)
+	evm := vm.NewEVM(context, txContext, statedb, params.MainnetChainConfig, vm.Config{Debug: true, Tracer: tracer})
+	msg, err := tx.AsMessage(*signer, nil, rules)
+	require.NoError(t, err)
+	st := core.NewStateTransition(evm, msg, new(core.GasPool).AddGas(tx.GetGas()).AddDataGas(tx.GetDataGas()))
+	_, err = st.TransitionDb(true /* refunds */, false /* gasBailout */)
+	require.NoError(t, err)
+
+	raw, err := tracer.GetResult()
+	require.NoError(t, err)
+	var res struct {
+		GasUsed uint64 `json:"gasUsed"`
+		Samples []struct {
+			Stack []string `json:"stack"`
+			Pc    uint64   `json:"pc"`
+			Op    string   `json:"op"`
+			Gas   uint64   `json:"gas"`
+			Count uint64   `json:"count"`
+		} `json:"samples"`
+		Collapsed string           `json:"collapsed"`
+		Pprof     hexutility.Bytes `json:"pprof"`
+	}
+	require.NoError(t, json.Unmarshal(raw, &res))
+
+	// the samples add up to the gas used by the execution
+	var gas uint64
+	for _, sample := range res.Samples {
+		gas += sample.Gas
+		require.Equal(t, uint64(1), sample.Count)
+	}
+	require.NotZero(t, res.GasUsed)
+	require.Equal(t, res.GasUsed, gas)
+
+	caller := to.Hex() + ":0xa9059cbb"
+	identity := libcommon.BytesToAddress([]byte{4}).Hex()
+	lines := strings.Split(res.Collapsed, "\n")
+	require.Contains(t, lines, caller+";"+identity+";PRECOMPILE@0x0 15")
+	require.Contains(t, lines, caller+";GAS@0xc 2")
+	require.Contains(t, lines, caller+";PUSH1@0x0 3")
+
+	p, err := profile.ParseData(res.Pprof)
+	require.NoError(t, err)
+	require.NoError(t, p.CheckValid())
+	require.Equal(t, "gas", p.SampleType[0].Type)
+	require.Len(t, p.Sample, len(res.Samples))
+}
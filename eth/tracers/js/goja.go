@@ -106,6 +106,7 @@ type jsTracer struct {
 	gasLimit          uint64                // Amount of gas bought for the whole tx
 	err               error                 // Any error that should stop tracing
 	obj               *goja.Object          // Trace object
+	sandbox           *sandbox              // Limits of the resources the JS code may use
 
 	// Methods exposed by tracer
 	result goja.Callable
@@ -133,15 +134,17 @@ type jsTracer struct {
 // The methods `step`, `enter`, and `exit` are optional, but note that
 // `enter` and `exit` always go together.
 func newJsTracer(code string, ctx *tracers.Context, cfg json.RawMessage) (tracers.Tracer, error) {
-	if c, ok := assetTracers[code]; ok {
-		code = c
+	program, err := compileTracer(tracerCode(code))
+	if err != nil {
+		return nil, err
 	}
 	vm := goja.New()
 	// By default field names are exported to JS as is, i.e. capitalized.
 	vm.SetFieldNameMapper(goja.UncapFieldNameMapper())
 	t := &jsTracer{
-		vm:  vm,
-		ctx: make(map[string]goja.Value),
+		vm:      vm,
+		ctx:     make(map[string]goja.Value),
+		sandbox: newSandbox(vm, currentLimits()),
 	}
 	if ctx == nil {
 		ctx = new(tracers.Context)
@@ -156,7 +159,7 @@ func newJsTracer(code string, ctx *tracers.Context, cfg json.RawMessage) (tracer
 
 	t.setTypeConverters()
 	t.setBuiltinFunctions()
-	ret, err := vm.RunString("(" + code + ")")
+	ret, err := t.sandbox.run(func() (goja.Value, error) { return vm.RunProgram(program) })
 	if err != nil {
 		return nil, err
 	}
@@ -191,7 +194,7 @@ func newJsTracer(code string, ctx *tracers.Context, cfg json.RawMessage) (tracer
 		if cfg != nil {
 			cfgStr = string(cfg)
 		}
-		if _, err := setup(obj, vm.ToValue(cfgStr)); err != nil {
+		if _, err := t.call(setup, obj, vm.ToValue(cfgStr)); err != nil {
 			return nil, err
 		}
 	}
@@ -270,7 +273,11 @@ func (t *jsTracer) CaptureState(pc uint64, op vm.OpCode, gas, cost uint64, scope
 	log.refund = t.env.IntraBlockState().GetRefund()
 	log.depth = depth
 	log.err = err
-	if _, err := t.step(t.obj, t.logValue, t.dbValue); err != nil {
+	if err := t.sandbox.step(); err != nil {
+		t.onError("step", err)
+		return
+	}
+	if _, err := t.call(t.step, t.obj, t.logValue, t.dbValue); err != nil {
 		t.onError("step", err)
 	}
 }
@@ -282,7 +289,7 @@ func (t *jsTracer) CaptureFault(pc uint64, op vm.OpCode, gas, cost uint64, scope
 	}
 	// Other log fields have been already set as part of the last CaptureState.
 	t.log.err = err
-	if _, err := t.fault(t.obj, t.logValue, t.dbValue); err != nil {
+	if _, err := t.call(t.fault, t.obj, t.logValue, t.dbValue); err != nil {
 		t.onError("fault", err)
 	}
 }
@@ -314,7 +321,7 @@ func (t *jsTracer) CaptureEnter(typ vm.OpCode, from libcommon.Address, to libcom
 		t.frame.value = value.ToBig()
 	}
 
-	if _, err := t.enter(t.obj, t.frameValue); err != nil {
+	if _, err := t.call(t.enter, t.obj, t.frameValue); err != nil {
 		t.onError("enter", err)
 	}
 }
@@ -330,7 +337,7 @@ func (t *jsTracer) CaptureExit(output []byte, gasUsed uint64, err error) {
 	t.frameResult.output = common.CopyBytes(output)
 	t.frameResult.err = err
 
-	if _, err := t.exit(t.obj, t.frameResultValue); err != nil {
+	if _, err := t.call(t.exit, t.obj, t.frameResultValue); err != nil {
 		t.onError("exit", err)
 	}
 }
@@ -338,7 +345,7 @@ func (t *jsTracer) CaptureExit(output []byte, gasUsed uint64, err error) {
 // GetResult calls the Javascript 'result' function and returns its value, or any accumulated error
 func (t *jsTracer) GetResult() (json.RawMessage, error) {
 	ctx := t.vm.ToValue(t.ctx)
-	res, err := t.result(t.obj, ctx, t.dbValue)
+	res, err := t.call(t.result, t.obj, ctx, t.dbValue)
 	if err != nil {
 		return nil, wrapError("result", err)
 	}
@@ -346,6 +353,9 @@ func (t *jsTracer) GetResult() (json.RawMessage, error) {
 	if err != nil {
 		return nil, err
 	}
+	if err = t.sandbox.alloc(len(encoded)); err != nil {
+		return nil, wrapError("result", err)
+	}
 	return json.RawMessage(encoded), t.err
 }
 
@@ -354,6 +364,11 @@ func (t *jsTracer) Stop(err error) {
 	t.vm.Interrupt(err)
 }
 
+// call runs a function of the tracer object within the limits of the sandbox.
+func (t *jsTracer) call(fn goja.Callable, this goja.Value, args ...goja.Value) (goja.Value, error) {
+	return t.sandbox.run(func() (goja.Value, error) { return fn(this, args...) })
+}
+
 // onError is called anytime the running JS code is interrupted
 // and returns an error. It in turn pings the EVM to cancel its
 // execution.
@@ -365,7 +380,7 @@ func (t *jsTracer) onError(context string, err error) {
 }
 
 func wrapError(context string, err error) error {
-	return fmt.Errorf("%v    in server-side tracer function '%v'", err, context)
+	return fmt.Errorf("%w    in server-side tracer function '%v'", err, context)
 }
 
 // setBuiltinFunctions injects Go functions which are available to tracers into the environment.
@@ -379,6 +394,10 @@ func (t *jsTracer) setBuiltinFunctions() {
 			vm.Interrupt(err)
 			return ""
 		}
+		if err = t.sandbox.alloc(2 + 2*len(b)); err != nil {
+			vm.Interrupt(err)
+			return ""
+		}
 		return hexutility.Encode(b)
 	})
 	vm.Set("toWord", func(v goja.Value) goja.Value {
@@ -504,6 +523,9 @@ func (t *jsTracer) setTypeConverters() error {
 	// Cache uint8ArrayType once to be used every time for less overhead.
 	uint8ArrayType := t.vm.Get("Uint8Array")
 	toBufWrapper := func(vm *goja.Runtime, val []byte) (goja.Value, error) {
+		if err := t.sandbox.alloc(len(val)); err != nil {
+			return nil, err
+		}
 		return toBuf(vm, uint8ArrayType, val)
 	}
 	t.toBuf = toBufWrapper
@@ -0,0 +1,187 @@
+package js
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dop251/goja"
+	lru "github.com/hashicorp/golang-lru/v2"
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+
+	"github.com/ledgerwatch/erigon/crypto"
+)
+
+// programCacheSize is the number of compiled tracers kept around, the built-in tracers
+// and a few user supplied ones.
+const programCacheSize = 64
+
+// maxCallStackSize is the call depth of the tracers limited in memory, deep enough for the
+// built-in tracers which recurse at most once per call frame.
+const maxCallStackSize = 4096
+
+var (
+	ErrStepLimit   = errors.New("JS tracer step limit reached")
+	ErrTimeLimit   = errors.New("JS tracer time limit reached")
+	ErrMemoryLimit = errors.New("JS tracer memory limit reached")
+)
+
+// Limits bound the resources a JS tracer may use, so that nodes can offer JS tracing to
+// untrusted users. Zero means no limit.
+type Limits struct {
+	Steps uint64        // number of EVM steps the tracer's step function is called for
+	Time  time.Duration // time spent running the tracer's code
+	// Bytes handed to the tracer's code by the helpers (buffers of memory.slice, slice,
+	// db.getCode..., strings of toHex) plus the size of its encoded result. goja does not
+	// account for the heap of a runtime, so the objects the code builds itself are bounded
+	// by the time limit and its call depth by maxCallStackSize.
+	Memory uint64
+}
+
+var (
+	limitsLock sync.RWMutex
+	limits     Limits
+
+	namedTracersLock sync.RWMutex
+	namedTracers     = make(map[string]string)
+
+	programs *lru.Cache[libcommon.Hash, *goja.Program]
+)
+
+func init() {
+	var err error
+	if programs, err = lru.New[libcommon.Hash, *goja.Program](programCacheSize); err != nil {
+		panic(err)
+	}
+}
+
+// SetLimits sets the limits of the JS tracers created from now on.
+func SetLimits(l Limits) {
+	limitsLock.Lock()
+	defer limitsLock.Unlock()
+	limits = l
+}
+
+func currentLimits() Limits {
+	limitsLock.RLock()
+	defer limitsLock.RUnlock()
+	return limits
+}
+
+// RegisterTracer makes a JS tracer available by name, like the built-in ones which it
+// takes precedence over.
+func RegisterTracer(name, code string) error {
+	if _, err := compileTracer(code); err != nil {
+		return fmt.Errorf("tracer %s: %w", name, err)
+	}
+	namedTracersLock.Lock()
+	defer namedTracersLock.Unlock()
+	namedTracers[name] = code
+	return nil
+}
+
+// LoadTracers registers the tracers of the .js files of a directory, named after the files.
+func LoadTracers(dir string) error {
+	files, err := filepath.Glob(filepath.Join(dir, "*.js"))
+	if err != nil {
+		return err
+	}
+	for _, file := range files {
+		code, err := os.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		if err = RegisterTracer(strings.TrimSuffix(filepath.Base(file), ".js"), string(code)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// tracerCode resolves the name of a registered or built-in tracer to its code.
+func tracerCode(code string) string {
+	namedTracersLock.RLock()
+	defer namedTracersLock.RUnlock()
+	if c, ok := namedTracers[code]; ok {
+		return c
+	}
+	if c, ok := assetTracers[code]; ok {
+		return c
+	}
+	return code
+}
+
+// compileTracer compiles the expression evaluating to the tracer object, the programs are
+// cached by the hash of the code and shared by the runtimes.
+func compileTracer(code string) (*goja.Program, error) {
+	hash := crypto.Keccak256Hash([]byte(code))
+	if program, ok := programs.Get(hash); ok {
+		return program, nil
+	}
+	program, err := goja.Compile("", "("+code+")", false)
+	if err != nil {
+		return nil, err
+	}
+	programs.Add(hash, program)
+	return program, nil
+}
+
+// sandbox enforces the limits on the code run by a tracer.
+type sandbox struct {
+	limits  Limits
+	vm      *goja.Runtime
+	steps   uint64
+	elapsed time.Duration
+	memory  uint64
+}
+
+func newSandbox(vm *goja.Runtime, limits Limits) *sandbox {
+	if limits.Memory != 0 {
+		vm.SetMaxCallStackSize(maxCallStackSize)
+	}
+	return &sandbox{limits: limits, vm: vm}
+}
+
+// step counts an EVM step the step function is going to be called for.
+func (s *sandbox) step() error {
+	s.steps++
+	if s.limits.Steps != 0 && s.steps > s.limits.Steps {
+		return ErrStepLimit
+	}
+	return nil
+}
+
+// alloc counts n bytes handed to the tracer's code.
+func (s *sandbox) alloc(n int) error {
+	s.memory += uint64(n)
+	if s.limits.Memory != 0 && s.memory > s.limits.Memory {
+		return ErrMemoryLimit
+	}
+	return nil
+}
+
+// run runs the tracer's code, interrupting it once it ran out of time.
+func (s *sandbox) run(fn func() (goja.Value, error)) (goja.Value, error) {
+	if s.limits.Time == 0 {
+		return fn()
+	}
+	if s.elapsed >= s.limits.Time {
+		return nil, ErrTimeLimit
+	}
+	timer := time.AfterFunc(s.limits.Time-s.elapsed, func() { s.vm.Interrupt(ErrTimeLimit) })
+	start := time.Now()
+	res, err := fn()
+	s.elapsed += time.Since(start)
+	timer.Stop()
+	if err != nil {
+		return nil, err
+	}
+	if s.elapsed >= s.limits.Time {
+		return nil, ErrTimeLimit
+	}
+	return res, nil
+}
@@ -20,10 +20,13 @@ import (
 	"encoding/json"
 	"errors"
 	"math/big"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/dop251/goja"
 	"github.com/holiman/uint256"
 	"github.com/ledgerwatch/erigon-lib/chain"
 	libcommon "github.com/ledgerwatch/erigon-lib/common"
@@ -31,6 +34,7 @@ import (
 	"github.com/ledgerwatch/erigon/core/state"
 	"github.com/ledgerwatch/erigon/core/vm"
 	"github.com/ledgerwatch/erigon/core/vm/evmtypes"
+	"github.com/ledgerwatch/erigon/crypto"
 	"github.com/ledgerwatch/erigon/eth/tracers"
 	"github.com/ledgerwatch/erigon/params"
 )
@@ -321,3 +325,84 @@ func TestSetup(t *testing.T) {
 		t.Errorf("tracer returned wrong result. have: %s, want: \"bar\"\n", string(have))
 	}
 }
+
+func TestLimits(t *testing.T) {
+	defer SetLimits(Limits{})
+	for i, tt := range []struct {
+		limits Limits
+		code   string
+		fail   error
+	}{
+		{
+			limits: Limits{Steps: 2},
+			code:   "{step: function() {}, fault: function() {}, result: function() { return null; }}",
+			fail:   ErrStepLimit,
+		}, {
+			limits: Limits{Time: 100 * time.Millisecond},
+			code:   "{step: function() { while(1); }, fault: function() {}, result: function() { return null; }}",
+			fail:   ErrTimeLimit,
+		}, {
+			limits: Limits{Memory: 1 << 10},
+			code:   "{a: [], step: function(log) { this.a.push(log.memory.slice(0, 512)); }, fault: function() {}, result: function() { return null; }}",
+			fail:   ErrMemoryLimit,
+		}, {
+			limits: Limits{Memory: 1 << 10},
+			code:   "{step: function() {}, fault: function() {}, result: function() { return new Array(1 << 10).join('x'); }}",
+			fail:   ErrMemoryLimit,
+		}, {
+			limits: Limits{Steps: 3, Time: time.Minute, Memory: 1 << 10},
+			code:   "{count: 0, step: function() { this.count += 1; }, fault: function() {}, result: function() { return this.count; }}",
+		},
+	} {
+		SetLimits(tt.limits)
+		tracer, err := newJsTracer(tt.code, nil, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err = runTrace(tracer, testCtx(), params.TestChainConfig, nil); !errors.Is(err, tt.fail) {
+			t.Errorf("testcase %d: expected error %v, got %v", i, tt.fail, err)
+		}
+	}
+}
+
+func TestCallDepthLimit(t *testing.T) {
+	defer SetLimits(Limits{})
+	SetLimits(Limits{Memory: 1 << 20})
+	tracer, err := newJsTracer("{f: function() { return this.f(); }, step: function() { this.f(); }, fault: function() {}, result: function() { return null; }}", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var overflow *goja.StackOverflowError
+	if _, err = runTrace(tracer, testCtx(), params.TestChainConfig, nil); !errors.As(err, &overflow) {
+		t.Errorf("expected a stack overflow, got %v", err)
+	}
+}
+
+func TestLoadTracers(t *testing.T) {
+	dir := t.TempDir()
+	code := "{count: 0, step: function() { this.count += 1; }, fault: function() {}, result: function() { return this.count; }}"
+	if err := os.WriteFile(filepath.Join(dir, "counter.js"), []byte(code), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := LoadTracers(dir); err != nil {
+		t.Fatal(err)
+	}
+	tracer, err := newJsTracer("counter", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !programs.Contains(crypto.Keccak256Hash([]byte(code))) {
+		t.Error("expected the compiled tracer to be cached")
+	}
+	if have, err := runTrace(tracer, testCtx(), params.TestChainConfig, nil); err != nil || string(have) != "3" {
+		t.Errorf("expected return value to be '3' got '%s', error %v", have, err)
+	}
+
+	// tracers which don't compile are rejected
+	if err := os.WriteFile(filepath.Join(dir, "broken.js"), []byte("{step: function() {"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := LoadTracers(dir); err == nil {
+		t.Error("expected an error for a tracer which doesn't compile")
+	}
+}
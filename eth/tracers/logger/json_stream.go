@@ -10,6 +10,7 @@ import (
 	libcommon "github.com/ledgerwatch/erigon-lib/common"
 
 	"github.com/ledgerwatch/erigon/common"
+	"github.com/ledgerwatch/erigon/common/compiler"
 	"github.com/ledgerwatch/erigon/core/vm"
 )
 
@@ -31,6 +32,7 @@ type JsonStreamLogger struct {
 	output    []byte //nolint
 	err       error  //nolint
 	env       vm.VMInterface
+	sources   *sourceTracker
 }
 
 // NewStructLogger returns a new logger
@@ -43,6 +45,9 @@ func NewJsonStreamLogger(cfg *LogConfig, ctx context.Context, stream *jsoniter.S
 	}
 	if cfg != nil {
 		logger.cfg = *cfg
+		if cfg.Sources != nil {
+			logger.sources = newSourceTracker(cfg.Sources)
+		}
 	}
 	return logger
 }
@@ -54,9 +59,15 @@ func (l *JsonStreamLogger) CaptureTxEnd(restGas uint64) {}
 // CaptureStart implements the Tracer interface to initialize the tracing operation.
 func (l *JsonStreamLogger) CaptureStart(env vm.VMInterface, from libcommon.Address, to libcommon.Address, precompile bool, create bool, input []byte, gas uint64, value *uint256.Int, code []byte) {
 	l.env = env
+	if l.sources != nil {
+		l.sources.enter(to, create)
+	}
 }
 
 func (l *JsonStreamLogger) CaptureEnter(typ vm.OpCode, from libcommon.Address, to libcommon.Address, precompile bool, create bool, input []byte, gas uint64, value *uint256.Int, code []byte) {
+	if l.sources != nil {
+		l.sources.enter(to, create)
+	}
 }
 
 // CaptureState logs a new structured log message and pushes it out to the environment
@@ -72,6 +83,13 @@ func (l *JsonStreamLogger) CaptureState(pc uint64, op vm.OpCode, gas, cost uint6
 		return
 	default:
 	}
+	var (
+		source      *compiler.SourceLocation
+		sourceStack []SourceFrame
+	)
+	if l.sources != nil {
+		source, sourceStack = l.sources.step(pc, op, contract.Code)
+	}
 	// check if already accumulated the specified number of logs
 	if l.cfg.Limit != 0 && l.cfg.Limit <= len(l.logs) {
 		return
@@ -131,6 +149,16 @@ func (l *JsonStreamLogger) CaptureState(pc uint64, op vm.OpCode, gas, cost uint6
 		l.stream.WriteObjectEnd()
 		//l.stream.WriteString(err.Error())
 	}
+	if source != nil {
+		l.stream.WriteMore()
+		l.stream.WriteObjectField("source")
+		l.stream.WriteVal(source)
+	}
+	if sourceStack != nil {
+		l.stream.WriteMore()
+		l.stream.WriteObjectField("sourceStack")
+		l.stream.WriteVal(sourceStack)
+	}
 	if !l.cfg.DisableStack {
 		l.stream.WriteMore()
 		l.stream.WriteObjectField("stack")
@@ -193,7 +221,13 @@ func (l *JsonStreamLogger) CaptureFault(pc uint64, op vm.OpCode, gas, cost uint6
 
 // CaptureEnd is called after the call finishes to finalize the tracing.
 func (l *JsonStreamLogger) CaptureEnd(output []byte, usedGas uint64, err error) {
+	if l.sources != nil {
+		l.sources.exit()
+	}
 }
 
 func (l *JsonStreamLogger) CaptureExit(output []byte, usedGas uint64, err error) {
+	if l.sources != nil {
+		l.sources.exit()
+	}
 }
@@ -45,6 +45,8 @@ type LogConfig struct {
 	Limit             int  // maximum length of output, but zero means unlimited
 	// Chain overrides, can be used to execute a trace using future fork rules
 	Overrides *chain.Config `json:"overrides,omitempty"`
+	// Solidity sources to annotate the steps with, only used by the JsonStreamLogger
+	Sources *SourceConfig `json:"sources,omitempty"`
 }
 
 //go:generate gencodec -type StructLog -field-override structLogMarshaling -out gen_structlog.go
@@ -0,0 +1,144 @@
+package logger
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+
+	"github.com/ledgerwatch/erigon/common/compiler"
+	"github.com/ledgerwatch/erigon/core/vm"
+)
+
+// SourceConfig asks the logger to annotate each step with its Solidity source location
+// and call stack, using the solc standard JSON the contracts were compiled with.
+type SourceConfig struct {
+	Contracts map[libcommon.Address]*SourceContract `json:"contracts,omitempty"`
+	// Dir holds a <address>.json file with the SourceContract of each of the contracts
+	// missing from Contracts. It is set by the node, not by the request.
+	Dir string `json:"-"`
+}
+
+// SourceContract is the solc standard JSON input and output a contract was compiled with.
+type SourceContract struct {
+	Input  *compiler.StandardInput  `json:"input,omitempty"` // only the lines are unknown without it
+	Output *compiler.StandardOutput `json:"output"`
+	Name   string                   `json:"name,omitempty"` // <source unit>:<contract>, optional if the output holds a single contract
+}
+
+// SourceFrame is an entry of the Solidity call stack, either a contract call or an
+// internal function call. The location is missing if the sources of the contract are
+// unknown.
+type SourceFrame struct {
+	Address libcommon.Address `json:"address"`
+	*compiler.SourceLocation
+}
+
+type sourceMapKey struct {
+	addr     libcommon.Address
+	creation bool
+}
+
+type sourceTrackerFrame struct {
+	addr     libcommon.Address
+	creation bool
+	loaded   bool
+	m        *compiler.SourceMap
+	calls    []*compiler.SourceLocation // call sites of the internal function calls
+	last     *compiler.SourceLocation
+}
+
+// sourceTracker follows the Solidity call stack through the calls and the jumps into and
+// out of internal functions.
+type sourceTracker struct {
+	cfg    *SourceConfig
+	maps   map[sourceMapKey]*compiler.SourceMap // nil for the contracts without sources
+	frames []*sourceTrackerFrame
+}
+
+func newSourceTracker(cfg *SourceConfig) *sourceTracker {
+	return &sourceTracker{cfg: cfg, maps: make(map[sourceMapKey]*compiler.SourceMap)}
+}
+
+func (t *sourceTracker) enter(addr libcommon.Address, creation bool) {
+	t.frames = append(t.frames, &sourceTrackerFrame{addr: addr, creation: creation})
+}
+
+func (t *sourceTracker) exit() {
+	if len(t.frames) > 0 {
+		t.frames = t.frames[:len(t.frames)-1]
+	}
+}
+
+// step returns the source location of the step and the call stack leading to it.
+func (t *sourceTracker) step(pc uint64, op vm.OpCode, code []byte) (*compiler.SourceLocation, []SourceFrame) {
+	if len(t.frames) == 0 {
+		return nil, nil
+	}
+	frame := t.frames[len(t.frames)-1]
+	if !frame.loaded {
+		frame.m, frame.loaded = t.sourceMap(frame.addr, frame.creation, code), true
+	}
+	if frame.m == nil {
+		return nil, t.stack()
+	}
+	loc, jump := frame.m.Location(pc)
+	frame.last = loc
+	stack := t.stack()
+	if op == vm.JUMP {
+		switch jump {
+		case 'i':
+			frame.calls = append(frame.calls, loc)
+		case 'o':
+			if len(frame.calls) > 0 {
+				frame.calls = frame.calls[:len(frame.calls)-1]
+			}
+		}
+	}
+	return loc, stack
+}
+
+func (t *sourceTracker) stack() []SourceFrame {
+	var stack []SourceFrame
+	for _, frame := range t.frames {
+		for _, call := range frame.calls {
+			stack = append(stack, SourceFrame{Address: frame.addr, SourceLocation: call})
+		}
+		stack = append(stack, SourceFrame{Address: frame.addr, SourceLocation: frame.last})
+	}
+	return stack
+}
+
+func (t *sourceTracker) sourceMap(addr libcommon.Address, creation bool, code []byte) *compiler.SourceMap {
+	key := sourceMapKey{addr: addr, creation: creation}
+	if m, ok := t.maps[key]; ok {
+		return m
+	}
+	var m *compiler.SourceMap
+	if contract := t.contract(addr); contract != nil && contract.Output != nil {
+		// the contracts without usable sources are just not annotated
+		m, _ = compiler.NewSourceMap(contract.Output, contract.Input, contract.Name, code, creation)
+	}
+	t.maps[key] = m
+	return m
+}
+
+func (t *sourceTracker) contract(addr libcommon.Address) *SourceContract {
+	if contract, ok := t.cfg.Contracts[addr]; ok {
+		return contract
+	}
+	if t.cfg.Dir == "" {
+		return nil
+	}
+	data, err := os.ReadFile(filepath.Join(t.cfg.Dir, strings.ToLower(addr.Hex())+".json"))
+	if err != nil {
+		return nil
+	}
+	var contract SourceContract
+	if err = json.Unmarshal(data, &contract); err != nil {
+		return nil
+	}
+	return &contract
+}
@@ -0,0 +1,80 @@
+package logger
+
+import (
+	"encoding/json"
+	"testing"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+
+	"github.com/ledgerwatch/erigon/common/compiler"
+	"github.com/ledgerwatch/erigon/core/vm"
+)
+
+const testSourceContract = `{
+  "input": {"sources": {"C.sol": {"content": "contract C {\n    function f() public {\n        g();\n    }\n    function g() internal {}\n}\n"}}},
+  "output": {
+    "sources": {
+      "C.sol": {"id": 0, "ast": {"nodeType": "ContractDefinition", "name": "C", "src": "0:88:0", "nodes": [
+        {"nodeType": "FunctionDefinition", "name": "f", "kind": "function", "src": "17:40:0"},
+        {"nodeType": "FunctionDefinition", "name": "g", "kind": "function", "src": "62:24:0"}
+      ]}}
+    },
+    "contracts": {
+      "C.sol": {"C": {"evm": {"deployedBytecode": {"object": "6004560056005b56", "sourceMap": "17:40:0:-;47:3:0:i;17:40:0:-;62:24:0:-;62:24:0:o"}}}}
+    }
+  }
+}`
+
+func TestSourceTracker(t *testing.T) {
+	var contract SourceContract
+	if err := json.Unmarshal([]byte(testSourceContract), &contract); err != nil {
+		t.Fatal(err)
+	}
+	addr, other := libcommon.Address{1}, libcommon.Address{2}
+	tracker := newSourceTracker(&SourceConfig{Contracts: map[libcommon.Address]*SourceContract{addr: &contract}})
+	// PUSH1 4, JUMP into g, STOP, g: JUMPDEST, JUMP back
+	code := []byte{byte(vm.PUSH1), 0x04, byte(vm.JUMP), byte(vm.STOP), byte(vm.JUMPDEST), byte(vm.JUMP)}
+
+	f := &compiler.SourceLocation{File: "C.sol", Line: 2, Column: 5, Start: 17, Length: 40, Function: "C.f"}
+	call := &compiler.SourceLocation{File: "C.sol", Line: 3, Column: 9, Start: 47, Length: 3, Function: "C.f"}
+	g := &compiler.SourceLocation{File: "C.sol", Line: 5, Column: 5, Start: 62, Length: 24, Function: "C.g"}
+
+	tracker.enter(addr, false)
+	steps := []struct {
+		pc    uint64
+		op    vm.OpCode
+		loc   *compiler.SourceLocation
+		stack []*compiler.SourceLocation
+	}{
+		{0, vm.PUSH1, f, []*compiler.SourceLocation{f}},
+		{2, vm.JUMP, call, []*compiler.SourceLocation{call}},
+		{4, vm.JUMPDEST, g, []*compiler.SourceLocation{call, g}},
+		{5, vm.JUMP, g, []*compiler.SourceLocation{call, g}},
+		{3, vm.STOP, f, []*compiler.SourceLocation{f}},
+	}
+	for i, step := range steps {
+		loc, stack := tracker.step(step.pc, step.op, code)
+		if *loc != *step.loc {
+			t.Fatalf("step %d: have location %+v, want %+v", i, loc, step.loc)
+		}
+		if len(stack) != len(step.stack) {
+			t.Fatalf("step %d: have stack %+v, want %+v", i, stack, step.stack)
+		}
+		for j, frame := range stack {
+			if frame.Address != addr || *frame.SourceLocation != *step.stack[j] {
+				t.Fatalf("step %d: have stack frame %d %+v, want %+v", i, j, frame, step.stack[j])
+			}
+		}
+	}
+
+	// calls of contracts without sources show up in the stack without a location
+	tracker.enter(other, false)
+	loc, stack := tracker.step(0, vm.STOP, []byte{byte(vm.STOP)})
+	if loc != nil || len(stack) != 2 || stack[1].Address != other || stack[1].SourceLocation != nil {
+		t.Fatalf("have location %+v, stack %+v", loc, stack)
+	}
+	tracker.exit()
+	if len(tracker.frames) != 1 {
+		t.Fatalf("have %d frames, want 1", len(tracker.frames))
+	}
+}
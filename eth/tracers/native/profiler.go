@@ -0,0 +1,315 @@
+package native
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/pprof/profile"
+	"github.com/holiman/uint256"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/common/hexutility"
+
+	"github.com/ledgerwatch/erigon/core/vm"
+	"github.com/ledgerwatch/erigon/eth/tracers"
+)
+
+func init() {
+	register("profilerTracer", newProfilerTracer)
+}
+
+// profileSample is the gas and wall time spent on one opcode at one pc of a call stack.
+// The gas of the calls made by an opcode is attributed to the callee, so the samples of
+// a transaction add up to the gas used by its execution.
+type profileSample struct {
+	Stack    []string          `json:"stack"` // outermost frame first
+	Contract libcommon.Address `json:"contract"`
+	Pc       uint64            `json:"pc"`
+	Op       string            `json:"op"`
+	Gas      uint64            `json:"gas"`
+	Time     time.Duration     `json:"time"` // nanoseconds
+	Count    uint64            `json:"count"`
+}
+
+type profileResult struct {
+	GasUsed   uint64           `json:"gasUsed"`
+	Samples   []*profileSample `json:"samples"`
+	Collapsed string           `json:"collapsed"`
+	Pprof     hexutility.Bytes `json:"pprof"`
+}
+
+// profileStep is the opcode a frame is executing, it is charged once the next one starts.
+type profileStep struct {
+	pc       uint64
+	op       vm.OpCode
+	gas      uint64 // gas available before the opcode
+	childGas uint64 // gas used by the calls made by the opcode
+	time     time.Duration
+}
+
+type profileFrame struct {
+	addr       libcommon.Address
+	key        string // the frame labels of the call stack, joined by ';'
+	stack      []string
+	gas        uint64
+	precompile bool
+	step       *profileStep
+}
+
+// profilerTracer attributes gas and wall time to contract, function selector, pc and
+// opcode. The result carries the samples along with a pprof profile and a flamegraph
+// in the collapsed stack format.
+type profilerTracer struct {
+	noopTracer
+	frames    []*profileFrame
+	samples   map[string]*profileSample
+	gasUsed   uint64
+	last      time.Time // the time elapsed since is charged to the step being executed
+	interrupt uint32    // Atomic flag to signal execution interruption
+	reason    error     // Textual reason for the interruption
+}
+
+// newProfilerTracer returns a native go tracer which profiles the gas and time spent
+// by the executed opcodes.
+func newProfilerTracer(ctx *tracers.Context, _ json.RawMessage) (tracers.Tracer, error) {
+	return &profilerTracer{samples: make(map[string]*profileSample)}, nil
+}
+
+// profileFrameLabel names a call frame after the contract and the function called.
+func profileFrameLabel(addr libcommon.Address, create bool, input []byte) string {
+	switch {
+	case create:
+		return addr.Hex() + ":constructor"
+	case len(input) >= 4:
+		return fmt.Sprintf("%s:%#x", addr.Hex(), input[:4])
+	default:
+		return addr.Hex()
+	}
+}
+
+func (t *profilerTracer) top() *profileFrame {
+	if len(t.frames) == 0 {
+		return nil
+	}
+	return t.frames[len(t.frames)-1]
+}
+
+// charge accounts the time elapsed since the last event to the step being executed.
+func (t *profilerTracer) charge(now time.Time) {
+	if frame := t.top(); frame != nil && frame.step != nil {
+		frame.step.time += now.Sub(t.last)
+	}
+	t.last = now
+}
+
+func (t *profilerTracer) push(addr libcommon.Address, precompile, create bool, input []byte, gas uint64) {
+	frame := &profileFrame{addr: addr, gas: gas, precompile: precompile}
+	if parent := t.top(); parent != nil {
+		frame.stack = append(frame.stack, parent.stack...)
+	}
+	frame.stack = append(frame.stack, profileFrameLabel(addr, create, input))
+	frame.key = strings.Join(frame.stack, ";")
+	t.frames = append(t.frames, frame)
+}
+
+// pop finishes the innermost frame and accounts the gas it used to the calling step.
+func (t *profilerTracer) pop(gasUsed uint64) {
+	frame := t.top()
+	if frame == nil {
+		return
+	}
+	t.frames = t.frames[:len(t.frames)-1]
+
+	if frame.step != nil {
+		var gasLeft uint64
+		if gasUsed < frame.gas {
+			gasLeft = frame.gas - gasUsed
+		}
+		t.finishStep(frame, gasLeft)
+	} else if frame.precompile && gasUsed > 0 {
+		t.record(frame, 0, "PRECOMPILE", gasUsed, 0)
+	}
+	if parent := t.top(); parent != nil && parent.step != nil {
+		parent.step.childGas += gasUsed
+	}
+}
+
+func (t *profilerTracer) finishStep(frame *profileFrame, gasLeft uint64) {
+	step := frame.step
+	if step == nil {
+		return
+	}
+	frame.step = nil
+	var gas uint64
+	if spent := step.gas - gasLeft; gasLeft < step.gas && spent > step.childGas {
+		gas = spent - step.childGas
+	}
+	t.record(frame, step.pc, step.op.String(), gas, step.time)
+}
+
+func (t *profilerTracer) record(frame *profileFrame, pc uint64, op string, gas uint64, elapsed time.Duration) {
+	key := fmt.Sprintf("%s;%s@%#x", frame.key, op, pc)
+	sample, ok := t.samples[key]
+	if !ok {
+		sample = &profileSample{Stack: frame.stack, Contract: frame.addr, Pc: pc, Op: op}
+		t.samples[key] = sample
+	}
+	sample.Gas += gas
+	sample.Time += elapsed
+	sample.Count++
+}
+
+// CaptureStart implements the EVMLogger interface to initialize the tracing operation.
+func (t *profilerTracer) CaptureStart(env vm.VMInterface, from libcommon.Address, to libcommon.Address, precompile, create bool, input []byte, gas uint64, value *uint256.Int, code []byte) {
+	t.last = time.Now()
+	t.push(to, precompile, create, input, gas)
+}
+
+// CaptureEnd is called after the call finishes to finalize the tracing.
+func (t *profilerTracer) CaptureEnd(output []byte, gasUsed uint64, err error) {
+	t.charge(time.Now())
+	t.pop(gasUsed)
+	t.gasUsed += gasUsed
+}
+
+// CaptureState implements the EVMLogger interface to trace a single step of VM execution.
+func (t *profilerTracer) CaptureState(pc uint64, op vm.OpCode, gas, cost uint64, scope *vm.ScopeContext, rData []byte, depth int, err error) {
+	// Skip if tracing was interrupted
+	if atomic.LoadUint32(&t.interrupt) > 0 {
+		return
+	}
+	frame := t.top()
+	if frame == nil {
+		return
+	}
+	t.charge(time.Now())
+	t.finishStep(frame, gas)
+	frame.step = &profileStep{pc: pc, op: op, gas: gas}
+}
+
+// CaptureEnter is called when EVM enters a new scope (via call, create or selfdestruct).
+func (t *profilerTracer) CaptureEnter(typ vm.OpCode, from libcommon.Address, to libcommon.Address, precompile, create bool, input []byte, gas uint64, value *uint256.Int, code []byte) {
+	t.charge(time.Now())
+	t.push(to, precompile, create, input, gas)
+}
+
+// CaptureExit is called when EVM exits a scope, even if the scope didn't
+// execute any code.
+func (t *profilerTracer) CaptureExit(output []byte, gasUsed uint64, err error) {
+	t.charge(time.Now())
+	t.pop(gasUsed)
+}
+
+// GetResult returns the samples, the heaviest first, along with the profile encoded
+// as a gzipped pprof protobuf and in the collapsed stack format, weighted by gas.
+func (t *profilerTracer) GetResult() (json.RawMessage, error) {
+	samples := make([]*profileSample, 0, len(t.samples))
+	for _, sample := range t.samples {
+		samples = append(samples, sample)
+	}
+	sort.Slice(samples, func(i, j int) bool {
+		if samples[i].Gas != samples[j].Gas {
+			return samples[i].Gas > samples[j].Gas
+		}
+		return profileSampleKey(samples[i]) < profileSampleKey(samples[j])
+	})
+
+	pprof, err := profilePprof(samples)
+	if err != nil {
+		return nil, err
+	}
+	res, err := json.Marshal(&profileResult{
+		GasUsed:   t.gasUsed,
+		Samples:   samples,
+		Collapsed: profileCollapsed(samples),
+		Pprof:     pprof,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res, t.reason
+}
+
+// Stop terminates execution of the tracer at the first opportune moment.
+func (t *profilerTracer) Stop(err error) {
+	t.reason = err
+	atomic.StoreUint32(&t.interrupt, 1)
+}
+
+func profileSampleKey(s *profileSample) string {
+	return fmt.Sprintf("%s;%s@%#x", strings.Join(s.Stack, ";"), s.Op, s.Pc)
+}
+
+// profileCollapsed renders the samples in the collapsed stack format read by the
+// flamegraph tools, one line per sample.
+func profileCollapsed(samples []*profileSample) string {
+	lines := make([]string, 0, len(samples))
+	for _, s := range samples {
+		if s.Gas == 0 {
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("%s %d", profileSampleKey(s), s.Gas))
+	}
+	sort.Strings(lines)
+	return strings.Join(lines, "\n")
+}
+
+// profilePprof encodes the samples as a gzipped pprof protobuf. The call frames are the
+// functions of the profile, the opcodes are leaf functions located in the contract at
+// their pc.
+func profilePprof(samples []*profileSample) ([]byte, error) {
+	p := &profile.Profile{
+		SampleType: []*profile.ValueType{
+			{Type: "gas", Unit: "count"},
+			{Type: "time", Unit: "nanoseconds"},
+			{Type: "steps", Unit: "count"},
+		},
+		DefaultSampleType: "gas",
+	}
+	functions := make(map[string]*profile.Function)
+	function := func(name, file string) *profile.Function {
+		key := name + "@" + file
+		if fn, ok := functions[key]; ok {
+			return fn
+		}
+		fn := &profile.Function{ID: uint64(len(p.Function) + 1), Name: name, SystemName: name, Filename: file}
+		functions[key] = fn
+		p.Function = append(p.Function, fn)
+		return fn
+	}
+	locations := make(map[string]*profile.Location)
+	location := func(key string, pc uint64, fn *profile.Function, line int64) *profile.Location {
+		if loc, ok := locations[key]; ok {
+			return loc
+		}
+		loc := &profile.Location{ID: uint64(len(p.Location) + 1), Address: pc, Line: []profile.Line{{Function: fn, Line: line}}}
+		locations[key] = loc
+		p.Location = append(p.Location, loc)
+		return loc
+	}
+
+	for _, s := range samples {
+		// pprof stacks start at the leaf
+		file := s.Contract.Hex()
+		stack := make([]*profile.Location, 0, len(s.Stack)+1)
+		stack = append(stack, location(profileSampleKey(s), s.Pc, function(s.Op, file), int64(s.Pc)))
+		for i := len(s.Stack) - 1; i >= 0; i-- {
+			stack = append(stack, location(s.Stack[i], 0, function(s.Stack[i], ""), 0))
+		}
+		p.Sample = append(p.Sample, &profile.Sample{
+			Location: stack,
+			Value:    []int64{int64(s.Gas), int64(s.Time), int64(s.Count)},
+		})
+	}
+
+	var buf bytes.Buffer
+	if err := p.Write(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -52,6 +52,7 @@ var UnknownPayloadErr = rpc.CustomError{Code: -38001, Message: "Unknown payload"
 var InvalidForkchoiceStateErr = rpc.CustomError{Code: -38002, Message: "Invalid forkchoice state"}
 var InvalidPayloadAttributesErr = rpc.CustomError{Code: -38003, Message: "Invalid payload attributes"}
 var TooLargeRequestErr = rpc.CustomError{Code: -38004, Message: "Too large request"}
+var UnsupportedForkErr = rpc.CustomError{Code: -38005, Message: "Unsupported fork"}
 
 type EthBackendServer struct {
 	remote.UnimplementedETHBACKENDServer // must be embedded to have forward compatible implementations.
@@ -60,7 +61,7 @@ type EthBackendServer struct {
 	eth         EthBackend
 	events      *shards.Events
 	db          kv.RoDB
-	blockReader services.BlockAndTxnReader
+	blockReader services.FullBlockReader
 	config      *chain.Config
 
 	// Block proposing for proof-of-stake
@@ -84,7 +85,7 @@ type EthBackend interface {
 	Peers(ctx context.Context) (*remote.PeersReply, error)
 }
 
-func NewEthBackendServer(ctx context.Context, eth EthBackend, db kv.RwDB, events *shards.Events, blockReader services.BlockAndTxnReader,
+func NewEthBackendServer(ctx context.Context, eth EthBackend, db kv.RwDB, events *shards.Events, blockReader services.FullBlockReader,
 	config *chain.Config, builderFunc builder.BlockBuilderFunc, hd *headerdownload.HeaderDownload, proposing bool, logger log.Logger,
 ) *EthBackendServer {
 	s := &EthBackendServer{ctx: ctx, eth: eth, events: events, db: db, blockReader: blockReader, config: config,
@@ -787,16 +788,20 @@ func (s *EthBackendServer) EngineGetPayloadBodiesByHashV1(ctx context.Context, r
 
 	for hashIdx, hash := range request.Hashes {
 		h := gointerfaces.ConvertH256ToHash(hash)
-		block, err := rawdb.ReadBlockByHash(tx, h)
+		number := rawdb.ReadHeaderNumber(tx, h)
+		if number == nil {
+			// unknown blocks have a null body
+			continue
+		}
+		body, err := s.blockReader.BodyWithTransactions(ctx, tx, h, *number)
 		if err != nil {
 			return nil, err
 		}
 
-		body, err := extractPayloadBodyFromBlock(block)
+		bodies[hashIdx], err = extractPayloadBodyFromBody(body)
 		if err != nil {
 			return nil, err
 		}
-		bodies[hashIdx] = body
 	}
 
 	return &remote.EngineGetPayloadBodiesV1Response{Bodies: bodies}, nil
@@ -812,7 +817,7 @@ func (s *EthBackendServer) EngineGetPayloadBodiesByRangeV1(ctx context.Context,
 	bodies := make([]*types2.ExecutionPayloadBodyV1, 0, request.Count)
 
 	for i := uint64(0); i < request.Count; i++ {
-		hash, err := rawdb.ReadCanonicalHash(tx, request.Start+i)
+		hash, err := s.blockReader.CanonicalHash(ctx, tx, request.Start+i)
 		if err != nil {
 			return nil, err
 		}
@@ -821,25 +826,27 @@ func (s *EthBackendServer) EngineGetPayloadBodiesByRangeV1(ctx context.Context,
 			break
 		}
 
-		block := rawdb.ReadBlock(tx, hash, request.Start+i)
-		body, err := extractPayloadBodyFromBlock(block)
+		body, err := s.blockReader.BodyWithTransactions(ctx, tx, hash, request.Start+i)
+		if err != nil {
+			return nil, err
+		}
+		payloadBody, err := extractPayloadBodyFromBody(body)
 		if err != nil {
 			return nil, err
 		}
-		bodies = append(bodies, body)
+		bodies = append(bodies, payloadBody)
 	}
 
 	return &remote.EngineGetPayloadBodiesV1Response{Bodies: bodies}, nil
 }
 
-func extractPayloadBodyFromBlock(block *types.Block) (*types2.ExecutionPayloadBodyV1, error) {
-	if block == nil {
+func extractPayloadBodyFromBody(body *types.Body) (*types2.ExecutionPayloadBodyV1, error) {
+	if body == nil {
 		return nil, nil
 	}
 
-	txs := block.Transactions()
-	bdTxs := make([][]byte, len(txs))
-	for idx, tx := range txs {
+	bdTxs := make([][]byte, len(body.Transactions))
+	for idx, tx := range body.Transactions {
 		var buf bytes.Buffer
 		if err := tx.MarshalBinary(&buf); err != nil {
 			return nil, err
@@ -848,24 +855,8 @@ func extractPayloadBodyFromBlock(block *types.Block) (*types2.ExecutionPayloadBo
 		}
 	}
 
-	wds := block.Withdrawals()
-	bdWds := make([]*types2.Withdrawal, len(wds))
-
-	if wds == nil {
-		// pre shanghai blocks could have nil withdrawals so nil the slice as per spec
-		bdWds = nil
-	} else {
-		for idx, wd := range wds {
-			bdWds[idx] = &types2.Withdrawal{
-				Index:          wd.Index,
-				ValidatorIndex: wd.Validator,
-				Address:        gointerfaces.ConvertAddressToH160(wd.Address),
-				Amount:         wd.Amount,
-			}
-		}
-	}
-
-	return &types2.ExecutionPayloadBodyV1{Transactions: bdTxs, Withdrawals: bdWds}, nil
+	// pre shanghai blocks could have nil withdrawals so nil the slice as per spec
+	return &types2.ExecutionPayloadBodyV1{Transactions: bdTxs, Withdrawals: ConvertWithdrawalsToRpc(body.Withdrawals)}, nil
 }
 
 func (s *EthBackendServer) evictOldBuilders() {
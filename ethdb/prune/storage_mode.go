@@ -308,6 +308,31 @@ func EnsureNotChanged(tx kv.GetPut, pruneMode Mode) (Mode, error) {
 	return pm, nil
 }
 
+// EnsureStricter - checks that pruneMode prunes at least the data pruned by the mode of the db at block head,
+// to apply it to an existing db: pruned data can't come back without re-sync.
+func EnsureStricter(old, pruneMode Mode, head uint64) error {
+	for _, amounts := range []struct {
+		flag     string
+		old, new BlockAmount
+	}{
+		{"h", old.History, pruneMode.History},
+		{"r", old.Receipts, pruneMode.Receipts},
+		{"t", old.TxIndex, pruneMode.TxIndex},
+		{"c", old.CallTraces, pruneMode.CallTraces},
+	} {
+		if !amounts.old.Enabled() {
+			continue
+		}
+		if !amounts.new.Enabled() {
+			return fmt.Errorf("--prune=%s can't be disabled, last time you used: %s", amounts.flag, old.String())
+		}
+		if amounts.new.PruneTo(head) < amounts.old.PruneTo(head) {
+			return fmt.Errorf("--prune.%s.%s=%d keeps blocks already pruned, last time you used: %s", amounts.flag, amounts.new.dbType(), amounts.new.toValue(), old.String())
+		}
+	}
+	return nil
+}
+
 func setIfNotExist(db kv.GetPut, pm Mode) error {
 	var (
 		err error
@@ -73,3 +73,24 @@ func TestBeforePruneTo(t *testing.T) {
 		})
 	}
 }
+
+func TestEnsureStricter(t *testing.T) {
+	const head = 3_000_000
+	old := DefaultMode
+	old.History = Distance(90_000)
+	old.Receipts = Before(1_000_000)
+
+	stricter := old
+	stricter.History = Before(2_950_000)
+	stricter.TxIndex = Distance(90_000)
+	assert.NoError(t, EnsureStricter(old, stricter, head))
+	assert.NoError(t, EnsureStricter(old, old, head))
+
+	keepsMore := old
+	keepsMore.History = Distance(100_000)
+	assert.Error(t, EnsureStricter(old, keepsMore, head))
+
+	disabled := old
+	disabled.Receipts = DefaultMode.Receipts
+	assert.Error(t, EnsureStricter(old, disabled, head))
+}
@@ -0,0 +1,49 @@
+package discover
+
+import (
+	"github.com/ledgerwatch/erigon/p2p/enode"
+)
+
+// TopicsEntry is the "topics" ENR entry. It lists the topics (e.g. custom network names)
+// a node serves. Advertising a topic is done by setting this entry on the local node, and
+// searching for it by filtering the v5 table walk with NewTopicFilter. This lets several
+// networks share a bootnode while nodes only dial peers of their own network.
+type TopicsEntry []string
+
+// ENRKey implements enr.Entry.
+func (TopicsEntry) ENRKey() string { return "topics" }
+
+// NewTopicFilter returns a node filter accepting nodes which advertise at least one of
+// the given topics. An empty topic list accepts every node.
+func NewTopicFilter(topics []string) func(*enode.Node) bool {
+	wanted := make(map[string]struct{}, len(topics))
+	for _, topic := range topics {
+		wanted[topic] = struct{}{}
+	}
+	return func(n *enode.Node) bool {
+		if len(wanted) == 0 {
+			return true
+		}
+		var entry TopicsEntry
+		if err := n.Load(&entry); err != nil {
+			return false
+		}
+		for _, topic := range entry {
+			if _, ok := wanted[topic]; ok {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// AdvertiseTopics sets the topics advertised in the local node record.
+func (t *UDPv5) AdvertiseTopics(topics []string) {
+	t.localNode.Set(TopicsEntry(topics))
+}
+
+// TopicNodes returns an iterator that finds random nodes in the DHT advertising
+// the given topic.
+func (t *UDPv5) TopicNodes(topic string) enode.Iterator {
+	return enode.Filter(t.RandomNodes(), NewTopicFilter([]string{topic}))
+}
@@ -0,0 +1,35 @@
+package discover
+
+import (
+	"testing"
+
+	"github.com/ledgerwatch/erigon/p2p/enode"
+	"github.com/ledgerwatch/erigon/p2p/enr"
+)
+
+func TestTopicFilter(t *testing.T) {
+	makeNode := func(topics ...string) *enode.Node {
+		var r enr.Record
+		if topics != nil {
+			r.Set(TopicsEntry(topics))
+		}
+		return enode.SignNull(&r, enode.ID{})
+	}
+	tests := []struct {
+		filter []string
+		node   *enode.Node
+		want   bool
+	}{
+		{nil, makeNode(), true},
+		{nil, makeNode("devnet-1"), true},
+		{[]string{"devnet-1"}, makeNode(), false},
+		{[]string{"devnet-1"}, makeNode("devnet-1"), true},
+		{[]string{"devnet-1"}, makeNode("devnet-2", "devnet-1"), true},
+		{[]string{"devnet-1", "l2"}, makeNode("devnet-2"), false},
+	}
+	for i, tt := range tests {
+		if have := NewTopicFilter(tt.filter)(tt.node); have != tt.want {
+			t.Errorf("test %d: filter mismatch: have %v, want %v", i, have, tt.want)
+		}
+	}
+}
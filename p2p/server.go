@@ -97,6 +97,15 @@ type Config struct {
 	// protocol should be started or not.
 	DiscoveryV5 bool `toml:",omitempty"`
 
+	// DiscoveryV5Topics lists the topics advertised in the local node record when
+	// V5 discovery is enabled. If set, the V5 nodes advertising one of these topics
+	// become dial candidates too, otherwise V5 discovery doesn't feed the dialer.
+	DiscoveryV5Topics []string `toml:",omitempty"`
+
+	// DialCandidateFilter, if set, is applied to the nodes produced by all discovery
+	// sources. Nodes for which it returns false are never dialed.
+	DialCandidateFilter func(*enode.Node) bool `toml:"-"`
+
 	// Name sets the node name of this server.
 	// Use common.MakeName to create a name that follows existing conventions.
 	Name string `toml:"-"`
@@ -583,7 +592,7 @@ func (srv *Server) setupDiscovery(ctx context.Context) error {
 	added := make(map[string]bool)
 	for _, proto := range srv.Protocols {
 		if proto.DialCandidates != nil && !added[proto.Name] {
-			srv.discmix.AddSource(proto.DialCandidates)
+			srv.addDiscoverySource(proto.DialCandidates)
 			added[proto.Name] = true
 		}
 	}
@@ -635,7 +644,7 @@ func (srv *Server) setupDiscovery(ctx context.Context) error {
 			return err
 		}
 		srv.ntab = ntab
-		srv.discmix.AddSource(ntab.RandomNodes())
+		srv.addDiscoverySource(ntab.RandomNodes())
 	}
 
 	// Discovery V5
@@ -655,10 +664,23 @@ func (srv *Server) setupDiscovery(ctx context.Context) error {
 		if err != nil {
 			return err
 		}
+		if len(srv.DiscoveryV5Topics) > 0 {
+			srv.DiscV5.AdvertiseTopics(srv.DiscoveryV5Topics)
+			srv.addDiscoverySource(enode.Filter(srv.DiscV5.RandomNodes(), discover.NewTopicFilter(srv.DiscoveryV5Topics)))
+		}
 	}
 	return nil
 }
 
+// addDiscoverySource adds a source of dial candidates to the discovery mixer,
+// applying DialCandidateFilter if it is configured.
+func (srv *Server) addDiscoverySource(iter enode.Iterator) {
+	if srv.DialCandidateFilter != nil {
+		iter = enode.Filter(iter, srv.DialCandidateFilter)
+	}
+	srv.discmix.AddSource(iter)
+}
+
 func (srv *Server) setupDialScheduler() {
 	config := dialConfig{
 		self:           srv.localnode.ID(),
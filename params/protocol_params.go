@@ -16,7 +16,11 @@
 
 package params
 
-import "math/big"
+import (
+	"math/big"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+)
 
 const (
 	GasLimitBoundDivisor uint64 = 1024               // The bound divisor of the gas limit, used in update calculations.
@@ -185,3 +189,6 @@ var (
 	MinimumDifficulty      = big.NewInt(131072) // The minimum that the difficulty may ever be.
 	DurationLimit          = big.NewInt(13)     // The decision boundary on the blocktime duration used to determine whether difficulty should go up or not.
 )
+
+// BeaconRootsAddress is the EIP-4788 contract keeping the recent parent beacon block roots.
+var BeaconRootsAddress = libcommon.HexToAddress("0x000F3df6D732807Ef1319fB7B8bB8522d0Beac02")
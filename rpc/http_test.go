@@ -23,6 +23,10 @@ import (
 	"testing"
 
 	"github.com/ledgerwatch/log/v3"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 )
 
 func confirmStatusCode(t *testing.T, got, want int) {
@@ -129,3 +133,43 @@ func TestHTTPRespBodyUnlimited(t *testing.T) {
 		t.Fatalf("response has wrong length %d, want %d", len(r), respLength)
 	}
 }
+
+// This checks that the spans of the calls continue the trace of the traceparent header.
+func TestHTTPTraceContext(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	s := newTestServer(log.New())
+	defer s.Stop()
+	ts := httptest.NewServer(s)
+	defer ts.Close()
+
+	body := `{"jsonrpc":"2.0","id":1,"method":"test_echo","params":["x",1]}`
+	request, err := http.NewRequest(http.MethodPost, ts.URL, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	request.Header.Set("Content-Type", contentType)
+	request.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	resp, err := http.DefaultClient.Do(request)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	confirmStatusCode(t, resp.StatusCode, http.StatusOK)
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("got %d spans, want 1", len(spans))
+	}
+	if name := spans[0].Name(); name != "test_echo" {
+		t.Fatalf("span name: got %s, want test_echo", name)
+	}
+	if traceID := spans[0].SpanContext().TraceID().String(); traceID != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Fatalf("trace ID: got %s, want 4bf92f3577b34da6a3ce929d0e0e4736", traceID)
+	}
+	if parentID := spans[0].Parent().SpanID().String(); parentID != "00f067aa0ba902b7" {
+		t.Fatalf("parent span ID: got %s, want 00f067aa0ba902b7", parentID)
+	}
+}
@@ -0,0 +1,50 @@
+package tests
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/ledgerwatch/erigon/core"
+)
+
+// BlockTestDivergence is the first value computed while importing the test blocks which
+// differs from the one in the test file.
+type BlockTestDivergence struct {
+	Block uint64 `json:"block"`
+	Field string `json:"field"` // stateRoot, receiptsRoot, gasUsed or lastBlockHash
+	Want  string `json:"want"`
+	Have  string `json:"have"`
+}
+
+// BlockTestError is returned by block tests which failed because the import diverged from
+// the test file. Divergence is nil if the failure couldn't be narrowed down to a value.
+type BlockTestError struct {
+	Err        error
+	Divergence *BlockTestDivergence
+}
+
+func (e *BlockTestError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *BlockTestError) Unwrap() error {
+	return e.Err
+}
+
+// divergenceOf returns the value which made the stages find a block invalid, nil if the
+// error isn't about a value differing from the header.
+func divergenceOf(err error) *BlockTestDivergence {
+	var badBlockErr *core.BadBlockError
+	if !errors.As(err, &badBlockErr) {
+		return nil
+	}
+	var rootErr *core.RootMismatchError
+	if errors.As(err, &rootErr) {
+		return &BlockTestDivergence{Block: badBlockErr.Number, Field: rootErr.Field, Want: rootErr.Want.Hex(), Have: rootErr.Have.Hex()}
+	}
+	var gasErr *core.GasUsedMismatchError
+	if errors.As(err, &gasErr) {
+		return &BlockTestDivergence{Block: badBlockErr.Number, Field: "gasUsed", Want: strconv.FormatUint(gasErr.Want, 10), Have: strconv.FormatUint(gasErr.Have, 10)}
+	}
+	return nil
+}
@@ -0,0 +1,29 @@
+package tests
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ledgerwatch/erigon/core"
+)
+
+func TestDivergenceOf(t *testing.T) {
+	require.Nil(t, divergenceOf(errors.New("did not import block 3")))
+	// mismatches are only divergences of blocks found invalid
+	require.Nil(t, divergenceOf(&core.GasUsedMismatchError{Have: 100, Want: 200}))
+
+	err := fmt.Errorf("block 3 was invalid: %w", &core.BadBlockError{Number: 3, Err: &core.RootMismatchError{Field: "receiptsRoot", Have: libcommon.Hash{0xaa}, Want: libcommon.Hash{0xbb}}})
+	require.Equal(t, &BlockTestDivergence{Block: 3, Field: "receiptsRoot", Want: libcommon.Hash{0xbb}.Hex(), Have: libcommon.Hash{0xaa}.Hex()}, divergenceOf(err))
+
+	err = &core.BadBlockError{Number: 4, Err: &core.RootMismatchError{Field: "stateRoot", Have: libcommon.Hash{0xcc}, Want: libcommon.Hash{0xdd}}}
+	require.Equal(t, &BlockTestDivergence{Block: 4, Field: "stateRoot", Want: libcommon.Hash{0xdd}.Hex(), Have: libcommon.Hash{0xcc}.Hex()}, divergenceOf(err))
+
+	err = &core.BadBlockError{Number: 5, Err: &core.GasUsedMismatchError{Have: 100, Want: 200}}
+	require.Equal(t, &BlockTestDivergence{Block: 5, Field: "gasUsed", Want: "200", Have: "100"}, divergenceOf(err))
+
+	require.Nil(t, divergenceOf(&core.BadBlockError{Number: 6, Err: errors.New("invalid sender")}))
+}
@@ -106,12 +106,30 @@ type btHeaderMarshaling struct {
 }
 
 func (bt *BlockTest) Run(t *testing.T, _ bool) error {
+	return bt.run(t)
+}
+
+// RunStandalone runs the test outside of `go test`, e.g. from `evm blocktest`. Each run has its
+// own temporary directory, removed once it's done, so the tests can run in parallel.
+func (bt *BlockTest) RunStandalone() error {
+	return bt.run(nil)
+}
+
+// Network returns the name of the fork the test is defined for.
+func (bt *BlockTest) Network() string {
+	return bt.json.Network
+}
+
+func (bt *BlockTest) run(tb testing.TB) error {
 	config, ok := Forks[bt.json.Network]
 	if !ok {
 		return UnsupportedForkError{bt.json.Network}
 	}
 	engine := ethconsensusconfig.CreateConsensusEngineBareBones(config, log.New())
-	m := stages.MockWithGenesisEngine(t, bt.genesis(config), engine, false)
+	m := stages.MockWithGenesisEngine(tb, bt.genesis(config), engine, false)
+	if tb == nil {
+		defer m.Close()
+	}
 
 	// import pre accounts & construct test genesis block & state root
 	if m.Genesis.Hash() != bt.json.Genesis.Hash {
@@ -133,7 +151,12 @@ func (bt *BlockTest) Run(t *testing.T, _ bool) error {
 	defer tx.Rollback()
 	cmlast := rawdb.ReadHeadBlockHash(tx)
 	if libcommon.Hash(bt.json.BestBlock) != cmlast {
-		return fmt.Errorf("last block hash validation mismatch: want: %x, have: %x", bt.json.BestBlock, cmlast)
+		err := fmt.Errorf("last block hash validation mismatch: want: %x, have: %x", bt.json.BestBlock, cmlast)
+		divergence := &BlockTestDivergence{Field: "lastBlockHash", Want: libcommon.Hash(bt.json.BestBlock).Hex(), Have: cmlast.Hex()}
+		if number := rawdb.ReadHeaderNumber(tx, cmlast); number != nil {
+			divergence.Block = *number
+		}
+		return &BlockTestError{Err: err, Divergence: divergence}
 	}
 	newDB := state.New(state.NewPlainStateReader(tx))
 	if err = bt.validatePostState(newDB); err != nil {
@@ -191,7 +214,10 @@ func (bt *BlockTest) insertBlocks(m *stages.MockSentry) ([]btBlock, error) {
 			if b.BlockHeader == nil {
 				continue // OK - block is supposed to be invalid, continue with next block
 			} else {
-				return nil, fmt.Errorf("block #%v insertion into chain failed: %w", cb.Number(), err1)
+				return nil, &BlockTestError{
+					Err:        fmt.Errorf("block #%v insertion into chain failed: %w", cb.Number(), err1),
+					Divergence: divergenceOf(err1),
+				}
 			}
 		} else if b.BlockHeader == nil {
 			if err := m.DB.View(context.Background(), func(tx kv.Tx) error {
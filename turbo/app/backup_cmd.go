@@ -1,6 +1,8 @@
 package app
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -9,6 +11,7 @@ import (
 	"github.com/ledgerwatch/erigon-lib/common/datadir"
 	"github.com/ledgerwatch/erigon-lib/common/dir"
 	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/ledgerwatch/erigon-lib/kv/mdbx"
 	"github.com/ledgerwatch/erigon/cmd/utils"
 	"github.com/ledgerwatch/erigon/cmd/utils/flags"
 	"github.com/ledgerwatch/erigon/turbo/backup"
@@ -22,15 +25,23 @@ import (
 var backupCommand = cli.Command{
 	Name: "alpha_backup",
 	Description: `Alpha verison of command. Backup all databases without stopping of Erigon.
-While this command has Alpha prefix - we recommend to stop Erigon for backup. 
+The chaindata is read in one long read transaction: the backup is consistent while Erigon keeps syncing
+(its database grows while the transaction is open).
+With --incremental, copies only the rows changed since the previous backup in --to.datadir,
+found from the stage progress recorded in its backup.json: the rows of the blocks since, and the rows of the state,
+history, trie and log/call index tables for the keys changed by these blocks, as found from their change sets, logs
+and call traces. The other tables (stage progress, sequences, bor tables...) are copied entirely.
+Use alpha_restore to check and restore a backup.
 Limitations: 
 - no support of datadir/snapshots folder. Recommendation: backup snapshots dir manually AFTER databases backup. Possible to implement in future.
 - no support of Consensus DB (copy it manually if you need). Possible to implement in future.
 - way to pipe output to compressor (lz4/zstd). Can compress target floder later or use zfs-with-enabled-compression.
 - jwt tocken: copy it manually - if need. 
 - no support of SentryDB (datadir/nodes folder). Because seems no much reason to backup it.
+- incremental backups need the change sets since the previous backup: take them more often than --prune.h.older blocks.
+- incremental backups keep the shards of the log and call indices pruned since the previous backup.
 
-Example: erigon backup --datadir=<your_datadir> --to.datadir=<backup_datadir>
+Example: erigon alpha_backup --datadir=<your_datadir> --to.datadir=<backup_datadir> --incremental
 
 TODO:
 - support of Consensus DB (copy it manually if you need). Possible to implement in future.
@@ -45,6 +56,24 @@ TODO:
 		&BackupLabelsFlag,
 		&BackupTablesFlag,
 		&WarmupThreadsFlag,
+		&BackupIncrementalFlag,
+	}, debug.Flags, logging.Flags),
+}
+
+var restoreCommand = cli.Command{
+	Name: "alpha_restore",
+	Description: `Alpha verison of command. Checks that the chaindata of a backup is consistent: stage progress
+matches the content of the tables and the backup.json of the backup. Then copies the databases of the backup
+to --datadir, which must not have them. Erigon must be stopped.
+
+Example: erigon alpha_restore --from.datadir=<backup_datadir> --datadir=<your_datadir>
+`,
+	Action: doRestore,
+	Flags: joinFlags([]cli.Flag{
+		&utils.DataDirFlag,
+		&FromDatadirFlag,
+		&RestoreCheckOnlyFlag,
+		&WarmupThreadsFlag,
 	}, debug.Flags, logging.Flags),
 }
 
@@ -54,6 +83,19 @@ var (
 		Usage:    "Target datadir",
 		Required: true,
 	}
+	FromDatadirFlag = flags.DirectoryFlag{
+		Name:     "from.datadir",
+		Usage:    "Datadir of the backup",
+		Required: true,
+	}
+	BackupIncrementalFlag = cli.BoolFlag{
+		Name:  "incremental",
+		Usage: "Copy only the rows of the chaindata changed since the previous backup in --to.datadir, if any",
+	}
+	RestoreCheckOnlyFlag = cli.BoolFlag{
+		Name:  "check.only",
+		Usage: "Check the backup without restoring it",
+	}
 	BackupLabelsFlag = cli.StringFlag{
 		Name:  "lables",
 		Usage: "Name of component to backup. Example: chaindata,txpool,downloader",
@@ -77,7 +119,12 @@ CloudDrives (and ssd) have bad-latency and good-parallel-throughput - then havin
 )
 
 func doBackup(cliCtx *cli.Context) error {
-	defer log.Info("backup done")
+	var logger log.Logger
+	var err error
+	if logger, err = debug.Setup(cliCtx, true /* rootLogger */); err != nil {
+		return err
+	}
+	defer logger.Info("backup done")
 
 	ctx := cliCtx.Context
 	dirs := datadir.New(cliCtx.String(utils.DataDirFlag.Name))
@@ -89,7 +136,7 @@ func doBackup(cliCtx *cli.Context) error {
 	}
 
 	var lables = []kv.Label{kv.ChainDB, kv.TxPoolDB, kv.DownloaderDB}
-	if cliCtx.IsSet(BackupToPageSizeFlag.Name) {
+	if cliCtx.IsSet(BackupLabelsFlag.Name) {
 		lables = lables[:0]
 		for _, l := range utils.SplitAndTrim(cliCtx.String(BackupLabelsFlag.Name)) {
 			lables = append(lables, kv.UnmarshalLabel(l))
@@ -109,23 +156,28 @@ func doBackup(cliCtx *cli.Context) error {
 	//kv.SentryDB no much reason to backup
 	//TODO: add support of kv.ConsensusDB
 	for _, label := range lables {
-		var from, to string
-		switch label {
-		case kv.ChainDB:
-			from, to = dirs.Chaindata, toDirs.Chaindata
-		case kv.TxPoolDB:
-			from, to = dirs.TxPool, toDirs.TxPool
-		case kv.DownloaderDB:
-			from, to = filepath.Join(dirs.Snap, "db"), filepath.Join(toDirs.Snap, "db")
-		default:
-			panic(fmt.Sprintf("unexpected: %+v", label))
-		}
-
+		from, to := labelDirs(label, dirs, toDirs)
 		if !dir.Exist(from) {
 			continue
 		}
 
-		if len(tables) == 0 { // if not partial backup - just drop target dir, to make backup more compact/fast (instead of clean tables)
+		var prev *backup.Marker
+		if label == kv.ChainDB {
+			if cliCtx.Bool(BackupIncrementalFlag.Name) && len(tables) == 0 && dir.Exist(to) {
+				if prev, err = backup.ReadMarker(toDirs.DataDir); err != nil {
+					return err
+				}
+			}
+			if prev == nil && cliCtx.Bool(BackupIncrementalFlag.Name) {
+				logger.Info("[backup] no previous backup, taking a full one")
+			}
+			// the content changes, the marker will be written again if the backup completes
+			if err := backup.RemoveMarker(toDirs.DataDir); err != nil {
+				return err
+			}
+		}
+
+		if len(tables) == 0 && prev == nil { // if not partial backup - just drop target dir, to make backup more compact/fast (instead of clean tables)
 			if err := os.RemoveAll(to); err != nil {
 				return fmt.Errorf("mkdir: %w, %s", err, to)
 			}
@@ -133,12 +185,123 @@ func doBackup(cliCtx *cli.Context) error {
 		if err := os.MkdirAll(to, 0740); err != nil { //owner: rw, group: r, others: -
 			return fmt.Errorf("mkdir: %w, %s", err, to)
 		}
-		log.Info("[backup] start", "label", label)
-		fromDB, toDB := backup.OpenPair(from, to, label, targetPageSize)
-		if err := backup.Kv2kv(ctx, fromDB, toDB, nil, readAheadThreads); err != nil {
+		logger.Info("[backup] start", "label", label)
+		if err := func() error {
+			fromDB, toDB := backup.OpenPair(from, to, label, targetPageSize)
+			defer fromDB.Close()
+			defer toDB.Close()
+			if label != kv.ChainDB || len(tables) > 0 {
+				return backup.Kv2kv(ctx, fromDB, toDB, tables, readAheadThreads)
+			}
+
+			marker, err := backup.Chaindata(ctx, fromDB, toDB, prev, dirs.Tmp, readAheadThreads, logger)
+			if err != nil {
+				if errors.Is(err, backup.ErrFullBackupRequired) {
+					return fmt.Errorf("%w, run without --%s", err, BackupIncrementalFlag.Name)
+				}
+				return err
+			}
+			if err := checkChaindata(ctx, toDB, marker, logger); err != nil {
+				return err
+			}
+			return backup.WriteMarker(toDirs.DataDir, marker)
+		}(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func doRestore(cliCtx *cli.Context) error {
+	var logger log.Logger
+	var err error
+	if logger, err = debug.Setup(cliCtx, true /* rootLogger */); err != nil {
+		return err
+	}
+
+	ctx := cliCtx.Context
+	fromDirs := datadir.New(cliCtx.String(FromDatadirFlag.Name))
+	dirs := datadir.New(cliCtx.String(utils.DataDirFlag.Name))
+
+	if !dir.Exist(fromDirs.Chaindata) {
+		return fmt.Errorf("no chaindata in %s", fromDirs.DataDir)
+	}
+	marker, err := backup.ReadMarker(fromDirs.DataDir)
+	if err != nil {
+		return err
+	}
+	if marker == nil {
+		logger.Warn("[restore] no backup.json in the backup, only checking the stage progress against the tables")
+	}
+	if err := func() error {
+		db := mdbx.NewMDBX(logger).Path(fromDirs.Chaindata).Readonly().MustOpen()
+		defer db.Close()
+		return checkChaindata(ctx, db, marker, logger)
+	}(); err != nil {
+		return err
+	}
+	logger.Info("[restore] backup is consistent")
+	if cliCtx.Bool(RestoreCheckOnlyFlag.Name) {
+		return nil
+	}
+
+	readAheadThreads := backup.ReadAheadThreads
+	if cliCtx.IsSet(WarmupThreadsFlag.Name) {
+		readAheadThreads = int(cliCtx.Uint64(WarmupThreadsFlag.Name))
+	}
+	for _, label := range []kv.Label{kv.ChainDB, kv.TxPoolDB, kv.DownloaderDB} {
+		from, to := labelDirs(label, fromDirs, dirs)
+		if !dir.Exist(from) {
+			continue
+		}
+		if dir.Exist(filepath.Join(to, "mdbx.dat")) {
+			return fmt.Errorf("%s already exists, move it away to restore the backup", to)
+		}
+		if err := os.MkdirAll(to, 0740); err != nil { //owner: rw, group: r, others: -
+			return fmt.Errorf("mkdir: %w, %s", err, to)
+		}
+		logger.Info("[restore] start", "label", label)
+		if err := func() error {
+			fromDB, toDB := backup.OpenPair(from, to, label, 0)
+			defer fromDB.Close()
+			defer toDB.Close()
+			return backup.Kv2kv(ctx, fromDB, toDB, nil, readAheadThreads)
+		}(); err != nil {
 			return err
 		}
 	}
+	logger.Info("[restore] done", "datadir", dirs.DataDir)
+	return nil
+}
+
+func labelDirs(label kv.Label, from, to datadir.Dirs) (string, string) {
+	switch label {
+	case kv.ChainDB:
+		return from.Chaindata, to.Chaindata
+	case kv.TxPoolDB:
+		return from.TxPool, to.TxPool
+	case kv.DownloaderDB:
+		return filepath.Join(from.Snap, "db"), filepath.Join(to.Snap, "db")
+	default:
+		panic(fmt.Sprintf("unexpected: %+v", label))
+	}
+}
 
+// checkChaindata prints the problems of the chaindata, and fails if there are any.
+func checkChaindata(ctx context.Context, db kv.RoDB, marker *backup.Marker, logger log.Logger) error {
+	var problems []string
+	if err := db.View(ctx, func(tx kv.Tx) (err error) {
+		problems, err = backup.CheckChaindata(tx, marker)
+		return err
+	}); err != nil {
+		return err
+	}
+	for _, p := range problems {
+		logger.Error("[backup] inconsistent chaindata", "problem", p)
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("chaindata is inconsistent: %d problems", len(problems))
+	}
 	return nil
 }
@@ -53,7 +53,10 @@ func MakeApp(name string, action cli.ActionFunc, cliFlags []cli.Flag) *cli.App {
 		&importCommand,
 		&snapshotCommand,
 		&supportCommand,
-		//&backupCommand,
+		&backupCommand,
+		&restoreCommand,
+		&pruneCommand,
+		&replicaCommand,
 	}
 	return app
 }
@@ -0,0 +1,249 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/c2h5oh/datasize"
+	"github.com/gofrs/flock"
+	"github.com/ledgerwatch/erigon-lib/common/datadir"
+	"github.com/ledgerwatch/erigon-lib/common/dir"
+	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/ledgerwatch/erigon-lib/kv/kvcfg"
+	"github.com/ledgerwatch/erigon-lib/kv/mdbx"
+	"github.com/ledgerwatch/log/v3"
+	"github.com/urfave/cli/v2"
+
+	"github.com/ledgerwatch/erigon/cmd/hack/tool/fromdb"
+	"github.com/ledgerwatch/erigon/cmd/utils"
+	"github.com/ledgerwatch/erigon/eth/ethconfig"
+	"github.com/ledgerwatch/erigon/eth/stagedsync"
+	"github.com/ledgerwatch/erigon/eth/stagedsync/stages"
+	"github.com/ledgerwatch/erigon/ethdb/prune"
+	"github.com/ledgerwatch/erigon/turbo/backup"
+	cli2 "github.com/ledgerwatch/erigon/turbo/cli"
+	"github.com/ledgerwatch/erigon/turbo/debug"
+	"github.com/ledgerwatch/erigon/turbo/logging"
+)
+
+var pruneCommand = cli.Command{
+	Name: "prune",
+	Description: `Applies a stricter prune mode to the chaindata of a stopped Erigon: Erigon refuses to start
+with a --prune different from the one of its db. Give the whole new mode, as to Erigon: it must prune at least
+the data already pruned (for example --prune=hrtc, or --prune.h.older=10000 after --prune=h).
+The stages prune their data in transactions of --prune.batch blocks, then the chaindata is copied to a new file
+to give the free space back to the filesystem (--compact=false to skip it). The copy needs space for the
+pruned chaindata.
+The new mode is saved first: if interrupted, Erigon finishes the pruning at start, run the command again to compact.
+
+Example: erigon prune --datadir=<your_datadir> --prune=hrtc
+`,
+	Action: doPrune,
+	Flags: joinFlags([]cli.Flag{
+		&utils.DataDirFlag,
+		&cli2.PruneFlag,
+		&cli2.PruneHistoryFlag,
+		&cli2.PruneReceiptFlag,
+		&cli2.PruneTxIndexFlag,
+		&cli2.PruneCallTracesFlag,
+		&cli2.PruneHistoryBeforeFlag,
+		&cli2.PruneReceiptBeforeFlag,
+		&cli2.PruneTxIndexBeforeFlag,
+		&cli2.PruneCallTracesBeforeFlag,
+		&PruneBatchFlag,
+		&PruneCompactFlag,
+		&WarmupThreadsFlag,
+	}, debug.Flags, logging.Flags),
+}
+
+var (
+	PruneBatchFlag = cli.Uint64Flag{
+		Name:  "prune.batch",
+		Usage: "Number of blocks pruned in one db transaction",
+		Value: 100_000,
+	}
+	PruneCompactFlag = cli.BoolFlag{
+		Name:  "compact",
+		Usage: "Copy the pruned chaindata to a new file to reclaim the disk space",
+		Value: true,
+	}
+)
+
+func doPrune(cliCtx *cli.Context) error {
+	var logger log.Logger
+	var err error
+	if logger, err = debug.Setup(cliCtx, true /* rootLogger */); err != nil {
+		return err
+	}
+
+	ctx := cliCtx.Context
+	dirs := datadir.New(cliCtx.String(utils.DataDirFlag.Name))
+	if !dir.Exist(dirs.Chaindata) {
+		return fmt.Errorf("no chaindata in %s", dirs.DataDir)
+	}
+	// the lock of the datadir is held by a running Erigon
+	lock := flock.New(filepath.Join(dirs.DataDir, "LOCK"))
+	locked, err := lock.TryLock()
+	if err != nil {
+		return err
+	}
+	if !locked {
+		return fmt.Errorf("datadir %s is used by a running Erigon, stop it first", dirs.DataDir)
+	}
+	defer lock.Unlock()
+
+	if err := pruneChaindata(ctx, cliCtx, dirs, logger); err != nil {
+		return err
+	}
+	if !cliCtx.Bool(PruneCompactFlag.Name) {
+		return nil
+	}
+	readAheadThreads := backup.ReadAheadThreads
+	if cliCtx.IsSet(WarmupThreadsFlag.Name) {
+		readAheadThreads = int(cliCtx.Uint64(WarmupThreadsFlag.Name))
+	}
+	return compactChaindata(ctx, dirs, readAheadThreads, logger)
+}
+
+// pruneStages are the stages whose progress bounds the blocks of the data pruned by pruneChaindata
+var pruneStages = []stages.SyncStage{stages.Execution, stages.AccountHistoryIndex, stages.StorageHistoryIndex, stages.LogIndex, stages.CallTraces, stages.TxLookup}
+
+func pruneChaindata(ctx context.Context, cliCtx *cli.Context, dirs datadir.Dirs, logger log.Logger) error {
+	db := mdbx.NewMDBX(logger).Label(kv.ChainDB).Path(dirs.Chaindata).MustOpen()
+	defer db.Close()
+	if kvcfg.HistoryV3.FromDB(db) {
+		return errors.New("history v3 is pruned by Erigon with its snapshots, not supported")
+	}
+
+	chainConfig := fromdb.ChainConfig(db)
+	pm, err := prune.FromCli(chainConfig.ChainID.Uint64(),
+		cliCtx.String(cli2.PruneFlag.Name),
+		cliCtx.Uint64(cli2.PruneHistoryFlag.Name),
+		cliCtx.Uint64(cli2.PruneReceiptFlag.Name),
+		cliCtx.Uint64(cli2.PruneTxIndexFlag.Name),
+		cliCtx.Uint64(cli2.PruneCallTracesFlag.Name),
+		cliCtx.Uint64(cli2.PruneHistoryBeforeFlag.Name),
+		cliCtx.Uint64(cli2.PruneReceiptBeforeFlag.Name),
+		cliCtx.Uint64(cli2.PruneTxIndexBeforeFlag.Name),
+		cliCtx.Uint64(cli2.PruneCallTracesBeforeFlag.Name),
+		nil,
+	)
+	if err != nil {
+		return err
+	}
+
+	var old prune.Mode
+	var execution, head uint64
+	if err := db.View(ctx, func(tx kv.Tx) error {
+		if old, err = prune.Get(tx); err != nil {
+			return err
+		}
+		for i, stage := range pruneStages {
+			progress, err := stages.GetStageProgress(tx, stage)
+			if err != nil {
+				return err
+			}
+			if i == 0 {
+				execution, head = progress, progress
+			}
+			if progress < head {
+				head = progress
+			}
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+	if err := prune.EnsureStricter(old, pm, execution); err != nil {
+		return err
+	}
+	logger.Info("[prune] start", "from", old.String(), "to", pm.String())
+	if err := db.Update(ctx, func(tx kv.RwTx) error { return prune.Override(tx, pm) }); err != nil {
+		return err
+	}
+
+	batch := cliCtx.Uint64(PruneBatchFlag.Name)
+	if batch == 0 {
+		return fmt.Errorf("--%s must be positive", PruneBatchFlag.Name)
+	}
+	for to := batch; ; to += batch {
+		mode, last := batchPruneMode(pm, to, head)
+		sync := stagedsync.New(
+			stagedsync.PruneStages(ctx,
+				stagedsync.StageExecuteBlocksCfg(db, mode, 0, nil, chainConfig, nil, nil, nil, false, false, false, dirs, nil, nil, nil, ethconfig.Defaults.Sync, nil),
+				stagedsync.StageHistoryCfg(db, mode, dirs.Tmp),
+				stagedsync.StageLogIndexCfg(db, mode, dirs.Tmp),
+				stagedsync.StageCallTracesCfg(db, mode, 0, dirs.Tmp),
+				stagedsync.StageTxLookupCfg(db, mode, dirs.Tmp, nil, chainConfig.Bor),
+			),
+			nil,
+			stagedsync.PruneStagesOrder,
+			logger,
+		)
+		if err := db.Update(ctx, func(tx kv.RwTx) error { return sync.RunPrune(db, tx, true /* firstCycle */) }); err != nil {
+			return err
+		}
+		if last {
+			break
+		}
+		logger.Info("[prune] progress", "block", to, "head", head)
+	}
+	logger.Info("[prune] done", "mode", pm.String())
+	return nil
+}
+
+// batchPruneMode is the mode pruning the data of the blocks before `to` at most. last is true if it's pm,
+// head is the lowest progress of the stages.
+func batchPruneMode(pm prune.Mode, to, head uint64) (mode prune.Mode, last bool) {
+	mode, last = pm, true
+	for _, amount := range []*prune.BlockAmount{&mode.History, &mode.Receipts, &mode.TxIndex, &mode.CallTraces} {
+		if (*amount).Enabled() && to < (*amount).PruneTo(head) {
+			*amount, last = prune.Before(to+1), false
+		}
+	}
+	return mode, last
+}
+
+// compactChaindata copies the chaindata to a new file, without the free pages, and replaces it.
+func compactChaindata(ctx context.Context, dirs datadir.Dirs, readAheadThreads int, logger log.Logger) error {
+	compacted, old := dirs.Chaindata+".compact", dirs.Chaindata+".old"
+	if err := os.RemoveAll(compacted); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(compacted, 0740); err != nil { //owner: rw, group: r, others: -
+		return fmt.Errorf("mkdir: %w, %s", err, compacted)
+	}
+	logger.Info("[prune] compacting", "to", compacted)
+	if err := func() error {
+		fromDB, toDB := backup.OpenPair(dirs.Chaindata, compacted, kv.ChainDB, 0)
+		defer fromDB.Close()
+		defer toDB.Close()
+		return backup.Kv2kv(ctx, fromDB, toDB, nil, readAheadThreads)
+	}(); err != nil {
+		return err
+	}
+
+	before, after := dbFileSize(dirs.Chaindata), dbFileSize(compacted)
+	if err := os.Rename(dirs.Chaindata, old); err != nil {
+		return err
+	}
+	if err := os.Rename(compacted, dirs.Chaindata); err != nil {
+		return fmt.Errorf("%w, move %s back to %s", err, old, dirs.Chaindata)
+	}
+	if err := os.RemoveAll(old); err != nil {
+		return err
+	}
+	logger.Info("[prune] compacted", "before", before, "after", after)
+	return nil
+}
+
+func dbFileSize(path string) datasize.ByteSize {
+	info, err := os.Stat(filepath.Join(path, "mdbx.dat"))
+	if err != nil {
+		return 0
+	}
+	return datasize.ByteSize(info.Size())
+}
@@ -0,0 +1,144 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+
+	"github.com/gofrs/flock"
+	"github.com/ledgerwatch/erigon-lib/common/datadir"
+	"github.com/ledgerwatch/erigon-lib/common/dir"
+	"github.com/ledgerwatch/erigon-lib/gointerfaces"
+	"github.com/ledgerwatch/erigon-lib/gointerfaces/grpcutil"
+	"github.com/ledgerwatch/erigon-lib/gointerfaces/remote"
+	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/ledgerwatch/erigon-lib/kv/kvcfg"
+	"github.com/ledgerwatch/erigon-lib/kv/mdbx"
+	"github.com/ledgerwatch/erigon-lib/kv/remotedb"
+	"github.com/ledgerwatch/erigon-lib/kv/remotedbserver"
+	"github.com/ledgerwatch/log/v3"
+	"github.com/urfave/cli/v2"
+
+	"github.com/ledgerwatch/erigon/cmd/hack/tool/fromdb"
+	"github.com/ledgerwatch/erigon/cmd/utils"
+	"github.com/ledgerwatch/erigon/eth/ethconfig"
+	"github.com/ledgerwatch/erigon/eth/stagedsync"
+	"github.com/ledgerwatch/erigon/turbo/debug"
+	"github.com/ledgerwatch/erigon/turbo/logging"
+	"github.com/ledgerwatch/erigon/turbo/replica"
+	"github.com/ledgerwatch/erigon/turbo/snapshotsync"
+	"github.com/ledgerwatch/erigon/turbo/snapshotsync/snap"
+)
+
+var replicaCommand = cli.Command{
+	Name: "replica",
+	Description: `Keeps the chaindata of the datadir following the one of a primary Erigon, for an rpcdaemon
+reading it locally: rpcdaemon --datadir=<replica_datadir> --private.api.addr=<primary_private_api>.
+The blocks executed by the primary are read from its state change stream and its private API, the replica
+runs the stages after Execution itself (hashed state, trie, indices). When the stream misses blocks or has
+unwinds, the replica copies the changes of the blocks from the db of the primary.
+Bootstrap the datadir with a backup of the primary (erigon alpha_backup) and a copy of its snapshots. The
+blocks the primary moves to new snapshots are kept in the chaindata of the replica. If the replica falls
+behind the pruning of the primary, it stops: bootstrap it again.
+History v3 is not supported.
+
+Example: erigon replica --datadir=<replica_datadir> --replica.primary=<primary_private_api>
+`,
+	Action: doReplica,
+	Flags: joinFlags([]cli.Flag{
+		&utils.DataDirFlag,
+		&ReplicaPrimaryFlag,
+		&utils.TLSCertFlag,
+		&utils.TLSKeyFlag,
+		&utils.TLSCACertFlag,
+	}, debug.Flags, logging.Flags),
+}
+
+var ReplicaPrimaryFlag = cli.StringFlag{
+	Name:  "replica.primary",
+	Usage: "Private API network address of the primary Erigon, for example: 127.0.0.1:9090",
+	Value: "127.0.0.1:9090",
+}
+
+func doReplica(cliCtx *cli.Context) error {
+	var logger log.Logger
+	var err error
+	if logger, err = debug.Setup(cliCtx, true /* rootLogger */); err != nil {
+		return err
+	}
+
+	ctx := cliCtx.Context
+	dirs := datadir.New(cliCtx.String(utils.DataDirFlag.Name))
+	if !dir.Exist(dirs.Chaindata) {
+		return fmt.Errorf("no chaindata in %s, restore a backup of the primary first", dirs.DataDir)
+	}
+	lock := flock.New(filepath.Join(dirs.DataDir, "LOCK"))
+	locked, err := lock.TryLock()
+	if err != nil {
+		return err
+	}
+	if !locked {
+		return fmt.Errorf("datadir %s is used by a running Erigon", dirs.DataDir)
+	}
+	defer lock.Unlock()
+
+	db := mdbx.NewMDBX(logger).Label(kv.ChainDB).Path(dirs.Chaindata).MustOpen()
+	defer db.Close()
+	if kvcfg.HistoryV3.FromDB(db) {
+		return errors.New("history v3 is not supported")
+	}
+	if kvcfg.TransactionsV3.FromDB(db) {
+		return errors.New("transactions v3 is not supported")
+	}
+
+	creds, err := grpcutil.TLS(cliCtx.String(utils.TLSCACertFlag.Name), cliCtx.String(utils.TLSCertFlag.Name), cliCtx.String(utils.TLSKeyFlag.Name))
+	if err != nil {
+		return fmt.Errorf("open tls cert: %w", err)
+	}
+	conn, err := grpcutil.Connect(creds, cliCtx.String(ReplicaPrimaryFlag.Name))
+	if err != nil {
+		return fmt.Errorf("could not connect to the primary: %w", err)
+	}
+	defer conn.Close()
+	kvClient := remote.NewKVClient(conn)
+	primary, err := remotedb.NewRemote(gointerfaces.VersionFromProto(remotedbserver.KvServiceAPIVersion), logger, kvClient).Open()
+	if err != nil {
+		return fmt.Errorf("could not connect to the db of the primary: %w", err)
+	}
+	defer primary.Close()
+
+	var useSnapshots bool
+	if err := db.View(ctx, func(tx kv.Tx) error {
+		useSnapshots, err = snap.Enabled(tx)
+		return err
+	}); err != nil {
+		return err
+	}
+	allSnapshots := snapshotsync.NewRoSnapshots(ethconfig.NewSnapCfg(useSnapshots, true, false), dirs.Snap, logger)
+	if useSnapshots {
+		if err := allSnapshots.ReopenFolder(); err != nil {
+			return err
+		}
+	}
+	defer allSnapshots.Close()
+	blockReader := snapshotsync.NewBlockReaderWithSnapshots(allSnapshots, false /* transactionsV3 */)
+
+	chainConfig, pm := fromdb.ChainConfig(db), fromdb.PruneMode(db)
+	sync := stagedsync.New(
+		stagedsync.ReplicaStages(ctx,
+			stagedsync.StageExecuteBlocksCfg(db, pm, 0, nil, chainConfig, nil, nil, nil, false, false, false, dirs, blockReader, nil, nil, ethconfig.Defaults.Sync, nil),
+			stagedsync.StageHashStateCfg(db, dirs, false /* historyV3 */, nil),
+			stagedsync.StageTrieCfg(db, true /* checkRoot */, true /* saveNewHashesToDB */, true /* badBlockHalt */, dirs.Tmp, blockReader, nil, false /* historyV3 */, nil),
+			stagedsync.StageHistoryCfg(db, pm, dirs.Tmp),
+			stagedsync.StageLogIndexCfg(db, pm, dirs.Tmp),
+			stagedsync.StageCallTracesCfg(db, pm, 0, dirs.Tmp),
+			stagedsync.StageTxLookupCfg(db, pm, dirs.Tmp, allSnapshots, chainConfig.Bor),
+			stagedsync.StageFinishCfg(db, dirs.Tmp, nil),
+		),
+		stagedsync.ReplicaUnwindOrder,
+		stagedsync.ReplicaPruneOrder,
+		logger,
+	)
+	logger.Info("[replica] following", "primary", cliCtx.String(ReplicaPrimaryFlag.Name), "prune", pm.String())
+	return replica.New(db, primary, kvClient, sync, dirs.Tmp, logger).Run(ctx)
+}
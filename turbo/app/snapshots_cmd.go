@@ -2,10 +2,12 @@ package app
 
 import (
 	"bufio"
+	"context"
 	"encoding/binary"
 	"errors"
 	"fmt"
 	"io"
+	"net/http"
 	"os"
 	"path/filepath"
 	"runtime"
@@ -33,6 +35,8 @@ import (
 	"github.com/ledgerwatch/erigon/turbo/debug"
 	"github.com/ledgerwatch/erigon/turbo/logging"
 	"github.com/ledgerwatch/erigon/turbo/snapshotsync"
+	"github.com/ledgerwatch/erigon/turbo/snapshotsync/httpdownloader"
+	"github.com/ledgerwatch/erigon/turbo/snapshotsync/snapcfg"
 	"github.com/ledgerwatch/log/v3"
 	"github.com/urfave/cli/v2"
 )
@@ -58,6 +62,24 @@ var snapshotCommand = cli.Command{
 				&SnapshotRebuildFlag,
 			}, debug.Flags, logging.Flags),
 		},
+		{
+			Name:   "verify",
+			Action: doVerifyCommand,
+			Usage:  "Check the block segments: torrent hashes, decoding of all records, consistency with indices. With --heal, quarantine corrupted segments and remove wrong indices (erigon must be stopped)",
+			Flags: joinFlags([]cli.Flag{
+				&utils.DataDirFlag,
+				&SnapshotHealFlag,
+			}, debug.Flags, logging.Flags),
+		},
+		{
+			Name:   "serve",
+			Action: doServeCommand,
+			Usage:  "Publish the snapshots of the node over HTTP, to be used by other nodes with --downloader.http.mirror",
+			Flags: joinFlags([]cli.Flag{
+				&utils.DataDirFlag,
+				&SnapshotServeAddrFlag,
+			}, debug.Flags, logging.Flags),
+		},
 		{
 			Name:   "retire",
 			Action: doRetireCommand,
@@ -118,6 +140,15 @@ var (
 		Name:  "rebuild",
 		Usage: "Force rebuild",
 	}
+	SnapshotHealFlag = cli.BoolFlag{
+		Name:  "heal",
+		Usage: "Move corrupted segments to the quarantine dir, to download them again on next start, and remove the indices not matching their segments, to build them again",
+	}
+	SnapshotServeAddrFlag = cli.StringFlag{
+		Name:  "serve.addr",
+		Usage: "Network address to serve the snapshots on, put it behind a reverse proxy to use HTTPS",
+		Value: "127.0.0.1:8088",
+	}
 )
 
 func preloadFileAsync(name string) {
@@ -244,6 +275,72 @@ func doIndicesCommand(cliCtx *cli.Context) error {
 	return nil
 }
 
+func doVerifyCommand(cliCtx *cli.Context) error {
+	var err error
+	var logger log.Logger
+	if logger, err = debug.Setup(cliCtx, true /* rootLogger */); err != nil {
+		return err
+	}
+	ctx := cliCtx.Context
+
+	dirs := datadir.New(cliCtx.String(utils.DataDirFlag.Name))
+	chainDB := mdbx.NewMDBX(logger).Path(dirs.Chaindata).Readonly().MustOpen()
+	chainConfig := fromdb.ChainConfig(chainDB)
+	chainDB.Close()
+	chainID, _ := uint256.FromBig(chainConfig.ChainID)
+	preverified := snapcfg.KnownCfg(chainConfig.ChainName, nil, nil).Preverified
+
+	problems, err := snapshotsync.VerifySegments(ctx, dirs.Snap, preverified, *chainID, estimate.IndexSnapshot.Workers(), logger)
+	if err != nil {
+		return err
+	}
+	for _, p := range problems {
+		fmt.Printf("%s: %v\n", p.File, p.Err)
+	}
+	if len(problems) == 0 {
+		logger.Info("[snapshots] All segments are fine")
+		return nil
+	}
+	if !cliCtx.Bool(SnapshotHealFlag.Name) {
+		return fmt.Errorf("%d corrupted segments, run with --%s to fix them", len(problems), SnapshotHealFlag.Name)
+	}
+	quarantined, err := snapshotsync.HealSegments(dirs.Snap, problems)
+	if err != nil {
+		return err
+	}
+	logger.Info("[snapshots] Healed", "quarantined", len(quarantined), "indices_removed", len(problems)-len(quarantined),
+		"quarantine", filepath.Join(dirs.Snap, snapshotsync.QuarantineDir))
+	return nil
+}
+
+func doServeCommand(cliCtx *cli.Context) error {
+	var err error
+	var logger log.Logger
+	if logger, err = debug.Setup(cliCtx, true /* rootLogger */); err != nil {
+		return err
+	}
+	ctx := cliCtx.Context
+
+	dirs := datadir.New(cliCtx.String(utils.DataDirFlag.Name))
+	addr := cliCtx.String(SnapshotServeAddrFlag.Name)
+	srv := &http.Server{
+		Addr:              addr,
+		Handler:           httpdownloader.NewServer(dirs.Snap),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		_ = srv.Shutdown(shutdownCtx)
+	}()
+	logger.Info("[snapshots] Serving", "addr", addr, "dir", dirs.Snap)
+	if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
 func doUncompress(cliCtx *cli.Context) error {
 	var logger log.Logger
 	var err error
@@ -54,11 +54,6 @@ func Kv2kv(ctx context.Context, src kv.RoDB, dst kv.RwDB, tables []string, readA
 	}
 	defer srcTx.Rollback()
 
-	commitEvery := time.NewTicker(5 * time.Minute)
-	defer commitEvery.Stop()
-	logEvery := time.NewTicker(20 * time.Second)
-	defer logEvery.Stop()
-
 	tablesMap := src.AllTables()
 	if len(tables) > 0 {
 		tablesMapCopy := maps.Clone(tablesMap)
@@ -68,15 +63,29 @@ func Kv2kv(ctx context.Context, src kv.RoDB, dst kv.RwDB, tables []string, readA
 		}
 	}
 
+	names := make([]string, 0, len(tablesMap))
 	for name, b := range tablesMap {
 		if b.IsDeprecated {
 			continue
 		}
+		names = append(names, name)
+	}
+	if err := copyTables(ctx, src, srcTx, dst, names, readAheadThreads); err != nil {
+		return err
+	}
+	log.Info("done")
+	return nil
+}
+
+// copyTables replaces the tables of dst with those read from srcTx.
+func copyTables(ctx context.Context, src kv.RoDB, srcTx kv.Tx, dst kv.RwDB, tables []string, readAheadThreads int) error {
+	logEvery := time.NewTicker(20 * time.Second)
+	defer logEvery.Stop()
+	for _, name := range tables {
 		if err := backupTable(ctx, src, srcTx, dst, name, readAheadThreads, logEvery); err != nil {
 			return err
 		}
 	}
-	log.Info("done")
 	return nil
 }
 
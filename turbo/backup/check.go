@@ -0,0 +1,145 @@
+package backup
+
+import (
+	"fmt"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/common/hexutility"
+	"github.com/ledgerwatch/erigon-lib/kv"
+
+	"github.com/ledgerwatch/erigon/common/dbutils"
+	"github.com/ledgerwatch/erigon/core/rawdb"
+	"github.com/ledgerwatch/erigon/eth/stagedsync/stages"
+)
+
+// stageOrder lists stages with the one whose progress they can't exceed.
+var stageOrder = [][2]stages.SyncStage{
+	{stages.Bodies, stages.Headers},
+	{stages.Senders, stages.Bodies},
+	{stages.Execution, stages.Senders},
+	{stages.HashState, stages.Execution},
+	{stages.IntermediateHashes, stages.HashState},
+	{stages.AccountHistoryIndex, stages.Execution},
+	{stages.StorageHistoryIndex, stages.Execution},
+	{stages.LogIndex, stages.Execution},
+	{stages.CallTraces, stages.Execution},
+	{stages.TxLookup, stages.Bodies},
+}
+
+// CheckChaindata checks that the content of the chain db matches the progress of its stages,
+// and the marker of the backup if not nil, before starting Erigon on a restored db. It
+// returns the problems found.
+func CheckChaindata(tx kv.Tx, marker *Marker) (problems []string, err error) {
+	progress := map[stages.SyncStage]uint64{}
+	for _, stage := range stages.AllStages {
+		if progress[stage], err = stages.GetStageProgress(tx, stage); err != nil {
+			return nil, err
+		}
+	}
+	if marker != nil {
+		for stage, want := range marker.Stages {
+			if have := progress[stages.SyncStage(stage)]; have != want {
+				problems = append(problems, fmt.Sprintf("stage %s is at block %d, the backup was taken at %d: the backup was interrupted", stage, have, want))
+			}
+		}
+	}
+	for _, pair := range stageOrder {
+		if progress[pair[0]] > progress[pair[1]] {
+			problems = append(problems, fmt.Sprintf("stage %s is at block %d, after stage %s at %d", pair[0], progress[pair[0]], pair[1], progress[pair[1]]))
+		}
+	}
+
+	// the blocks of snapshots are not in the db
+	frozen := progress[stages.Snapshots]
+	header := func(stage stages.SyncStage) (libcommon.Hash, bool, error) {
+		block := progress[stage]
+		if block <= frozen {
+			return libcommon.Hash{}, false, nil
+		}
+		hash, err := rawdb.ReadCanonicalHash(tx, block)
+		if err != nil {
+			return hash, false, err
+		}
+		if hash == (libcommon.Hash{}) || !rawdb.HasHeader(tx, hash, block) {
+			problems = append(problems, fmt.Sprintf("stage %s is at block %d, which has no canonical header", stage, block))
+			return hash, false, nil
+		}
+		return hash, true, nil
+	}
+	if _, _, err := header(stages.Headers); err != nil {
+		return nil, err
+	}
+	if hash, ok, err := header(stages.Bodies); err != nil {
+		return nil, err
+	} else if ok {
+		block := progress[stages.Bodies]
+		body, err := rawdb.ReadBodyForStorageByKey(tx, dbutils.BlockBodyKey(block, hash))
+		if err != nil {
+			return nil, err
+		}
+		if body == nil {
+			problems = append(problems, fmt.Sprintf("stage %s is at block %d, which has no body", stages.Bodies, block))
+		} else if body.TxAmount > 2 { // without the system txs
+			for _, id := range []uint64{body.BaseTxId + 1, body.BaseTxId + uint64(body.TxAmount) - 2} {
+				if has, err := tx.Has(kv.EthTx, hexutility.EncodeTs(id)); err != nil {
+					return nil, err
+				} else if !has {
+					problems = append(problems, fmt.Sprintf("stage %s is at block %d, whose transaction %d is missing", stages.Bodies, block, id))
+				}
+			}
+		}
+	}
+	if hash, ok, err := header(stages.Senders); err != nil {
+		return nil, err
+	} else if ok {
+		block := progress[stages.Senders]
+		body, err := rawdb.ReadBodyForStorageByKey(tx, dbutils.BlockBodyKey(block, hash))
+		if err != nil {
+			return nil, err
+		}
+		if body != nil && body.TxAmount > 2 {
+			if has, err := tx.Has(kv.Senders, dbutils.BlockBodyKey(block, hash)); err != nil {
+				return nil, err
+			} else if !has {
+				problems = append(problems, fmt.Sprintf("stage %s is at block %d, which has no senders", stages.Senders, block))
+			}
+		}
+	}
+
+	// the execution writes the rows of the blocks up to its progress only
+	execution := progress[stages.Execution]
+	for _, table := range []string{kv.AccountChangeSet, kv.StorageChangeSet, kv.Receipts, kv.Log, kv.CallTraceSet} {
+		c, err := tx.Cursor(table)
+		if err != nil {
+			return nil, err
+		}
+		k, _, err := c.Seek(hexutility.EncodeTs(execution + 1))
+		c.Close()
+		if err != nil {
+			return nil, err
+		}
+		if k != nil {
+			problems = append(problems, fmt.Sprintf("%s has rows of blocks after the progress of stage %s at %d", table, stages.Execution, execution))
+		}
+	}
+	if execution > 0 {
+		for _, table := range []string{kv.PlainState, kv.HashedAccounts} {
+			if table == kv.HashedAccounts && progress[stages.HashState] == 0 {
+				continue
+			}
+			c, err := tx.Cursor(table)
+			if err != nil {
+				return nil, err
+			}
+			k, _, err := c.First()
+			c.Close()
+			if err != nil {
+				return nil, err
+			}
+			if k == nil {
+				problems = append(problems, fmt.Sprintf("%s is empty", table))
+			}
+		}
+	}
+	return problems, nil
+}
@@ -0,0 +1,707 @@
+package backup
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/common/hexutility"
+	"github.com/ledgerwatch/erigon-lib/common/length"
+	"github.com/ledgerwatch/erigon-lib/etl"
+	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/ledgerwatch/erigon-lib/kv/temporal/historyv2"
+	"github.com/ledgerwatch/log/v3"
+	"golang.org/x/exp/slices"
+
+	"github.com/ledgerwatch/erigon/common"
+	"github.com/ledgerwatch/erigon/common/dbutils"
+	"github.com/ledgerwatch/erigon/common/hexutil"
+	"github.com/ledgerwatch/erigon/core/rawdb"
+	"github.com/ledgerwatch/erigon/core/types"
+	"github.com/ledgerwatch/erigon/eth/stagedsync/stages"
+	"github.com/ledgerwatch/erigon/ethdb/cbor"
+)
+
+// MarkerFile is the file of the backup datadir recording the progress of the chain db when
+// the backup was taken, to take the next backups incrementally and to check restores.
+const MarkerFile = "backup.json"
+
+var ErrFullBackupRequired = errors.New("full backup required")
+
+// Marker records the progress of the stages of a backed up chain db.
+type Marker struct {
+	Time time.Time `json:"time"`
+	// Block is the lowest progress of the stages writing the tables copied incrementally: the
+	// rows of the blocks after it are copied again by the next incremental backup
+	Block uint64         `json:"block"`
+	Hash  libcommon.Hash `json:"hash"` // canonical hash of Block, to detect unwinds below it
+	TxID  uint64         `json:"txId"` // first id of the transactions of the blocks after Block
+	// Stages is the progress of all the stages, which the restored db must match
+	Stages map[string]uint64 `json:"stages"`
+}
+
+// incrementalStages are the stages writing the tables copied incrementally, whose progress
+// bounds the rows copied. The index stages lag the execution: the rows they write later for
+// the blocks before the marker would be missed otherwise.
+var incrementalStages = []stages.SyncStage{
+	stages.Headers,
+	stages.Bodies,
+	stages.Senders,
+	stages.Execution,
+	stages.HashState,
+	stages.IntermediateHashes,
+	stages.AccountHistoryIndex,
+	stages.StorageHistoryIndex,
+	stages.LogIndex,
+	stages.CallTraces,
+	stages.TxLookup,
+}
+
+// blockTables are the tables whose keys start with the block number. Their rows of the blocks
+// after the marker are copied again, to also get the unwinds.
+var blockTables = []string{
+	kv.Headers,
+	kv.HeaderCanonical,
+	kv.HeaderTD,
+	kv.BlockBody,
+	kv.Senders,
+	kv.Receipts,
+	kv.Log,
+	kv.AccountChangeSet,
+	kv.StorageChangeSet,
+	kv.CallTraceSet,
+	kv.MaxTxNum,
+}
+
+// copyMode is how the rows of a table changed by the blocks after the marker are copied.
+type copyMode int
+
+const (
+	copyByKey    copyMode = iota // the row of the key
+	copyByPrefix                 // the rows starting with the key, for several rows per account or slot
+	// the shards of the bitmap index of the key which may hold the blocks after the marker
+	copyShards
+	// the row of the key, or for the hash of an account the storage tries of the account
+	// removed since
+	copyStorageTries
+)
+
+// changedKeyTables are the tables whose rows are copied when they are modified by the blocks
+// after the marker, as found from the change sets, logs, call traces and transactions, before
+// and after the backup. The rows of the trie tables are the ones along the paths of the
+// changed keys.
+var changedKeyTables = map[string]copyMode{
+	kv.PlainState:        copyByKey,
+	kv.HashedAccounts:    copyByKey,
+	kv.HashedStorage:     copyByKey,
+	kv.IncarnationMap:    copyByKey,
+	kv.TxLookup:          copyByKey,
+	kv.TrieOfAccounts:    copyByKey,
+	kv.TrieOfStorage:     copyStorageTries,
+	kv.PlainContractCode: copyByPrefix,
+	kv.ContractCode:      copyByPrefix,
+	kv.AccountsHistory:   copyByPrefix,
+	kv.StorageHistory:    copyByPrefix,
+	kv.LogAddressIndex:   copyShards,
+	kv.LogTopicIndex:     copyShards,
+	kv.CallFromIndex:     copyShards,
+	kv.CallToIndex:       copyShards,
+}
+
+// incrementalTables are all the tables supported by incremental backups. The other tables are
+// copied entirely.
+var incrementalTables = func() map[string]bool {
+	tables := map[string]bool{kv.EthTx: true, kv.Code: true}
+	for _, table := range blockTables {
+		tables[table] = true
+	}
+	for table := range changedKeyTables {
+		tables[table] = true
+	}
+	return tables
+}()
+
+// NewMarker reads the progress of the stages of the chain db.
+func NewMarker(tx kv.Tx) (*Marker, error) {
+	m := &Marker{Time: time.Now().UTC(), Stages: map[string]uint64{}}
+	for _, stage := range stages.AllStages {
+		progress, err := stages.GetStageProgress(tx, stage)
+		if err != nil {
+			return nil, err
+		}
+		m.Stages[string(stage)] = progress
+	}
+	m.Block = m.Stages[string(stages.Headers)]
+	for _, stage := range incrementalStages {
+		if progress := m.Stages[string(stage)]; progress < m.Block {
+			m.Block = progress
+		}
+	}
+	if m.Block == 0 {
+		return m, nil
+	}
+	var err error
+	if m.Hash, err = rawdb.ReadCanonicalHash(tx, m.Block); err != nil {
+		return nil, err
+	}
+	if m.Hash == (libcommon.Hash{}) { // in snapshots
+		return m, nil
+	}
+	body, err := rawdb.ReadBodyForStorageByKey(tx, dbutils.BlockBodyKey(m.Block, m.Hash))
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		m.TxID = body.BaseTxId + uint64(body.TxAmount)
+	}
+	return m, nil
+}
+
+// ReadMarker reads the marker of the backup in datadir, or returns nil if there is none.
+func ReadMarker(datadir string) (*Marker, error) {
+	data, err := os.ReadFile(filepath.Join(datadir, MarkerFile))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	m := &Marker{}
+	if err := json.Unmarshal(data, m); err != nil {
+		return nil, fmt.Errorf("%s: %w", MarkerFile, err)
+	}
+	return m, nil
+}
+
+// WriteMarker replaces the marker of the backup in datadir.
+func WriteMarker(datadir string, m *Marker) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmpPath := filepath.Join(datadir, MarkerFile+".tmp")
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, filepath.Join(datadir, MarkerFile))
+}
+
+// RemoveMarker removes the marker of the backup in datadir, when its content is replaced.
+func RemoveMarker(datadir string) error {
+	if err := os.Remove(filepath.Join(datadir, MarkerFile)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// Chaindata backs up the chain db from a single read transaction, so it can run while Erigon
+// keeps syncing. With the marker of the previous backup in dst, it copies only the rows
+// changed since then, and the tables not supported by incremental backups. Otherwise it
+// copies all the tables. It returns the marker of the new backup.
+func Chaindata(ctx context.Context, src kv.RoDB, dst kv.RwDB, prev *Marker, tmpdir string, readAheadThreads int, logger log.Logger) (*Marker, error) {
+	srcTx, err := src.BeginRo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer srcTx.Rollback()
+	marker, err := NewMarker(srcTx)
+	if err != nil {
+		return nil, err
+	}
+
+	var tables []string
+	for name, cfg := range src.AllTables() {
+		if cfg.IsDeprecated || (prev != nil && incrementalTables[name]) {
+			continue
+		}
+		tables = append(tables, name)
+	}
+	slices.Sort(tables)
+	if prev == nil {
+		logger.Info("[backup] full", "block", marker.Block)
+		if err := copyTables(ctx, src, srcTx, dst, tables, readAheadThreads); err != nil {
+			return nil, err
+		}
+		return marker, nil
+	}
+
+	if err := checkIncremental(srcTx, prev); err != nil {
+		return nil, err
+	}
+	logger.Info("[backup] incremental", "from_block", prev.Block+1, "to_block", marker.Block)
+	if err := copyTables(ctx, src, srcTx, dst, tables, readAheadThreads); err != nil {
+		return nil, err
+	}
+	dstTx, err := dst.BeginRw(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer dstTx.Rollback()
+	if err := incremental(ctx, srcTx, dstTx, prev, tmpdir, logger); err != nil {
+		return nil, err
+	}
+	if err := dstTx.Commit(); err != nil {
+		return nil, err
+	}
+	return marker, nil
+}
+
+// checkIncremental checks that the changes since the previous backup can be found: the db
+// was not unwound below it, and the change sets were not pruned since.
+func checkIncremental(srcTx kv.Tx, prev *Marker) error {
+	if prev.Hash != (libcommon.Hash{}) {
+		hash, err := rawdb.ReadCanonicalHash(srcTx, prev.Block)
+		if err != nil {
+			return err
+		}
+		if hash != prev.Hash {
+			return fmt.Errorf("%w: block %d was unwound since the previous backup", ErrFullBackupRequired, prev.Block)
+		}
+	}
+	pruned, err := changeSetsPruned(srcTx, prev.Block+1)
+	if err != nil {
+		return err
+	}
+	if pruned {
+		return fmt.Errorf("%w: the change sets were pruned since the previous backup", ErrFullBackupRequired)
+	}
+	return nil
+}
+
+// changeSetsPruned returns whether the change sets of the executed blocks from the given one
+// on were pruned.
+func changeSetsPruned(tx kv.Tx, from uint64) (bool, error) {
+	execution, err := stages.GetStageProgress(tx, stages.Execution)
+	if err != nil {
+		return false, err
+	}
+	if execution < from {
+		return false, nil
+	}
+	// all the blocks change accounts, at least the balance of the coinbase, but not always
+	// the storage
+	c, err := tx.Cursor(kv.AccountChangeSet)
+	if err != nil {
+		return false, err
+	}
+	defer c.Close()
+	k, _, err := c.First()
+	if err != nil {
+		return false, err
+	}
+	return k == nil || binary.BigEndian.Uint64(k) > from, nil
+}
+
+// incremental copies the rows of src changed since the previous backup into dst.
+func incremental(ctx context.Context, srcTx kv.Tx, dstTx kv.RwTx, prev *Marker, tmpdir string, logger log.Logger) error {
+	return copyChanges(ctx, srcTx, dstTx, prev.Block+1, prev.TxID, changedKeyTables, true /* deletePruned */, tmpdir, logger)
+}
+
+// copyChanges copies into dst the rows of the block tables and the transactions of src from
+// the given block and transaction on, and the rows of keyTables changed by these blocks.
+// With deletePruned, the rows of the block tables pruned from src are removed from dst.
+func copyChanges(ctx context.Context, srcTx kv.Tx, dstTx kv.RwTx, from, fromTxID uint64, keyTables map[string]copyMode, deletePruned bool, tmpdir string, logger log.Logger) error {
+	collectors := make(map[string]*etl.Collector, len(keyTables))
+	for table := range keyTables {
+		collectors[table] = etl.NewCollector("backup", tmpdir, etl.NewOldestEntryBuffer(etl.BufferOptimalSize), logger)
+		defer collectors[table].Close()
+	}
+	// the keys changed by the blocks of dst, which may have been unwound since, and by the
+	// blocks of src
+	for _, tx := range []kv.Tx{dstTx, srcTx} {
+		if err := collectChangedKeys(ctx, tx, from, fromTxID, collectors); err != nil {
+			return err
+		}
+	}
+
+	for _, table := range blockTables {
+		if err := copyRange(ctx, srcTx, dstTx, table, hexutility.EncodeTs(from), deletePruned); err != nil {
+			return fmt.Errorf("%s: %w", table, err)
+		}
+	}
+	if err := copyRange(ctx, srcTx, dstTx, kv.EthTx, hexutility.EncodeTs(fromTxID), deletePruned); err != nil {
+		return fmt.Errorf("%s: %w", kv.EthTx, err)
+	}
+
+	tables := make([]string, 0, len(keyTables))
+	for table := range keyTables {
+		tables = append(tables, table)
+	}
+	slices.Sort(tables)
+	for _, table := range tables {
+		mode := keyTables[table]
+		if err := collectors[table].Load(dstTx, "", func(k, _ []byte, _ etl.CurrentTableReader, _ etl.LoadNextFunc) error {
+			switch {
+			case mode == copyByPrefix:
+				return copyPrefix(srcTx, dstTx, table, k)
+			case mode == copyShards:
+				return copyShardsFrom(srcTx, dstTx, table, k, from)
+			case mode == copyStorageTries && len(k) == length.Hash:
+				return removeStorageTries(srcTx, dstTx, k)
+			}
+			return copyKey(srcTx, dstTx, table, k)
+		}, etl.TransformArgs{Quit: ctx.Done()}); err != nil {
+			return fmt.Errorf("%s: %w", table, err)
+		}
+	}
+	return nil
+}
+
+// collectChangedKeys collects the keys and prefixes of the tables of the collectors changed by
+// the blocks from the given one on.
+func collectChangedKeys(ctx context.Context, tx kv.Tx, from, fromTxID uint64, collectors map[string]*etl.Collector) error {
+	collect := func(table string, k []byte) error {
+		if c, ok := collectors[table]; ok {
+			return c.Collect(k, nil)
+		}
+		return nil
+	}
+	// the trie rows are keyed by the nibbles of the paths, the root of the accounts trie is not
+	// stored but the ones of the storage tries are
+	var nibbles []byte
+	collectPath := func(table string, prefix []byte, key libcommon.Hash, minLen int) error {
+		if _, ok := collectors[table]; !ok {
+			return nil
+		}
+		hexutil.DecompressNibbles(key[:], &nibbles)
+		for i := minLen; i < len(nibbles); i++ {
+			if err := collect(table, append(common.CopyBytes(prefix), nibbles[:i]...)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if err := historyv2.ForEach(tx, kv.AccountChangeSet, hexutility.EncodeTs(from), func(_ uint64, k, _ []byte) error {
+		addrHash, err := common.HashData(k)
+		if err != nil {
+			return err
+		}
+		for _, table := range []string{kv.PlainState, kv.IncarnationMap, kv.PlainContractCode, kv.AccountsHistory} {
+			if err := collect(table, k); err != nil {
+				return err
+			}
+		}
+		for _, table := range []string{kv.HashedAccounts, kv.ContractCode, kv.TrieOfStorage} {
+			if err := collect(table, addrHash[:]); err != nil {
+				return err
+			}
+		}
+		return collectPath(kv.TrieOfAccounts, nil, addrHash, 1)
+	}); err != nil {
+		return err
+	}
+	if err := historyv2.ForEach(tx, kv.StorageChangeSet, hexutility.EncodeTs(from), func(_ uint64, k, _ []byte) error {
+		if len(k) != length.Addr+length.Incarnation+length.Hash {
+			return fmt.Errorf("unexpected storage key length %d", len(k))
+		}
+		addrHash, err := common.HashData(k[:length.Addr])
+		if err != nil {
+			return err
+		}
+		locHash, err := common.HashData(k[length.Addr+length.Incarnation:])
+		if err != nil {
+			return err
+		}
+		inc := binary.BigEndian.Uint64(k[length.Addr:])
+		if err := collect(kv.PlainState, k); err != nil {
+			return err
+		}
+		if err := collect(kv.HashedStorage, dbutils.GenerateCompositeStorageKey(addrHash, inc, locHash)); err != nil {
+			return err
+		}
+		if err := collect(kv.StorageHistory, dbutils.CompositeKeyWithoutIncarnation(k)); err != nil {
+			return err
+		}
+		// the storage root is not in the account change sets
+		if err := collectPath(kv.TrieOfAccounts, nil, addrHash, 1); err != nil {
+			return err
+		}
+		return collectPath(kv.TrieOfStorage, append(addrHash[:], k[length.Addr:length.Addr+length.Incarnation]...), locHash, 0)
+	}); err != nil {
+		return err
+	}
+	if err := collectIndexKeys(ctx, tx, from, collect); err != nil {
+		return err
+	}
+	if _, ok := collectors[kv.TxLookup]; !ok {
+		return nil
+	}
+	return tx.ForEach(kv.EthTx, hexutility.EncodeTs(fromTxID), func(k, v []byte) error {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+		txn, err := types.DecodeTransaction(v)
+		if err != nil {
+			return fmt.Errorf("tx %d: %w", binary.BigEndian.Uint64(k), err)
+		}
+		hash := txn.Hash()
+		return collect(kv.TxLookup, hash[:])
+	})
+}
+
+// collectIndexKeys collects the addresses and topics of the logs and the addresses of the call
+// traces of the blocks from the given one on, the keys of the bitmap indices.
+func collectIndexKeys(ctx context.Context, tx kv.Tx, from uint64, collect func(table string, k []byte) error) error {
+	if err := tx.ForEach(kv.Log, hexutility.EncodeTs(from), func(k, v []byte) error {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+		var logs types.Logs
+		if err := cbor.Unmarshal(&logs, bytes.NewReader(v)); err != nil {
+			return fmt.Errorf("logs of block %d: %w", binary.BigEndian.Uint64(k), err)
+		}
+		for _, l := range logs {
+			if err := collect(kv.LogAddressIndex, l.Address[:]); err != nil {
+				return err
+			}
+			for _, topic := range l.Topics {
+				if err := collect(kv.LogTopicIndex, topic[:]); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+	return tx.ForEach(kv.CallTraceSet, hexutility.EncodeTs(from), func(_, v []byte) error {
+		if len(v) != length.Addr+1 {
+			return fmt.Errorf("wrong size of value in %s: %x (size %d)", kv.CallTraceSet, v, len(v))
+		}
+		if v[length.Addr]&1 > 0 {
+			if err := collect(kv.CallFromIndex, v[:length.Addr]); err != nil {
+				return err
+			}
+		}
+		if v[length.Addr]&2 > 0 {
+			return collect(kv.CallToIndex, v[:length.Addr])
+		}
+		return nil
+	})
+}
+
+// copyRange replaces the rows of dst from the given key on with those of src. With
+// deletePruned, the rows of dst before the first one of src, pruned from src, are removed.
+func copyRange(ctx context.Context, srcTx kv.Tx, dstTx kv.RwTx, table string, from []byte, deletePruned bool) error {
+	srcC, err := srcTx.Cursor(table)
+	if err != nil {
+		return err
+	}
+	defer srcC.Close()
+	dstC, err := dstTx.RwCursor(table)
+	if err != nil {
+		return err
+	}
+	defer dstC.Close()
+	dupsortC, isDupsort := dstC.(kv.RwCursorDupSort)
+	deleteCurrent := dstC.DeleteCurrent
+	if isDupsort {
+		deleteCurrent = dupsortC.DeleteCurrentDuplicates
+	}
+
+	if deletePruned {
+		pruned, _, err := srcC.First()
+		if err != nil {
+			return err
+		}
+		if pruned == nil || bytes.Compare(pruned, from) > 0 {
+			pruned = from
+		}
+		for k, _, err := dstC.First(); k != nil && bytes.Compare(k, pruned) < 0; k, _, err = dstC.First() {
+			if err != nil {
+				return err
+			}
+			if err := deleteCurrent(); err != nil {
+				return err
+			}
+		}
+	}
+	for k, _, err := dstC.Seek(from); k != nil; k, _, err = dstC.Seek(from) {
+		if err != nil {
+			return err
+		}
+		if err := deleteCurrent(); err != nil {
+			return err
+		}
+	}
+
+	for k, v, err := srcC.Seek(from); k != nil; k, v, err = srcC.Next() {
+		if err != nil {
+			return err
+		}
+		if isDupsort {
+			err = dupsortC.AppendDup(k, v)
+		} else {
+			err = dstC.Append(k, v)
+		}
+		if err != nil {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+	}
+	return nil
+}
+
+// copyKey replaces the row of dst with the one of src, or removes it if src has none.
+func copyKey(srcTx kv.Tx, dstTx kv.RwTx, table string, k []byte) error {
+	v, err := srcTx.GetOne(table, k)
+	if err != nil {
+		return err
+	}
+	if v == nil {
+		return dstTx.Delete(table, k)
+	}
+	return dstTx.Put(table, k, v)
+}
+
+// copyPrefix replaces the rows of dst starting with the prefix with those of src. The code of
+// the contracts is copied along with the code hashes.
+func copyPrefix(srcTx kv.Tx, dstTx kv.RwTx, table string, prefix []byte) error {
+	c, err := dstTx.RwCursor(table)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+	for k, _, err := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, _, err = c.Seek(prefix) {
+		if err != nil {
+			return err
+		}
+		if err := c.DeleteCurrent(); err != nil {
+			return err
+		}
+	}
+	return srcTx.ForPrefix(table, prefix, func(k, v []byte) error {
+		if err := dstTx.Put(table, k, v); err != nil {
+			return err
+		}
+		if table != kv.PlainContractCode && table != kv.ContractCode {
+			return nil
+		}
+		if has, err := dstTx.Has(kv.Code, v); err != nil || has {
+			return err
+		}
+		code, err := srcTx.GetOne(kv.Code, v)
+		if err != nil || code == nil {
+			return err
+		}
+		return dstTx.Put(kv.Code, v, code)
+	})
+}
+
+// copyShardsFrom replaces the shards of the bitmaps of dst starting with the prefix which may hold
+// blocks from the given one on with those of src. The shards are keyed by their last block and
+// only the last one grows, so the shards of dst ending before the block are the same in src.
+// The shards pruned from src since are kept.
+func copyShardsFrom(srcTx kv.Tx, dstTx kv.RwTx, table string, prefix []byte, from uint64) error {
+	c, err := dstTx.RwCursor(table)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+	start := prefix
+	for k, _, err := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, _, err = c.Next() {
+		if err != nil {
+			return err
+		}
+		suffix := k[len(prefix):]
+		var last uint64
+		switch len(suffix) {
+		case 4:
+			last = uint64(binary.BigEndian.Uint32(suffix))
+		case 8:
+			last = binary.BigEndian.Uint64(suffix)
+		default:
+			return fmt.Errorf("unexpected shard key %x of %x", k, prefix)
+		}
+		if last >= from {
+			break
+		}
+		start = append(common.CopyBytes(k), 0) // right after the shard
+	}
+	for k, _, err := c.Seek(start); k != nil && bytes.HasPrefix(k, prefix); k, _, err = c.Seek(start) {
+		if err != nil {
+			return err
+		}
+		if err := c.DeleteCurrent(); err != nil {
+			return err
+		}
+	}
+	srcC, err := srcTx.Cursor(table)
+	if err != nil {
+		return err
+	}
+	defer srcC.Close()
+	for k, v, err := srcC.Seek(start); k != nil && bytes.HasPrefix(k, prefix); k, v, err = srcC.Next() {
+		if err != nil {
+			return err
+		}
+		if err := dstTx.Put(table, k, v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// removeStorageTries removes from dst the storage tries of the account which are not in src
+// anymore, as the account self-destructed or was recreated since. The rows of the tries kept
+// are copied along the paths of the changed slots.
+func removeStorageTries(srcTx kv.Tx, dstTx kv.RwTx, addrHash []byte) error {
+	c, err := dstTx.RwCursor(kv.TrieOfStorage)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+	srcC, err := srcTx.Cursor(kv.TrieOfStorage)
+	if err != nil {
+		return err
+	}
+	defer srcC.Close()
+	k, _, err := c.Seek(addrHash)
+	if err != nil {
+		return err
+	}
+	for k != nil && bytes.HasPrefix(k, addrHash) {
+		if len(k) < length.Hash+length.Incarnation {
+			return fmt.Errorf("unexpected storage trie key %x", k)
+		}
+		accWithInc := common.CopyBytes(k[:length.Hash+length.Incarnation])
+		srcK, _, err := srcC.Seek(accWithInc)
+		if err != nil {
+			return err
+		}
+		if srcK != nil && bytes.HasPrefix(srcK, accWithInc) {
+			next, ok := kv.NextSubtree(accWithInc)
+			if !ok {
+				return nil
+			}
+			if k, _, err = c.Seek(next); err != nil {
+				return err
+			}
+			continue
+		}
+		for k != nil && bytes.HasPrefix(k, accWithInc) {
+			if err := c.DeleteCurrent(); err != nil {
+				return err
+			}
+			if k, _, err = c.Seek(accWithInc); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,263 @@
+package backup
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"math/big"
+	"testing"
+
+	"github.com/holiman/uint256"
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/common/hexutility"
+	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/ledgerwatch/erigon-lib/kv/memdb"
+	"github.com/ledgerwatch/log/v3"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ledgerwatch/erigon/common"
+	"github.com/ledgerwatch/erigon/common/dbutils"
+	"github.com/ledgerwatch/erigon/common/hexutil"
+	"github.com/ledgerwatch/erigon/core/rawdb"
+	"github.com/ledgerwatch/erigon/core/types"
+	"github.com/ledgerwatch/erigon/eth/stagedsync/stages"
+	"github.com/ledgerwatch/erigon/ethdb/cbor"
+	"github.com/ledgerwatch/erigon/rlp"
+)
+
+// writeBlock writes the header and body of a block, the given account change and the
+// progress of the stages. It returns the hash of the block.
+func writeBlock(t *testing.T, tx kv.RwTx, number uint64, extra byte, addr libcommon.Address, account []byte) libcommon.Hash {
+	t.Helper()
+	header := &types.Header{Number: big.NewInt(int64(number)), Extra: []byte{extra}}
+	rawdb.WriteHeader(tx, header)
+	require.NoError(t, rawdb.WriteCanonicalHash(tx, header.Hash(), number))
+	require.NoError(t, rawdb.WriteBodyForStorage(tx, header.Hash(), number, &types.BodyForStorage{BaseTxId: number * 2, TxAmount: 2}))
+	require.NoError(t, tx.Put(kv.AccountChangeSet, hexutility.EncodeTs(number), addr[:]))
+	addrHash, err := common.HashData(addr[:])
+	require.NoError(t, err)
+	require.NoError(t, tx.Put(kv.PlainState, addr[:], account))
+	require.NoError(t, tx.Put(kv.HashedAccounts, addrHash[:], account))
+	for _, stage := range []stages.SyncStage{stages.Headers, stages.Bodies, stages.Senders, stages.Execution} {
+		require.NoError(t, stages.SaveStageProgress(tx, stage, number))
+	}
+	return header.Hash()
+}
+
+// saveLaggingProgress saves the progress of the stages running after the execution.
+func saveLaggingProgress(t *testing.T, tx kv.RwTx, number uint64) {
+	t.Helper()
+	for _, stage := range []stages.SyncStage{stages.HashState, stages.IntermediateHashes, stages.AccountHistoryIndex, stages.StorageHistoryIndex, stages.LogIndex, stages.CallTraces, stages.TxLookup} {
+		require.NoError(t, stages.SaveStageProgress(tx, stage, number))
+	}
+}
+
+func requireEqualTables(t *testing.T, src, dst kv.RwDB) {
+	t.Helper()
+	read := func(db kv.RwDB, table string) (rows [][2]string) {
+		require.NoError(t, db.View(context.Background(), func(tx kv.Tx) error {
+			return tx.ForEach(table, nil, func(k, v []byte) error {
+				rows = append(rows, [2]string{string(k), string(v)})
+				return nil
+			})
+		}))
+		return rows
+	}
+	for table, cfg := range src.AllTables() {
+		if !cfg.IsDeprecated {
+			require.Equal(t, read(src, table), read(dst, table), table)
+		}
+	}
+}
+
+func TestIncrementalBackup(t *testing.T) {
+	ctx, logger := context.Background(), log.New()
+	src, dst := memdb.NewTestDB(t), memdb.NewTestDB(t)
+	a, b := libcommon.Address{1}, libcommon.Address{2}
+	storageKey := dbutils.PlainGenerateCompositeStorageKey(a[:], 1, libcommon.Hash{3}.Bytes())
+
+	require.NoError(t, src.Update(ctx, func(tx kv.RwTx) error {
+		writeBlock(t, tx, 1, 0, a, []byte{1})
+		writeBlock(t, tx, 2, 0, b, []byte{2})
+		return nil
+	}))
+	marker, err := Chaindata(ctx, src, dst, nil, t.TempDir(), 1, logger)
+	require.NoError(t, err)
+	require.Equal(t, uint64(0), marker.Block) // no HashState nor indices yet
+	requireEqualTables(t, src, dst)
+
+	var hash3 libcommon.Hash
+	require.NoError(t, src.Update(ctx, func(tx kv.RwTx) error {
+		hash3 = writeBlock(t, tx, 3, 0, a, []byte{3})
+		require.NoError(t, tx.Put(kv.StorageChangeSet, append(hexutility.EncodeTs(3), storageKey[:28]...), storageKey[28:]))
+		require.NoError(t, tx.Put(kv.PlainState, storageKey, []byte{4}))
+		saveLaggingProgress(t, tx, 2)
+		return nil
+	}))
+	marker, err = Chaindata(ctx, src, dst, marker, t.TempDir(), 1, logger)
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), marker.Block)
+	requireEqualTables(t, src, dst)
+
+	// block 3 is unwound: its change sets are not in src anymore, the changes are found in the backup
+	require.NoError(t, src.Update(ctx, func(tx kv.RwTx) error {
+		require.NoError(t, tx.Delete(kv.AccountChangeSet, hexutility.EncodeTs(3)))
+		require.NoError(t, tx.Delete(kv.StorageChangeSet, append(hexutility.EncodeTs(3), storageKey[:28]...)))
+		require.NoError(t, tx.Delete(kv.Headers, dbutils.HeaderKey(3, hash3)))
+		require.NoError(t, tx.Delete(kv.BlockBody, dbutils.BlockBodyKey(3, hash3)))
+		require.NoError(t, tx.Delete(kv.PlainState, storageKey))
+		require.NoError(t, tx.Put(kv.PlainState, a[:], []byte{1}))
+		aHash, err := common.HashData(a[:])
+		require.NoError(t, err)
+		require.NoError(t, tx.Put(kv.HashedAccounts, aHash[:], []byte{1}))
+		writeBlock(t, tx, 3, 1, b, []byte{5})
+		saveLaggingProgress(t, tx, 3)
+		return nil
+	}))
+	marker, err = Chaindata(ctx, src, dst, marker, t.TempDir(), 1, logger)
+	require.NoError(t, err)
+	require.Equal(t, uint64(3), marker.Block)
+	requireEqualTables(t, src, dst)
+
+	require.NoError(t, dst.View(ctx, func(tx kv.Tx) error {
+		problems, err := CheckChaindata(tx, marker)
+		require.NoError(t, err)
+		require.Empty(t, problems)
+		return nil
+	}))
+
+	// a backup interrupted before its marker is written
+	require.NoError(t, dst.Update(ctx, func(tx kv.RwTx) error {
+		return stages.SaveStageProgress(tx, stages.Execution, 4)
+	}))
+	require.NoError(t, dst.View(ctx, func(tx kv.Tx) error {
+		problems, err := CheckChaindata(tx, marker)
+		require.NoError(t, err)
+		require.Len(t, problems, 2) // the marker, and Execution after Senders
+		return nil
+	}))
+
+	// an unwind below the marker
+	require.NoError(t, src.Update(ctx, func(tx kv.RwTx) error {
+		return rawdb.WriteCanonicalHash(tx, libcommon.Hash{5}, 3)
+	}))
+	_, err = Chaindata(ctx, src, dst, marker, t.TempDir(), 1, logger)
+	require.ErrorIs(t, err, ErrFullBackupRequired)
+}
+
+func TestIncrementalBackupLaggingIndices(t *testing.T) {
+	ctx, logger := context.Background(), log.New()
+	src, dst := memdb.NewTestDB(t), memdb.NewTestDB(t)
+	a, b := libcommon.Address{1}, libcommon.Address{2}
+
+	require.NoError(t, src.Update(ctx, func(tx kv.RwTx) error {
+		writeBlock(t, tx, 1, 0, a, []byte{1})
+		writeBlock(t, tx, 2, 0, b, []byte{2})
+		saveLaggingProgress(t, tx, 2)
+		// the indices of block 1 only
+		for _, stage := range []stages.SyncStage{stages.AccountHistoryIndex, stages.StorageHistoryIndex, stages.LogIndex, stages.CallTraces, stages.TxLookup} {
+			require.NoError(t, stages.SaveStageProgress(tx, stage, 1))
+		}
+		require.NoError(t, tx.Put(kv.AccountsHistory, append(a[:], hexutility.EncodeTs(1)...), []byte{1}))
+		return tx.Put(kv.TxLookup, libcommon.Hash{1}.Bytes(), []byte{1})
+	}))
+	marker, err := Chaindata(ctx, src, dst, nil, t.TempDir(), 1, logger)
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), marker.Block) // bounded by the indices, not the execution
+	requireEqualTables(t, src, dst)
+
+	// the indices catch up with the execution, writing the rows of block 2
+	require.NoError(t, src.Update(ctx, func(tx kv.RwTx) error {
+		saveLaggingProgress(t, tx, 2)
+		require.NoError(t, tx.Put(kv.AccountsHistory, append(b[:], hexutility.EncodeTs(2)...), []byte{2}))
+		require.NoError(t, tx.Put(kv.CallTraceSet, hexutility.EncodeTs(2), b[:]))
+		// the first transaction of block 2
+		txn := types.NewTransaction(0, b, uint256.NewInt(1), 21000, uint256.NewInt(1), nil)
+		txnRlp, err := rlp.EncodeToBytes(txn)
+		require.NoError(t, err)
+		require.NoError(t, tx.Put(kv.EthTx, hexutility.EncodeTs(marker.TxID), txnRlp))
+		txnHash := txn.Hash()
+		return tx.Put(kv.TxLookup, txnHash[:], []byte{2})
+	}))
+	marker, err = Chaindata(ctx, src, dst, marker, t.TempDir(), 1, logger)
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), marker.Block)
+	requireEqualTables(t, src, dst)
+}
+
+func TestIncrementalBackupIndicesAndTries(t *testing.T) {
+	ctx, logger := context.Background(), log.New()
+	src, dst := memdb.NewTestDB(t), memdb.NewTestDB(t)
+	a, b, c := libcommon.Address{1}, libcommon.Address{2}, libcommon.Address{3}
+	topic := libcommon.Hash{4}
+	aHash, err := common.HashData(a[:])
+	require.NoError(t, err)
+	cHash, err := common.HashData(c[:])
+	require.NoError(t, err)
+	slot := libcommon.Hash{5}
+	slotHash, err := common.HashData(slot[:])
+	require.NoError(t, err)
+	shard32 := func(k []byte, last uint32) []byte {
+		return binary.BigEndian.AppendUint32(common.CopyBytes(k), last)
+	}
+	shard64 := func(k []byte, last uint64) []byte {
+		return binary.BigEndian.AppendUint64(common.CopyBytes(k), last)
+	}
+	var aPath, slotPath []byte
+	hexutil.DecompressNibbles(aHash[:], &aPath)
+	hexutil.DecompressNibbles(slotHash[:], &slotPath)
+	aStorageTrie := append(aHash[:], hexutility.EncodeTs(1)...)
+	cStorageTrie := append(cHash[:], hexutility.EncodeTs(1)...)
+
+	require.NoError(t, src.Update(ctx, func(tx kv.RwTx) error {
+		writeBlock(t, tx, 1, 0, a, []byte{1})
+		writeBlock(t, tx, 2, 0, c, []byte{2})
+		saveLaggingProgress(t, tx, 2)
+		require.NoError(t, tx.Put(kv.LogAddressIndex, shard32(a[:], 1), []byte{1}))
+		require.NoError(t, tx.Put(kv.LogAddressIndex, shard32(a[:], 0xFFFFFFFF), []byte{2}))
+		require.NoError(t, tx.Put(kv.LogTopicIndex, shard32(topic[:], 0xFFFFFFFF), []byte{1}))
+		require.NoError(t, tx.Put(kv.CallToIndex, shard64(b[:], 0xFFFFFFFFFFFFFFFF), []byte{1}))
+		require.NoError(t, tx.Put(kv.TrieOfAccounts, aPath[:1], []byte{1}))
+		require.NoError(t, tx.Put(kv.TrieOfAccounts, aPath[:2], []byte{1}))
+		require.NoError(t, tx.Put(kv.TrieOfStorage, aStorageTrie, []byte{1}))
+		require.NoError(t, tx.Put(kv.TrieOfStorage, append(common.CopyBytes(aStorageTrie), slotPath[:1]...), []byte{1}))
+		require.NoError(t, tx.Put(kv.TrieOfStorage, cStorageTrie, []byte{1}))
+		return nil
+	}))
+	marker, err := Chaindata(ctx, src, dst, nil, t.TempDir(), 1, logger)
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), marker.Block)
+	requireEqualTables(t, src, dst)
+
+	// block 3 logs and calls to a and b, changes a slot of a and self-destructs c
+	require.NoError(t, src.Update(ctx, func(tx kv.RwTx) error {
+		writeBlock(t, tx, 3, 0, a, []byte{3})
+		storageKey := dbutils.PlainGenerateCompositeStorageKey(a[:], 1, slot[:])
+		require.NoError(t, tx.Put(kv.StorageChangeSet, append(hexutility.EncodeTs(3), storageKey[:28]...), storageKey[28:]))
+		require.NoError(t, tx.Put(kv.AccountChangeSet, hexutility.EncodeTs(3), c[:]))
+		require.NoError(t, tx.Delete(kv.PlainState, c[:]))
+		require.NoError(t, tx.Delete(kv.HashedAccounts, cHash[:]))
+		saveLaggingProgress(t, tx, 3)
+
+		var logs bytes.Buffer
+		require.NoError(t, cbor.Marshal(&logs, types.Logs{{Address: a, Topics: []libcommon.Hash{topic}}}))
+		require.NoError(t, tx.Put(kv.Log, dbutils.LogKey(3, 0), logs.Bytes()))
+		require.NoError(t, tx.Put(kv.CallTraceSet, hexutility.EncodeTs(3), append(b[:], 2)))
+		// the last shard of a is split
+		require.NoError(t, tx.Put(kv.LogAddressIndex, shard32(a[:], 2), []byte{2}))
+		require.NoError(t, tx.Put(kv.LogAddressIndex, shard32(a[:], 0xFFFFFFFF), []byte{3}))
+		require.NoError(t, tx.Put(kv.LogTopicIndex, shard32(topic[:], 0xFFFFFFFF), []byte{1, 3}))
+		require.NoError(t, tx.Put(kv.CallToIndex, shard64(b[:], 0xFFFFFFFFFFFFFFFF), []byte{1, 3}))
+
+		require.NoError(t, tx.Put(kv.TrieOfAccounts, aPath[:1], []byte{2}))
+		require.NoError(t, tx.Delete(kv.TrieOfAccounts, aPath[:2]))
+		require.NoError(t, tx.Put(kv.TrieOfAccounts, aPath[:3], []byte{2}))
+		require.NoError(t, tx.Put(kv.TrieOfStorage, aStorageTrie, []byte{2}))
+		require.NoError(t, tx.Put(kv.TrieOfStorage, append(common.CopyBytes(aStorageTrie), slotPath[:2]...), []byte{2}))
+		return tx.Delete(kv.TrieOfStorage, cStorageTrie)
+	}))
+	marker, err = Chaindata(ctx, src, dst, marker, t.TempDir(), 1, logger)
+	require.NoError(t, err)
+	require.Equal(t, uint64(3), marker.Block)
+	requireEqualTables(t, src, dst)
+}
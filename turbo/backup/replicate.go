@@ -0,0 +1,121 @@
+package backup
+
+import (
+	"context"
+	"fmt"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/common/hexutility"
+	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/ledgerwatch/log/v3"
+
+	"github.com/ledgerwatch/erigon/common/dbutils"
+	"github.com/ledgerwatch/erigon/core/rawdb"
+	"github.com/ledgerwatch/erigon/eth/stagedsync/stages"
+)
+
+// ReplicatedStages are the stages whose rows are copied from the primary to its replicas, which
+// run the following stages themselves.
+var ReplicatedStages = []stages.SyncStage{stages.Headers, stages.BlockHashes, stages.Bodies, stages.Senders, stages.Execution}
+
+// replicatedKeyTables are the state tables written by the Execution stage, copied by key as
+// changedKeyTables.
+var replicatedKeyTables = map[string]copyMode{
+	kv.PlainState:        copyByKey,
+	kv.IncarnationMap:    copyByKey,
+	kv.PlainContractCode: copyByPrefix,
+}
+
+// Replicate copies into dst the rows written by ReplicatedStages in src for the blocks from the
+// given one on, replacing the rows of dst of these blocks, and the progress of the stages. The
+// blocks before it must be the same in both dbs. The rows removed from src by its pruning or
+// moved to its snapshots are kept in dst.
+func Replicate(ctx context.Context, srcTx kv.Tx, dstTx kv.RwTx, from uint64, tmpdir string, logger log.Logger) error {
+	if from == 0 {
+		return fmt.Errorf("%w: the genesis block can't be replicated", ErrFullBackupRequired)
+	}
+	hash, err := rawdb.ReadCanonicalHash(srcTx, from-1)
+	if err != nil {
+		return err
+	}
+	body, err := rawdb.ReadBodyForStorageByKey(srcTx, dbutils.BlockBodyKey(from-1, hash))
+	if err != nil {
+		return err
+	}
+	if body == nil {
+		return fmt.Errorf("%w: block %d is not in the db of the primary", ErrFullBackupRequired, from-1)
+	}
+	pruned, err := changeSetsPruned(srcTx, from)
+	if err != nil {
+		return err
+	}
+	if pruned {
+		return fmt.Errorf("%w: the change sets of block %d were pruned from the primary", ErrFullBackupRequired, from)
+	}
+
+	if err := copyChanges(ctx, srcTx, dstTx, from, body.BaseTxId+uint64(body.TxAmount), replicatedKeyTables, false /* deletePruned */, tmpdir, logger); err != nil {
+		return err
+	}
+	if err := srcTx.ForEach(kv.Headers, hexutility.EncodeTs(from), func(k, _ []byte) error {
+		return dstTx.Put(kv.HeaderNumber, k[8:], k[:8])
+	}); err != nil {
+		return err
+	}
+	if err := copyHead(srcTx, dstTx); err != nil {
+		return err
+	}
+	for _, stage := range ReplicatedStages {
+		progress, err := stages.GetStageProgress(srcTx, stage)
+		if err != nil {
+			return err
+		}
+		if err := stages.SaveStageProgress(dstTx, stage, progress); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ReplicateBlock copies into dst the rows written by ReplicatedStages in src for a canonical
+// block, except the state, which the replicas get from the state change stream of the primary.
+// The progress of the stages is left to the caller.
+func ReplicateBlock(srcTx kv.Tx, dstTx kv.RwTx, number uint64, hash libcommon.Hash) error {
+	canonical, err := rawdb.ReadCanonicalHash(srcTx, number)
+	if err != nil {
+		return err
+	}
+	if canonical != hash {
+		return fmt.Errorf("block %d %x is not canonical in the primary", number, hash)
+	}
+	body, err := rawdb.ReadBodyForStorageByKey(srcTx, dbutils.BlockBodyKey(number, hash))
+	if err != nil {
+		return err
+	}
+	if body == nil {
+		return fmt.Errorf("block %d %x is not in the db of the primary", number, hash)
+	}
+
+	prefix := hexutility.EncodeTs(number)
+	for _, table := range blockTables {
+		if err := copyPrefix(srcTx, dstTx, table, prefix); err != nil {
+			return fmt.Errorf("%s: %w", table, err)
+		}
+	}
+	for id := body.BaseTxId; id < body.BaseTxId+uint64(body.TxAmount); id++ {
+		if err := copyKey(srcTx, dstTx, kv.EthTx, hexutility.EncodeTs(id)); err != nil {
+			return fmt.Errorf("%s: %w", kv.EthTx, err)
+		}
+	}
+	if err := dstTx.Put(kv.HeaderNumber, hash[:], prefix); err != nil {
+		return err
+	}
+	return copyHead(srcTx, dstTx)
+}
+
+// copyHead copies the head header and the sequence of the transaction ids.
+func copyHead(srcTx kv.Tx, dstTx kv.RwTx) error {
+	if err := rawdb.WriteHeadHeaderHash(dstTx, rawdb.ReadHeadHeaderHash(srcTx)); err != nil {
+		return err
+	}
+	return copyKey(srcTx, dstTx, kv.Sequence, []byte(kv.EthTx))
+}
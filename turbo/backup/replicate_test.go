@@ -0,0 +1,86 @@
+package backup
+
+import (
+	"context"
+	"testing"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/common/hexutility"
+	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/ledgerwatch/erigon-lib/kv/memdb"
+	"github.com/ledgerwatch/log/v3"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ledgerwatch/erigon/common/dbutils"
+	"github.com/ledgerwatch/erigon/core/rawdb"
+	"github.com/ledgerwatch/erigon/eth/stagedsync/stages"
+)
+
+func TestReplicate(t *testing.T) {
+	ctx, logger := context.Background(), log.New()
+	src, dst := memdb.NewTestDB(t), memdb.NewTestDB(t)
+	a, b := libcommon.Address{1}, libcommon.Address{2}
+
+	var hash1 libcommon.Hash
+	for _, db := range []kv.RwDB{src, dst} {
+		require.NoError(t, db.Update(ctx, func(tx kv.RwTx) error {
+			writeBlock(t, tx, 0, 0, a, []byte{0})
+			hash1 = writeBlock(t, tx, 1, 0, a, []byte{1})
+			return nil
+		}))
+	}
+	// block 2 of dst is unwound in src
+	require.NoError(t, dst.Update(ctx, func(tx kv.RwTx) error {
+		writeBlock(t, tx, 2, 1, b, []byte{2})
+		return nil
+	}))
+	var hash2 libcommon.Hash
+	require.NoError(t, src.Update(ctx, func(tx kv.RwTx) error {
+		hash2 = writeBlock(t, tx, 2, 0, a, []byte{3})
+		writeBlock(t, tx, 3, 0, a, []byte{4})
+		// block 1 is moved to the snapshots
+		return tx.Delete(kv.Headers, dbutils.HeaderKey(1, hash1))
+	}))
+
+	srcTx, err := src.BeginRo(ctx)
+	require.NoError(t, err)
+	defer srcTx.Rollback()
+	dstTx, err := dst.BeginRw(ctx)
+	require.NoError(t, err)
+	defer dstTx.Rollback()
+	require.NoError(t, Replicate(ctx, srcTx, dstTx, 2, t.TempDir(), logger))
+
+	header := rawdb.ReadHeader(dstTx, hash1, 1)
+	require.NotNil(t, header)
+	number := rawdb.ReadHeaderNumber(dstTx, hash2)
+	require.NotNil(t, number)
+	require.Equal(t, uint64(2), *number)
+	account, err := dstTx.GetOne(kv.PlainState, a[:])
+	require.NoError(t, err)
+	require.Equal(t, []byte{4}, account)
+	account, err = dstTx.GetOne(kv.PlainState, b[:])
+	require.NoError(t, err)
+	require.Nil(t, account) // only changed in the unwound block
+	execution, err := stages.GetStageProgress(dstTx, stages.Execution)
+	require.NoError(t, err)
+	require.Equal(t, uint64(3), execution)
+
+	require.NoError(t, src.Update(ctx, func(tx kv.RwTx) error {
+		writeBlock(t, tx, 4, 0, b, []byte{5})
+		return nil
+	}))
+	srcTx.Rollback()
+	srcTx, err = src.BeginRo(ctx)
+	require.NoError(t, err)
+	hash4, err := rawdb.ReadCanonicalHash(srcTx, 4)
+	require.NoError(t, err)
+	require.Error(t, ReplicateBlock(srcTx, dstTx, 4, libcommon.Hash{4}))
+	require.NoError(t, ReplicateBlock(srcTx, dstTx, 4, hash4))
+	require.NotNil(t, rawdb.ReadHeader(dstTx, hash4, 4))
+	changes, err := dstTx.GetOne(kv.AccountChangeSet, hexutility.EncodeTs(4))
+	require.NoError(t, err)
+	require.Equal(t, b[:], changes)
+	account, err = dstTx.GetOne(kv.PlainState, b[:])
+	require.NoError(t, err)
+	require.Nil(t, account) // from the state change stream
+}
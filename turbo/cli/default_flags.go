@@ -72,6 +72,11 @@ var DefaultFlags = []cli.Flag{
 	&utils.RpcGasCapFlag,
 	&utils.RpcBatchLimit,
 	&utils.RpcReturnDataLimit,
+	&utils.RpcJSTracerStepLimit,
+	&utils.RpcJSTracerTimeLimit,
+	&utils.RpcJSTracerMemoryLimit,
+	&utils.RpcJSTracersDir,
+	&utils.RpcTraceParallelWorkersFlag,
 	&utils.TxpoolApiAddrFlag,
 	&utils.TraceMaxtracesFlag,
 	&HTTPReadTimeoutFlag,
@@ -84,6 +89,7 @@ var DefaultFlags = []cli.Flag{
 
 	&utils.SnapKeepBlocksFlag,
 	&utils.SnapStopFlag,
+	&utils.SnapCheckFlag,
 	&utils.DbPageSizeFlag,
 	&utils.DbSizeLimitFlag,
 	&utils.TorrentPortFlag,
@@ -100,6 +106,7 @@ var DefaultFlags = []cli.Flag{
 	&utils.NATFlag,
 	&utils.NoDiscoverFlag,
 	&utils.DiscoveryV5Flag,
+	&utils.DiscoveryV5TopicsFlag,
 	&utils.NetrestrictFlag,
 	&utils.NodeKeyFileFlag,
 	&utils.NodeKeyHexFlag,
@@ -139,6 +146,7 @@ var DefaultFlags = []cli.Flag{
 	&utils.DisableIPV6,
 	&utils.NoDownloaderFlag,
 	&utils.DownloaderVerifyFlag,
+	&utils.DownloaderHTTPMirrorFlag,
 	&HealthCheckFlag,
 	&utils.HeimdallURLFlag,
 	&utils.WithoutHeimdallFlag,
@@ -396,6 +396,11 @@ func setEmbeddedRpcDaemon(ctx *cli.Context, cfg *nodecfg.Config, logger log.Logg
 		TraceCompatibility:   ctx.Bool(utils.RpcTraceCompatFlag.Name),
 		BatchLimit:           ctx.Int(utils.RpcBatchLimit.Name),
 		ReturnDataLimit:      ctx.Int(utils.RpcReturnDataLimit.Name),
+		JSTracerStepLimit:    ctx.Uint64(utils.RpcJSTracerStepLimit.Name),
+		JSTracerTimeLimit:    ctx.Duration(utils.RpcJSTracerTimeLimit.Name),
+		JSTracerMemoryLimit:  ctx.Uint64(utils.RpcJSTracerMemoryLimit.Name),
+		JSTracersDir:         ctx.String(utils.RpcJSTracersDir.Name),
+		TraceParallelWorkers: ctx.Int(utils.RpcTraceParallelWorkersFlag.Name),
 
 		TxPoolApiAddr: ctx.String(utils.TxpoolApiAddrFlag.Name),
 
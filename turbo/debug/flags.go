@@ -85,6 +85,7 @@ var (
 var Flags = []cli.Flag{
 	&pprofFlag, &pprofAddrFlag, &pprofPortFlag,
 	&cpuprofileFlag, &traceFlag,
+	&otelEndpointFlag, &otelInsecureFlag, &otelSampleRatioFlag,
 }
 
 // SetupCobra sets up logging, profiling and tracing for cobra commands
@@ -119,6 +120,24 @@ func SetupCobra(cmd *cobra.Command, filePrefix string) (log.Logger, error) {
 		}
 	}
 
+	otelEndpoint, err := flags.GetString(otelEndpointFlag.Name)
+	if err != nil {
+		return logger, err
+	}
+	if otelEndpoint != "" {
+		otelInsecure, err := flags.GetBool(otelInsecureFlag.Name)
+		if err != nil {
+			return logger, err
+		}
+		otelSampleRatio, err := flags.GetFloat64(otelSampleRatioFlag.Name)
+		if err != nil {
+			return logger, err
+		}
+		if err := SetupOtel(filePrefix, otelEndpoint, otelInsecure, otelSampleRatio); err != nil {
+			return logger, err
+		}
+	}
+
 	go ListenSignals(nil)
 	pprof, err := flags.GetBool(pprofFlag.Name)
 	if err != nil {
@@ -182,6 +201,12 @@ func Setup(ctx *cli.Context, rootLogger bool) (log.Logger, error) {
 			return logger, err
 		}
 	}
+	if otelEndpoint := ctx.String(otelEndpointFlag.Name); otelEndpoint != "" {
+		if err := SetupOtel("erigon", otelEndpoint, ctx.Bool(otelInsecureFlag.Name), ctx.Float64(otelSampleRatioFlag.Name)); err != nil {
+			return logger, err
+		}
+	}
+
 	pprofEnabled := ctx.Bool(pprofFlag.Name)
 	metricsEnabled := ctx.Bool(metricsEnabledFlag.Name)
 	metricsAddr := ctx.String(metricsAddrFlag.Name)
@@ -235,6 +260,7 @@ func StartPProf(address string, withMetrics bool) {
 func Exit() {
 	_ = Handler.StopCPUProfile()
 	_ = Handler.StopGoTrace()
+	stopOtel()
 }
 
 // RaiseFdLimit raises out the number of allowed file handles per process
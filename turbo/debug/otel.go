@@ -0,0 +1,67 @@
+package debug
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ledgerwatch/log/v3"
+	"github.com/urfave/cli/v2"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+var (
+	otelEndpointFlag = cli.StringFlag{
+		Name:  "otel.endpoint",
+		Usage: "OTLP/HTTP endpoint (host:port) to export the OpenTelemetry traces of the stage loop and the RPC calls to",
+	}
+	otelInsecureFlag = cli.BoolFlag{
+		Name:  "otel.insecure",
+		Usage: "Export the OpenTelemetry traces over plain HTTP",
+	}
+	otelSampleRatioFlag = cli.Float64Flag{
+		Name:  "otel.sample.ratio",
+		Usage: "Ratio of the traces sampled. The traces of the RPC calls sampled by the caller are always sampled",
+		Value: 1,
+	}
+)
+
+// otelShutdown flushes the spans not exported yet, nil if tracing is disabled.
+var otelShutdown func(ctx context.Context) error
+
+// SetupOtel exports the OpenTelemetry traces to the OTLP endpoint, and continues the traces of the
+// incoming HTTP requests from their W3C traceparent header.
+func SetupOtel(serviceName, endpoint string, insecure bool, sampleRatio float64) error {
+	opts := []otlptracehttp.Option{otlptracehttp.WithEndpoint(endpoint)}
+	if insecure {
+		opts = append(opts, otlptracehttp.WithInsecure())
+	}
+	exporter, err := otlptracehttp.New(context.Background(), opts...)
+	if err != nil {
+		return fmt.Errorf("could not create the OTLP exporter: %w", err)
+	}
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewSchemaless(attribute.String("service.name", serviceName))),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(sampleRatio))),
+	)
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+	otelShutdown = provider.Shutdown
+	log.Info("Exporting OpenTelemetry traces", "endpoint", endpoint, "sampleRatio", sampleRatio)
+	return nil
+}
+
+func stopOtel() {
+	if otelShutdown == nil {
+		return
+	}
+	if err := otelShutdown(context.Background()); err != nil {
+		log.Warn("Failed to flush the OpenTelemetry traces", "err", err)
+	}
+	otelShutdown = nil
+}
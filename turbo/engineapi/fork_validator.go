@@ -14,7 +14,9 @@
 package engineapi
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"sync"
 
@@ -26,6 +28,7 @@ import (
 
 	"github.com/ledgerwatch/erigon/common/dbutils"
 	"github.com/ledgerwatch/erigon/common/math"
+	"github.com/ledgerwatch/erigon/core"
 	"github.com/ledgerwatch/erigon/core/rawdb"
 	"github.com/ledgerwatch/erigon/core/types"
 	"github.com/ledgerwatch/erigon/turbo/shards"
@@ -34,6 +37,9 @@ import (
 // the maximum point from the current head, past which side forks are not validated anymore.
 const maxForkDepth = 32 // 32 slots is the duration of an epoch thus there cannot be side forks in PoS deeper than 32 blocks from head.
 
+// the maximum number of invalid blocks and descendants kept in memory, the lowest ones are forgotten first.
+const maxInvalidHeaders = 512
+
 type validatePayloadFunc func(kv.RwTx, *types.Header, *types.RawBody, uint64, []*types.Header, []*types.RawBody, *shards.Notifications) error
 
 type ForkValidator struct {
@@ -41,6 +47,10 @@ type ForkValidator struct {
 	// blocks saved are required to have at most distance maxForkDepth from the head.
 	// if we miss a segment, we only accept the block and give up on full validation.
 	sideForksBlock map[libcommon.Hash]types.RawBlock
+	// Hash => block found invalid or descendant of one, the payloads resent by the CL are not validated again.
+	// the invalid blocks are kept in the db and loaded back on the first validation after a restart.
+	invalidHeaders       map[libcommon.Hash]invalidHeader
+	invalidHeadersLoaded bool
 	// current memory batch containing chain head that extend canonical fork.
 	extendingFork *memdb.MemoryMutation
 	// notifications accumulated for the extending fork
@@ -59,6 +69,7 @@ type ForkValidator struct {
 func NewForkValidatorMock(currentHeight uint64) *ForkValidator {
 	return &ForkValidator{
 		sideForksBlock: make(map[libcommon.Hash]types.RawBlock),
+		invalidHeaders: make(map[libcommon.Hash]invalidHeader),
 		currentHeight:  currentHeight,
 	}
 }
@@ -66,6 +77,7 @@ func NewForkValidatorMock(currentHeight uint64) *ForkValidator {
 func NewForkValidator(currentHeight uint64, validatePayload validatePayloadFunc, tmpDir string) *ForkValidator {
 	return &ForkValidator{
 		sideForksBlock:  make(map[libcommon.Hash]types.RawBlock),
+		invalidHeaders:  make(map[libcommon.Hash]invalidHeader),
 		validatePayload: validatePayload,
 		currentHeight:   currentHeight,
 		tmpDir:          tmpDir,
@@ -148,6 +160,15 @@ func (fv *ForkValidator) ValidatePayload(tx kv.RwTx, header *types.Header, body
 	}
 	defer fv.clean()
 
+	// If the block or one of its ancestors was found invalid, we do not validate it again.
+	var invalid bool
+	if invalid, latestValidHash, validationError, criticalError = fv.invalidAncestor(tx, header); invalid || criticalError != nil {
+		if invalid {
+			status = remote.EngineStatus_INVALID
+		}
+		return
+	}
+
 	// If the block is stored within the side fork it means it was already validated.
 	if _, ok := fv.sideForksBlock[header.Hash()]; ok {
 		status = remote.EngineStatus_VALID
@@ -168,7 +189,11 @@ func (fv *ForkValidator) ValidatePayload(tx kv.RwTx, header *types.Header, body
 		}
 		// Update fork head hash.
 		fv.extendingForkHeadHash = header.Hash()
-		return fv.validateAndStorePayload(fv.extendingFork, header, body, 0, nil, nil, fv.extendingForkNotifications)
+		status, latestValidHash, validationError, criticalError = fv.validateAndStorePayload(fv.extendingFork, header, body, 0, nil, nil, fv.extendingForkNotifications)
+		if validationError != nil {
+			criticalError = fv.reportInvalid(tx, header, body, latestValidHash, validationError)
+		}
+		return
 	}
 
 	// if the block is not in range of maxForkDepth from head then we do not validate it.
@@ -224,7 +249,117 @@ func (fv *ForkValidator) ValidatePayload(tx kv.RwTx, header *types.Header, body
 		Events:      shards.NewEvents(),
 		Accumulator: shards.NewAccumulator(),
 	}
-	return fv.validateAndStorePayload(batch, header, body, unwindPoint, headersChain, bodiesChain, notifications)
+	status, latestValidHash, validationError, criticalError = fv.validateAndStorePayload(batch, header, body, unwindPoint, headersChain, bodiesChain, notifications)
+	if validationError != nil {
+		criticalError = fv.reportInvalid(tx, header, body, latestValidHash, validationError)
+	}
+	return
+}
+
+// ReportInvalid records a block found invalid outside of the payload validation, so that it
+// and its descendants are not validated again.
+func (fv *ForkValidator) ReportInvalid(tx kv.RwTx, header *types.Header, body *types.RawBody, latestValidHash libcommon.Hash, validationError error) error {
+	fv.lock.Lock()
+	defer fv.lock.Unlock()
+	return fv.reportInvalid(tx, header, body, latestValidHash, validationError)
+}
+
+// invalidHeader is a block found invalid, or a descendant of one.
+type invalidHeader struct {
+	number          uint64
+	latestValidHash libcommon.Hash
+	// the invalid block, zero if it is the block itself.
+	invalidAncestor libcommon.Hash
+	validationError error
+}
+
+// invalidAncestor returns whether the block or one of its ancestors is a known invalid block,
+// with the last valid ancestor of the invalid chain. The ancestors are looked up in the side
+// forks and the db, up to maxForkDepth blocks back.
+func (fv *ForkValidator) invalidAncestor(tx kv.Tx, header *types.Header) (invalid bool, latestValidHash libcommon.Hash, validationError error, criticalError error) {
+	if !fv.invalidHeadersLoaded {
+		badBlocks, err := rawdb.ReadBadBlocks(tx)
+		if err != nil {
+			criticalError = err
+			return
+		}
+		for _, badBlock := range badBlocks {
+			fv.addInvalidHeader(badBlock.Header.Hash(), invalidHeader{number: badBlock.Header.Number.Uint64(), latestValidHash: badBlock.LastValidHash, validationError: errors.New(badBlock.ValidationError)})
+		}
+		fv.invalidHeadersLoaded = true
+	}
+	hash := header.Hash()
+	ih, ok := fv.invalidHeaders[hash]
+	if !ok {
+		var ancestor libcommon.Hash
+		if ancestor, ih, ok, criticalError = fv.findInvalidAncestor(tx, header); !ok || criticalError != nil {
+			return
+		}
+		ih = invalidHeader{number: header.Number.Uint64(), latestValidHash: ih.latestValidHash, invalidAncestor: ih.invalidAncestor, validationError: ih.validationError}
+		if ih.invalidAncestor == (libcommon.Hash{}) {
+			ih.invalidAncestor = ancestor
+		}
+		fv.addInvalidHeader(hash, ih)
+	}
+	if ih.invalidAncestor == (libcommon.Hash{}) {
+		return true, ih.latestValidHash, ih.validationError, nil
+	}
+	return true, ih.latestValidHash, fmt.Errorf("links to invalid block %x: %w", ih.invalidAncestor, ih.validationError), nil
+}
+
+// findInvalidAncestor walks back the ancestors of the block until a known invalid one, a
+// canonical block already processed or a missing header.
+func (fv *ForkValidator) findInvalidAncestor(tx kv.Tx, header *types.Header) (libcommon.Hash, invalidHeader, bool, error) {
+	hash, number := header.ParentHash, header.Number.Uint64()
+	for i := 0; i < maxForkDepth && number > 0; i++ {
+		number--
+		if ih, ok := fv.invalidHeaders[hash]; ok {
+			return hash, ih, true, nil
+		}
+		// blocks of the side forks were validated.
+		if _, ok := fv.sideForksBlock[hash]; ok {
+			break
+		}
+		if number <= fv.currentHeight {
+			canonical, err := rawdb.IsCanonicalHash(tx, hash)
+			if err != nil {
+				return libcommon.Hash{}, invalidHeader{}, false, err
+			}
+			if canonical {
+				break
+			}
+		}
+		parent := rawdb.ReadHeader(tx, hash, number)
+		if parent == nil {
+			break
+		}
+		hash = parent.ParentHash
+	}
+	return libcommon.Hash{}, invalidHeader{}, false, nil
+}
+
+// reportInvalid records the block as invalid and keeps it in the db, for restarts and debug_getBadBlocks.
+func (fv *ForkValidator) reportInvalid(tx kv.RwTx, header *types.Header, body *types.RawBody, latestValidHash libcommon.Hash, validationError error) error {
+	fv.addInvalidHeader(header.Hash(), invalidHeader{number: header.Number.Uint64(), latestValidHash: latestValidHash, validationError: validationError})
+	return rawdb.WriteBadBlock(tx, &rawdb.BadBlock{Header: header, Body: body, LastValidHash: latestValidHash, ValidationError: validationError.Error()})
+}
+
+func (fv *ForkValidator) addInvalidHeader(hash libcommon.Hash, ih invalidHeader) {
+	if _, ok := fv.invalidHeaders[hash]; !ok && len(fv.invalidHeaders) >= maxInvalidHeaders {
+		// forget the lowest one, it is validated again if resent
+		var lowest libcommon.Hash
+		var lowestNumber uint64 = math.MaxUint64
+		for h, other := range fv.invalidHeaders {
+			if other.number < lowestNumber || (other.number == lowestNumber && bytes.Compare(h[:], lowest[:]) < 0) {
+				lowest, lowestNumber = h, other.number
+			}
+		}
+		if ih.number < lowestNumber {
+			return
+		}
+		delete(fv.invalidHeaders, lowest)
+	}
+	fv.invalidHeaders[hash] = ih
 }
 
 // Clear wipes out current extending fork data, this method is called after fcu is called,
@@ -270,13 +405,21 @@ func (fv *ForkValidator) validateAndStorePayload(tx kv.RwTx, header *types.Heade
 	validationError = fv.validatePayload(tx, header, body, unwindPoint, headersChain, bodiesChain, notifications)
 	latestValidHash = header.Hash()
 	if validationError != nil {
-		latestValidHash = header.ParentHash
-		status = remote.EngineStatus_INVALID
 		if fv.extendingFork != nil {
 			fv.extendingFork.Rollback()
 			fv.extendingFork = nil
 		}
 		fv.extendingForkHeadHash = libcommon.Hash{}
+		// Only the blocks breaking the consensus rules are invalid, the ones which failed to be
+		// processed for other reasons, like a database error, are validated again if resent.
+		var badBlockErr *core.BadBlockError
+		if !errors.As(validationError, &badBlockErr) {
+			criticalError, validationError = fmt.Errorf("payload %d %x could not be validated: %w", header.Number.Uint64(), header.Hash(), validationError), nil
+			latestValidHash = libcommon.Hash{}
+			return
+		}
+		latestValidHash = header.ParentHash
+		status = remote.EngineStatus_INVALID
 		return
 	}
 	// If we do not have the body we can recover it from the batch.
@@ -298,11 +441,17 @@ func (fv *ForkValidator) validateAndStorePayload(tx kv.RwTx, header *types.Heade
 	return
 }
 
-// clean wipes out all outdated side forks whose distance exceed the height of the head.
+// clean wipes out all outdated side forks whose distance exceed the height of the head,
+// and the invalid blocks too far below the head to be resent.
 func (fv *ForkValidator) clean() {
 	for hash, sb := range fv.sideForksBlock {
 		if math.AbsoluteDifference(fv.currentHeight, sb.Header.Number.Uint64()) > maxForkDepth {
 			delete(fv.sideForksBlock, hash)
 		}
 	}
+	for hash, ih := range fv.invalidHeaders {
+		if ih.number+maxForkDepth < fv.currentHeight {
+			delete(fv.invalidHeaders, hash)
+		}
+	}
 }
@@ -0,0 +1,207 @@
+package engineapi
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/holiman/uint256"
+	"github.com/ledgerwatch/erigon-lib/chain"
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/gointerfaces/remote"
+	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/ledgerwatch/erigon-lib/kv/memdb"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ledgerwatch/erigon/consensus/ethash"
+	"github.com/ledgerwatch/erigon/core"
+	"github.com/ledgerwatch/erigon/core/rawdb"
+	"github.com/ledgerwatch/erigon/core/state"
+	"github.com/ledgerwatch/erigon/core/types"
+	"github.com/ledgerwatch/erigon/core/types/accounts"
+	"github.com/ledgerwatch/erigon/core/vm"
+	"github.com/ledgerwatch/erigon/crypto"
+	"github.com/ledgerwatch/erigon/params"
+	"github.com/ledgerwatch/erigon/turbo/shards"
+)
+
+func TestForkValidatorInvalidAncestor(t *testing.T) {
+	_, tx := memdb.NewTestTx(t)
+	var validations int
+	validatePayload := func(kv.RwTx, *types.Header, *types.RawBody, uint64, []*types.Header, []*types.RawBody, *shards.Notifications) error {
+		validations++
+		return &core.BadBlockError{Number: 1, Err: errors.New("invalid state root")}
+	}
+	fv := NewForkValidator(0, validatePayload, t.TempDir())
+
+	parentHash := libcommon.Hash{1}
+	bad := &types.Header{ParentHash: parentHash, Number: big.NewInt(1)}
+	status, latestValidHash, validationError, criticalError := fv.ValidatePayload(tx, bad, &types.RawBody{}, true)
+	require.NoError(t, criticalError)
+	require.Equal(t, remote.EngineStatus_INVALID, status)
+	require.Equal(t, parentHash, latestValidHash)
+	require.EqualError(t, validationError, "invalid state root")
+
+	// resent payloads and descendants are not validated again
+	status, latestValidHash, validationError, criticalError = fv.ValidatePayload(tx, bad, &types.RawBody{}, true)
+	require.NoError(t, criticalError)
+	require.Equal(t, remote.EngineStatus_INVALID, status)
+	require.Equal(t, parentHash, latestValidHash)
+	require.EqualError(t, validationError, "invalid state root")
+	child := &types.Header{ParentHash: bad.Hash(), Number: big.NewInt(2)}
+	grandChild := &types.Header{ParentHash: child.Hash(), Number: big.NewInt(3)}
+	for _, header := range []*types.Header{child, grandChild} {
+		status, latestValidHash, validationError, criticalError = fv.ValidatePayload(tx, header, &types.RawBody{}, true)
+		require.NoError(t, criticalError)
+		require.Equal(t, remote.EngineStatus_INVALID, status)
+		require.Equal(t, parentHash, latestValidHash)
+		require.ErrorContains(t, validationError, "links to invalid block "+bad.Hash().Hex()[2:])
+	}
+	require.Equal(t, 1, validations)
+
+	// the invalid block is kept in the db across restarts
+	badBlocks, err := rawdb.ReadBadBlocks(tx)
+	require.NoError(t, err)
+	require.Len(t, badBlocks, 1)
+	require.Equal(t, bad.Hash(), badBlocks[0].Header.Hash())
+	require.Equal(t, parentHash, badBlocks[0].LastValidHash)
+	require.Equal(t, "invalid state root", badBlocks[0].ValidationError)
+
+	fv = NewForkValidator(0, validatePayload, t.TempDir())
+	status, latestValidHash, _, criticalError = fv.ValidatePayload(tx, child, &types.RawBody{}, true)
+	require.NoError(t, criticalError)
+	require.Equal(t, remote.EngineStatus_INVALID, status)
+	require.Equal(t, parentHash, latestValidHash)
+	require.Equal(t, 1, validations)
+}
+
+func TestForkValidatorInvalidAncestorInDb(t *testing.T) {
+	_, tx := memdb.NewTestTx(t)
+	validatePayload := func(kv.RwTx, *types.Header, *types.RawBody, uint64, []*types.Header, []*types.RawBody, *shards.Notifications) error {
+		return &core.BadBlockError{Number: 1, Err: errors.New("invalid state root")}
+	}
+	fv := NewForkValidator(0, validatePayload, t.TempDir())
+
+	parentHash := libcommon.Hash{1}
+	bad := &types.Header{ParentHash: parentHash, Number: big.NewInt(1)}
+	_, _, _, criticalError := fv.ValidatePayload(tx, bad, &types.RawBody{}, true)
+	require.NoError(t, criticalError)
+
+	// the child was never sent as a payload, only its header was downloaded
+	child := &types.Header{ParentHash: bad.Hash(), Number: big.NewInt(2)}
+	rawdb.WriteHeader(tx, child)
+	grandChild := &types.Header{ParentHash: child.Hash(), Number: big.NewInt(3)}
+	status, latestValidHash, validationError, criticalError := fv.ValidatePayload(tx, grandChild, &types.RawBody{}, true)
+	require.NoError(t, criticalError)
+	require.Equal(t, remote.EngineStatus_INVALID, status)
+	require.Equal(t, parentHash, latestValidHash)
+	require.ErrorContains(t, validationError, "links to invalid block "+bad.Hash().Hex()[2:])
+}
+
+func TestForkValidatorTransientError(t *testing.T) {
+	_, tx := memdb.NewTestTx(t)
+	var validations int
+	validatePayload := func(kv.RwTx, *types.Header, *types.RawBody, uint64, []*types.Header, []*types.RawBody, *shards.Notifications) error {
+		validations++
+		if validations == 1 {
+			return errors.New("mdbx_txn_begin: MDBX_MAP_FULL")
+		}
+		return nil
+	}
+	fv := NewForkValidator(0, validatePayload, t.TempDir())
+
+	header := &types.Header{ParentHash: libcommon.Hash{1}, Number: big.NewInt(1)}
+	_, _, validationError, criticalError := fv.ValidatePayload(tx, header, &types.RawBody{}, true)
+	require.ErrorContains(t, criticalError, "MDBX_MAP_FULL")
+	require.NoError(t, validationError)
+
+	// the failure was not a consensus one, the payload is validated again when resent
+	status, latestValidHash, validationError, criticalError := fv.ValidatePayload(tx, header, &types.RawBody{}, true)
+	require.NoError(t, criticalError)
+	require.NoError(t, validationError)
+	require.Equal(t, remote.EngineStatus_VALID, status)
+	require.Equal(t, header.Hash(), latestValidHash)
+	require.Equal(t, 2, validations)
+
+	badBlocks, err := rawdb.ReadBadBlocks(tx)
+	require.NoError(t, err)
+	require.Empty(t, badBlocks)
+}
+
+var errStateRead = errors.New("mdbx_cursor_get: MDBX_EIO")
+
+type failingStateReader struct{}
+
+func (failingStateReader) ReadAccountData(libcommon.Address) (*accounts.Account, error) {
+	return nil, errStateRead
+}
+
+func (failingStateReader) ReadAccountStorage(libcommon.Address, uint64, *libcommon.Hash) ([]byte, error) {
+	return nil, errStateRead
+}
+
+func (failingStateReader) ReadAccountCode(libcommon.Address, uint64, libcommon.Hash) ([]byte, error) {
+	return nil, errStateRead
+}
+
+func (failingStateReader) ReadAccountCodeSize(libcommon.Address, uint64, libcommon.Hash) (int, error) {
+	return 0, errStateRead
+}
+
+func (failingStateReader) ReadAccountIncarnation(libcommon.Address) (uint64, error) {
+	return 0, errStateRead
+}
+
+func TestForkValidatorStateReadFailure(t *testing.T) {
+	_, tx := memdb.NewTestTx(t)
+	config := &chain.Config{ChainID: big.NewInt(1), Ethash: new(chain.EthashConfig)}
+	key, _ := crypto.GenerateKey()
+	txn, err := types.SignTx(types.NewTransaction(0, libcommon.Address{1}, uint256.NewInt(1), params.TxGas, uint256.NewInt(1), nil), *types.MakeFrontierSigner(), key)
+	require.NoError(t, err)
+	block := types.NewBlock(&types.Header{ParentHash: libcommon.Hash{1}, Number: big.NewInt(1), GasLimit: params.TxGas, GasUsed: params.TxGas, Difficulty: big.NewInt(1)}, types.Transactions{txn}, nil, nil, nil)
+
+	// the sender can't be read, which would otherwise look like a lack of funds
+	var validations int
+	validatePayload := func(kv.RwTx, *types.Header, *types.RawBody, uint64, []*types.Header, []*types.RawBody, *shards.Notifications) error {
+		validations++
+		getHash := func(uint64) libcommon.Hash { return libcommon.Hash{} }
+		_, err := core.ExecuteBlockEphemerally(config, &vm.Config{}, getHash, ethash.NewFaker(), block, failingStateReader{}, state.NewNoopWriter(), nil, nil)
+		if core.IsBlockValidationError(err) {
+			return &core.BadBlockError{Number: block.NumberU64(), Hash: block.Hash(), Err: err}
+		}
+		return err
+	}
+	fv := NewForkValidator(0, validatePayload, t.TempDir())
+
+	for i := 1; i <= 2; i++ {
+		status, _, validationError, criticalError := fv.ValidatePayload(tx, block.Header(), block.RawBody(), true)
+		require.ErrorIs(t, criticalError, errStateRead)
+		require.NotErrorIs(t, criticalError, core.ErrInsufficientFunds)
+		require.NoError(t, validationError)
+		require.NotEqual(t, remote.EngineStatus_INVALID, status)
+		require.Equal(t, i, validations)
+	}
+
+	badBlocks, err := rawdb.ReadBadBlocks(tx)
+	require.NoError(t, err)
+	require.Empty(t, badBlocks)
+}
+
+func TestForkValidatorInvalidHeadersBounded(t *testing.T) {
+	fv := NewForkValidatorMock(0)
+	for i := uint64(1); i <= maxInvalidHeaders+10; i++ {
+		fv.addInvalidHeader(libcommon.Hash{byte(i), byte(i >> 8)}, invalidHeader{number: i})
+	}
+	require.Len(t, fv.invalidHeaders, maxInvalidHeaders)
+	// the lowest ones were forgotten
+	_, ok := fv.invalidHeaders[libcommon.Hash{10}]
+	require.False(t, ok)
+	_, ok = fv.invalidHeaders[libcommon.Hash{11}]
+	require.True(t, ok)
+
+	fv.currentHeight = 400
+	fv.clean()
+	for _, ih := range fv.invalidHeaders {
+		require.GreaterOrEqual(t, ih.number+maxForkDepth, fv.currentHeight)
+	}
+}
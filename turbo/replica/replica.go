@@ -0,0 +1,249 @@
+// Package replica keeps a chain db following the one of a primary Erigon, to serve the RPC API
+// from a local disk. The blocks and the state written by the stages up to Execution come from
+// the primary, the replica runs the following stages itself.
+package replica
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/gointerfaces"
+	"github.com/ledgerwatch/erigon-lib/gointerfaces/grpcutil"
+	"github.com/ledgerwatch/erigon-lib/gointerfaces/remote"
+	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/ledgerwatch/log/v3"
+	"google.golang.org/grpc"
+
+	"github.com/ledgerwatch/erigon/core/rawdb"
+	"github.com/ledgerwatch/erigon/eth/stagedsync"
+	"github.com/ledgerwatch/erigon/eth/stagedsync/stages"
+	"github.com/ledgerwatch/erigon/turbo/backup"
+)
+
+// errCatchUp is returned when the state changes of the stream can't be applied on top of the
+// replica, which then copies the blocks from the primary.
+var errCatchUp = errors.New("catch up required")
+
+type StateChangesClient interface {
+	StateChanges(ctx context.Context, in *remote.StateChangeRequest, opts ...grpc.CallOption) (remote.KV_StateChangesClient, error)
+}
+
+type Replica struct {
+	db      kv.RwDB
+	primary kv.RoDB
+	client  StateChangesClient
+	sync    *stagedsync.Sync // stagedsync.ReplicaStages
+	tmpdir  string
+	logger  log.Logger
+}
+
+func New(db kv.RwDB, primary kv.RoDB, client StateChangesClient, sync *stagedsync.Sync, tmpdir string, logger log.Logger) *Replica {
+	return &Replica{db: db, primary: primary, client: client, sync: sync, tmpdir: tmpdir, logger: logger}
+}
+
+// Run follows the primary until the context is canceled. The blocks executed by the primary
+// are applied from its state change stream, the replica catches up by copying the changes of
+// the blocks from the db of the primary when the stream misses blocks or has unwinds.
+func (r *Replica) Run(ctx context.Context) error {
+	for {
+		err := r.follow(ctx)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+		if errors.Is(err, backup.ErrFullBackupRequired) {
+			return err
+		}
+		if !grpcutil.IsRetryLater(err) && !grpcutil.IsEndOfStream(err) {
+			r.logger.Warn("[replica] following the primary", "err", err)
+		}
+		time.Sleep(3 * time.Second)
+	}
+}
+
+func (r *Replica) follow(ctx context.Context) error {
+	streamCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	stream, err := r.client.StateChanges(streamCtx, &remote.StateChangeRequest{WithStorage: true, WithTransactions: false}, grpc.WaitForReady(true))
+	if err != nil {
+		return err
+	}
+	// the stream only has the blocks executed after the subscription
+	if err := r.catchUp(ctx); err != nil {
+		return err
+	}
+	for batch, err := stream.Recv(); ; batch, err = stream.Recv() {
+		if err != nil {
+			return err
+		}
+		if batch == nil {
+			return nil
+		}
+		if err := r.apply(ctx, batch.ChangeBatch); err != nil {
+			if !errors.Is(err, errCatchUp) {
+				return err
+			}
+			r.logger.Debug("[replica] stream", "err", err)
+			if err := r.catchUp(ctx); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// apply copies the blocks of the state changes from the primary and applies their state, if
+// they follow the head of the replica.
+func (r *Replica) apply(ctx context.Context, changes []*remote.StateChange) error {
+	tx, err := r.db.BeginRw(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	primaryTx, err := r.primary.BeginRo(ctx)
+	if err != nil {
+		return err
+	}
+	defer primaryTx.Rollback()
+
+	head, err := stages.GetStageProgress(tx, stages.Execution)
+	if err != nil {
+		return err
+	}
+	headHash, err := rawdb.ReadCanonicalHash(tx, head)
+	if err != nil {
+		return err
+	}
+	from := head + 1
+	for _, change := range changes {
+		hash := gointerfaces.ConvertH256ToHash(change.BlockHash)
+		if change.Direction != remote.Direction_FORWARD {
+			return fmt.Errorf("%w: unwind to block %d", errCatchUp, change.BlockHeight)
+		}
+		if change.BlockHeight <= head {
+			canonical, err := rawdb.ReadCanonicalHash(tx, change.BlockHeight)
+			if err != nil {
+				return err
+			}
+			if canonical != hash {
+				return fmt.Errorf("%w: block %d was replaced", errCatchUp, change.BlockHeight)
+			}
+			continue // copied by a catch up
+		}
+		if change.BlockHeight != head+1 {
+			return fmt.Errorf("%w: missing blocks %d-%d", errCatchUp, head+1, change.BlockHeight-1)
+		}
+		if err := backup.ReplicateBlock(primaryTx, tx, change.BlockHeight, hash); err != nil {
+			return fmt.Errorf("%w: %s", errCatchUp, err)
+		}
+		header := rawdb.ReadHeader(tx, hash, change.BlockHeight)
+		if header == nil || header.ParentHash != headHash {
+			return fmt.Errorf("%w: block %d is not a child of block %d", errCatchUp, change.BlockHeight, head)
+		}
+		if err := applyStateChange(tx, change); err != nil {
+			return err
+		}
+		head, headHash = change.BlockHeight, hash
+	}
+	if head < from {
+		return nil
+	}
+	for _, stage := range backup.ReplicatedStages {
+		progress, err := stages.GetStageProgress(tx, stage)
+		if err != nil {
+			return err
+		}
+		if progress >= head && stage != stages.Execution {
+			continue // the headers and bodies copied by a catch up
+		}
+		if err := stages.SaveStageProgress(tx, stage, head); err != nil {
+			return err
+		}
+	}
+	if err := r.runStages(tx); err != nil {
+		// a state root mismatch: the stream missed changes
+		return fmt.Errorf("%w: %s", errCatchUp, err)
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	r.logger.Debug("[replica] applied", "from", from, "to", head)
+	return nil
+}
+
+// catchUp unwinds the replica to its last block in the primary, and copies the following ones.
+func (r *Replica) catchUp(ctx context.Context) error {
+	tx, err := r.db.BeginRw(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	primaryTx, err := r.primary.BeginRo(ctx)
+	if err != nil {
+		return err
+	}
+	defer primaryTx.Rollback()
+
+	head, err := stages.GetStageProgress(tx, stages.Execution)
+	if err != nil {
+		return err
+	}
+	primaryHead, err := stages.GetStageProgress(primaryTx, stages.Execution)
+	if err != nil {
+		return err
+	}
+	ancestor := head
+	if primaryHead < ancestor {
+		ancestor = primaryHead
+	}
+	for ; ancestor > 0; ancestor-- {
+		hash, err := rawdb.ReadCanonicalHash(tx, ancestor)
+		if err != nil {
+			return err
+		}
+		if hash == (libcommon.Hash{}) { // in the snapshots
+			break
+		}
+		primaryHash, err := rawdb.ReadCanonicalHash(primaryTx, ancestor)
+		if err != nil {
+			return err
+		}
+		if hash == primaryHash {
+			break
+		}
+	}
+	if ancestor == head && ancestor == primaryHead {
+		return nil
+	}
+
+	if ancestor < head {
+		r.logger.Info("[replica] unwinding", "from", head, "to", ancestor)
+		r.sync.UnwindTo(ancestor, libcommon.Hash{})
+		if err := r.sync.RunUnwind(r.db, tx); err != nil {
+			return err
+		}
+	}
+	r.logger.Info("[replica] catching up", "from", ancestor+1, "to", primaryHead)
+	if err := backup.Replicate(ctx, primaryTx, tx, ancestor+1, r.tmpdir, r.logger); err != nil {
+		return err
+	}
+	if err := r.runStages(tx); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// runStages runs the stages following Execution on the blocks copied from the primary.
+func (r *Replica) runStages(tx kv.RwTx) error {
+	// a failed run stays at its failed stage
+	if err := r.sync.SetCurrentStage(stages.Execution); err != nil {
+		return err
+	}
+	if err := r.sync.Run(r.db, tx, false /* firstCycle */); err != nil {
+		return err
+	}
+	return r.sync.RunPrune(r.db, tx, false /* firstCycle */)
+}
@@ -0,0 +1,79 @@
+package replica
+
+import (
+	"encoding/binary"
+
+	"github.com/ledgerwatch/erigon-lib/gointerfaces"
+	"github.com/ledgerwatch/erigon-lib/gointerfaces/remote"
+	"github.com/ledgerwatch/erigon-lib/kv"
+
+	"github.com/ledgerwatch/erigon/common/dbutils"
+	"github.com/ledgerwatch/erigon/core/types/accounts"
+	"github.com/ledgerwatch/erigon/crypto"
+)
+
+// applyStateChange writes the state changed by a block into the plain state tables, as the
+// PlainStateWriter of the primary did when executing it.
+func applyStateChange(tx kv.RwTx, change *remote.StateChange) error {
+	for _, ac := range change.Changes {
+		address := gointerfaces.ConvertH160toAddress(ac.Address)
+		switch ac.Action {
+		case remote.Action_UPSERT, remote.Action_UPSERT_CODE:
+			if err := tx.Put(kv.PlainState, address[:], ac.Data); err != nil {
+				return err
+			}
+		case remote.Action_REMOVE:
+			if err := deleteAccount(tx, address[:]); err != nil {
+				return err
+			}
+		}
+		if ac.Action == remote.Action_CODE || ac.Action == remote.Action_UPSERT_CODE {
+			codeHash := crypto.Keccak256Hash(ac.Code)
+			if err := tx.Put(kv.Code, codeHash[:], ac.Code); err != nil {
+				return err
+			}
+			if err := tx.Put(kv.PlainContractCode, dbutils.PlainGenerateStoragePrefix(address[:], ac.Incarnation), codeHash[:]); err != nil {
+				return err
+			}
+		}
+		for _, sc := range ac.StorageChanges {
+			location := gointerfaces.ConvertH256ToHash(sc.Location)
+			k := dbutils.PlainGenerateCompositeStorageKey(address[:], ac.Incarnation, location[:])
+			if len(sc.Data) == 0 {
+				if err := tx.Delete(kv.PlainState, k); err != nil {
+					return err
+				}
+				continue
+			}
+			if err := tx.Put(kv.PlainState, k, sc.Data); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// deleteAccount deletes the account and records its incarnation, the next one of the contract
+// re-created at its address.
+func deleteAccount(tx kv.RwTx, address []byte) error {
+	enc, err := tx.GetOne(kv.PlainState, address)
+	if err != nil {
+		return err
+	}
+	if len(enc) == 0 {
+		return nil
+	}
+	var original accounts.Account
+	if err := original.DecodeForStorage(enc); err != nil {
+		return err
+	}
+	if err := tx.Delete(kv.PlainState, address); err != nil {
+		return err
+	}
+	if original.Incarnation > 0 {
+		var b [8]byte
+		binary.BigEndian.PutUint64(b[:], original.Incarnation)
+		return tx.Put(kv.IncarnationMap, address, b[:])
+	}
+	return nil
+}
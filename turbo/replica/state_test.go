@@ -0,0 +1,70 @@
+package replica
+
+import (
+	"context"
+	"testing"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/gointerfaces/remote"
+	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/ledgerwatch/erigon-lib/kv/memdb"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ledgerwatch/erigon/common/dbutils"
+	"github.com/ledgerwatch/erigon/core/types/accounts"
+	"github.com/ledgerwatch/erigon/crypto"
+	"github.com/ledgerwatch/erigon/turbo/shards"
+)
+
+func TestApplyStateChange(t *testing.T) {
+	_, tx := memdb.NewTestTx(t)
+	a, b := libcommon.Address{1}, libcommon.Address{2}
+	location := libcommon.Hash{3}
+	code := []byte{0x60, 0x00}
+	account := accounts.Account{Nonce: 1, Incarnation: 1}
+	enc := make([]byte, account.EncodingLengthForStorage())
+	account.EncodeForStorage(enc)
+
+	accumulator := shards.NewAccumulator()
+	accumulator.StartChange(1, libcommon.Hash{1}, nil, false)
+	accumulator.ChangeAccount(a, 1, enc)
+	accumulator.ChangeCode(a, 1, code)
+	accumulator.ChangeStorage(a, 1, location, []byte{4})
+	accumulator.ChangeAccount(b, 1, enc)
+	accumulator.StartChange(2, libcommon.Hash{2}, nil, false)
+	accumulator.ChangeStorage(a, 1, location, nil)
+	accumulator.DeleteAccount(b)
+	batch := &remote.StateChangeBatch{}
+	accumulator.SendAndReset(context.Background(), consumer(func(sc *remote.StateChangeBatch) { batch = sc }), 0, 0)
+	require.Len(t, batch.ChangeBatch, 2)
+
+	storageKey := dbutils.PlainGenerateCompositeStorageKey(a[:], 1, location[:])
+	require.NoError(t, applyStateChange(tx, batch.ChangeBatch[0]))
+	v, err := tx.GetOne(kv.PlainState, a[:])
+	require.NoError(t, err)
+	require.Equal(t, enc, v)
+	codeHash, err := tx.GetOne(kv.PlainContractCode, dbutils.PlainGenerateStoragePrefix(a[:], 1))
+	require.NoError(t, err)
+	require.Equal(t, crypto.Keccak256(code), codeHash)
+	v, err = tx.GetOne(kv.Code, codeHash)
+	require.NoError(t, err)
+	require.Equal(t, code, v)
+	v, err = tx.GetOne(kv.PlainState, storageKey)
+	require.NoError(t, err)
+	require.Equal(t, []byte{4}, v)
+
+	require.NoError(t, applyStateChange(tx, batch.ChangeBatch[1]))
+	v, err = tx.GetOne(kv.PlainState, storageKey)
+	require.NoError(t, err)
+	require.Nil(t, v)
+	v, err = tx.GetOne(kv.PlainState, b[:])
+	require.NoError(t, err)
+	require.Nil(t, v)
+	v, err = tx.GetOne(kv.IncarnationMap, b[:])
+	require.NoError(t, err)
+	require.Equal(t, []byte{0, 0, 0, 0, 0, 0, 0, 1}, v)
+}
+
+type consumer func(sc *remote.StateChangeBatch)
+
+func (c consumer) SendStateChanges(_ context.Context, sc *remote.StateChangeBatch) { c(sc) }
@@ -1046,6 +1046,7 @@ func chooseSegmentEnd(from, to, blocksPerFile uint64) uint64 {
 
 type BlockRetire struct {
 	working               atomic.Bool
+	verifying             atomic.Bool
 	needSaveFilesListInDB atomic.Bool
 
 	workers   int
@@ -2,11 +2,14 @@ package snapshotsync
 
 import (
 	"context"
+	"math/big"
+	"os"
 	"path/filepath"
 	"testing"
 	"testing/fstest"
 
 	"github.com/holiman/uint256"
+	"github.com/ledgerwatch/erigon-lib/common/background"
 	"github.com/ledgerwatch/erigon-lib/compress"
 	"github.com/ledgerwatch/erigon-lib/downloader/snaptype"
 	"github.com/ledgerwatch/erigon-lib/recsplit"
@@ -14,8 +17,10 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/ledgerwatch/erigon/common/math"
+	"github.com/ledgerwatch/erigon/core/types"
 	"github.com/ledgerwatch/erigon/eth/ethconfig"
 	"github.com/ledgerwatch/erigon/params/networkname"
+	"github.com/ledgerwatch/erigon/rlp"
 	"github.com/ledgerwatch/erigon/turbo/snapshotsync/snapcfg"
 )
 
@@ -240,3 +245,68 @@ func TestParseCompressedFileName(t *testing.T) {
 	require.Equal(1_000, int(f.From))
 	require.Equal(2_000, int(f.To))
 }
+
+func createTestHeadersSegment(t *testing.T, dir string, from, to uint64, logger log.Logger, corrupt func(i uint64, h *types.Header)) string {
+	segPath := filepath.Join(dir, snaptype.SegmentFileName(from, to, snaptype.Headers))
+	c, err := compress.NewCompressor(context.Background(), "test", segPath, dir, 100, 1, log.LvlDebug, logger)
+	require.NoError(t, err)
+	defer c.Close()
+	for i := from; i < to; i++ {
+		h := &types.Header{Number: new(big.Int).SetUint64(i), Difficulty: big.NewInt(1)}
+		headerRlp, err := rlp.EncodeToBytes(h)
+		require.NoError(t, err)
+		word := append([]byte{h.Hash()[0]}, headerRlp...)
+		if corrupt != nil {
+			corrupt(i, h)
+			headerRlp, err = rlp.EncodeToBytes(h)
+			require.NoError(t, err)
+			word = append(word[:1], headerRlp...)
+		}
+		require.NoError(t, c.AddWord(word))
+	}
+	require.NoError(t, c.Compress())
+	require.NoError(t, HeadersIdx(context.Background(), segPath, from, dir, &background.Progress{}, log.LvlDebug, logger))
+	return segPath
+}
+
+func TestVerifySegments(t *testing.T) {
+	logger := log.New()
+	dir, require := t.TempDir(), require.New(t)
+
+	createTestHeadersSegment(t, dir, 0, 1_000, logger, nil)
+	problems, err := VerifySegments(context.Background(), dir, nil, uint256.Int{}, 1, logger)
+	require.NoError(err)
+	require.Empty(problems)
+
+	// a header whose first byte of hash doesn't match
+	createTestHeadersSegment(t, dir, 1_000, 2_000, logger, func(i uint64, h *types.Header) {
+		if i == 1_500 {
+			h.Extra = []byte{1}
+		}
+	})
+	problems, err = VerifySegments(context.Background(), dir, nil, uint256.Int{}, 1, logger)
+	require.NoError(err)
+	require.Len(problems, 1)
+	corrupted := snaptype.SegmentFileName(1_000, 2_000, snaptype.Headers)
+	require.Equal(corrupted, problems[0].File)
+
+	// an index built for another segment
+	idxName := snaptype.IdxFileName(0, 1_000, snaptype.Headers.String())
+	require.NoError(os.Remove(filepath.Join(dir, idxName)))
+	other := t.TempDir()
+	createTestHeadersSegment(t, other, 0, 1_000, logger, func(i uint64, h *types.Header) { h.Time = 1 })
+	require.NoError(os.Rename(filepath.Join(other, idxName), filepath.Join(dir, idxName)))
+	problems, err = VerifySegments(context.Background(), dir, nil, uint256.Int{}, 1, logger)
+	require.NoError(err)
+	require.Len(problems, 2)
+	require.True(problems[0].IndexOnly())
+	require.False(problems[1].IndexOnly())
+
+	quarantined, err := HealSegments(dir, problems)
+	require.NoError(err)
+	require.Equal([]string{corrupted}, quarantined)
+	require.FileExists(filepath.Join(dir, QuarantineDir, corrupted))
+	require.NoFileExists(filepath.Join(dir, corrupted))
+	require.NoFileExists(filepath.Join(dir, idxName))
+	require.FileExists(filepath.Join(dir, snaptype.SegmentFileName(0, 1_000, snaptype.Headers)))
+}
@@ -0,0 +1,534 @@
+package snapshotsync
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/anacrolix/torrent/bencode"
+	"github.com/anacrolix/torrent/metainfo"
+	"github.com/holiman/uint256"
+	common2 "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/common/dbg"
+	"github.com/ledgerwatch/erigon-lib/compress"
+	"github.com/ledgerwatch/erigon-lib/downloader/downloadercfg"
+	"github.com/ledgerwatch/erigon-lib/downloader/snaptype"
+	"github.com/ledgerwatch/erigon-lib/recsplit"
+	types2 "github.com/ledgerwatch/erigon-lib/types"
+	"github.com/ledgerwatch/log/v3"
+	"golang.org/x/exp/slices"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/ledgerwatch/erigon/core/types"
+	"github.com/ledgerwatch/erigon/crypto"
+	"github.com/ledgerwatch/erigon/crypto/cryptopool"
+	"github.com/ledgerwatch/erigon/rlp"
+	"github.com/ledgerwatch/erigon/turbo/snapshotsync/snapcfg"
+)
+
+// QuarantineDir is the sub-directory of the snapshots dir where the corrupted segments are moved
+const QuarantineDir = "quarantine"
+
+// ErrIndexMismatch tells that the indices of a segment don't match its records, while the
+// segment itself is fine: the indices can be removed and built again.
+var ErrIndexMismatch = errors.New("index does not match segment")
+
+// SegmentProblem is a problem found in a segment file, or only in its indices if
+// errors.Is(Err, ErrIndexMismatch).
+type SegmentProblem struct {
+	File string // name of the .seg file
+	Err  error
+}
+
+func (p SegmentProblem) IndexOnly() bool { return errors.Is(p.Err, ErrIndexMismatch) }
+
+// VerifySegments checks the block segments of the snapshots dir: their torrent hashes if they
+// are preverified, the decoding of all their records and the consistency of the records with
+// each other and with the indices. It returns the problems found, sorted by file name.
+func VerifySegments(ctx context.Context, snapDir string, preverified snapcfg.Preverified, chainID uint256.Int, workers int, logger log.Logger) ([]SegmentProblem, error) {
+	segments, err := snaptype.Segments(snapDir)
+	if err != nil {
+		return nil, err
+	}
+	hashes := make(map[string]string, len(preverified))
+	for _, p := range preverified {
+		hashes[p.Name] = p.Hash
+	}
+
+	var (
+		lock     sync.Mutex
+		problems []SegmentProblem
+		checked  int
+	)
+	logEvery := time.NewTicker(20 * time.Second)
+	defer logEvery.Stop()
+
+	g, gCtx := errgroup.WithContext(ctx)
+	g.SetLimit(workers)
+	for i := range segments {
+		f := segments[i]
+		g.Go(func() error {
+			_, fName := filepath.Split(f.Path)
+			err := verifySegment(gCtx, snapDir, f, hashes[fName], chainID)
+			if gCtx.Err() != nil {
+				return gCtx.Err()
+			}
+			lock.Lock()
+			defer lock.Unlock()
+			checked++
+			if err != nil {
+				logger.Warn("[snapshots] corrupted segment", "file", fName, "err", err)
+				problems = append(problems, SegmentProblem{File: fName, Err: err})
+			}
+			select {
+			case <-logEvery.C:
+				logger.Info("[snapshots] Verifying", "checked", fmt.Sprintf("%d/%d", checked, len(segments)), "problems", len(problems))
+			default:
+			}
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+	slices.SortFunc(problems, func(a, b SegmentProblem) bool { return a.File < b.File })
+	return problems, nil
+}
+
+func verifySegment(ctx context.Context, snapDir string, f snaptype.FileInfo, torrentHash string, chainID uint256.Int) (err error) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			err = fmt.Errorf("%v, %s", rec, dbg.Stack())
+		}
+	}()
+	if torrentHash != "" {
+		if err := verifyTorrentHash(f.Path, torrentHash); err != nil {
+			return err
+		}
+	}
+	switch f.T {
+	case snaptype.Headers:
+		return verifyHeadersSegment(ctx, snapDir, f)
+	case snaptype.Bodies:
+		return verifyBodiesSegment(ctx, snapDir, f)
+	case snaptype.Transactions:
+		return verifyTxsSegment(ctx, snapDir, f, chainID)
+	}
+	return nil
+}
+
+// verifyTorrentHash checks the info hash of the file against the preverified one.
+func verifyTorrentHash(segmentPath, expected string) error {
+	_, fName := filepath.Split(segmentPath)
+	have, err := TorrentInfoHash(segmentPath, fName)
+	if err != nil {
+		return err
+	}
+	if have != expected {
+		return fmt.Errorf("torrent hash mismatch: have %s, want %s", have, expected)
+	}
+	return nil
+}
+
+// TorrentInfoHash returns the info hash of the file in hex, computed like the downloader does
+// when it creates a .torrent file for it under the given name.
+func TorrentInfoHash(filePath, name string) (string, error) {
+	info := &metainfo.Info{PieceLength: downloadercfg.DefaultPieceSize}
+	if err := info.BuildFromFilePath(filePath); err != nil {
+		return "", err
+	}
+	info.Name = name
+	infoBytes, err := bencode.Marshal(info)
+	if err != nil {
+		return "", err
+	}
+	mi := metainfo.MetaInfo{InfoBytes: infoBytes}
+	return mi.HashInfoBytes().String(), nil
+}
+
+// openIndex opens the index of the segment, or returns nil if it doesn't exist yet or is
+// older than the segment, in which case it will be built again anyway.
+func openIndex(snapDir string, f snaptype.FileInfo, t snaptype.Type, d *compress.Decompressor) (*recsplit.Index, error) {
+	idx, err := recsplit.OpenIndex(filepath.Join(snapDir, snaptype.IdxFileName(f.From, f.To, t.String())))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("%w: %v", ErrIndexMismatch, err)
+	}
+	if idx.ModTime().Before(d.ModTime()) {
+		idx.Close()
+		return nil, nil
+	}
+	return idx, nil
+}
+
+func verifyHeadersSegment(ctx context.Context, snapDir string, f snaptype.FileInfo) error {
+	d, err := compress.NewDecompressor(f.Path)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+	if uint64(d.Count()) != f.To-f.From {
+		return fmt.Errorf("have %d headers, want %d", d.Count(), f.To-f.From)
+	}
+	idx, err := openIndex(snapDir, f, snaptype.Headers, d)
+	if err != nil {
+		return err
+	}
+	var reader *recsplit.IndexReader
+	if idx != nil {
+		defer idx.Close()
+		reader = recsplit.NewIndexReader(idx)
+	}
+
+	hasher := crypto.NewKeccakState()
+	defer cryptopool.ReturnToPoolKeccak256(hasher)
+	var h common2.Hash
+	var indexErr error
+	r := bytes.NewReader(nil)
+	word := make([]byte, 0, 4096)
+
+	defer d.EnableReadAhead().DisableReadAhead()
+	g := d.MakeGetter()
+	var i, offset, nextPos uint64
+	for g.HasNext() {
+		word, nextPos = g.Next(word[:0])
+		blockNum := f.From + i
+		if len(word) == 0 {
+			return fmt.Errorf("empty header %d", blockNum)
+		}
+		var header types.Header
+		r.Reset(word[1:])
+		if err := rlp.Decode(r, &header); err != nil {
+			return fmt.Errorf("header %d: %w", blockNum, err)
+		}
+		if header.Number.Uint64() != blockNum {
+			return fmt.Errorf("header %d has number %d", blockNum, header.Number.Uint64())
+		}
+		hasher.Reset()
+		hasher.Write(word[1:])
+		hasher.Read(h[:])
+		if word[0] != h[0] {
+			return fmt.Errorf("header %d: first byte of hash %x, want %x", blockNum, word[0], h[0])
+		}
+		if idx != nil && indexErr == nil {
+			if o := idx.OrdinalLookup(i); o != offset {
+				indexErr = fmt.Errorf("%w: header %d at offset %d, index has %d", ErrIndexMismatch, blockNum, offset, o)
+			} else if id := reader.Lookup(h[:]); id != i {
+				indexErr = fmt.Errorf("%w: hash of header %d maps to %d", ErrIndexMismatch, blockNum, f.From+id)
+			}
+		}
+		i++
+		offset = nextPos
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+	}
+	return indexErr
+}
+
+func verifyBodiesSegment(ctx context.Context, snapDir string, f snaptype.FileInfo) error {
+	d, err := compress.NewDecompressor(f.Path)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+	if uint64(d.Count()) != f.To-f.From {
+		return fmt.Errorf("have %d bodies, want %d", d.Count(), f.To-f.From)
+	}
+	idx, err := openIndex(snapDir, f, snaptype.Bodies, d)
+	if err != nil {
+		return err
+	}
+	if idx != nil {
+		defer idx.Close()
+	}
+
+	var indexErr error
+	var nextTxID uint64
+	word := make([]byte, 0, 4096)
+
+	defer d.EnableReadAhead().DisableReadAhead()
+	g := d.MakeGetter()
+	var i, offset, nextPos uint64
+	for g.HasNext() {
+		word, nextPos = g.Next(word[:0])
+		blockNum := f.From + i
+		var body types.BodyForStorage
+		if err := rlp.DecodeBytes(word, &body); err != nil {
+			return fmt.Errorf("body %d: %w", blockNum, err)
+		}
+		if i > 0 && body.BaseTxId != nextTxID {
+			return fmt.Errorf("body %d: base tx id %d, want %d", blockNum, body.BaseTxId, nextTxID)
+		}
+		nextTxID = body.BaseTxId + uint64(body.TxAmount)
+		if idx != nil && indexErr == nil {
+			if o := idx.OrdinalLookup(i); o != offset {
+				indexErr = fmt.Errorf("%w: body %d at offset %d, index has %d", ErrIndexMismatch, blockNum, offset, o)
+			}
+		}
+		i++
+		offset = nextPos
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+	}
+	return indexErr
+}
+
+// verifyTxsSegment decodes the transactions of the segment, checks their amount against the
+// bodies of the same range, and the indices by hash and to the block numbers.
+func verifyTxsSegment(ctx context.Context, snapDir string, f snaptype.FileInfo, chainID uint256.Int) error {
+	bodies, err := compress.NewDecompressor(filepath.Join(snapDir, snaptype.SegmentFileName(f.From, f.To, snaptype.Bodies)))
+	if err != nil {
+		return fmt.Errorf("bodies of the range: %w", err)
+	}
+	defer bodies.Close()
+	d, err := compress.NewDecompressor(f.Path)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+
+	idx, err := openIndex(snapDir, f, snaptype.Transactions, d)
+	if err != nil {
+		return err
+	}
+	var reader *recsplit.IndexReader
+	if idx != nil {
+		defer idx.Close()
+		reader = recsplit.NewIndexReader(idx)
+	}
+	idx2Block, err := openIndex(snapDir, f, snaptype.Transactions2Block, d)
+	if err != nil {
+		return err
+	}
+	var reader2Block *recsplit.IndexReader
+	if idx2Block != nil {
+		defer idx2Block.Close()
+		reader2Block = recsplit.NewIndexReader(idx2Block)
+	}
+
+	parseCtx := types2.NewTxParseContext(chainID)
+	parseCtx.WithSender(false)
+	slot := types2.TxSlot{}
+	var indexErr error
+	bodyBuf, word := make([]byte, 0, 4096), make([]byte, 0, 4096)
+
+	defer d.EnableReadAhead().DisableReadAhead()
+	defer bodies.EnableReadAhead().DisableReadAhead()
+	g, bodyGetter := d.MakeGetter(), bodies.MakeGetter()
+	blockNum := f.From
+	body := &types.BodyForStorage{}
+	if !bodyGetter.HasNext() {
+		return errors.New("no bodies in the range")
+	}
+	bodyBuf, _ = bodyGetter.Next(bodyBuf[:0])
+	if err := rlp.DecodeBytes(bodyBuf, body); err != nil {
+		return fmt.Errorf("bodies of the range: %w", err)
+	}
+	firstTxID := body.BaseTxId
+
+	var i, offset, nextPos uint64
+	for g.HasNext() {
+		word, nextPos = g.Next(word[:0])
+		for body.BaseTxId+uint64(body.TxAmount) <= firstTxID+i { // skip empty blocks
+			if !bodyGetter.HasNext() {
+				return fmt.Errorf("more transactions than in the bodies of the range: %d", d.Count())
+			}
+			bodyBuf, _ = bodyGetter.Next(bodyBuf[:0])
+			if err := rlp.DecodeBytes(bodyBuf, body); err != nil {
+				return fmt.Errorf("bodies of the range: %w", err)
+			}
+			blockNum++
+		}
+
+		isSystemTx := len(word) == 0
+		if isSystemTx { // system-txs hash:pad32(txnID)
+			binary.BigEndian.PutUint64(slot.IDHash[:], firstTxID+i)
+		} else {
+			firstTxByteAndlengthOfAddress := 21
+			if len(word) < firstTxByteAndlengthOfAddress {
+				return fmt.Errorf("transaction %d in block %d: too short", firstTxID+i, blockNum)
+			}
+			if _, err := parseCtx.ParseTransaction(word[firstTxByteAndlengthOfAddress:], 0, &slot, nil, true /* hasEnvelope */, nil /* validateHash */); err != nil {
+				return fmt.Errorf("transaction %d in block %d: %w", firstTxID+i, blockNum, err)
+			}
+			if word[0] != slot.IDHash[0] {
+				return fmt.Errorf("transaction %d in block %d: first byte of hash %x, want %x", firstTxID+i, blockNum, word[0], slot.IDHash[0])
+			}
+		}
+		if indexErr == nil {
+			if idx != nil {
+				if o := idx.OrdinalLookup(i); o != offset {
+					indexErr = fmt.Errorf("%w: transaction %d at offset %d, index has %d", ErrIndexMismatch, firstTxID+i, offset, o)
+				} else if id := reader.Lookup(slot.IDHash[:]); id != i {
+					indexErr = fmt.Errorf("%w: hash of transaction %d maps to %d", ErrIndexMismatch, firstTxID+i, firstTxID+id)
+				}
+			}
+			if idx2Block != nil && indexErr == nil {
+				if n := reader2Block.Lookup(slot.IDHash[:]); n != blockNum {
+					indexErr = fmt.Errorf("%w: transaction %d of block %d maps to block %d", ErrIndexMismatch, firstTxID+i, blockNum, n)
+				}
+			}
+		}
+		i++
+		offset = nextPos
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+	}
+	for bodyGetter.HasNext() {
+		bodyBuf, _ = bodyGetter.Next(bodyBuf[:0])
+		if err := rlp.DecodeBytes(bodyBuf, body); err != nil {
+			return fmt.Errorf("bodies of the range: %w", err)
+		}
+	}
+	if expected := body.BaseTxId + uint64(body.TxAmount) - firstTxID; i != expected {
+		return fmt.Errorf("have %d transactions, bodies of the range have %d", i, expected)
+	}
+	return indexErr
+}
+
+// segmentFiles returns the names of the files of the segment in the snapshots dir: the segment
+// itself, its indices and its .torrent file.
+func segmentFiles(snapDir, fName string) (seg string, indices []string, torrent string, err error) {
+	f, err := snaptype.ParseFileName(snapDir, fName)
+	if err != nil {
+		return "", nil, "", err
+	}
+	indices = []string{snaptype.IdxFileName(f.From, f.To, f.T.String())}
+	if f.T == snaptype.Transactions {
+		indices = append(indices, snaptype.IdxFileName(f.From, f.To, snaptype.Transactions2Block.String()))
+	}
+	return fName, indices, fName + ".torrent", nil
+}
+
+// RemoveSegmentIndices removes the indices of the segment, to build them again.
+func RemoveSegmentIndices(snapDir, fName string) error {
+	_, indices, _, err := segmentFiles(snapDir, fName)
+	if err != nil {
+		return err
+	}
+	for _, name := range indices {
+		if err := os.Remove(filepath.Join(snapDir, name)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+	return nil
+}
+
+// QuarantineSegment moves the segment, its indices and its .torrent file to the quarantine
+// dir, so that the segment gets downloaded again.
+func QuarantineSegment(snapDir, fName string) error {
+	seg, indices, torrent, err := segmentFiles(snapDir, fName)
+	if err != nil {
+		return err
+	}
+	quarantine := filepath.Join(snapDir, QuarantineDir)
+	if err := os.MkdirAll(quarantine, 0755); err != nil {
+		return err
+	}
+	for _, name := range append([]string{seg, torrent}, indices...) {
+		if err := os.Rename(filepath.Join(snapDir, name), filepath.Join(quarantine, name)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+	return nil
+}
+
+// HealSegments removes the indices of the segments which only have wrong indices, and
+// quarantines the others. It returns the names of the quarantined segments.
+func HealSegments(snapDir string, problems []SegmentProblem) (quarantined []string, err error) {
+	for _, p := range problems {
+		if p.IndexOnly() {
+			if err := RemoveSegmentIndices(snapDir, p.File); err != nil {
+				return quarantined, err
+			}
+			continue
+		}
+		if err := QuarantineSegment(snapDir, p.File); err != nil {
+			return quarantined, err
+		}
+		quarantined = append(quarantined, p.File)
+	}
+	return quarantined, nil
+}
+
+// VerifyInBackground verifies the segments once, in the background. The corrupted segments are
+// closed, quarantined and requested again from the downloader; the segments with wrong indices
+// are closed too, and their indices are built again on the next start.
+func (br *BlockRetire) VerifyInBackground(ctx context.Context, preverified snapcfg.Preverified, chainID uint256.Int) {
+	if !br.verifying.CompareAndSwap(false, true) {
+		return
+	}
+	go func() {
+		logger := br.logger
+		snapshots := br.Snapshots()
+		logger.Info("[snapshots] Verifying segments in the background")
+		problems, err := VerifySegments(ctx, snapshots.Dir(), preverified, chainID, 1, logger)
+		if err != nil {
+			if !errors.Is(err, context.Canceled) {
+				logger.Warn("[snapshots] verify segments", "err", err)
+			}
+			return
+		}
+		if len(problems) == 0 {
+			logger.Info("[snapshots] Segments verified")
+			return
+		}
+
+		// stop serving the bad segments before moving their files
+		bad := make(map[string]bool, len(problems))
+		for _, p := range problems {
+			bad[p.File] = true
+		}
+		var keep []string
+		for _, fName := range snapshots.Files() {
+			if !bad[fName] {
+				keep = append(keep, fName)
+			}
+		}
+		if err := snapshots.ReopenList(keep, true); err != nil {
+			logger.Warn("[snapshots] close corrupted segments", "err", err)
+			return
+		}
+		quarantined, err := HealSegments(snapshots.Dir(), problems)
+		if err != nil {
+			logger.Warn("[snapshots] heal segments", "err", err)
+		}
+		logger.Warn("[snapshots] Corrupted segments closed", "quarantined", strings.Join(quarantined, ","), "problems", len(problems))
+		if len(quarantined) == 0 || br.downloader == nil || reflect.ValueOf(br.downloader).IsNil() {
+			return
+		}
+		hashes := make(map[string]string, len(preverified))
+		for _, p := range preverified {
+			hashes[p.Name] = p.Hash
+		}
+		downloadRequest := make([]DownloadRequest, 0, len(quarantined))
+		for _, fName := range quarantined {
+			downloadRequest = append(downloadRequest, NewDownloadRequest(nil, fName, hashes[fName]))
+		}
+		if err := RequestSnapshotsDownload(ctx, downloadRequest, br.downloader); err != nil {
+			logger.Warn("[snapshots] request download of quarantined segments", "err", err)
+		}
+	}()
+}
@@ -0,0 +1,371 @@
+// Package httpdownloader downloads snapshots from an HTTP(S) mirror, for networks where
+// BitTorrent is not available. It implements the Downloader gRPC service, so it can replace
+// the BitTorrent downloader within Erigon or in cmd/downloader.
+package httpdownloader
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ledgerwatch/erigon-lib/gointerfaces"
+	proto_downloader "github.com/ledgerwatch/erigon-lib/gointerfaces/downloader"
+	"github.com/ledgerwatch/log/v3"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	"github.com/ledgerwatch/erigon/turbo/snapshotsync"
+)
+
+// partSuffix is appended to the name of the files being downloaded
+const partSuffix = ".part"
+
+// DefaultWorkers is the number of files downloaded in parallel by default
+const DefaultWorkers = 3
+
+var retryInterval = 10 * time.Second
+
+type file struct {
+	path      string // relative to the snapshots dir
+	hash      string // torrent info hash in hex, empty if unknown
+	total     atomic.Int64
+	completed atomic.Int64
+	done      atomic.Bool
+}
+
+// Downloader fetches the requested files from the mirror, resuming partial downloads with
+// range requests, and verifies them against their torrent hashes when they are known.
+type Downloader struct {
+	proto_downloader.UnimplementedDownloaderServer
+
+	mirror *url.URL
+	dir    string
+	client *http.Client
+	logger log.Logger
+
+	lock  sync.Mutex
+	files map[string]*file
+	queue chan *file
+
+	statsLock     sync.Mutex
+	lastStatsTime time.Time
+	lastStatsSize int64
+}
+
+// New creates a Downloader storing the files into snapDir, and starts its workers.
+func New(ctx context.Context, mirror string, snapDir string, workers int, logger log.Logger) (*Downloader, error) {
+	u, err := url.Parse(mirror)
+	if err != nil {
+		return nil, fmt.Errorf("invalid snapshots mirror: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("invalid snapshots mirror %q: scheme must be http or https", mirror)
+	}
+	if workers <= 0 {
+		workers = 1
+	}
+	d := &Downloader{
+		mirror: u,
+		dir:    snapDir,
+		client: &http.Client{Transport: &http.Transport{Proxy: http.ProxyFromEnvironment, ResponseHeaderTimeout: time.Minute}},
+		logger: logger,
+		files:  map[string]*file{},
+		queue:  make(chan *file, 16*1024),
+	}
+	for i := 0; i < workers; i++ {
+		go d.worker(ctx)
+	}
+	return d, nil
+}
+
+// Download implements the Downloader service: it queues the files not present yet, and
+// returns immediately. Items without a path (ranges of blocks to seed) are ignored.
+func (d *Downloader) Download(ctx context.Context, request *proto_downloader.DownloadRequest) (*emptypb.Empty, error) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	for _, it := range request.Items {
+		if it.Path == "" || d.files[it.Path] != nil {
+			continue
+		}
+		if !isLocalPath(it.Path) {
+			return nil, fmt.Errorf("invalid path %q", it.Path)
+		}
+		f := &file{path: it.Path}
+		if it.TorrentHash != nil {
+			addr := gointerfaces.ConvertH160toAddress(it.TorrentHash)
+			f.hash = fmt.Sprintf("%x", addr[:])
+		}
+		d.files[it.Path] = f
+		if st, err := os.Stat(filepath.Join(d.dir, it.Path)); err == nil {
+			// existing files are not downloaded again, like with BitTorrent
+			f.total.Store(st.Size())
+			f.completed.Store(st.Size())
+			f.done.Store(true)
+			continue
+		}
+		select {
+		case d.queue <- f:
+		default:
+			return nil, errors.New("too many files requested")
+		}
+	}
+	return &emptypb.Empty{}, nil
+}
+
+// Delete implements the Downloader service by removing the files.
+func (d *Downloader) Delete(ctx context.Context, request *proto_downloader.DeleteRequest) (*emptypb.Empty, error) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	for _, p := range request.Paths {
+		if !isLocalPath(p) {
+			return nil, fmt.Errorf("invalid path %q", p)
+		}
+		if f := d.files[p]; f != nil && !f.done.Load() {
+			return nil, fmt.Errorf("can't delete %s while downloading it", p)
+		}
+		delete(d.files, p)
+		if err := os.Remove(filepath.Join(d.dir, p)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return nil, err
+		}
+	}
+	return &emptypb.Empty{}, nil
+}
+
+// Verify implements the Downloader service by hashing again the downloaded files whose torrent
+// hashes are known. The files not matching are removed, to be downloaded on next start.
+func (d *Downloader) Verify(ctx context.Context, request *proto_downloader.VerifyRequest) (*emptypb.Empty, error) {
+	d.lock.Lock()
+	files := make([]*file, 0, len(d.files))
+	for _, f := range d.files {
+		if f.done.Load() && f.hash != "" {
+			files = append(files, f)
+		}
+	}
+	d.lock.Unlock()
+
+	var corrupted []string
+	for _, f := range files {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		fPath := filepath.Join(d.dir, f.path)
+		if err := d.verify(fPath, f); err != nil {
+			d.logger.Warn("[snapshots] removing corrupted file", "file", f.path, "err", err)
+			if err := os.Remove(fPath); err != nil {
+				return nil, err
+			}
+			corrupted = append(corrupted, f.path)
+		}
+	}
+	if len(corrupted) > 0 {
+		return nil, fmt.Errorf("corrupted files removed, restart to download them again: %s", strings.Join(corrupted, ", "))
+	}
+	return &emptypb.Empty{}, nil
+}
+
+// Stats implements the Downloader service. There are no torrent metadata to wait for, nor peers.
+func (d *Downloader) Stats(ctx context.Context, request *proto_downloader.StatsRequest) (*proto_downloader.StatsResponse, error) {
+	d.lock.Lock()
+	stats := &proto_downloader.StatsResponse{Completed: true}
+	for _, f := range d.files {
+		stats.FilesTotal++
+		stats.BytesCompleted += uint64(f.completed.Load())
+		if total := f.total.Load(); total > 0 {
+			stats.BytesTotal += uint64(total)
+		}
+		stats.Completed = stats.Completed && f.done.Load()
+	}
+	d.lock.Unlock()
+	stats.MetadataReady = stats.FilesTotal
+	if stats.BytesTotal > 0 {
+		stats.Progress = float32(float64(stats.BytesCompleted) * 100 / float64(stats.BytesTotal))
+	}
+	if stats.Completed {
+		stats.Progress = 100
+	}
+
+	d.statsLock.Lock()
+	defer d.statsLock.Unlock()
+	now := time.Now()
+	if !d.lastStatsTime.IsZero() {
+		if interval := now.Sub(d.lastStatsTime).Seconds(); interval > 0 && int64(stats.BytesCompleted) > d.lastStatsSize {
+			stats.DownloadRate = uint64(float64(int64(stats.BytesCompleted)-d.lastStatsSize) / interval)
+		}
+	}
+	d.lastStatsTime, d.lastStatsSize = now, int64(stats.BytesCompleted)
+	return stats, nil
+}
+
+func (d *Downloader) worker(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case f := <-d.queue:
+			for {
+				err := d.download(ctx, f)
+				if err == nil {
+					break
+				}
+				if ctx.Err() != nil {
+					return
+				}
+				d.logger.Warn("[snapshots] download from mirror", "file", f.path, "err", err, "retry_in", retryInterval)
+				select {
+				case <-ctx.Done():
+					return
+				case <-time.After(retryInterval):
+				}
+			}
+		}
+	}
+}
+
+// download fetches the file into a .part file, resuming it if it exists, then verifies and
+// renames it.
+func (d *Downloader) download(ctx context.Context, f *file) error {
+	fPath := filepath.Join(d.dir, f.path)
+	partPath := fPath + partSuffix
+	if err := os.MkdirAll(filepath.Dir(fPath), 0755); err != nil {
+		return err
+	}
+	out, err := os.OpenFile(partPath, os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+	offset, err := out.Seek(0, io.SeekEnd)
+	if err != nil {
+		return err
+	}
+
+	u := *d.mirror
+	u.Path = path.Join(u.Path, filepath.ToSlash(f.path))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return err
+	}
+	if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+	}
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	switch resp.StatusCode {
+	case http.StatusPartialContent:
+		total, err := contentRangeTotal(resp.Header.Get("Content-Range"))
+		if err != nil {
+			return err
+		}
+		f.total.Store(total)
+	case http.StatusOK: // no range support, or nothing downloaded yet
+		if err := out.Truncate(0); err != nil {
+			return err
+		}
+		if offset, err = out.Seek(0, io.SeekStart); err != nil {
+			return err
+		}
+		f.total.Store(resp.ContentLength)
+	case http.StatusRequestedRangeNotSatisfiable: // the part file is complete, or bigger than the file
+		if total, err := contentRangeTotal(resp.Header.Get("Content-Range")); err != nil || total != offset {
+			if err := os.Remove(partPath); err != nil {
+				return err
+			}
+			return fmt.Errorf("partial download does not match the file on the mirror, restarting it")
+		}
+		f.total.Store(offset)
+	default:
+		return fmt.Errorf("%s: %s", u.String(), resp.Status)
+	}
+	f.completed.Store(offset)
+
+	if resp.StatusCode != http.StatusRequestedRangeNotSatisfiable {
+		if _, err := io.Copy(out, &progressReader{r: resp.Body, n: &f.completed}); err != nil {
+			return err
+		}
+		if f.total.Load() >= 0 && f.completed.Load() != f.total.Load() {
+			return fmt.Errorf("incomplete download: %d of %d bytes", f.completed.Load(), f.total.Load())
+		}
+	}
+	if err := out.Sync(); err != nil {
+		return err
+	}
+	if err := out.Close(); err != nil {
+		return err
+	}
+
+	if err := d.verify(partPath, f); err != nil {
+		if err := os.Remove(partPath); err != nil {
+			return err
+		}
+		f.completed.Store(0)
+		return err
+	}
+	if err := os.Rename(partPath, fPath); err != nil {
+		return err
+	}
+	f.total.Store(f.completed.Load())
+	f.done.Store(true)
+	d.logger.Info("[snapshots] downloaded from mirror", "file", f.path)
+	return nil
+}
+
+// verify checks the torrent hash of the file, named like f, if it's known.
+func (d *Downloader) verify(fPath string, f *file) error {
+	if f.hash == "" {
+		return nil
+	}
+	have, err := snapshotsync.TorrentInfoHash(fPath, filepath.ToSlash(f.path))
+	if err != nil {
+		return err
+	}
+	if have != f.hash {
+		return fmt.Errorf("torrent hash mismatch: have %s, want %s", have, f.hash)
+	}
+	return nil
+}
+
+// contentRangeTotal returns the size of the file from a Content-Range header: bytes 0-10/100 or bytes */100.
+func contentRangeTotal(contentRange string) (int64, error) {
+	i := strings.LastIndexByte(contentRange, '/')
+	if !strings.HasPrefix(contentRange, "bytes ") || i < 0 {
+		return 0, fmt.Errorf("invalid Content-Range %q", contentRange)
+	}
+	total, err := strconv.ParseInt(contentRange[i+1:], 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid Content-Range %q", contentRange)
+	}
+	return total, nil
+}
+
+// isLocalPath tells whether the path is relative and within the snapshots dir
+func isLocalPath(p string) bool {
+	if p == "" || filepath.IsAbs(p) {
+		return false
+	}
+	p = filepath.Clean(p)
+	return p != ".." && !strings.HasPrefix(p, ".."+string(filepath.Separator))
+}
+
+type progressReader struct {
+	r io.Reader
+	n *atomic.Int64
+}
+
+func (r *progressReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	r.n.Add(int64(n))
+	return n, err
+}
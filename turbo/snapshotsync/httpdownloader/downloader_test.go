@@ -0,0 +1,168 @@
+package httpdownloader
+
+import (
+	"bytes"
+	"context"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ledgerwatch/erigon-lib/downloader/downloadergrpc"
+	proto_downloader "github.com/ledgerwatch/erigon-lib/gointerfaces/downloader"
+	"github.com/ledgerwatch/log/v3"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ledgerwatch/erigon/turbo/snapshotsync"
+)
+
+func waitCompleted(t *testing.T, d *Downloader) *proto_downloader.StatsResponse {
+	t.Helper()
+	deadline := time.Now().Add(10 * time.Second)
+	for {
+		stats, err := d.Stats(context.Background(), &proto_downloader.StatsRequest{})
+		require.NoError(t, err)
+		if stats.Completed {
+			return stats
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("download not completed: %+v", stats)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestDownloadFromMirror(t *testing.T) {
+	retryInterval = 10 * time.Millisecond
+	logger := log.New()
+
+	// the mirror
+	mirrorDir := t.TempDir()
+	content := make([]byte, 1_000_000)
+	rand.New(rand.NewSource(1)).Read(content)
+	const segment = "v1-000000-000500-headers.seg"
+	segmentPath := filepath.Join(mirrorDir, segment)
+	require.NoError(t, os.WriteFile(segmentPath, content, 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(mirrorDir, "v1-000000-000500-headers.idx"), content[:10], 0644))
+	hash, err := snapshotsync.TorrentInfoHash(segmentPath, segment)
+	require.NoError(t, err)
+
+	var requests atomic.Int64
+	handler := NewServer(mirrorDir)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		handler.ServeHTTP(w, r)
+	}))
+	defer srv.Close()
+
+	t.Run("download and resume", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		snapDir := t.TempDir()
+		// an interrupted download
+		require.NoError(t, os.WriteFile(filepath.Join(snapDir, segment+partSuffix), content[:300_000], 0644))
+
+		d, err := New(ctx, srv.URL+"/", snapDir, 2, logger)
+		require.NoError(t, err)
+		_, err = d.Download(ctx, &proto_downloader.DownloadRequest{Items: []*proto_downloader.DownloadItem{
+			{Path: segment, TorrentHash: downloadergrpc.String2Proto(hash)},
+		}})
+		require.NoError(t, err)
+		stats := waitCompleted(t, d)
+		require.Equal(t, uint64(len(content)), stats.BytesCompleted)
+		require.Equal(t, uint64(len(content)), stats.BytesTotal)
+		require.Equal(t, float32(100), stats.Progress)
+
+		have, err := os.ReadFile(filepath.Join(snapDir, segment))
+		require.NoError(t, err)
+		require.True(t, bytes.Equal(content, have))
+		_, err = os.Stat(filepath.Join(snapDir, segment+partSuffix))
+		require.ErrorIs(t, err, os.ErrNotExist)
+
+		_, err = d.Verify(ctx, &proto_downloader.VerifyRequest{})
+		require.NoError(t, err)
+	})
+
+	t.Run("hash mismatch", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		snapDir := t.TempDir()
+		// the beginning of the part file differs from the file on the mirror
+		corrupted := append([]byte{}, content[:300_000]...)
+		corrupted[0]++
+		require.NoError(t, os.WriteFile(filepath.Join(snapDir, segment+partSuffix), corrupted, 0644))
+
+		d, err := New(ctx, srv.URL, snapDir, 1, logger)
+		require.NoError(t, err)
+		_, err = d.Download(ctx, &proto_downloader.DownloadRequest{Items: []*proto_downloader.DownloadItem{
+			{Path: segment, TorrentHash: downloadergrpc.String2Proto(hash)},
+		}})
+		require.NoError(t, err)
+		waitCompleted(t, d)
+		have, err := os.ReadFile(filepath.Join(snapDir, segment))
+		require.NoError(t, err)
+		require.True(t, bytes.Equal(content, have))
+	})
+
+	t.Run("existing and missing files", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		snapDir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(snapDir, segment), content, 0644))
+
+		d, err := New(ctx, srv.URL, snapDir, 1, logger)
+		require.NoError(t, err)
+		before := requests.Load()
+		_, err = d.Download(ctx, &proto_downloader.DownloadRequest{Items: []*proto_downloader.DownloadItem{
+			{Path: segment, TorrentHash: downloadergrpc.String2Proto(hash)},
+		}})
+		require.NoError(t, err)
+		waitCompleted(t, d)
+		require.Equal(t, before, requests.Load())
+
+		_, err = d.Download(ctx, &proto_downloader.DownloadRequest{Items: []*proto_downloader.DownloadItem{
+			{Path: "v1-000500-001000-headers.seg"},
+		}})
+		require.NoError(t, err)
+		time.Sleep(50 * time.Millisecond)
+		stats, err := d.Stats(ctx, &proto_downloader.StatsRequest{})
+		require.NoError(t, err)
+		require.False(t, stats.Completed)
+
+		_, err = d.Download(ctx, &proto_downloader.DownloadRequest{Items: []*proto_downloader.DownloadItem{{Path: "../chaindata/mdbx.dat"}}})
+		require.Error(t, err)
+	})
+}
+
+func TestServer(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "v1-000000-000500-headers.seg"), []byte("0123456789"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "v1-000000-000500-headers.idx"), []byte("index"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "v1-000500-001000-headers.seg.part"), []byte("part"), 0644))
+	srv := httptest.NewServer(NewServer(dir))
+	defer srv.Close()
+
+	get := func(path string, header http.Header) *http.Response {
+		req, err := http.NewRequest(http.MethodGet, srv.URL+path, nil)
+		require.NoError(t, err)
+		for k, v := range header {
+			req.Header[k] = v
+		}
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		return resp
+	}
+
+	require.Equal(t, http.StatusOK, get("/v1-000000-000500-headers.seg", nil).StatusCode)
+	resp := get("/v1-000000-000500-headers.seg", http.Header{"Range": []string{"bytes=4-"}})
+	require.Equal(t, http.StatusPartialContent, resp.StatusCode)
+	require.Equal(t, "bytes 4-9/10", resp.Header.Get("Content-Range"))
+	require.Equal(t, http.StatusNotFound, get("/v1-000000-000500-headers.idx", nil).StatusCode)
+	require.Equal(t, http.StatusNotFound, get("/v1-000500-001000-headers.seg.part", nil).StatusCode)
+	require.Equal(t, http.StatusNotFound, get("/", nil).StatusCode)
+}
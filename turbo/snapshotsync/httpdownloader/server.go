@@ -0,0 +1,47 @@
+package httpdownloader
+
+import (
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+)
+
+// servedExtensions are the files published by NewServer: segments, state history and their
+// .torrent files. Indices are built locally, and partial downloads are never served.
+var servedExtensions = map[string]bool{
+	".seg":     true,
+	".kv":      true,
+	".v":       true,
+	".ef":      true,
+	".torrent": true,
+}
+
+// NewServer returns a handler publishing the snapshots of snapDir, to be used as the mirror
+// of a Downloader. Range requests are supported. Directories are not listed.
+func NewServer(snapDir string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return
+		}
+		name := path.Clean("/" + r.URL.Path)[1:]
+		if !servedExtensions[path.Ext(name)] {
+			http.NotFound(w, r)
+			return
+		}
+		f, err := os.Open(filepath.Join(snapDir, filepath.FromSlash(name)))
+		if err != nil {
+			http.NotFound(w, r)
+			return
+		}
+		defer f.Close()
+		st, err := f.Stat()
+		if err != nil || !st.Mode().IsRegular() {
+			http.NotFound(w, r)
+			return
+		}
+		http.ServeContent(w, r, name, st.ModTime(), f)
+	})
+}
@@ -98,6 +98,8 @@ type MockSentry struct {
 	TransactionsV3 bool
 	agg            *libstate.AggregatorV3
 	BlockSnapshots *snapshotsync.RoSnapshots
+
+	badBlockErr error // why the last chain inserted was found invalid
 }
 
 func (ms *MockSentry) Close() {
@@ -117,6 +119,9 @@ func (ms *MockSentry) Close() {
 	if ms.DB != nil {
 		ms.DB.Close()
 	}
+	if ms.tb == nil {
+		os.RemoveAll(ms.Dirs.DataDir)
+	}
 }
 
 // Stream returns stream, waiting if necessary
@@ -213,13 +218,13 @@ func MockWithGenesisPruneMode(tb testing.TB, gspec *types.Genesis, key *ecdsa.Pr
 
 func MockWithEverything(tb testing.TB, gspec *types.Genesis, key *ecdsa.PrivateKey, prune prune.Mode, engine consensus.Engine, withTxPool bool, withPosDownloader bool) *MockSentry {
 	var tmpdir string
+	var err error
 	if tb != nil {
 		tmpdir = tb.TempDir()
-	} else {
-		tmpdir = os.TempDir()
+	} else if tmpdir, err = os.MkdirTemp("", "mock-sentry-"); err != nil {
+		panic(err)
 	}
 	dirs := datadir.New(tmpdir)
-	var err error
 
 	cfg := ethconfig.Defaults
 	cfg.StateStream = true
@@ -622,6 +627,7 @@ func (ms *MockSentry) insertPoWBlocks(chain *core.ChainPack) error {
 	if _, err = StageLoopStep(ms.Ctx, ms.ChainConfig, ms.DB, ms.Sync, ms.Notifications, initialCycle, ms.UpdateHead, ms.Log, nil); err != nil {
 		return err
 	}
+	ms.badBlockErr = ms.Sync.BadBlockErr()
 	if ms.TxPool != nil {
 		ms.ReceiveWg.Wait() // Wait for TxPool notification
 	}
@@ -647,7 +653,9 @@ func (ms *MockSentry) insertPoSBlocks(chain *core.ChainPack) error {
 		return err
 	}
 	SendPayloadStatus(ms.HeaderDownload(), headBlockHash, err)
-	ms.ReceivePayloadStatus()
+	if payloadStatus := ms.ReceivePayloadStatus(); payloadStatus.ValidationError != nil {
+		ms.badBlockErr = payloadStatus.ValidationError
+	}
 
 	fc := engineapi.ForkChoiceMessage{
 		HeadBlockHash:      chain.TopBlock.Hash(),
@@ -665,7 +673,10 @@ func (ms *MockSentry) insertPoSBlocks(chain *core.ChainPack) error {
 	return nil
 }
 
+// InsertChain inserts the blocks as if received from a peer or the consensus layer. If the chain is
+// found invalid, the returned error wraps the reason given by the stages, like a *core.BadBlockError.
 func (ms *MockSentry) InsertChain(chain *core.ChainPack) error {
+	ms.badBlockErr = nil
 	if err := ms.insertPoWBlocks(chain); err != nil {
 		return err
 	}
@@ -675,21 +686,21 @@ func (ms *MockSentry) InsertChain(chain *core.ChainPack) error {
 	// Check if the latest header was imported or rolled back
 	if err := ms.DB.View(ms.Ctx, func(tx kv.Tx) error {
 		if rawdb.ReadHeader(tx, chain.TopBlock.Hash(), chain.TopBlock.NumberU64()) == nil {
-			return fmt.Errorf("did not import block %d %x", chain.TopBlock.NumberU64(), chain.TopBlock.Hash())
+			return ms.withBadBlockErr(fmt.Errorf("did not import block %d %x", chain.TopBlock.NumberU64(), chain.TopBlock.Hash()))
 		}
 		execAt, err := stages.GetStageProgress(tx, stages.Execution)
 		if err != nil {
 			return err
 		}
 		if execAt == 0 {
-			return fmt.Errorf("sentryMock.InsertChain end up with Execution stage progress = 0")
+			return ms.withBadBlockErr(fmt.Errorf("sentryMock.InsertChain end up with Execution stage progress = 0"))
 		}
 		return nil
 	}); err != nil {
 		return err
 	}
 	if ms.sentriesClient.Hd.IsBadHeader(chain.TopBlock.Hash()) {
-		return fmt.Errorf("block %d %x was invalid", chain.TopBlock.NumberU64(), chain.TopBlock.Hash())
+		return ms.withBadBlockErr(fmt.Errorf("block %d %x was invalid", chain.TopBlock.NumberU64(), chain.TopBlock.Hash()))
 	}
 	//if ms.HistoryV3 {
 	//if err := ms.agg.BuildFiles(ms.Ctx, ms.DB); err != nil {
@@ -708,6 +719,13 @@ func (ms *MockSentry) InsertChain(chain *core.ChainPack) error {
 	return nil
 }
 
+func (ms *MockSentry) withBadBlockErr(err error) error {
+	if ms.badBlockErr == nil {
+		return err
+	}
+	return fmt.Errorf("%v: %w", err, ms.badBlockErr)
+}
+
 func (ms *MockSentry) SendPayloadRequest(message *types.Block) {
 	ms.sentriesClient.Hd.BeaconRequestList.AddPayloadRequest(message)
 }
@@ -724,6 +742,28 @@ func (ms *MockSentry) HeaderDownload() *headerdownload.HeaderDownload {
 	return ms.sentriesClient.Hd
 }
 
+// BuildBlock assembles a proof-of-stake block from the transactions of the pool, the way the block builder
+// of the node does. It needs the mock to be created with the pool.
+func (ms *MockSentry) BuildBlock(param *core.BlockBuilderParameters, interrupt *int32) (*types.BlockWithReceipts, error) {
+	miningConfig := ethconfig.Defaults.Miner
+	miningState := stagedsync.NewProposingState(&miningConfig)
+	miningState.MiningConfig.Etherbase = param.SuggestedFeeRecipient
+	blockReader := snapshotsync.NewBlockReaderWithSnapshots(ms.BlockSnapshots, ms.TransactionsV3)
+	proposingSync := stagedsync.New(
+		stagedsync.MiningStages(ms.Ctx,
+			stagedsync.StageMiningCreateBlockCfg(ms.DB, miningState, *ms.ChainConfig, ms.Engine, ms.TxPool, ms.txPoolDB, param, ms.Dirs.Tmp),
+			stagedsync.StageMiningExecCfg(ms.DB, miningState, ms.Notifications.Events, *ms.ChainConfig, ms.Engine, &vm.Config{}, ms.Dirs.Tmp, interrupt, param.PayloadId, ms.TxPool, ms.txPoolDB, ms.BlockSnapshots, ms.TransactionsV3),
+			stagedsync.StageHashStateCfg(ms.DB, ms.Dirs, ms.HistoryV3, ms.agg),
+			stagedsync.StageTrieCfg(ms.DB, false, true, true, ms.Dirs.Tmp, blockReader, nil, ms.HistoryV3, ms.agg),
+			stagedsync.StageMiningFinishCfg(ms.DB, *ms.ChainConfig, ms.Engine, miningState, nil),
+		), stagedsync.MiningUnwindOrder, stagedsync.MiningPruneOrder,
+		ms.Log)
+	if err := MiningStep(ms.Ctx, ms.DB, proposingSync, ms.Dirs.Tmp); err != nil {
+		return nil, err
+	}
+	return <-miningState.MiningResultPOSCh, nil
+}
+
 func (ms *MockSentry) NewHistoryStateReader(blockNum uint64, tx kv.Tx) state.StateReader {
 	r, err := rpchelper.CreateHistoryStateReader(tx, blockNum, 0, ms.HistoryV3, ms.ChainConfig.ChainName)
 	if err != nil {
@@ -0,0 +1,183 @@
+package transactions
+
+import (
+	"context"
+	"math/big"
+	"time"
+
+	"github.com/VictoriaMetrics/metrics"
+	jsoniter "github.com/json-iterator/go"
+	"github.com/ledgerwatch/log/v3"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/ledgerwatch/erigon-lib/chain"
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/kv"
+
+	"github.com/ledgerwatch/erigon/consensus"
+	"github.com/ledgerwatch/erigon/core"
+	"github.com/ledgerwatch/erigon/core/state"
+	"github.com/ledgerwatch/erigon/core/types"
+	"github.com/ledgerwatch/erigon/core/vm/evmtypes"
+	"github.com/ledgerwatch/erigon/eth/tracers"
+	"github.com/ledgerwatch/erigon/turbo/rpchelper"
+)
+
+// ParallelTraceRetraces counts the transactions traced again because an earlier transaction
+// of their block overwrote what they read.
+var ParallelTraceRetraces = metrics.NewCounter(`trace_parallel_retraces`)
+
+// TxTrace is what TraceTx wrote for a transaction and the error it returned.
+type TxTrace struct {
+	Output []byte
+	Err    error
+}
+
+type speculativeTrace struct {
+	done   chan struct{}
+	ibs    *state.IntraBlockState
+	reader *state.OverlayReader
+	trace  TxTrace
+}
+
+// TraceTxsParallel traces the transactions of a block with several workers, on top of ibs
+// and reader as returned by ComputeTxEnv for the first transaction.
+//
+// Like the exec3 workers, each transaction runs speculatively on the state committed so
+// far, recording what it reads, and the transactions are committed in order once the
+// values they read are checked to be still current. A transaction which read values
+// overwritten by an earlier one is traced again on the committed state, so the traces
+// are the ones of sequential tracing.
+//
+// The fees are credited to the coinbase without reading it, as the IntraBlockState records
+// balance increases of accounts it has not loaded, and applied to the committed balance
+// when the transaction is committed. So transactions conflict on the coinbase only if they
+// actually read it, for example by sending to it or through the tracer.
+func TraceTxsParallel(
+	ctx context.Context,
+	db kv.RoDB,
+	engine consensus.EngineReader,
+	block *types.Block,
+	chainConfig *chain.Config,
+	blockCtx evmtypes.BlockContext,
+	ibs *state.IntraBlockState,
+	reader state.StateReader,
+	excessDataGas *big.Int,
+	historyV3 bool,
+	config *tracers.TraceConfig,
+	callTimeout time.Duration,
+	streams jsoniter.StreamPool,
+	workers int,
+) ([]TxTrace, error) {
+	rules := chainConfig.Rules(block.NumberU64(), block.Time())
+	signer := types.MakeSigner(chainConfig, block.NumberU64(), block.Time())
+	header := block.Header()
+	txns := block.Transactions()
+
+	// the changes of the block initialisation are the first ones to commit
+	overlay := state.NewOverlayState()
+	if err := ibs.CommitBlock(rules, overlay.Writer()); err != nil {
+		return nil, err
+	}
+
+	traceTx := func(ctx context.Context, idx int, r state.StateReader) (*state.IntraBlockState, TxTrace) {
+		txn := txns[idx]
+		txIbs := state.New(r)
+		txIbs.SetTxContext(txn.Hash(), block.Hash(), idx)
+		msg, _ := txn.AsMessage(*signer, block.BaseFee(), rules)
+		if msg.FeeCap().IsZero() && engine != nil {
+			syscall := func(contract libcommon.Address, data []byte) ([]byte, error) {
+				return core.SysCallContract(contract, data, chainConfig, txIbs, header, engine, true /* constCall */, excessDataGas)
+			}
+			msg.SetIsFree(engine.IsServiceTransaction(msg.From(), syscall))
+		}
+		txCtx := evmtypes.TxContext{
+			TxHash:   txn.Hash(),
+			Origin:   msg.From(),
+			GasPrice: msg.GasPrice(),
+		}
+		stream := streams.BorrowStream(nil)
+		defer streams.ReturnStream(stream)
+		err := TraceTx(ctx, msg, blockCtx, txCtx, txIbs, config, chainConfig, stream, callTimeout)
+		return txIbs, TxTrace{Output: append([]byte(nil), stream.Buffer()...), Err: err}
+	}
+
+	specs := make([]speculativeTrace, len(txns))
+	next := make(chan int, len(txns))
+	for idx := range specs {
+		specs[idx].done = make(chan struct{})
+		next <- idx
+	}
+	close(next)
+
+	ctx, cancel := context.WithCancel(ctx)
+	g, gCtx := errgroup.WithContext(ctx)
+	defer func() {
+		cancel()
+		_ = g.Wait()
+	}()
+	for i := 0; i < workers; i++ {
+		g.Go(func() error {
+			// the workers read the state at the start of the block through their own transactions
+			tx, err := db.BeginRo(gCtx)
+			if err != nil {
+				return err
+			}
+			defer tx.Rollback()
+			base, err := rpchelper.CreateHistoryStateReader(tx, block.NumberU64(), 0, historyV3, chainConfig.ChainName)
+			if err != nil {
+				return err
+			}
+			for idx := range next {
+				if err := gCtx.Err(); err != nil {
+					return err
+				}
+				spec := &specs[idx]
+				spec.reader = overlay.NewReader(base)
+				spec.ibs, spec.trace = traceTx(gCtx, idx, spec.reader)
+				close(spec.done)
+			}
+			return nil
+		})
+	}
+
+	traces := make([]TxTrace, len(txns))
+	var retraced int
+	for idx := range specs {
+		spec := &specs[idx]
+		select {
+		case <-spec.done:
+		case <-gCtx.Done():
+			if err := g.Wait(); err != nil {
+				return nil, err
+			}
+			return nil, gCtx.Err()
+		}
+		// the changes of a transaction whose tracing failed are kept, as in sequential tracing
+		ok, err := overlay.Commit(spec.reader, reader, func(w state.StateWriter) error {
+			return spec.ibs.FinalizeTx(rules, w)
+		})
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			retraced++
+			ParallelTraceRetraces.Inc()
+			r := overlay.NewReader(reader)
+			txIbs, trace := traceTx(gCtx, idx, r)
+			if _, err = overlay.Commit(r, reader, func(w state.StateWriter) error {
+				return txIbs.FinalizeTx(rules, w)
+			}); err != nil {
+				return nil, err
+			}
+			spec.trace = trace
+		}
+		traces[idx] = spec.trace
+		spec.ibs, spec.reader = nil, nil
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+	log.Debug("Traced block in parallel", "block", block.NumberU64(), "txs", len(txns), "retraced", retraced, "workers", workers)
+	return traces, nil
+}
@@ -59,3 +59,15 @@ func EndCluster(w io.Writer) {
 		`}
 `)
 }
+
+func Arrow(w io.Writer, from string, to string, dashed bool) {
+	if dashed {
+		fmt.Fprintf(w,
+			`%s -> %s [dir=forward style=dashed];
+`, from, to)
+	} else {
+		fmt.Fprintf(w,
+			`%s -> %s [dir=forward];
+`, from, to)
+	}
+}